@@ -27,7 +27,7 @@ type Engine struct {
 
 var Engines = []Engine{{
 	"query-engine",
-	"PRISMA_QUERY_ENGINE_BINARY",
+	QueryEngineEnv,
 }, {
 	"schema-engine",
 	"PRISMA_SCHEMA_ENGINE_BINARY",
@@ -98,11 +98,16 @@ func FetchEngine(dir string, engineName string, binaryName string) error {
 
 	to := GetEnginePath(dir, engineName, binaryName)
 
-	if _, err := os.Stat(to); !os.IsNotExist(err) {
+	if cached(to) {
 		logger.Debug.Printf("%s is cached at %s", engineName, to)
 		return nil
 	}
 
+	extension, codec, err := compression()
+	if err != nil {
+		return err
+	}
+
 	url := platform.CheckForExtension(binaryName, fmt.Sprintf(EngineURL, EngineVersion, binaryName, engineName))
 
 	logger.Debug.Printf("%s is missing, downloading...", engineName)
@@ -113,6 +118,13 @@ func FetchEngine(dir string, engineName string, binaryName string) error {
 		return fmt.Errorf("could not download %s to %s: %w", url, to, err)
 	}
 
+	if codec != nil {
+		if err := compressFile(to, to+"."+extension, codec); err != nil {
+			return fmt.Errorf("could not compress %s: %w", to, err)
+		}
+		logger.Debug.Printf("stored %s compressed at %s.%s", engineName, to, extension)
+	}
+
 	logger.Debug.Printf("%s done", engineName)
 
 	return nil
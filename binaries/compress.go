@@ -0,0 +1,320 @@
+package binaries
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// CompressionEnv selects the codec which is used to store downloaded engines compressed in the cache, e.g. "gz".
+// If it is not set, engines are stored with DefaultCompression; set it to "none" to store them uncompressed.
+const CompressionEnv = "PRISMA_ENGINES_COMPRESSION"
+
+// DefaultCompression is the extension of the codec which is used if CompressionEnv is not set
+const DefaultCompression = "zst"
+
+// MaxExtracted is the number of extracted engines which are kept in the extraction dir. Less recently used engines
+// are removed when an engine is extracted.
+var MaxExtracted = 4
+
+// Codec compresses and decompresses cached engines
+type Codec interface {
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, gzip.BestCompression)
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+}
+
+func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
+
+var codecs = struct {
+	sync.RWMutex
+	byExtension map[string]Codec
+}{
+	byExtension: map[string]Codec{"gz": gzipCodec{}, "zst": zstdCodec{}},
+}
+
+// RegisterCodec registers a codec for the given file extension, so that engines can be stored in other formats than
+// zstd and gzip, e.g. xz:
+//
+//	binaries.RegisterCodec("xz", xzCodec{})
+//
+// The codec is used for new downloads if CompressionEnv is set to the extension; cached engines are extracted with
+// the codec matching their extension.
+func RegisterCodec(extension string, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+	codecs.byExtension[extension] = codec
+}
+
+// compression returns the extension and codec selected with CompressionEnv, or an empty extension if engines should
+// be stored uncompressed
+func compression() (string, Codec, error) {
+	extension := os.Getenv(CompressionEnv)
+	if extension == "" {
+		extension = DefaultCompression
+	}
+	if extension == "none" {
+		return "", nil, nil
+	}
+
+	codecs.RLock()
+	defer codecs.RUnlock()
+	codec, ok := codecs.byExtension[extension]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown %s %q; register a codec with binaries.RegisterCodec", CompressionEnv, extension)
+	}
+	return extension, codec, nil
+}
+
+// compressed returns the path and codec of a compressed variant of file, if any exists
+func compressed(file string) (string, Codec, bool) {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	extensions := make([]string, 0, len(codecs.byExtension))
+	for extension := range codecs.byExtension {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+
+	for _, extension := range extensions {
+		if _, err := os.Stat(file + "." + extension); err == nil {
+			return file + "." + extension, codecs.byExtension[extension], true
+		}
+	}
+	return "", nil, false
+}
+
+// cached reports whether the engine at file exists, either uncompressed or compressed
+func cached(file string) bool {
+	if _, err := os.Stat(file); err == nil {
+		return true
+	}
+	_, _, ok := compressed(file)
+	return ok
+}
+
+// compressFile compresses from into to and removes from
+func compressFile(from, to string, codec Codec) error {
+	input, err := os.Open(from)
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer input.Close()
+
+	tmp := to + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer out.Close()
+
+	w, err := codec.NewWriter(out)
+	if err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+	if _, err := io.Copy(w, input); err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	if err := os.Rename(tmp, to); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+	_ = input.Close()
+	return os.Remove(from)
+}
+
+// extracted memoizes the extraction paths of compressed engines in this process
+var extracted sync.Map
+
+// ResolveEngine returns the path of an executable engine for file. If only a compressed variant of file exists in the
+// cache, it is extracted on demand; extractions are reused across processes and only the MaxExtracted most recently
+// used ones are kept.
+func ResolveEngine(file string) (string, error) {
+	if _, err := os.Stat(file); err == nil {
+		return file, nil
+	}
+
+	source, codec, ok := compressed(file)
+	if !ok {
+		return "", fmt.Errorf("engine %s: %w", file, os.ErrNotExist)
+	}
+
+	if to, ok := extracted.Load(source); ok {
+		if _, err := os.Stat(to.(string)); err == nil {
+			return to.(string), nil
+		}
+	}
+
+	to, err := extract(source, path.Base(file), codec)
+	if err != nil {
+		return "", fmt.Errorf("extract %s: %w", source, err)
+	}
+	extracted.Store(source, to)
+	return to, nil
+}
+
+// ExtractDir returns the directory compressed engines are extracted to
+func ExtractDir() string {
+	return path.Join(GlobalCacheDir(), "extracted")
+}
+
+// extractPath returns the path a compressed engine is extracted to
+func extractPath(source, name string) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+
+	// the key changes when the compressed engine is replaced
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d", source, info.Size(), info.ModTime().UnixNano())))
+	return path.Join(ExtractDir(), hex.EncodeToString(sum[:8]), name), nil
+}
+
+func extract(source, name string, codec Codec) (string, error) {
+	start := time.Now()
+
+	to, err := extractPath(source, name)
+	if err != nil {
+		return "", err
+	}
+	dir := path.Dir(to)
+
+	now := time.Now()
+	if _, err := os.Stat(to); err == nil {
+		// mark as recently used
+		_ = os.Chtimes(dir, now, now)
+		return to, nil
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("mkdir: %w", err)
+	}
+
+	input, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("open: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer input.Close()
+
+	r, err := codec.NewReader(input)
+	if err != nil {
+		return "", fmt.Errorf("decompress: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer r.Close()
+
+	// extract to a temp file first, so that other processes never see a partially written engine
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("create: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil { //nolint:gosec
+		_ = tmp.Close()
+		return "", fmt.Errorf("decompress: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("close: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), os.ModePerm); err != nil {
+		return "", fmt.Errorf("chmod: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), to); err != nil {
+		return "", fmt.Errorf("rename: %w", err)
+	}
+	_ = os.Chtimes(dir, now, now)
+
+	logger.Debug.Printf("extracted %s to %s in %s", source, to, time.Since(start))
+
+	evictExtracted(dir)
+
+	return to, nil
+}
+
+// evictExtracted removes the least recently used extractions exceeding MaxExtracted, keeping keep
+func evictExtracted(keep string) {
+	entries, err := os.ReadDir(ExtractDir())
+	if err != nil {
+		return
+	}
+
+	type extraction struct {
+		dir  string
+		used time.Time
+	}
+	var extractions []extraction
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		extractions = append(extractions, extraction{path.Join(ExtractDir(), entry.Name()), info.ModTime()})
+	}
+
+	sort.Slice(extractions, func(i, j int) bool {
+		return extractions[i].used.After(extractions[j].used)
+	})
+
+	kept := 1
+	for _, e := range extractions {
+		if e.dir == keep {
+			continue
+		}
+		if kept < MaxExtracted {
+			kept++
+			continue
+		}
+		logger.Debug.Printf("removing least recently used extracted engine %s", e.dir)
+		if err := os.RemoveAll(e.dir); err != nil {
+			logger.Debug.Printf("could not remove %s: %s", e.dir, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package binaries
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// compressedEngine writes a zstd-compressed fake engine to the cache and returns its uncompressed path
+func compressedEngine(t *testing.T, dir, name, content string) string {
+	return compressedEngineWith(t, dir, name, content, "zst", zstdCodec{})
+}
+
+func compressedEngineWith(t *testing.T, dir, name, content, extension string, codec Codec) string {
+	file := path.Join(dir, name)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressFile(file, file+"."+extension, codec); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestResolveEngine(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PRISMA_GLOBAL_CACHE_DIR", t.TempDir())
+
+	file := compressedEngine(t, dir, "prisma-query-engine-debian", "engine")
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Fatalf("expected uncompressed engine to be removed, got %v", err)
+	}
+	massert.Equal(t, true, cached(file))
+
+	resolved, err := ResolveEngine(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "prisma-query-engine-debian", path.Base(resolved))
+
+	content, err := os.ReadFile(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "engine", string(content))
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, info.Mode()&0100 != 0)
+
+	// the extraction is reused
+	again, err := ResolveEngine(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, resolved, again)
+
+	// uncompressed engines are used as they are
+	plain := path.Join(dir, "plain")
+	if err := os.WriteFile(plain, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err = ResolveEngine(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, plain, resolved)
+
+	_, err = ResolveEngine(path.Join(dir, "missing"))
+	massert.Equal(t, true, errors.Is(err, os.ErrNotExist))
+}
+
+func TestResolveEngine_gzip(t *testing.T) {
+	t.Setenv("PRISMA_GLOBAL_CACHE_DIR", t.TempDir())
+
+	resolved, err := ResolveEngine(compressedEngineWith(t, t.TempDir(), "engine", "gzip engine", "gz", gzipCodec{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "gzip engine", string(content))
+}
+
+func TestResolveEngine_evictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PRISMA_GLOBAL_CACHE_DIR", t.TempDir())
+
+	max := MaxExtracted
+	MaxExtracted = 2
+	defer func() {
+		MaxExtracted = max
+	}()
+
+	var resolved []string
+	for _, name := range []string{"a", "b", "c"} {
+		r, err := ResolveEngine(compressedEngine(t, dir, name, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolved = append(resolved, r)
+	}
+
+	entries, err := os.ReadDir(ExtractDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 2, len(entries))
+
+	_, err = os.Stat(resolved[0])
+	massert.Equal(t, true, os.IsNotExist(err))
+
+	// evicted engines are extracted again
+	r, err := ResolveEngine(path.Join(dir, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, resolved[0], r)
+	if _, err := os.Stat(r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	t.Setenv(CompressionEnv, "")
+	extension, _, err := compression()
+	massert.Equal(t, "zst", extension)
+	massert.Equal(t, nil, err)
+
+	t.Setenv(CompressionEnv, "none")
+	extension, codec, err := compression()
+	massert.Equal(t, "", extension)
+	massert.Equal(t, nil, codec)
+	massert.Equal(t, nil, err)
+
+	t.Setenv(CompressionEnv, "gz")
+	extension, _, err = compression()
+	massert.Equal(t, "gz", extension)
+	massert.Equal(t, nil, err)
+
+	t.Setenv(CompressionEnv, "xz")
+	_, _, err = compression()
+	massert.Equal(t, `unknown PRISMA_ENGINES_COMPRESSION "xz"; register a codec with binaries.RegisterCodec`, err.Error())
+
+	RegisterCodec("xz", gzipCodec{})
+	defer func() {
+		codecs.Lock()
+		delete(codecs.byExtension, "xz")
+		codecs.Unlock()
+	}()
+	extension, _, err = compression()
+	massert.Equal(t, "xz", extension)
+	massert.Equal(t, nil, err)
+}
@@ -0,0 +1,198 @@
+package binaries
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+
+	"github.com/steebchen/prisma-client-go/binaries/platform"
+)
+
+// QueryEngineEnv overrides the path of the query engine binary
+const QueryEngineEnv = "PRISMA_QUERY_ENGINE_BINARY"
+
+// Candidate is a path where the query engine is looked up at runtime
+type Candidate struct {
+	// Source describes the lookup location, e.g. "local" for the working directory
+	Source string
+
+	Path string
+
+	// Cached is true for engines in the global cache dir, which may be stored compressed
+	Cached bool
+}
+
+// QueryEngineCandidates returns the paths where the query engine is looked up at runtime if QueryEngineEnv is not set,
+// in order. The exact binary name of the platform, which includes e.g. the OpenSSL version, takes precedence over the
+// static one.
+func QueryEngineCandidates() []Candidate {
+	static := platform.CheckForExtension(platform.Name(), platform.BinaryPlatformNameStatic())
+	exact := platform.CheckForExtension(platform.Name(), platform.BinaryPlatformNameDynamic())
+
+	const name = "prisma-query-engine-"
+	unpackDir := GlobalUnpackDir(EngineVersion)
+	cacheDir := path.Join(GlobalCacheDir(), EngineVersion)
+
+	return []Candidate{
+		{Source: "local", Path: path.Join("./", name+exact)},
+		{Source: "local", Path: path.Join("./", name+static)},
+		{Source: "cache", Path: path.Join(cacheDir, name+exact), Cached: true},
+		{Source: "cache", Path: path.Join(cacheDir, name+static), Cached: true},
+		{Source: "unpack", Path: path.Join(unpackDir, name+exact)},
+		{Source: "unpack", Path: path.Join(unpackDir, name+static)},
+	}
+}
+
+// QueryEngineArgs returns the arguments the query engine is spawned with, following the arguments selecting where it
+// listens, e.g. "-p 4466"
+func QueryEngineArgs(listen []string, tracing bool) []string {
+	args := append([]string{}, listen...)
+	args = append(args, "--enable-raw-queries")
+	if tracing {
+		args = append(args, "--enable-open-telemetry", "--enable-telemetry-in-response")
+	}
+	return args
+}
+
+// Download is a file which is fetched if it isn't cached yet
+type Download struct {
+	// Name is the name of the binary, e.g. "query-engine"
+	Name string
+
+	URL string
+
+	// Path is where the file is stored, which has the extension of the codec if engines are stored compressed
+	Path string
+
+	// Cached is true if the file already exists, so it won't be downloaded
+	Cached bool
+}
+
+// Spawn describes how the query engine is started
+type Spawn struct {
+	// Binary is the path of the query engine which would be spawned; empty if no query engine was found
+	Binary string
+
+	// Source is the lookup location of Binary, e.g. "cache", or QueryEngineEnv if it was set
+	Source string
+
+	// Candidates are the paths which are checked for the query engine, in order
+	Candidates []Candidate
+
+	// Args are the arguments for the default configuration; the port or socket path are chosen when the engine starts
+	Args []string
+}
+
+// InstallPlan describes which files are downloaded and which binary is spawned for the current platform
+type InstallPlan struct {
+	// Platform is the static binary name of the platform, e.g. "linux-static-x64"
+	Platform string
+
+	// ExactPlatform is the exact binary name of the platform, e.g. "debian-openssl-3.0.x"
+	ExactPlatform string
+
+	PrismaVersion string
+	EngineVersion string
+
+	// CacheDir is the directory downloads are stored in
+	CacheDir string
+
+	// Downloads are the files fetched by FetchNative, e.g. when running the generator
+	Downloads []Download
+
+	// Spawn describes how the query engine is started at runtime
+	Spawn Spawn
+}
+
+// Plan returns which URLs would be fetched, to which paths, and which query engine would be spawned with which
+// arguments, without downloading, extracting or spawning any engine. This allows to pre-approve the URLs and to
+// pre-bake the files into images. Compressed engines are reported at the path they would be extracted to. To detect
+// the exact platform like the runtime does, Plan reads /etc/os-release and runs `openssl version` on linux.
+func Plan() (*InstallPlan, error) {
+	extension, _, err := compression()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := GlobalCacheDir()
+	static := platform.BinaryPlatformNameStatic()
+
+	p := &InstallPlan{
+		Platform:      static,
+		ExactPlatform: platform.BinaryPlatformNameDynamic(),
+		PrismaVersion: PrismaVersion,
+		EngineVersion: EngineVersion,
+		CacheDir:      dir,
+	}
+
+	cli := platform.CheckForExtension(platform.Name(), path.Join(dir, PrismaCLIName()))
+	_, err = os.Stat(cli)
+	p.Downloads = append(p.Downloads, Download{
+		Name:   "prisma-cli",
+		URL:    platform.CheckForExtension(platform.Name(), fmt.Sprintf(PrismaURL, "prisma-cli", PrismaVersion, platform.Name(), platform.Arch())),
+		Path:   cli,
+		Cached: err == nil,
+	})
+
+	for _, e := range Engines {
+		to := GetEnginePath(dir, e.Name, static)
+		download := Download{
+			Name:   e.Name,
+			URL:    platform.CheckForExtension(static, fmt.Sprintf(EngineURL, EngineVersion, static, e.Name)),
+			Path:   to,
+			Cached: cached(to),
+		}
+		if extension != "" {
+			download.Path = to + "." + extension
+		}
+		p.Downloads = append(p.Downloads, download)
+	}
+
+	p.Spawn, err = planSpawn()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func planSpawn() (Spawn, error) {
+	// the query engine listens on a unix socket except on windows
+	listen := []string{"--unix-path", "<socket>"}
+	if runtime.GOOS == "windows" {
+		listen = []string{"-p", "<port>"}
+	}
+
+	s := Spawn{
+		Candidates: QueryEngineCandidates(),
+		Args:       QueryEngineArgs(listen, false),
+	}
+
+	if file := os.Getenv(QueryEngineEnv); file != "" {
+		s.Binary = file
+		s.Source = QueryEngineEnv
+		return s, nil
+	}
+
+	for _, c := range s.Candidates {
+		if _, err := os.Stat(c.Path); err == nil {
+			s.Binary = c.Path
+			s.Source = c.Source
+			return s, nil
+		}
+		if !c.Cached {
+			continue
+		}
+		if source, _, ok := compressed(c.Path); ok {
+			to, err := extractPath(source, path.Base(c.Path))
+			if err != nil {
+				return Spawn{}, err
+			}
+			s.Binary = to
+			s.Source = c.Source
+			return s, nil
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,77 @@
+package binaries
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestPlan(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("PRISMA_GLOBAL_CACHE_DIR", cache)
+	t.Setenv("PRISMA_UNPACK_DIR", t.TempDir())
+	t.Setenv(QueryEngineEnv, "")
+	t.Setenv(CompressionEnv, "none")
+
+	p, err := Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, cache, p.CacheDir)
+	massert.Equal(t, 3, len(p.Downloads))
+	for _, d := range p.Downloads {
+		massert.Equal(t, false, d.Cached)
+		massert.Equal(t, true, strings.HasPrefix(d.Path, cache))
+	}
+	massert.Equal(t, "query-engine", p.Downloads[1].Name)
+	massert.Equal(t, true, strings.Contains(p.Downloads[1].URL, EngineVersion))
+	massert.Equal(t, "", p.Spawn.Binary)
+	massert.Equal(t, 6, len(p.Spawn.Candidates))
+	massert.Equal(t, "--enable-raw-queries", p.Spawn.Args[len(p.Spawn.Args)-1])
+
+	// downloaded engines are spawned from the cache
+	engine := p.Downloads[1].Path
+	if err := os.MkdirAll(path.Dir(engine), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(engine, []byte("engine"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, p.Downloads[1].Cached)
+	massert.Equal(t, engine, p.Spawn.Binary)
+	massert.Equal(t, "cache", p.Spawn.Source)
+
+	// compressed engines are spawned from the extraction dir
+	if err := compressFile(engine, engine+".gz", gzipCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(CompressionEnv, "gz")
+
+	p, err = Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, p.Downloads[1].Cached)
+	massert.Equal(t, engine+".gz", p.Downloads[1].Path)
+	massert.Equal(t, true, strings.HasPrefix(p.Spawn.Binary, ExtractDir()))
+	if _, err := os.Stat(p.Spawn.Binary); !os.IsNotExist(err) {
+		t.Fatalf("expected the engine not to be extracted, got %v", err)
+	}
+
+	// the env var takes precedence
+	t.Setenv(QueryEngineEnv, "/opt/query-engine")
+	p, err = Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "/opt/query-engine", p.Spawn.Binary)
+	massert.Equal(t, QueryEngineEnv, p.Spawn.Source)
+}
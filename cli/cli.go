@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
+	"strings"
 
 	"github.com/steebchen/prisma-client-go/binaries"
 	"github.com/steebchen/prisma-client-go/binaries/platform"
@@ -13,6 +15,42 @@ import (
 
 // Run the prisma CLI with given arguments
 func Run(arguments []string, output bool) error {
+	cmd, err := command(context.Background(), arguments)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdin = os.Stdin
+
+	if output {
+		cmd.Stderr = os.Stderr
+		cmd.Stdout = os.Stdout
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not run %+v: %w", arguments, err)
+	}
+
+	return nil
+}
+
+// Output runs the prisma CLI with given arguments and returns its combined stdout and stderr, which is also returned
+// as part of the error if the command fails
+func Output(ctx context.Context, arguments []string) ([]byte, error) {
+	cmd, err := command(ctx, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("could not run %+v: %w: %s", arguments, err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// command prepares the prisma CLI command with given arguments and the environment pointing to the cached engines
+func command(ctx context.Context, arguments []string) (*exec.Cmd, error) {
 	logger.Debug.Printf("running cli with args %+v", arguments)
 	// TODO respect initial PRISMA_<name>_BINARY env
 	// TODO optionally override CLI filepath using PRISMA_CLI_PATH
@@ -20,14 +58,14 @@ func Run(arguments []string, output bool) error {
 	dir := binaries.GlobalCacheDir()
 
 	if err := binaries.FetchNative(dir); err != nil {
-		return fmt.Errorf("could not fetch binaries: %w", err)
+		return nil, fmt.Errorf("could not fetch binaries: %w", err)
 	}
 
 	prisma := binaries.PrismaCLIName()
 
 	logger.Debug.Printf("running %s %+v", path.Join(dir, prisma), arguments)
 
-	cmd := exec.Command(path.Join(dir, prisma), arguments...) //nolint:gosec
+	cmd := exec.CommandContext(ctx, path.Join(dir, prisma), arguments...) //nolint:gosec
 	binaryName := platform.CheckForExtension(platform.Name(), platform.BinaryPlatformNameStatic())
 
 	cmd.Env = os.Environ()
@@ -42,21 +80,16 @@ func Run(arguments []string, output bool) error {
 			value = env
 		} else {
 			value = path.Join(dir, binaries.EngineVersion, fmt.Sprintf("prisma-%s-%s", engine.Name, binaryName))
+			// cached engines may be stored compressed
+			resolved, err := binaries.ResolveEngine(value)
+			if err != nil {
+				return nil, fmt.Errorf("resolve %s: %w", engine.Name, err)
+			}
+			value = resolved
 		}
 
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", engine.Env, value))
 	}
 
-	cmd.Stdin = os.Stdin
-
-	if output {
-		cmd.Stderr = os.Stderr
-		cmd.Stdout = os.Stdout
-	}
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("could not run %+v: %w", arguments, err)
-	}
-
-	return nil
+	return cmd, nil
 }
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/migrate"
+)
+
+// DefaultSeedPackage is the package containing the seed program, relative to the working directory
+const DefaultSeedPackage = "./prisma/seed"
+
+const seedUsage = `Usage: go run github.com/steebchen/prisma-client-go seed [flags] [package]
+
+Prepares the database and runs the seed program in package, which defaults to ` + DefaultSeedPackage + `.
+The seed program usually calls db.Seed with a function which creates the data.
+
+Flags:
+`
+
+// Seed runs the seed command with the given arguments, which applies the migrations or pushes the schema if
+// requested and then runs the seed program with `go run`
+func Seed(ctx context.Context, arguments []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	schema := flags.String("schema", "prisma/schema.prisma", "path of the Prisma schema")
+	deploy := flags.Bool("migrate", false, "apply pending migrations before seeding")
+	push := flags.Bool("push", false, "push the schema before seeding, e.g. for databases without migrations")
+	reset := flags.Bool("force-reset", false, "with --push, reset the database first, deleting all data")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), seedUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(arguments); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *deploy && *push {
+		return fmt.Errorf("seed: --migrate and --push can't be used together")
+	}
+	if *reset && !*push {
+		return fmt.Errorf("seed: --force-reset requires --push")
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("seed: expected at most one package, got %v", flags.Args())
+	}
+
+	pkg := DefaultSeedPackage
+	if flags.NArg() == 1 {
+		pkg = flags.Arg(0)
+	}
+
+	// the database url is read from the environment, just like the client does
+	switch {
+	case *deploy:
+		result, err := migrate.Deploy(ctx, *schema, "")
+		if err != nil {
+			return fmt.Errorf("seed: %w", err)
+		}
+		logger.Info.Printf("applied %d migrations", len(result.Applied))
+	case *push:
+		result, err := migrate.Push(ctx, *schema, "", migrate.ForceReset(*reset))
+		if err != nil {
+			return fmt.Errorf("seed: %w", err)
+		}
+		logger.Info.Printf("pushed the schema in %d steps", result.ExecutedSteps)
+	}
+
+	logger.Info.Printf("running seed program %s", pkg)
+
+	cmd := exec.CommandContext(ctx, "go", "run", pkg) //nolint:gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("seed: run %s: %w", pkg, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestSeed_invalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{{
+		name: "migrate and push",
+		args: []string{"--migrate", "--push"},
+		want: "seed: --migrate and --push can't be used together",
+	}, {
+		name: "force reset without push",
+		args: []string{"--force-reset"},
+		want: "seed: --force-reset requires --push",
+	}, {
+		name: "multiple packages",
+		args: []string{"./a", "./b"},
+		want: "seed: expected at most one package, got [./a ./b]",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Seed(context.Background(), tt.args, &out, &out)
+			massert.Equal(t, tt.want, err.Error())
+		})
+	}
+}
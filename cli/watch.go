@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+)
+
+// WatchFlag is the flag of the generate command which regenerates the client whenever the schema changes
+const WatchFlag = "--watch"
+
+// watchInterval is how often the schema is checked for changes
+var watchInterval = 250 * time.Millisecond
+
+// watchDebounce is how long the schema has to stay unchanged before the client is regenerated, so that saving several
+// files at once only regenerates the client once
+var watchDebounce = 300 * time.Millisecond
+
+// HasWatchFlag returns whether the arguments of the generate command contain --watch
+func HasWatchFlag(arguments []string) bool {
+	for _, arg := range arguments {
+		if arg == WatchFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch generates the client with `prisma generate` and the given arguments, and then regenerates it whenever the
+// schema changes until ctx is done. The schema is read from the --schema argument or the default locations of the
+// Prisma CLI. Failed generations, e.g. because of a syntax error, are reported to stderr and don't stop watching.
+func Watch(ctx context.Context, arguments []string, stdout, stderr io.Writer) error {
+	var args []string
+	for _, arg := range arguments {
+		if arg != WatchFlag {
+			args = append(args, arg)
+		}
+	}
+
+	path, err := schemaPath(args)
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return watch(ctx, path, stderr, func(ctx context.Context) error {
+		cmd, err := command(ctx, append([]string{"generate"}, args...))
+		if err != nil {
+			return err
+		}
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	})
+}
+
+// watch calls generate once and then each time the schema at path changed and stayed unchanged for watchDebounce
+func watch(ctx context.Context, path string, stderr io.Writer, generate func(ctx context.Context) error) error {
+	current, err := readSchema(path)
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	run := func() {
+		if err := generate(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(stderr, "could not generate the client: %s\n", err)
+			return
+		}
+		fmt.Fprintf(stderr, "watching %s for changes\n", path)
+	}
+	run()
+
+	generated := current
+	var changed time.Time
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			schema, err := readSchema(path)
+			if err != nil {
+				// editors may briefly remove files while saving them
+				continue
+			}
+			if schema != current {
+				current, changed = schema, now
+				continue
+			}
+			if current != generated && now.Sub(changed) >= watchDebounce {
+				generated = current
+				fmt.Fprintf(stderr, "schema changed, regenerating the client\n")
+				run()
+			}
+		}
+	}
+}
+
+// readSchema returns the paths and contents of all files of the schema at path
+func readSchema(path string) (string, error) {
+	files, err := schemaengine.ReadSchema(path)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, file := range files {
+		b.WriteString(file.Path)
+		b.WriteByte(0)
+		b.WriteString(file.Content)
+		b.WriteByte(0)
+	}
+	return b.String(), nil
+}
+
+// schemaPath returns the schema set with --schema, or the first default location of the Prisma CLI which exists
+func schemaPath(arguments []string) (string, error) {
+	for i, arg := range arguments {
+		if value, ok := strings.CutPrefix(arg, "--schema="); ok {
+			return value, nil
+		}
+		if arg == "--schema" {
+			if i+1 == len(arguments) {
+				return "", fmt.Errorf("--schema requires a path")
+			}
+			return arguments[i+1], nil
+		}
+	}
+	for _, path := range []string{"schema.prisma", "prisma/schema.prisma", "prisma/schema"} {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no schema found, set its path with --schema")
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestHasWatchFlag(t *testing.T) {
+	massert.Equal(t, true, HasWatchFlag([]string{"--schema", "schema.prisma", "--watch"}))
+	massert.Equal(t, false, HasWatchFlag([]string{"--schema", "schema.prisma"}))
+}
+
+func TestSchemaPath(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{{
+		name: "separate value",
+		args: []string{"--schema", "prisma/schema", "--watch"},
+		want: "prisma/schema",
+	}, {
+		name: "inline value",
+		args: []string{"--watch", "--schema=custom.prisma"},
+		want: "custom.prisma",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schemaPath(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := schemaPath([]string{"--schema"})
+	massert.Equal(t, "--schema requires a path", err.Error())
+}
+
+func TestWatch(t *testing.T) {
+	interval, debounce := watchInterval, watchDebounce
+	watchInterval, watchDebounce = 10*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() {
+		watchInterval, watchDebounce = interval, debounce
+	})
+
+	path := filepath.Join(t.TempDir(), "schema.prisma")
+	if err := os.WriteFile(path, []byte("model A {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs atomic.Int32
+	done := make(chan error)
+	go func() {
+		var out bytes.Buffer
+		done <- watch(ctx, path, &out, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+	}()
+
+	waitFor := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for runs.Load() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d generations, got %d", want, runs.Load())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// the client is generated when watching starts
+	waitFor(1)
+
+	// quick successive changes are debounced into one generation
+	for _, content := range []string{"model A { id Int @id }", "model B { id Int @id }"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(watchInterval)
+	}
+	waitFor(2)
+
+	// saving without changes doesn't regenerate the client
+	if err := os.WriteFile(path, []byte("model B { id Int @id }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(4 * watchDebounce)
+	massert.Equal(t, int32(2), runs.Load())
+
+	cancel()
+	massert.Equal(t, nil, <-done)
+}
@@ -0,0 +1,174 @@
+// Package containers provides throwaway PostgreSQL and MySQL databases for integration tests, which run in Docker
+// containers started with testcontainers-go. It is a separate module so that projects which don't use it don't
+// depend on testcontainers-go.
+//
+// Example:
+//
+//	func TestCreateUser(t *testing.T) {
+//	  client := containers.NewPostgres(t, "../prisma/schema.prisma", func(url string) *db.PrismaClient {
+//	    return db.NewClient(db.WithDatasourceURL(url))
+//	  })
+//
+//	  // use client
+//	}
+package containers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/steebchen/prisma-client-go/dbtest"
+)
+
+const (
+	// DefaultPostgresImage is the image NewPostgres uses unless WithImage is given
+	DefaultPostgresImage = "postgres:16-alpine"
+
+	// DefaultMySQLImage is the image NewMySQL uses unless WithImage is given
+	DefaultMySQLImage = "mysql:8.4"
+)
+
+const (
+	database = "prisma"
+	user     = "prisma"
+	password = "prisma"
+)
+
+// Option configures a container
+type Option func(*options)
+
+type options struct {
+	image string
+}
+
+// WithImage sets the Docker image of the container, e.g. "postgres:15" to test against a specific database version
+func WithImage(image string) Option {
+	return func(o *options) {
+		o.image = image
+	}
+}
+
+// NewPostgres starts a PostgreSQL container, pushes the schema of the file at schemaPath and returns a connected
+// client created with newClient. The client is disconnected and the container is terminated when the test ends. The
+// provider of the datasource must be "postgresql", and its url must be set with env().
+//
+// The test is skipped if Docker is not available.
+func NewPostgres[C dbtest.Client](t testing.TB, schemaPath string, newClient dbtest.NewClientFunc[C], opts ...Option) C {
+	t.Helper()
+
+	o := options{
+		image: DefaultPostgresImage,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	port := nat.Port("5432/tcp")
+	address := start(t, testcontainers.ContainerRequest{
+		Image:        o.image,
+		ExposedPorts: []string{string(port)},
+		Env: map[string]string{
+			"POSTGRES_DB":       database,
+			"POSTGRES_USER":     user,
+			"POSTGRES_PASSWORD": password,
+		},
+		// postgres restarts once after running the init scripts
+		WaitingFor: wait.ForAll(
+			wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+			wait.ForListeningPort(port),
+		),
+	}, port)
+
+	return dbtest.Push(t, schemaPath, databaseURL("postgresql", address)+"?sslmode=disable", newClient)
+}
+
+// NewMySQL starts a MySQL container, pushes the schema of the file at schemaPath and returns a connected client
+// created with newClient. The client is disconnected and the container is terminated when the test ends. The provider
+// of the datasource must be "mysql", and its url must be set with env().
+//
+// The test is skipped if Docker is not available.
+func NewMySQL[C dbtest.Client](t testing.TB, schemaPath string, newClient dbtest.NewClientFunc[C], opts ...Option) C {
+	t.Helper()
+
+	o := options{
+		image: DefaultMySQLImage,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	port := nat.Port("3306/tcp")
+	address := start(t, testcontainers.ContainerRequest{
+		Image:        o.image,
+		ExposedPorts: []string{string(port)},
+		Env: map[string]string{
+			"MYSQL_DATABASE":      database,
+			"MYSQL_USER":          user,
+			"MYSQL_PASSWORD":      password,
+			"MYSQL_ROOT_PASSWORD": password,
+		},
+		// the temporary server which runs the init scripts listens on port 0
+		WaitingFor: wait.ForAll(
+			wait.ForLog("port: 3306  MySQL Community Server"),
+			wait.ForListeningPort(port),
+		),
+	}, port)
+
+	return dbtest.Push(t, schemaPath, databaseURL("mysql", address), newClient)
+}
+
+// start starts a container, terminates it when the test ends and returns the host address of port
+func start(t testing.TB, req testcontainers.ContainerRequest, port nat.Port) string {
+	t.Helper()
+
+	ctx := context.Background()
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err == nil {
+		err = provider.Health(ctx)
+	}
+	if err != nil {
+		t.Skipf("containers: docker is not available: %s", err)
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	// a container may have been created even if it failed to start
+	if container != nil {
+		t.Cleanup(func() {
+			if err := container.Terminate(context.Background()); err != nil {
+				t.Errorf("containers: terminate %s: %s", req.Image, err)
+			}
+		})
+	}
+	if err != nil {
+		t.Fatalf("containers: start %s: %s", req.Image, err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("containers: get host of %s: %s", req.Image, err)
+	}
+	mapped, err := container.MappedPort(ctx, port)
+	if err != nil {
+		t.Fatalf("containers: get port of %s: %s", req.Image, err)
+	}
+	return fmt.Sprintf("%s:%s", host, mapped.Port())
+}
+
+func databaseURL(scheme, address string) string {
+	u := url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(user, password),
+		Host:   address,
+		Path:   "/" + database,
+	}
+	return u.String()
+}
@@ -0,0 +1,82 @@
+// Package dbtest provides throwaway databases for tests, which are prepared with the project schema and cleaned up
+// when the test ends, along with a connected client.
+//
+// Example:
+//
+//	func TestCreateUser(t *testing.T) {
+//	  client := dbtest.SQLite(t, "../prisma/schema.prisma", func(url string) *db.PrismaClient {
+//	    return db.NewClient(db.WithDatasourceURL(url))
+//	  })
+//
+//	  // use client
+//	}
+package dbtest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/migrate"
+	"github.com/steebchen/prisma-client-go/runtime/lifecycle"
+)
+
+// Client is implemented by the clients of all generated packages
+type Client interface {
+	Lifecycle() *lifecycle.Lifecycle
+}
+
+// NewClientFunc creates a client for the given database url, usually db.NewClient(db.WithDatasourceURL(url))
+type NewClientFunc[C Client] func(url string) C
+
+// SQLite creates an SQLite database in a temporary directory, pushes the schema at schemaPath and returns a connected
+// client created with newClient. The client is disconnected and the database is deleted when the test
+// ends. The provider of the datasource must be "sqlite", and its url must be set with env().
+//
+// The database is a file rather than an in-memory database, as the schema engine and the query engine run in separate
+// processes which can't share memory.
+func SQLite[C Client](t testing.TB, schemaPath string, newClient NewClientFunc[C]) C {
+	t.Helper()
+
+	files, err := schemaengine.ReadSchema(schemaPath)
+	if err != nil {
+		t.Fatalf("dbtest: %s", err)
+	}
+	if provider, _ := schemaengine.DatasourceProvider(schemaengine.JoinSchema(files)); provider != "sqlite" {
+		t.Fatalf("dbtest: the datasource provider of %s must be sqlite, got %q", schemaPath, provider)
+	}
+
+	url := "file:" + filepath.ToSlash(filepath.Join(t.TempDir(), "test.db"))
+	return Push(t, schemaPath, url, newClient)
+}
+
+// Push pushes the schema at schemaPath, a file or a directory of .prisma files, to the database at url, deleting all
+// data, and returns a connected client created with newClient, which is disconnected when the test ends. It is used by
+// the other helpers and can be used to prepare any other database, e.g. one started by CI. The datasource url of the
+// schema must be set with env().
+func Push[C Client](t testing.TB, schemaPath string, url string, newClient NewClientFunc[C]) C {
+	t.Helper()
+
+	if _, err := migrate.Push(context.Background(), schemaPath, url, migrate.ForceReset(true)); err != nil {
+		t.Fatalf("dbtest: push schema: %s", err)
+	}
+
+	return connect(t, url, newClient)
+}
+
+// connect creates a client for url, connects it and disconnects it when the test ends
+func connect[C Client](t testing.TB, url string, newClient NewClientFunc[C]) C {
+	t.Helper()
+
+	client := newClient(url)
+	if err := client.Lifecycle().Connect(); err != nil {
+		t.Fatalf("dbtest: connect: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Lifecycle().Disconnect(); err != nil {
+			t.Errorf("dbtest: disconnect: %s", err)
+		}
+	})
+	return client
+}
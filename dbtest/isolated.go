@@ -0,0 +1,110 @@
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/migrate"
+)
+
+// Isolated creates a uniquely named schema (PostgreSQL, CockroachDB) or database (MySQL) on the server of
+// databaseURL, applies the migrations next to the schema file at schemaPath, or pushes the schema if there are none,
+// and returns a connected client created with newClient for it. The client is disconnected and the schema or
+// database is dropped when the test ends, so tests using the same server can run in parallel with t.Parallel().
+//
+// For SQLite, a separate database file is created instead. The datasource url of the schema must be set with env().
+func Isolated[C Client](t testing.TB, schemaPath string, databaseURL string, newClient NewClientFunc[C]) C {
+	t.Helper()
+
+	files, err := schemaengine.ReadSchema(schemaPath)
+	if err != nil {
+		t.Fatalf("dbtest: %s", err)
+	}
+	provider, _ := schemaengine.DatasourceProvider(schemaengine.JoinSchema(files))
+
+	name := isolatedName(t)
+
+	var isolatedURL, create, drop string
+	switch provider {
+	case "postgresql", "postgres", "cockroachdb":
+		isolatedURL, err = withQuery(databaseURL, "schema", name)
+		create = fmt.Sprintf(`CREATE SCHEMA "%s";`, name)
+		drop = fmt.Sprintf(`DROP SCHEMA IF EXISTS "%s" CASCADE;`, name)
+	case "mysql":
+		isolatedURL, err = withPath(databaseURL, name)
+		create = fmt.Sprintf("CREATE DATABASE `%s`;", name)
+		drop = fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;", name)
+	case "sqlite":
+		isolatedURL = "file:" + filepath.ToSlash(filepath.Join(t.TempDir(), name+".db"))
+	default:
+		t.Fatalf("dbtest: isolated databases are not supported for provider %q", provider)
+	}
+	if err != nil {
+		t.Fatalf("dbtest: invalid database url: %s", err)
+	}
+
+	ctx := context.Background()
+	if create != "" {
+		if err := migrate.Execute(ctx, databaseURL, create); err != nil {
+			t.Fatalf("dbtest: create %s: %s", name, err)
+		}
+		t.Cleanup(func() {
+			if err := migrate.Execute(context.Background(), databaseURL, drop); err != nil {
+				t.Errorf("dbtest: drop %s: %s", name, err)
+			}
+		})
+	}
+
+	if _, err := migrate.Reset(ctx, schemaPath, isolatedURL); err != nil {
+		t.Fatalf("dbtest: migrate %s: %s", name, err)
+	}
+
+	return connect(t, isolatedURL, newClient)
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// isolatedName returns a unique name for a schema or database of the test, which is readable when debugging and
+// short enough for all databases
+func isolatedName(t testing.TB) string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("dbtest: generate name: %s", err)
+	}
+
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(t.Name()), "_")
+	if len(name) > 40 {
+		name = name[:40]
+	}
+	return fmt.Sprintf("test_%s_%s", strings.Trim(name, "_"), hex.EncodeToString(b))
+}
+
+// withQuery sets a query parameter of a database url
+func withQuery(databaseURL, key, value string) (string, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// withPath sets the database name of a database url
+func withPath(databaseURL, database string) (string, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return "", err
+	}
+	u.Path = "/" + database
+	return u.String(), nil
+}
@@ -0,0 +1,40 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/transaction"
+)
+
+// TransactionClient is implemented by the clients of all generated packages
+type TransactionClient[C any] interface {
+	Client
+	InteractiveTransaction(ctx context.Context, fn func(tx C) error, opts ...transaction.Option) error
+}
+
+// rollbackTimeout is how long the test body of WithRollback may run before the engine rolls the transaction back
+const rollbackTimeout = 10 * time.Minute
+
+// errRollback makes InteractiveTransaction roll back the transaction of WithRollback
+var errRollback = errors.New("dbtest: rollback")
+
+// WithRollback runs fn with a client whose queries are sent within a transaction, which is always rolled back when fn
+// returns, even if the test fails. Tests can thereby share a database prepared once, e.g. in TestMain, without
+// seeing each other's changes.
+//
+// All queries of fn must use tx. Code which commits its own transactions, such as client.Prisma.Transaction, can't be
+// tested this way.
+func WithRollback[C TransactionClient[C]](t testing.TB, client C, fn func(tx C)) {
+	t.Helper()
+
+	err := client.InteractiveTransaction(context.Background(), func(tx C) error {
+		fn(tx)
+		return errRollback
+	}, transaction.WithTimeout(rollbackTimeout))
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("dbtest: transaction: %s", err)
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/steebchen/prisma-client-go/binaries"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// DisableCacheEnv can be set to true to skip reading and writing the warm cache, e.g. on read-only filesystems
+const DisableCacheEnv = "PRISMA_CLIENT_GO_DISABLE_CACHE"
+
+// warmCache persists small pieces of startup metadata in the global cache dir, so that expensive steps such as
+// schema uploads or engine version checks can be skipped after a restart.
+// Entries are keyed by a hash (usually of the schema), so a changed schema never reads a stale entry.
+type warmCache struct {
+	dir string
+}
+
+func newWarmCache(namespace string) *warmCache {
+	if cacheDisabled() {
+		return nil
+	}
+	return &warmCache{
+		dir: path.Join(binaries.GlobalCacheDir(), "warm", namespace),
+	}
+}
+
+// cacheDisabled reports whether DisableCacheEnv is set to a true value such as "true" or "1"
+func cacheDisabled() bool {
+	value := os.Getenv(DisableCacheEnv)
+	if value == "" {
+		return false
+	}
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Info.Printf("invalid value %q for %s, expected a boolean; the warm cache stays enabled", value, DisableCacheEnv)
+		return false
+	}
+	return disabled
+}
+
+func (c *warmCache) file(key string) string {
+	return path.Join(c.dir, fmt.Sprintf("%x", sha256.Sum256([]byte(key))))
+}
+
+// Get returns the cached value for a key; ok is false if there is no entry or the cache is disabled
+func (c *warmCache) Get(key string) (value []byte, ok bool) {
+	if c == nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.file(key))
+	if err != nil {
+		return nil, false
+	}
+	logger.Debug.Printf("warm cache hit for %s", key)
+	return data, true
+}
+
+// Set stores a value for a key. Errors are logged but not returned, as the cache is only an optimization.
+func (c *warmCache) Set(key string, value []byte) {
+	if c == nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		logger.Debug.Printf("warm cache: could not create dir %s: %s", c.dir, err)
+		return
+	}
+	// write to a unique temp file first so concurrent processes never read partial entries or overwrite each other's
+	// temp files
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(c.file(key))+".*.tmp")
+	if err != nil {
+		logger.Debug.Printf("warm cache: could not create temp file in %s: %s", c.dir, err)
+		return
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// CreateTemp uses 0600, but entries are shared like the rest of the cache dir
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err != nil {
+		logger.Debug.Printf("warm cache: could not write %s: %s", tmp.Name(), err)
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.file(key)); err != nil {
+		logger.Debug.Printf("warm cache: could not rename %s: %s", tmp.Name(), err)
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// Delete removes an entry, e.g. when it turned out to be stale
+func (c *warmCache) Delete(key string) {
+	if c == nil {
+		return
+	}
+	_ = os.Remove(c.file(key))
+}
@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestWarmCache(t *testing.T) {
+	t.Setenv("PRISMA_GLOBAL_CACHE_DIR", t.TempDir())
+
+	cache := newWarmCache("test")
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("expected no entry")
+	}
+
+	cache.Set("a", []byte("value"))
+
+	got, ok := cache.Get("a")
+	if !ok {
+		t.Fatalf("expected entry")
+	}
+	if string(got) != "value" {
+		t.Errorf("Get() = %s, want %s", got, "value")
+	}
+
+	// a new instance reads the persisted entry, like after a restart
+	if _, ok := newWarmCache("test").Get("a"); !ok {
+		t.Fatalf("expected persisted entry")
+	}
+
+	cache.Delete("a")
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("expected entry to be deleted")
+	}
+}
+
+func TestWarmCache_disabled(t *testing.T) {
+	t.Setenv("PRISMA_GLOBAL_CACHE_DIR", t.TempDir())
+	t.Setenv(DisableCacheEnv, "true")
+
+	cache := newWarmCache("test")
+	cache.Set("a", []byte("value"))
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("expected no entry when cache is disabled")
+	}
+}
+
+func TestWarmCache_disabledValues(t *testing.T) {
+	for value, disabled := range map[string]bool{"": false, "true": true, "1": true, "false": false, "0": false, "invalid": false} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv(DisableCacheEnv, value)
+			if actual := newWarmCache("test") == nil; actual != disabled {
+				t.Errorf("disabled = %t, want %t", actual, disabled)
+			}
+		})
+	}
+}
+
+func TestWarmCache_concurrentSet(t *testing.T) {
+	t.Setenv("PRISMA_GLOBAL_CACHE_DIR", t.TempDir())
+
+	cache := newWarmCache("test")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cache.Set("a", []byte(fmt.Sprintf("value %d", i)))
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatalf("expected entry")
+	}
+
+	// no temp files are left behind
+	entries, err := os.ReadDir(cache.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 file in the cache dir, got %d", len(entries))
+	}
+}
@@ -14,11 +14,45 @@ import (
 
 var errNotFound = fmt.Errorf("not found; re-upload schema")
 
-func request(ctx context.Context, client *http.Client, method string, url string, payload []byte, apply func(*http.Request)) ([]byte, error) {
+// connectionError wraps errors which happened while sending a request or reading its response, e.g. because the
+// engine process died, as opposed to errors returned by the engine
+type connectionError struct {
+	err error
+}
+
+func (e *connectionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *connectionError) Unwrap() error {
+	return e.err
+}
+
+func request(ctx context.Context, client *http.Client, options Options, method string, url string, payload []byte, apply func(*http.Request)) ([]byte, error) {
 	if logger.Enabled {
 		logger.Debug.Printf("prisma engine payload: `%s`", payload)
 	}
 
+	if options.MaxRequestSize > 0 && len(payload) > options.MaxRequestSize {
+		return nil, &PayloadTooLargeError{
+			Direction: "request",
+			Size:      len(payload),
+			Limit:     options.MaxRequestSize,
+		}
+	}
+
+	compressed := false
+	if options.CompressRequests && len(payload) >= options.CompressMinSize {
+		size := len(payload)
+		var err error
+		payload, err = compress(payload)
+		if err != nil {
+			return nil, fmt.Errorf("compress payload: %w", err)
+		}
+		compressed = true
+		logger.Debug.Printf("compressed payload from %d to %d bytes", size, len(payload))
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(payload))
 	if err != nil {
 		return nil, fmt.Errorf("raw post: %w", err)
@@ -26,12 +60,16 @@ func request(ctx context.Context, client *http.Client, method string, url string
 
 	apply(req)
 
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
 	req = req.WithContext(ctx)
 
 	startReq := time.Now()
 	rawResponse, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("raw post: %w", err)
+		return nil, fmt.Errorf("raw post: %w", &connectionError{err})
 	}
 	defer func() {
 		if err := rawResponse.Body.Close(); err != nil {
@@ -41,9 +79,23 @@ func request(ctx context.Context, client *http.Client, method string, url string
 	reqDuration := time.Since(startReq)
 	logger.Debug.Printf("[timing] query engine raw request took %s", reqDuration)
 
-	responseBody, err := io.ReadAll(rawResponse.Body)
+	var body io.Reader = rawResponse.Body
+	if options.MaxResponseSize > 0 {
+		// read one more byte than allowed to detect whether the limit was exceeded
+		body = io.LimitReader(rawResponse.Body, int64(options.MaxResponseSize)+1)
+	}
+
+	responseBody, err := io.ReadAll(body)
 	if err != nil {
-		return nil, fmt.Errorf("raw read: %w", err)
+		return nil, fmt.Errorf("raw read: %w", &connectionError{err})
+	}
+
+	if options.MaxResponseSize > 0 && len(responseBody) > options.MaxResponseSize {
+		return nil, &PayloadTooLargeError{
+			Direction: "response",
+			Size:      len(responseBody),
+			Limit:     options.MaxResponseSize,
+		}
 	}
 
 	if rawResponse.StatusCode == http.StatusNotFound {
@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TransactionOptions configures an interactive transaction
+type TransactionOptions struct {
+	// MaxWait is how long to wait for a connection from the pool; the engine default is 2s
+	MaxWait time.Duration
+
+	// Timeout is how long the transaction may run before the engine rolls it back; the engine default is 5s
+	Timeout time.Duration
+
+	// IsolationLevel is the isolation level of the transaction, e.g. "Serializable"; the database default is used if
+	// empty
+	IsolationLevel string
+}
+
+// Transactor is implemented by engines which support interactive transactions, i.e. transactions which span multiple
+// requests. Queries are sent within the transaction when their context was created with WithTransactionID.
+type Transactor interface {
+	StartTransaction(ctx context.Context, options TransactionOptions) (string, error)
+	CommitTransaction(ctx context.Context, id string) error
+	RollbackTransaction(ctx context.Context, id string) error
+}
+
+type transactionIDKey struct{}
+
+// WithTransactionID returns a context which makes the engine send queries within the interactive transaction id
+func WithTransactionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, transactionIDKey{}, id)
+}
+
+// transactionIDFromContext returns the interactive transaction id of ctx, if any
+func transactionIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(transactionIDKey{}).(string)
+	return id
+}
+
+// NewTransactionEngine returns an engine which sends all queries through e within the interactive transaction id.
+// Connecting or disconnecting it has no effect, as the transaction belongs to e.
+func NewTransactionEngine(e Engine, id string) Engine {
+	return &transactionEngine{
+		engine: e,
+		id:     id,
+	}
+}
+
+// InTransaction returns whether e sends queries within an interactive transaction, i.e. whether it was returned by
+// NewTransactionEngine
+func InTransaction(e Engine) bool {
+	_, ok := e.(*transactionEngine)
+	return ok
+}
+
+type transactionEngine struct {
+	engine Engine
+	id     string
+}
+
+func (e *transactionEngine) Connect() error {
+	return nil
+}
+
+func (e *transactionEngine) Disconnect() error {
+	return nil
+}
+
+func (e *transactionEngine) Do(ctx context.Context, payload interface{}, into interface{}) error {
+	return e.engine.Do(WithTransactionID(ctx, e.id), payload, into)
+}
+
+func (e *transactionEngine) Batch(ctx context.Context, payload interface{}, into interface{}) error {
+	return e.engine.Batch(WithTransactionID(ctx, e.id), payload, into)
+}
+
+func (e *transactionEngine) Name() string {
+	return e.engine.Name()
+}
+
+// StartTransaction starts an interactive transaction and returns its id
+func (e *QueryEngine) StartTransaction(ctx context.Context, options TransactionOptions) (string, error) {
+	payload := map[string]interface{}{}
+	if options.MaxWait > 0 {
+		payload["max_wait"] = options.MaxWait.Milliseconds()
+	}
+	if options.Timeout > 0 {
+		payload["timeout"] = options.Timeout.Milliseconds()
+	}
+	if options.IsolationLevel != "" {
+		payload["isolation_level"] = options.IsolationLevel
+	}
+
+	body, err := e.Request(ctx, "POST", "/transaction/start", payload, true)
+	if err != nil {
+		return "", fmt.Errorf("start transaction: %w", err)
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("start transaction: json unmarshal: %w", err)
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("start transaction: unexpected response %s", body)
+	}
+	return response.ID, nil
+}
+
+// CommitTransaction commits the interactive transaction id
+func (e *QueryEngine) CommitTransaction(ctx context.Context, id string) error {
+	if _, err := e.Request(ctx, "POST", "/transaction/"+id+"/commit", map[string]interface{}{}, true); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RollbackTransaction rolls back the interactive transaction id
+func (e *QueryEngine) RollbackTransaction(ctx context.Context, id string) error {
+	if _, err := e.Request(ctx, "POST", "/transaction/"+id+"/rollback", map[string]interface{}{}, true); err != nil {
+		return fmt.Errorf("rollback transaction: %w", err)
+	}
+	return nil
+}
+
+// StartTransaction starts an interactive transaction on the shared engine
+func (e *SharedEngine) StartTransaction(ctx context.Context, options TransactionOptions) (string, error) {
+	t, err := e.transactor()
+	if err != nil {
+		return "", err
+	}
+	return t.StartTransaction(ctx, options)
+}
+
+// CommitTransaction commits an interactive transaction on the shared engine
+func (e *SharedEngine) CommitTransaction(ctx context.Context, id string) error {
+	t, err := e.transactor()
+	if err != nil {
+		return err
+	}
+	return t.CommitTransaction(ctx, id)
+}
+
+// RollbackTransaction rolls back an interactive transaction on the shared engine
+func (e *SharedEngine) RollbackTransaction(ctx context.Context, id string) error {
+	t, err := e.transactor()
+	if err != nil {
+		return err
+	}
+	return t.RollbackTransaction(ctx, id)
+}
+
+func (e *SharedEngine) transactor() (Transactor, error) {
+	engine, err := e.current()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := engine.(Transactor)
+	if !ok {
+		return nil, fmt.Errorf("the %s does not support interactive transactions", engine.Name())
+	}
+	return t, nil
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQueryEngine_interactiveTransaction(t *testing.T) {
+	var requests []string
+	var start map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		requests = append(requests, r.URL.Path+" "+r.Header.Get("X-transaction-id"))
+		switch r.URL.Path {
+		case "/transaction/start":
+			_ = json.NewDecoder(r.Body).Decode(&start)
+			_, _ = w.Write([]byte(`{"id":"tx1"}`))
+		case "/":
+			_, _ = w.Write([]byte(`{"data":{"result":{"id":"abc"}}}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	e := NewQueryEngine("", false, "[]", "", WithEngineURL(srv.URL+"/"))
+	if err := e.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Disconnect() //nolint:errcheck
+
+	ctx := context.Background()
+	id, err := e.StartTransaction(ctx, TransactionOptions{Timeout: time.Minute, IsolationLevel: "Serializable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "tx1", id)
+	massert.Equal(t, map[string]interface{}{"timeout": float64(60000), "isolation_level": "Serializable"}, start)
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := NewTransactionEngine(e, id).Do(ctx, protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Do(ctx, protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.RollbackTransaction(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, []string{
+		"/transaction/start ",
+		"/ tx1",
+		"/ ",
+		"/transaction/tx1/rollback ",
+	}, requests)
+}
@@ -0,0 +1,336 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/generator"
+	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+// LibraryPathEnv can be set to the path of the query engine shared library used by the LibraryEngine
+const LibraryPathEnv = "PRISMA_QUERY_ENGINE_LIBRARY"
+
+// ErrLibraryUnsupported is returned when connecting a LibraryEngine in a binary which was built without FFI support
+var ErrLibraryUnsupported = errors.New("the library engine requires cgo and the prisma_ffi build tag, e.g. go build -tags prisma_ffi")
+
+// library is the in-process query engine loaded from a shared library
+type library interface {
+	connect() error
+	// query runs a request; trace contains the trace headers as JSON object
+	query(body string, trace string) (string, error)
+	// disconnect disconnects the query engine and closes the library, even if disconnecting failed
+	disconnect() error
+	// close destroys the query engine and unloads the library without disconnecting it, e.g. if connecting failed
+	close()
+}
+
+// loadLibrary opens the query engine library; it is replaced in tests
+var loadLibrary = openLibrary
+
+func NewLibraryEngine(schema string, datasources string, datasourceURL string, options ...Option) *LibraryEngine {
+	return &LibraryEngine{
+		Schema:        schema,
+		datasources:   datasources,
+		datasourceURL: datasourceURL,
+		options:       newOptions(options),
+	}
+}
+
+// LibraryEngine runs the query engine in-process by loading it as a C shared library via cgo, so that no child
+// process has to be spawned and managed.
+type LibraryEngine struct {
+	// Schema contains the prisma Schema
+	Schema string
+
+	// datasources holds the raw datasources
+	datasources string
+
+	// datasourceURL holds the sanitized datasourceURL which is overridden in the datasource above
+	datasourceURL string
+
+	// lib holds the loaded query engine library
+	lib library
+
+	// connected indicates whether the user has called Connect()
+	connected bool
+
+	// disconnected indicates whether the user has called Disconnect()
+	disconnected bool
+
+	// options holds optional engine settings
+	options Options
+
+	// inflight tracks queries which are currently running in the library, including the ones whose context was
+	// cancelled, so that Disconnect doesn't destroy the engine while they still use it
+	inflight sync.WaitGroup
+
+	// mu guards lib, connected and disconnected
+	mu sync.Mutex
+}
+
+func (e *LibraryEngine) Name() string {
+	return "library-engine"
+}
+
+func (e *LibraryEngine) Connect() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.connected {
+		return fmt.Errorf("the library engine is already connected")
+	}
+
+	path := e.options.LibraryPath
+	if path == "" {
+		path = os.Getenv(LibraryPathEnv)
+	}
+	if path == "" {
+		return fmt.Errorf("no query engine library found; set %s or use engine.WithLibraryPath", LibraryPathEnv)
+	}
+
+	overrides, err := e.datasourceOverrides()
+	if err != nil {
+		return fmt.Errorf("datasource overrides: %w", err)
+	}
+
+	startLoad := time.Now()
+
+	lib, err := loadLibrary(path, e.Schema, overrides)
+	if err != nil {
+		return fmt.Errorf("open query engine library %s: %w", path, err)
+	}
+
+	logger.Debug.Printf("[timing] loading query engine library took %s", time.Since(startLoad))
+
+	if err := lib.connect(); err != nil {
+		lib.close()
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	e.lib = lib
+	e.connected = true
+
+	logger.Debug.Printf("connected.")
+
+	return nil
+}
+
+func (e *LibraryEngine) Disconnect() error {
+	e.mu.Lock()
+	e.disconnected = true
+	lib := e.lib
+	e.lib = nil
+	e.mu.Unlock()
+
+	logger.Debug.Printf("disconnecting...")
+
+	if lib == nil {
+		return nil
+	}
+
+	// queries can't be interrupted, so wait until the running ones return before the library is unloaded
+	e.inflight.Wait()
+
+	if err := lib.disconnect(); err != nil {
+		return fmt.Errorf("disconnect: %w", err)
+	}
+
+	logger.Debug.Printf("disconnected.")
+	return nil
+}
+
+// Do sends the request to the query engine library and unmarshals the response
+func (e *LibraryEngine) Do(ctx context.Context, payload interface{}, v interface{}) error {
+	ctx, cancel := withQueryDeadline(ctx)
+	defer cancel()
+
+	startReq := time.Now()
+
+	ctx, trace := e.options.startTrace(ctx)
+	body, err := e.Request(ctx, payload)
+	trace.finish(ctx, body, err)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	logger.Debug.Printf("[timing] query engine request took %s", time.Since(startReq))
+	logger.Debug.Printf("[timing] query engine response %s", body)
+
+	startParse := time.Now()
+
+	var response protocol.GQLResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("json gql response unmarshal: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		e := response.Errors[0]
+		if e.RawMessage() == internalUpdateNotFoundMessage ||
+			e.RawMessage() == internalDeleteNotFoundMessage {
+			return types.ErrNotFound
+		}
+
+		if e.UserFacingError != nil {
+			return fmt.Errorf("user facing error: %w", e.UserFacingError)
+		}
+
+		return fmt.Errorf("internal error: %s", e.RawMessage())
+	}
+
+	if !typedResultsFromContext(ctx) {
+		response.Data.Result, err = transformResponse(response.Data.Result)
+		if err != nil {
+			return fmt.Errorf("transform response: %w", err)
+		}
+	}
+
+	if e.options.StrictNumbers {
+		if err := checkPrecision(response.Data.Result); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(response.Data.Result, v); err != nil {
+		return fmt.Errorf("json data result unmarshal: %w", err)
+	}
+
+	logger.Debug.Printf("[timing] request unmarshaling took %s", time.Since(startParse))
+
+	return nil
+}
+
+// Batch sends a batch request to the query engine library; used for transactions
+func (e *LibraryEngine) Batch(ctx context.Context, payload interface{}, v interface{}) error {
+	ctx, trace := e.options.startTrace(ctx)
+	body, err := e.Request(ctx, payload)
+	trace.finish(ctx, body, err)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	body, err = transformResponse(body)
+	if err != nil {
+		return fmt.Errorf("transform response: %w", err)
+	}
+
+	if e.options.StrictNumbers {
+		if err := checkBatchPrecision(body); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("json body unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+// Request sends a raw payload to the query engine library. The call itself can not be interrupted, so when ctx is
+// cancelled, Request returns early and the result of the running query is discarded; Disconnect still waits for it.
+func (e *LibraryEngine) Request(ctx context.Context, payload interface{}) ([]byte, error) {
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("payload marshal: %w", err)
+	}
+
+	if limit := e.options.MaxRequestSize; limit > 0 && len(requestBody) > limit {
+		return nil, &PayloadTooLargeError{Direction: "request", Size: len(requestBody), Limit: limit}
+	}
+
+	trace := []byte("{}")
+	if headers := traceHeaders(ctx); headers != nil {
+		if trace, err = json.Marshal(headers); err != nil {
+			return nil, fmt.Errorf("trace marshal: %w", err)
+		}
+	}
+
+	e.mu.Lock()
+	if e.disconnected {
+		e.mu.Unlock()
+		logger.Info.Printf("A query was executed after Disconnect() was called. Make sure to not send any queries after calling .Prisma.Disconnect() the client.")
+		return nil, fmt.Errorf("client is already disconnected")
+	}
+	if !e.connected {
+		e.mu.Unlock()
+		logger.Info.Printf("A query was executed before Connect() was called. Make sure to call .Prisma.Connect() before sending any queries.")
+		return nil, fmt.Errorf("client is not connected yet")
+	}
+	lib := e.lib
+	// the query is tracked until the library returns, even if ctx is cancelled before
+	e.inflight.Add(1)
+	e.mu.Unlock()
+
+	type result struct {
+		body string
+		err  error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		defer e.inflight.Done()
+		body, err := lib.query(string(requestBody), string(trace))
+		done <- result{body, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		if limit := e.options.MaxResponseSize; limit > 0 && len(r.body) > limit {
+			return nil, &PayloadTooLargeError{Direction: "response", Size: len(r.body), Limit: limit}
+		}
+		return []byte(r.body), nil
+	}
+}
+
+// datasourceOverrides returns the datasource URLs to override as JSON object, keyed by datasource name
+func (e *LibraryEngine) datasourceOverrides() (string, error) {
+	var datasources []generator.Datasource
+	if err := json.Unmarshal([]byte(e.datasources), &datasources); err != nil {
+		return "", fmt.Errorf("unmarshal datasources: %w", err)
+	}
+
+	overrides := make(map[string]string)
+	for _, ds := range datasources {
+		if ds.URL.Value != "" || ds.URL.FromEnvVar != "" {
+			overrides[ds.Name.String()] = e.datasourceURL
+		}
+	}
+
+	raw, err := json.Marshal(overrides)
+	if err != nil {
+		return "", fmt.Errorf("marshal datasources: %w", err)
+	}
+
+	return string(raw), nil
+}
+
+// environ returns the environment variables as JSON object, as expected by the query engine library
+func environ() (string, error) {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+
+	raw, err := json.Marshal(env)
+	if err != nil {
+		return "", fmt.Errorf("marshal env: %w", err)
+	}
+
+	return string(raw), nil
+}
@@ -0,0 +1,204 @@
+//go:build prisma_ffi && cgo
+
+package engine
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdbool.h>
+#include <stdlib.h>
+
+// The declarations follow the C ABI of the Prisma query engine library (query-engine-c-abi).
+
+typedef struct ConstructorOptionsNative {
+	const char *config_dir;
+} ConstructorOptionsNative;
+
+typedef struct ConstructorOptions {
+	const char *id;
+	const char *datamodel;
+	const char *base_path;
+	const char *log_level;
+	bool log_queries;
+	const char *datasource_overrides;
+	const char *env;
+	bool ignore_env_var_errors;
+	ConstructorOptionsNative native;
+	void (*log_callback)(const char *, const char *);
+} ConstructorOptions;
+
+typedef int (*prisma_create_t)(ConstructorOptions, void **, char **);
+typedef int (*prisma_connect_t)(void *, const char *, char **);
+typedef const char *(*prisma_query_t)(void *, const char *, const char *, const char *, char **);
+typedef int (*prisma_disconnect_t)(void *, const char *);
+typedef int (*prisma_destroy_t)(void *);
+
+static void prisma_go_log_callback(const char *id, const char *message) {}
+
+static int prisma_go_create(void *fn, const char *datamodel, const char *overrides, const char *env, void **qe, char **err) {
+	ConstructorOptions options = {0};
+	options.id = "prisma-client-go";
+	options.datamodel = datamodel;
+	options.base_path = "";
+	options.log_level = "error";
+	options.datasource_overrides = overrides;
+	options.env = env;
+	options.native.config_dir = "";
+	options.log_callback = prisma_go_log_callback;
+	return ((prisma_create_t)fn)(options, qe, err);
+}
+
+static int prisma_go_connect(void *fn, void *qe, char **err) {
+	return ((prisma_connect_t)fn)(qe, "{}", err);
+}
+
+static const char *prisma_go_query(void *fn, void *qe, const char *body, const char *trace, char **err) {
+	return ((prisma_query_t)fn)(qe, body, trace, NULL, err);
+}
+
+static int prisma_go_disconnect(void *fn, void *qe) {
+	return ((prisma_disconnect_t)fn)(qe, "{}");
+}
+
+static int prisma_go_destroy(void *fn, void *qe) {
+	return ((prisma_destroy_t)fn)(qe);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// LibraryEngineDefault reports whether generated clients use the LibraryEngine by default,
+// which is the case when building with cgo and the prisma_ffi build tag
+const LibraryEngineDefault = true
+
+type ffiLibrary struct {
+	handle unsafe.Pointer
+	qe     unsafe.Pointer
+
+	connectFn    unsafe.Pointer
+	queryFn      unsafe.Pointer
+	disconnectFn unsafe.Pointer
+	destroyFn    unsafe.Pointer
+}
+
+func openLibrary(path, schema, datasourceOverrides string) (library, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	handle := C.dlopen(cPath, C.RTLD_NOW|C.RTLD_LOCAL)
+	if handle == nil {
+		return nil, fmt.Errorf("dlopen: %s", C.GoString(C.dlerror()))
+	}
+
+	lib := &ffiLibrary{
+		handle: handle,
+	}
+
+	createFn, err := lib.symbol("prisma_create")
+	if err != nil {
+		lib.close()
+		return nil, err
+	}
+	for name, fn := range map[string]*unsafe.Pointer{
+		"prisma_connect":    &lib.connectFn,
+		"prisma_query":      &lib.queryFn,
+		"prisma_disconnect": &lib.disconnectFn,
+		"prisma_destroy":    &lib.destroyFn,
+	} {
+		if *fn, err = lib.symbol(name); err != nil {
+			lib.close()
+			return nil, err
+		}
+	}
+
+	env, err := environ()
+	if err != nil {
+		lib.close()
+		return nil, err
+	}
+
+	cSchema := C.CString(schema)
+	defer C.free(unsafe.Pointer(cSchema))
+	cOverrides := C.CString(datasourceOverrides)
+	defer C.free(unsafe.Pointer(cOverrides))
+	cEnv := C.CString(env)
+	defer C.free(unsafe.Pointer(cEnv))
+
+	var qe unsafe.Pointer
+	var cErr *C.char
+	if C.prisma_go_create(createFn, cSchema, cOverrides, cEnv, &qe, &cErr) != 0 {
+		lib.close()
+		return nil, fmt.Errorf("create query engine: %s", takeString(cErr))
+	}
+	lib.qe = qe
+
+	return lib, nil
+}
+
+func (l *ffiLibrary) symbol(name string) (unsafe.Pointer, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	fn := C.dlsym(l.handle, cName)
+	if fn == nil {
+		return nil, fmt.Errorf("symbol %s not found in query engine library", name)
+	}
+	return fn, nil
+}
+
+func (l *ffiLibrary) connect() error {
+	var cErr *C.char
+	if C.prisma_go_connect(l.connectFn, l.qe, &cErr) != 0 {
+		return fmt.Errorf("connect query engine: %s", takeString(cErr))
+	}
+	return nil
+}
+
+func (l *ffiLibrary) query(body string, trace string) (string, error) {
+	cBody := C.CString(body)
+	defer C.free(unsafe.Pointer(cBody))
+	cTrace := C.CString(trace)
+	defer C.free(unsafe.Pointer(cTrace))
+
+	var cErr *C.char
+	result := C.prisma_go_query(l.queryFn, l.qe, cBody, cTrace, &cErr)
+	if result == nil {
+		return "", fmt.Errorf("query engine: %s", takeString(cErr))
+	}
+	return takeString(result), nil
+}
+
+// disconnect disconnects the query engine and always closes the library, even if disconnecting failed
+func (l *ffiLibrary) disconnect() error {
+	defer l.close()
+	if code := C.prisma_go_disconnect(l.disconnectFn, l.qe); code != 0 {
+		return fmt.Errorf("disconnect query engine: error code %d", int(code))
+	}
+	return nil
+}
+
+// close destroys the query engine and unloads the library
+func (l *ffiLibrary) close() {
+	if l.qe != nil {
+		C.prisma_go_destroy(l.destroyFn, l.qe)
+		l.qe = nil
+	}
+	if l.handle != nil {
+		C.dlclose(l.handle)
+		l.handle = nil
+	}
+}
+
+// takeString copies a string returned by the query engine library and frees it.
+// The library allocates strings with the system allocator, so they can be freed with free.
+func takeString(s *C.char) string {
+	if s == nil {
+		return "unknown error"
+	}
+	defer C.free(unsafe.Pointer(s))
+	return C.GoString(s)
+}
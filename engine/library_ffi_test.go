@@ -0,0 +1,137 @@
+//go:build prisma_ffi && cgo
+
+package engine
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+)
+
+// fakeLibrarySource implements the query engine C ABI and echoes the datamodel in every response
+const fakeLibrarySource = `
+#include <stdlib.h>
+#include <string.h>
+#include <stdio.h>
+#include <stdbool.h>
+
+typedef struct { const char *config_dir; } ConstructorOptionsNative;
+typedef struct {
+	const char *id;
+	const char *datamodel;
+	const char *base_path;
+	const char *log_level;
+	bool log_queries;
+	const char *datasource_overrides;
+	const char *env;
+	bool ignore_env_var_errors;
+	ConstructorOptionsNative native;
+	void (*log_callback)(const char *, const char *);
+} ConstructorOptions;
+
+typedef struct { char *datamodel; int connected; } QueryEngine;
+
+int prisma_create(ConstructorOptions options, QueryEngine **qe, char **err) {
+	if (strlen(options.datamodel) == 0) {
+		*err = strdup("empty datamodel");
+		return 1;
+	}
+	*qe = calloc(1, sizeof(QueryEngine));
+	(*qe)->datamodel = strdup(options.datamodel);
+	return 0;
+}
+
+int prisma_connect(QueryEngine *qe, const char *trace, char **err) {
+	qe->connected = 1;
+	return 0;
+}
+
+const char *prisma_query(QueryEngine *qe, const char *body, const char *header, const char *tx, char **err) {
+	if (!qe->connected) {
+		*err = strdup("not connected");
+		return NULL;
+	}
+	char *result = malloc(strlen(qe->datamodel) + 64);
+	sprintf(result, "{\"data\":{\"result\":{\"datamodel\":\"%s\"}}}", qe->datamodel);
+	return result;
+}
+
+int prisma_disconnect(QueryEngine *qe, const char *header) {
+	qe->connected = 0;
+	return 0;
+}
+
+int prisma_destroy(QueryEngine *qe) {
+	free(qe->datamodel);
+	free(qe);
+	return 0;
+}
+`
+
+func buildFakeLibrary(t *testing.T) string {
+	t.Helper()
+
+	cc := os.Getenv("CC")
+	if cc == "" {
+		cc = "gcc"
+	}
+	if _, err := exec.LookPath(cc); err != nil {
+		t.Skipf("no C compiler found: %s", err)
+	}
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "query_engine.c")
+	if err := os.WriteFile(source, []byte(fakeLibrarySource), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lib := filepath.Join(dir, "libquery_engine.so")
+	if out, err := exec.Command(cc, "-shared", "-fPIC", "-o", lib, source).CombinedOutput(); err != nil {
+		t.Fatalf("compile fake library: %s: %s", err, out)
+	}
+
+	return lib
+}
+
+func TestLibraryEngine_ffi(t *testing.T) {
+	lib := buildFakeLibrary(t)
+
+	e := NewLibraryEngine("model", `[]`, "", WithLibraryPath(lib))
+	if err := e.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	var actual struct {
+		Datamodel string `json:"datamodel"`
+	}
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.Datamodel != "model" {
+		t.Errorf("expected datamodel %q, got %q", "model", actual.Datamodel)
+	}
+
+	if err := e.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLibraryEngine_ffi_createError(t *testing.T) {
+	lib := buildFakeLibrary(t)
+
+	e := NewLibraryEngine("", `[]`, "", WithLibraryPath(lib))
+	err := e.Connect()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := "open query engine library " + lib + ": create query engine: empty datamodel"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
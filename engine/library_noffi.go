@@ -0,0 +1,11 @@
+//go:build !prisma_ffi || !cgo
+
+package engine
+
+// LibraryEngineDefault reports whether generated clients use the LibraryEngine by default,
+// which is the case when building with cgo and the prisma_ffi build tag
+const LibraryEngineDefault = false
+
+func openLibrary(path, schema, datasourceOverrides string) (library, error) {
+	return nil, ErrLibraryUnsupported
+}
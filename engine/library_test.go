@@ -0,0 +1,208 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type fakeLibrary struct {
+	response   string
+	delay      time.Duration
+	connectErr error
+
+	mu      sync.Mutex
+	queries []string
+	traces  []string
+	events  []string
+}
+
+func (l *fakeLibrary) connect() error {
+	l.record("connect", "")
+	return l.connectErr
+}
+
+func (l *fakeLibrary) query(body string, trace string) (string, error) {
+	l.record("query", body)
+	l.mu.Lock()
+	l.traces = append(l.traces, trace)
+	l.mu.Unlock()
+	time.Sleep(l.delay)
+	l.record("query done", "")
+	return l.response, nil
+}
+
+func (l *fakeLibrary) disconnect() error {
+	l.record("disconnect", "")
+	return nil
+}
+
+func (l *fakeLibrary) close() {
+	l.record("close", "")
+}
+
+func (l *fakeLibrary) record(event string, query string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if query != "" {
+		l.queries = append(l.queries, query)
+	}
+	l.events = append(l.events, event)
+}
+
+func newConnectedLibraryEngine(lib library, options ...Option) *LibraryEngine {
+	e := NewLibraryEngine("", `[{"name":"db","url":{"fromEnvVar":"DATABASE_URL"}}]`, "file:dev.db", options...)
+	e.lib = lib
+	e.connected = true
+	return e
+}
+
+func TestLibraryEngine_Do(t *testing.T) {
+	lib := &fakeLibrary{
+		response: `{"data":{"result":{"id":"123","count":9007199254740993}}}`,
+	}
+	e := newConnectedLibraryEngine(lib)
+
+	var actual struct {
+		ID    string `json:"id"`
+		Count int64  `json:"count"`
+	}
+	payload := protocol.GQLRequest{Query: `query {result: findUniqueUser(where:{id:"123",},) {id count }}`}
+	if err := e.Do(context.Background(), payload, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, "123", actual.ID)
+	massert.Equal(t, int64(9007199254740993), actual.Count)
+	massert.Equal(t, []string{`{"query":"query {result: findUniqueUser(where:{id:\"123\",},) {id count }}","variables":null}`}, lib.queries)
+}
+
+func TestLibraryEngine_Do_errors(t *testing.T) {
+	t.Run("not connected", func(t *testing.T) {
+		e := NewLibraryEngine("", "[]", "")
+		err := e.Do(context.Background(), protocol.GQLRequest{}, nil)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		e := newConnectedLibraryEngine(&fakeLibrary{response: `{}`, delay: time.Second})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := e.Do(ctx, protocol.GQLRequest{}, nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("payload too large", func(t *testing.T) {
+		e := newConnectedLibraryEngine(&fakeLibrary{response: `{}`}, WithMaxPayloadSize(10, 0))
+		err := e.Do(context.Background(), protocol.GQLRequest{Query: "query { a very long query }"}, nil)
+		if !IsPayloadTooLarge(err) {
+			t.Fatalf("expected payload too large, got %v", err)
+		}
+	})
+}
+
+func TestLibraryEngine_Disconnect_waitsForQueries(t *testing.T) {
+	lib := &fakeLibrary{response: `{}`, delay: 100 * time.Millisecond}
+	e := newConnectedLibraryEngine(lib)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := e.Do(ctx, protocol.GQLRequest{}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// the query still runs in the library, so it must finish before the library is unloaded
+	if err := e.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"query", "query done", "disconnect"}, lib.events)
+
+	// queries after Disconnect are rejected and a second Disconnect doesn't unload the library again
+	err = e.Do(context.Background(), protocol.GQLRequest{}, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if err := e.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"query", "query done", "disconnect"}, lib.events)
+}
+
+func TestLibraryEngine_concurrent(t *testing.T) {
+	lib := &fakeLibrary{response: `{"data":{"result":{}}}`, delay: time.Millisecond}
+	e := newConnectedLibraryEngine(lib)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// queries either succeed or fail because the engine was disconnected
+			var v interface{}
+			_ = e.Do(context.Background(), protocol.GQLRequest{}, &v)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = e.Disconnect()
+	}()
+	wg.Wait()
+
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+	massert.Equal(t, "disconnect", lib.events[len(lib.events)-1])
+}
+
+func TestLibraryEngine_datasourceOverrides(t *testing.T) {
+	e := NewLibraryEngine("", `[{"name":"db","url":{"fromEnvVar":"DATABASE_URL"}}]`, "postgresql://localhost/db")
+
+	actual, err := e.datasourceOverrides()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, `{"db":"postgresql://localhost/db"}`, actual)
+}
+
+func TestLibraryEngine_Connect(t *testing.T) {
+	original := loadLibrary
+	defer func() {
+		loadLibrary = original
+	}()
+	var lib *fakeLibrary
+	loadLibrary = func(path, schema, datasourceOverrides string) (library, error) {
+		return lib, nil
+	}
+
+	t.Run("connect error closes the library", func(t *testing.T) {
+		lib = &fakeLibrary{connectErr: errors.New("refused")}
+		e := NewLibraryEngine("", "[]", "", WithLibraryPath("libquery_engine.so"))
+		err := e.Connect()
+		massert.Equal(t, "connect: refused", err.Error())
+		massert.Equal(t, []string{"connect", "close"}, lib.events)
+	})
+
+	t.Run("already connected", func(t *testing.T) {
+		lib = &fakeLibrary{}
+		e := NewLibraryEngine("", "[]", "", WithLibraryPath("libquery_engine.so"))
+		if err := e.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		err := e.Connect()
+		massert.Equal(t, "the library engine is already connected", err.Error())
+		massert.Equal(t, []string{"connect"}, lib.events)
+	})
+}
@@ -4,17 +4,17 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
-	"path"
 	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 
 	"github.com/steebchen/prisma-client-go/binaries"
-	"github.com/steebchen/prisma-client-go/binaries/platform"
 	"github.com/steebchen/prisma-client-go/binaries/unpack"
 	"github.com/steebchen/prisma-client-go/generator"
 	"github.com/steebchen/prisma-client-go/logger"
@@ -22,11 +22,13 @@ import (
 
 func (e *QueryEngine) Connect() error {
 	e.closed = make(chan interface{})
+	e.restarted = make(chan struct{})
 
 	success := false
 	defer func() {
 		if !success {
-			close(e.closed)
+			e.stopSupervisor()
+			e.removeSocket()
 		}
 	}()
 
@@ -38,13 +40,21 @@ func (e *QueryEngine) Connect() error {
 
 	startEngine := time.Now()
 
-	file, err := e.ensure()
-	if err != nil {
-		return fmt.Errorf("ensure: %w", err)
-	}
+	var file string
+	if e.options.EngineURL != "" {
+		if err := e.connectRemote(e.options.EngineURL); err != nil {
+			return fmt.Errorf("connect remote engine: %w", err)
+		}
+	} else {
+		var err error
+		file, err = e.ensure()
+		if err != nil {
+			return fmt.Errorf("ensure: %w", err)
+		}
 
-	if err := e.spawn(file); err != nil {
-		return fmt.Errorf("spawn: %w", err)
+		if err := e.spawn(file); err != nil {
+			return fmt.Errorf("spawn: %w", err)
+		}
 	}
 
 	logger.Debug.Printf("connecting took %s", time.Since(startEngine))
@@ -56,69 +66,141 @@ func (e *QueryEngine) Connect() error {
 	e.connected = true
 	success = true
 
+	// remote engines are managed externally and can't be restarted
+	if e.proc != nil && e.options.restartPolicy().MaxAttempts > 0 {
+		go e.supervise(file, e.proc)
+	}
+
 	logger.Debug.Printf("connected.")
 
 	return nil
 }
 
+// DefaultDisconnectTimeout is how long Disconnect waits for in-flight queries and for the query engine to exit
+// before the engine is killed
+const DefaultDisconnectTimeout = 10 * time.Second
+
 func (e *QueryEngine) Disconnect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDisconnectTimeout)
+	defer cancel()
+	return e.DisconnectContext(ctx)
+}
+
+// DisconnectContext rejects new queries, waits for in-flight queries to finish and then asks the query engine to shut
+// down gracefully. If in-flight queries don't finish or the engine doesn't exit until ctx is done, it is killed.
+func (e *QueryEngine) DisconnectContext(ctx context.Context) error {
+	e.mu.Lock()
 	e.disconnected = true
+	e.mu.Unlock()
+
 	logger.Debug.Printf("disconnecting...")
 
-	if platform.Name() == "windows" {
-		if err := e.cmd.Process.Kill(); err != nil {
-			return fmt.Errorf("kill process: %w", err)
+	// stop the supervisor first, so that the engine is not restarted
+	e.stopSupervisor()
+
+	if e.options.EngineURL == "" {
+		// make sure the engine is running before waiting for in-flight queries
+		if _, err := e.currentProcess(); err != nil {
+			return err
 		}
+	}
+
+	inflight := make(chan struct{})
+	go func() {
+		e.inflight.Wait()
+		close(inflight)
+	}()
+
+	select {
+	case <-inflight:
+	case <-ctx.Done():
+		logger.Info.Printf("in-flight queries did not finish before disconnecting: %s", ctx.Err())
+	}
+
+	// remote engines keep running, as they are managed externally
+	if e.options.EngineURL != "" {
+		logger.Debug.Printf("disconnected.")
 		return nil
 	}
 
-	if err := e.cmd.Process.Signal(os.Interrupt); err != nil {
-		return fmt.Errorf("send signal: %w", err)
+	proc, err := e.currentProcess()
+	if err != nil {
+		return err
+	}
+
+	if err := terminate(proc.cmd.Process); err != nil {
+		logger.Debug.Printf("could not terminate query engine: %s", err)
 	}
 
-	if err := e.cmd.Wait(); err != nil {
-		if err.Error() != "signal: interrupt" {
-			return fmt.Errorf("wait for process: %w", err)
+	select {
+	case <-proc.exited:
+	case <-ctx.Done():
+		logger.Info.Printf("query engine did not exit in time; killing it")
+		if err := kill(proc.cmd.Process); err != nil {
+			return fmt.Errorf("kill process: %w", err)
 		}
+		<-proc.exited
 	}
 
-	close(e.closed)
+	e.removeSocket()
+
+	// the exit code after being terminated doesn't matter, but other errors do
+	var exitErr *exec.ExitError
+	if proc.err != nil && !errors.As(proc.err, &exitErr) {
+		return fmt.Errorf("wait for process: %w", proc.err)
+	}
 
 	logger.Debug.Printf("disconnected.")
 	return nil
 }
 
-func (e *QueryEngine) ensure() (string, error) {
-	ensureEngine := time.Now()
+// stopSupervisor closes e.closed unless it was closed already. The check and the close happen under e.mu, so that
+// concurrent calls to Disconnect don't close the channel twice.
+func (e *QueryEngine) stopSupervisor() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed == nil {
+		return
+	}
+	select {
+	case <-e.closed:
+	default:
+		close(e.closed)
+	}
+}
 
-	unpackPath := binaries.GlobalUnpackDir(binaries.EngineVersion)
-	cachePath := binaries.GlobalCacheDir()
+// removeSocket removes the unix domain socket and its directory, if any
+func (e *QueryEngine) removeSocket() {
+	e.mu.Lock()
+	dir := e.socketDir
+	e.socketDir = ""
+	e.mu.Unlock()
+
+	if dir == "" {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Debug.Printf("could not remove socket dir %s: %s", dir, err)
+	}
+}
 
-	// check for darwin/windows/linux first
-	binaryName := platform.CheckForExtension(platform.Name(), platform.BinaryPlatformNameStatic())
-	exactBinaryName := platform.CheckForExtension(platform.Name(), platform.BinaryPlatformNameDynamic())
+func (e *QueryEngine) ensure() (string, error) {
+	ensureEngine := time.Now()
 
 	var file string
 	// forceVersion saves whether a version check should be done, which should be disabled
 	// when providing a custom query engine value
 	forceVersion := true
 
-	name := "prisma-query-engine-"
-	localStatic := path.Join("./", name+binaryName)
-	localExact := path.Join("./", name+exactBinaryName)
-	globalUnpackStatic := path.Join(unpackPath, name+binaryName)
-	globalUnpackExact := path.Join(unpackPath, name+exactBinaryName)
-	cacheStatic := path.Join(cachePath, binaries.EngineVersion, name+binaryName)
-	cacheExact := path.Join(cachePath, binaries.EngineVersion, name+exactBinaryName)
-
-	logger.Debug.Printf("checking for local query engine `%s` or `%s`", localStatic, localExact)
-	logger.Debug.Printf("checking for global query engine `%s` or `%s`", globalUnpackStatic, globalUnpackExact)
-	logger.Debug.Printf("checking for cached query engine `%s` or `%s`", cacheStatic, cacheExact)
+	candidates := binaries.QueryEngineCandidates()
+	for _, c := range candidates {
+		logger.Debug.Printf("checking for %s query engine `%s`", c.Source, c.Path)
+	}
 
 	// TODO write tests for all cases
 
 	// first, check if the query engine binary is being overridden by PRISMA_QUERY_ENGINE_BINARY
-	prismaQueryEngineBinary := os.Getenv("PRISMA_QUERY_ENGINE_BINARY")
+	prismaQueryEngineBinary := os.Getenv(binaries.QueryEngineEnv)
 	if prismaQueryEngineBinary != "" {
 		logger.Debug.Printf("PRISMA_QUERY_ENGINE_BINARY is defined, using %s", prismaQueryEngineBinary)
 
@@ -140,24 +222,21 @@ func (e *QueryEngine) ensure() (string, error) {
 			}
 		}
 
-		if info, err := os.Stat(localExact); err == nil {
-			file = localExact
-			logger.Debug.Printf("exact query engine found in working directory: %s %+v", file, info)
-		} else if info, err = os.Stat(localStatic); err == nil {
-			file = localStatic
-			logger.Debug.Printf("query engine found in working directory: %s %+v", file, info)
-		} else if info, err = os.Stat(cacheExact); err == nil {
-			file = cacheExact
-			logger.Debug.Printf("query engine found in cache path: %s %+v", file, info)
-		} else if info, err = os.Stat(cacheStatic); err == nil {
-			file = cacheStatic
-			logger.Debug.Printf("exact query engine found in cache path: %s %+v", file, info)
-		} else if info, err = os.Stat(globalUnpackExact); err == nil {
-			file = globalUnpackExact
-			logger.Debug.Printf("query engine found in global path: %s %+v", file, info)
-		} else if info, err = os.Stat(globalUnpackStatic); err == nil {
-			file = globalUnpackStatic
-			logger.Debug.Printf("exact query engine found in global path: %s %+v", file, info)
+		for _, c := range candidates {
+			if c.Cached {
+				// cached engines may be stored compressed and are extracted on demand
+				if cached, err := binaries.ResolveEngine(c.Path); err == nil {
+					file = cached
+					logger.Debug.Printf("query engine found in %s path: %s", c.Source, file)
+					break
+				}
+				continue
+			}
+			if info, err := os.Stat(c.Path); err == nil {
+				file = c.Path
+				logger.Debug.Printf("query engine found in %s path: %s %+v", c.Source, file, info)
+				break
+			}
 		}
 	}
 
@@ -167,15 +246,18 @@ func (e *QueryEngine) ensure() (string, error) {
 	}
 
 	startVersion := time.Now()
-	out, err := exec.Command(file, "--version").Output()
+	out, err := engineVersion(file)
 	if err != nil {
 		return "", fmt.Errorf("version check failed: %w", err)
 	}
 	logger.Debug.Printf("version check took %s", time.Since(startVersion))
 
-	if v := strings.TrimSpace(strings.Replace(string(out), "query-engine", "", 1)); binaries.EngineVersion != v {
-		note := "Did you forget to run `go run github.com/steebchen/prisma-client-go generate`?"
-		msg := fmt.Errorf("expected query engine version `%s` but got `%s`\n%s", binaries.EngineVersion, v, note)
+	if v := strings.TrimSpace(strings.Replace(string(out), "query-engine", "", 1)); e.options.expectedVersion() != v {
+		msg := &VersionError{
+			Engine:   file,
+			Expected: e.options.expectedVersion(),
+			Actual:   v,
+		}
 		if forceVersion {
 			return "", msg
 		}
@@ -189,6 +271,31 @@ func (e *QueryEngine) ensure() (string, error) {
 	return file, nil
 }
 
+// engineVersion returns the output of `query-engine --version`. The result is persisted in the warm cache keyed by
+// the binary path, size and modification time, so restarts don't have to spawn the binary just for the version check.
+func engineVersion(file string) ([]byte, error) {
+	cache := newWarmCache("version")
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%s:%d:%d", file, info.Size(), info.ModTime().UnixNano())
+
+	if out, ok := cache.Get(key); ok {
+		return out, nil
+	}
+
+	out, err := exec.Command(file, "--version").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Set(key, out)
+
+	return out, nil
+}
+
 type DatasourceOverride struct {
 	Name string `json:"name"`
 	URL  string `json:"url"`
@@ -226,31 +333,59 @@ func (e *QueryEngine) GetEncodedDatasources() (string, error) {
 }
 
 func (e *QueryEngine) spawn(file string) error {
-	port, err := getPort()
-	if err != nil {
-		return fmt.Errorf("get free port: %w", err)
-	}
+	var args []string
+	var client *http.Client
+	var httpURL, socketDir, address string
+	if e.options.useUnixSocket() {
+		dir, socket, err := newUnixSocket()
+		if err != nil {
+			return err
+		}
+		socketDir = dir
+
+		logger.Debug.Printf("running query-engine on unix socket %s", socket)
+
+		// the host is ignored as all requests are sent to the socket
+		httpURL = "http://localhost"
+		address = "unix://" + socket
+		client = unixSocketClient(socket)
+		args = []string{"--unix-path", socket}
+	} else {
+		port, err := getPort()
+		if err != nil {
+			return fmt.Errorf("get free port: %w", err)
+		}
 
-	logger.Debug.Printf("running query-engine on port %s", port)
+		logger.Debug.Printf("running query-engine on port %s", port)
 
-	e.httpURL = "http://localhost:" + port
+		httpURL = "http://localhost:" + port
+		address = httpURL
+		client = &http.Client{}
+		args = []string{"-p", port}
+	}
 
-	e.cmd = exec.Command(file, "-p", port, "--enable-raw-queries")
+	cmd := exec.Command(file, binaries.QueryEngineArgs(args, e.options.Tracer != nil)...)
 
-	e.cmd.SysProcAttr = getSysProcAttr()
+	cmd.SysProcAttr = getSysProcAttr()
 
-	e.cmd.Stdout = os.Stdout
+	e.mu.Lock()
+	e.stderrTail = nil
+	e.mu.Unlock()
 
-	e.onEngineError = make(chan string)
+	stdoutDone, err := e.streamStdout(cmd)
+	if err != nil {
+		return fmt.Errorf("setup stream: %w", err)
+	}
 
-	if err := e.streamStderr(e.cmd, e.onEngineError); err != nil {
+	stderrDone, err := e.streamStderr(cmd)
+	if err != nil {
 		return fmt.Errorf("setup stream: %w", err)
 	}
 
-	e.cmd.Env = append(
+	cmd.Env = append(
 		os.Environ(),
 		"PRISMA_DML="+e.Schema,
-		"RUST_LOG=error",
+		"RUST_LOG="+e.options.logLevel(),
 		"RUST_LOG_FORMAT=json",
 		"PRISMA_CLIENT_ENGINE_TYPE=binary",
 		"PRISMA_ENGINE_PROTOCOL=graphql",
@@ -262,72 +397,123 @@ func (e *QueryEngine) spawn(file string) error {
 	}
 
 	if encDS != "" {
-		e.cmd.Env = append(
-			e.cmd.Env,
+		cmd.Env = append(
+			cmd.Env,
 			"OVERWRITE_DATASOURCES="+encDS,
 		)
 	}
 
-	// TODO fine tune this using log levels
-	if logger.Enabled {
-		e.cmd.Env = append(
-			e.cmd.Env,
+	if e.options.logsQueries() {
+		cmd.Env = append(
+			cmd.Env,
 			"PRISMA_LOG_QUERIES=y",
-			"RUST_LOG=info",
 		)
 	}
 
 	logger.Debug.Printf("starting engine...")
 
-	if err := e.cmd.Start(); err != nil {
+	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("start command: %w", err)
 	}
 
+	if err := afterStart(cmd); err != nil {
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("after start: %w", err)
+	}
+
+	proc := &process{
+		cmd:    cmd,
+		exited: make(chan struct{}),
+	}
+	go func() {
+		// stdout and stderr must be read completely before calling Wait, as Wait closes the pipes
+		<-stdoutDone
+		<-stderrDone
+		proc.err = cmd.Wait()
+		close(proc.exited)
+	}()
+
+	e.mu.Lock()
+	e.proc = proc
+	e.http = client
+	e.httpURL = httpURL
+	e.socketDir = socketDir
+	e.mu.Unlock()
+
 	logger.Debug.Printf("connecting to engine...")
 
-	// send a basic readiness healthcheck and retry if unsuccessful
-	var connectErr error
-	for i := 0; i < 100; i++ {
+	if err := e.awaitReady(proc, address); err != nil {
+		// don't leave a process behind which never became ready
+		_ = cmd.Process.Kill()
+		<-proc.exited
+		return err
+	}
+
+	return nil
+}
+
+// awaitReady sends a basic readiness healthcheck and retries until the query engine is ready or the startup timeout
+// is exceeded
+func (e *QueryEngine) awaitReady(proc *process, address string) error {
+	timeout := e.options.startupTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
 		e.mu.Lock()
+		lastEngineError := e.lastEngineError
+		e.mu.Unlock()
+
 		// return an error early if an engine error already happened
-		if e.lastEngineError != "" {
-			e.mu.Unlock()
-			return fmt.Errorf("query engine errored: %w", fmt.Errorf(e.lastEngineError))
+		if lastEngineError != "" {
+			return e.startupError(address, fmt.Errorf("query engine errored: %w", errors.New(lastEngineError)))
 		}
-		e.mu.Unlock()
 
-		body, err := e.Request(context.Background(), "GET", "/status", map[string]interface{}{}, false)
-		if err != nil {
-			connectErr = err
-			logger.Debug.Printf("could not connect; retrying...")
-			time.Sleep(100 * time.Millisecond)
-			continue
+		// remote engines have no process
+		var exited <-chan struct{}
+		if proc != nil {
+			exited = proc.exited
 		}
 
-		var response struct {
-			Status string `json:"status"`
+		select {
+		case <-exited:
+			return e.startupError(address, fmt.Errorf("query engine exited before it was ready: %v", proc.err))
+		default:
 		}
 
-		if err := json.Unmarshal(body, &response); err != nil {
-			connectErr = err
-			logger.Debug.Printf("could not unmarshal response %s; retrying...", body)
-			time.Sleep(50 * time.Millisecond)
-			continue
+		err := e.checkStatus(ctx)
+		if err == nil {
+			return nil
 		}
 
-		if response.Status != "ok" {
-			connectErr = fmt.Errorf("unexpected status: " + response.Status)
-			logger.Debug.Printf("could not connect due to unexpected status %s  ; retrying...", response.Status)
-			time.Sleep(50 * time.Millisecond)
-			continue
+		logger.Debug.Printf("query engine is not ready: %s; retrying...", err)
+
+		select {
+		case <-ctx.Done():
+			return e.startupError(address, fmt.Errorf("query engine was not ready after %s: %w", timeout, err))
+		case <-exited:
+		case <-time.After(readinessInterval):
 		}
+	}
+}
+
+// checkStatus returns an error if the query engine is not ready
+func (e *QueryEngine) checkStatus(ctx context.Context) error {
+	body, err := e.Request(ctx, "GET", "/status", map[string]interface{}{}, false)
+	if err != nil {
+		return fmt.Errorf("readiness query error: %w", err)
+	}
+
+	var response struct {
+		Status string `json:"status"`
+	}
 
-		connectErr = nil
-		break
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("could not unmarshal response %s: %w", body, err)
 	}
 
-	if connectErr != nil {
-		return fmt.Errorf("readiness query error: %w", connectErr)
+	if response.Status != "ok" {
+		return fmt.Errorf("unexpected status: %s", response.Status)
 	}
 
 	return nil
@@ -0,0 +1,11 @@
+package engine
+
+import "syscall"
+
+// setParentDeathSignal makes the kernel kill the query engine when the parent process dies, so that no orphaned
+// engine processes remain even if the parent is killed without calling Disconnect.
+// The signal is sent when the OS thread which started the engine exits, which the Go runtime only does for threads
+// locked with runtime.LockOSThread, so in practice it is sent when the parent process exits.
+func setParentDeathSignal(attr *syscall.SysProcAttr) {
+	attr.Pdeathsig = syscall.SIGKILL
+}
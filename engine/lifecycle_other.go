@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+
+package engine
+
+import "syscall"
+
+// setParentDeathSignal is not supported on this platform, so the query engine is only stopped by Disconnect
+func setParentDeathSignal(attr *syscall.SysProcAttr) {}
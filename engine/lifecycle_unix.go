@@ -2,10 +2,34 @@
 
 package engine
 
-import "syscall"
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
 
 func getSysProcAttr() *syscall.SysProcAttr {
-	return &syscall.SysProcAttr{
+	attr := &syscall.SysProcAttr{
 		Setpgid: true,
 	}
+	setParentDeathSignal(attr)
+	return attr
+}
+
+// afterStart is called after the query engine process was started
+func afterStart(cmd *exec.Cmd) error {
+	return nil
+}
+
+// terminate asks the query engine process to shut down gracefully
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
+
+// kill kills the query engine process and its process group
+func kill(p *os.Process) error {
+	if err := syscall.Kill(-p.Pid, syscall.SIGKILL); err != nil {
+		return p.Kill()
+	}
+	return nil
 }
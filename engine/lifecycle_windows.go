@@ -1,7 +1,120 @@
 package engine
 
-import "syscall"
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"unsafe"
+)
 
 func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{}
 }
+
+// afterStart assigns the query engine process to a job object which kills it when the parent process dies, so that
+// no orphaned engine processes remain even if the parent is killed without calling Disconnect
+func afterStart(cmd *exec.Cmd) error {
+	job, err := killOnCloseJob()
+	if err != nil {
+		return err
+	}
+
+	const processSetQuota, processTerminate = 0x0100, 0x0001
+	handle, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		return fmt.Errorf("open process: %w", err)
+	}
+	defer syscall.CloseHandle(handle) //nolint:errcheck
+
+	if r, _, err := procAssignProcessToJobObject.Call(uintptr(job), uintptr(handle)); r == 0 {
+		return fmt.Errorf("assign process to job object: %w", err)
+	}
+	return nil
+}
+
+// terminate stops the query engine process; windows has no signal for a graceful shutdown of console processes
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
+
+// kill kills the query engine process
+func kill(p *os.Process) error {
+	return p.Kill()
+}
+
+var (
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = kernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+
+	jobOnce   sync.Once
+	jobHandle syscall.Handle
+	jobErr    error
+)
+
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// killOnCloseJob returns a job object which kills all its processes when its last handle is closed, which happens
+// when the parent process exits. The handle is intentionally never closed.
+func killOnCloseJob() (syscall.Handle, error) {
+	jobOnce.Do(func() {
+		const jobObjectExtendedLimitInformationClass = 9
+		const jobObjectLimitKillOnJobClose = 0x2000
+
+		r, _, err := procCreateJobObjectW.Call(0, 0)
+		if r == 0 {
+			jobErr = fmt.Errorf("create job object: %w", err)
+			return
+		}
+		handle := syscall.Handle(r)
+
+		var info jobObjectExtendedLimitInformation
+		info.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnJobClose
+
+		r, _, err = procSetInformationJobObject.Call(
+			uintptr(handle),
+			jobObjectExtendedLimitInformationClass,
+			uintptr(unsafe.Pointer(&info)),
+			unsafe.Sizeof(info),
+		)
+		if r == 0 {
+			_ = syscall.CloseHandle(handle)
+			jobErr = fmt.Errorf("set job object information: %w", err)
+			return
+		}
+
+		jobHandle = handle
+	})
+	return jobHandle, jobErr
+}
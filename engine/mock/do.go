@@ -12,44 +12,72 @@ func (e *Engine) Do(_ context.Context, payload interface{}, v interface{}) error
 	e.expMu.Lock()
 	defer e.expMu.Unlock()
 
+	req := payload.(protocol.GQLRequest)
+	want, err := e.match(req.Query)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(want, &v); err != nil {
+		return fmt.Errorf("error happened at marshaling expectation want: %w", err)
+	}
+	return nil
+}
+
+// Batch answers the queries of a transaction with the results of their expectations. If any expectation returns an
+// error, the transaction fails with that error.
+func (e *Engine) Batch(_ context.Context, payload interface{}, v interface{}) error {
+	e.expMu.Lock()
+	defer e.expMu.Unlock()
+
+	req := payload.(protocol.GQLBatchRequest)
+	response := v.(*protocol.GQLBatchResponse)
+	response.Result = make([]protocol.GQLResponse, len(req.Batch))
+	for i, query := range req.Batch {
+		want, err := e.match(query.Query)
+		if err != nil {
+			return err
+		}
+		response.Result[i].Data.Result = want
+	}
+	return nil
+}
+
+// match finds the expectation of a query and marks it as met. Expectations which were not met yet are preferred, so
+// that the same query can be expected multiple times with different results, which are returned in order.
+func (e *Engine) match(query string) (json.RawMessage, error) {
 	expectations := *e.expectations
 
 	n := -1
-	for i, e := range expectations {
-		req := payload.(protocol.GQLRequest)
-		str, err := e.Query.Build()
+	for i, exp := range expectations {
+		str, err := exp.Query.Build()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if str == req.Query {
+		if str != query {
+			continue
+		}
+		if n == -1 || (expectations[n].Success && !exp.Success) {
 			n = i
+		}
+		if !exp.Success {
 			break
 		}
 	}
 	if n == -1 {
-		panic("could not find query")
+		return nil, fmt.Errorf("mock: no expectation matches the query %s", query)
 	}
-	var retErr error
+
+	expectations[n].Success = true
 	switch {
 	case expectations[n].Want != nil:
 		r, err := json.Marshal(expectations[n].Want)
 		if err != nil {
-			return fmt.Errorf("error happened at unmarshaling expectation want: %w", err)
-		}
-		if err := json.Unmarshal(r, &v); err != nil {
-			return fmt.Errorf("error happened at marshaling expectation want: %w", err)
+			return nil, fmt.Errorf("error happened at unmarshaling expectation want: %w", err)
 		}
+		return r, nil
 	case expectations[n].WantErr != nil:
-		retErr = expectations[n].WantErr
+		return nil, expectations[n].WantErr
 	default:
 		panic("need to define either Want or WantErr")
 	}
-	expectations[n].Success = true
-	*e.expectations = expectations
-	return retErr
-}
-
-func (e *Engine) Batch(context.Context, interface{}, interface{}) error {
-	// TODO
-	panic("TODO")
 }
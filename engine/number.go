@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+)
+
+// ErrPrecisionLoss is returned in strict number mode when a number in an engine response can not be represented
+// in Go without losing precision
+var ErrPrecisionLoss = fmt.Errorf("number can not be decoded without losing precision")
+
+// checkPrecision walks through a json document and returns ErrPrecisionLoss if any number either is an integer
+// outside the int64 range or is a floating point number which can not be represented as a float64 exactly as sent
+func checkPrecision(data []byte) error {
+	var m interface{}
+	if err := unmarshalNumbers(data, &m); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	return checkValue(m)
+}
+
+// checkBatchPrecision runs checkPrecision on the result of each query of a batch response
+func checkBatchPrecision(body []byte) error {
+	var response protocol.GQLBatchResponse
+	if err := unmarshalNumbers(body, &response); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	for _, result := range response.Result {
+		if err := checkPrecision(result.Data.Result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkValue(v interface{}) error {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, item := range value {
+			if err := checkValue(item); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := checkValue(item); err != nil {
+				return err
+			}
+		}
+	case json.Number:
+		return checkNumber(value)
+	}
+	return nil
+}
+
+func checkNumber(n json.Number) error {
+	str := n.String()
+
+	if !strings.ContainsAny(str, ".eE") {
+		if _, err := strconv.ParseInt(str, 10, 64); err != nil {
+			return fmt.Errorf("%w: %s overflows int64", ErrPrecisionLoss, str)
+		}
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s overflows float64", ErrPrecisionLoss, str)
+	}
+
+	// compare the exact decimal value which was sent with the shortest decimal representation of the float64
+	expected, ok := new(big.Rat).SetString(str)
+	if !ok {
+		return fmt.Errorf("invalid number %s", str)
+	}
+	actual, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return fmt.Errorf("invalid number %s", str)
+	}
+	if expected.Cmp(actual) != 0 {
+		return fmt.Errorf("%w: %s would be decoded as %s", ErrPrecisionLoss, str, strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+)
+
+func Test_checkPrecision(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{{
+		name: "int",
+		data: `{"a":5}`,
+	}, {
+		name: "int64 beyond 2^53",
+		data: `{"a":9007199254740993}`,
+	}, {
+		name: "max int64",
+		data: `[9223372036854775807,-9223372036854775808]`,
+	}, {
+		name: "float",
+		data: `{"a":0.1,"b":5.5,"c":1e10}`,
+	}, {
+		name: "strings are ignored",
+		data: `{"a":"99999999999999999999999"}`,
+	}, {
+		name:    "int overflow",
+		data:    `{"a":{"b":[9223372036854775808]}}`,
+		wantErr: true,
+	}, {
+		name:    "float with too many digits",
+		data:    `{"a":0.12345678901234567890}`,
+		wantErr: true,
+	}, {
+		name:    "float overflow",
+		data:    `[1e400]`,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPrecision([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPrecision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrPrecisionLoss) {
+				t.Errorf("checkPrecision() error = %v, want ErrPrecisionLoss", err)
+			}
+		})
+	}
+}
+
+func TestQueryEngine_strictNumbers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"result":{"amount":0.12345678901234567890}}}`))
+	}))
+	defer srv.Close()
+
+	for _, strict := range []bool{false, true} {
+		var options []Option
+		if strict {
+			options = append(options, WithStrictNumbers())
+		}
+		e := NewQueryEngine("", false, "[]", "", append(options, WithEngineURL(srv.URL+"/"))...)
+		if err := e.Connect(); err != nil {
+			t.Fatal(err)
+		}
+
+		var result struct {
+			Amount float64 `json:"amount"`
+		}
+		err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &result)
+		if strict != errors.Is(err, ErrPrecisionLoss) {
+			t.Errorf("strict = %t: unexpected error %v", strict, err)
+		}
+
+		if err := e.Disconnect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestQueryEngine_strictNumbersBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"batchResult":[{"data":{"result":{"id":9007199254740993}}},{"data":{"result":{"id":92233720368547758070}}}]}`))
+	}))
+	defer srv.Close()
+
+	for _, strict := range []bool{false, true} {
+		var options []Option
+		if strict {
+			options = append(options, WithStrictNumbers())
+		}
+		e := NewQueryEngine("", false, "[]", "", append(options, WithEngineURL(srv.URL+"/"))...)
+		if err := e.Connect(); err != nil {
+			t.Fatal(err)
+		}
+
+		var response protocol.GQLBatchResponse
+		err := e.Batch(context.Background(), protocol.GQLBatchRequest{Transaction: true}, &response)
+		if strict != errors.Is(err, ErrPrecisionLoss) {
+			t.Errorf("strict = %t: unexpected error %v", strict, err)
+		}
+
+		if !strict {
+			// int64 values above 2^53 are decoded without losing precision
+			var result struct {
+				ID int64 `json:"id"`
+			}
+			if err := json.Unmarshal(response.Result[0].Data.Result, &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.ID != 9007199254740993 {
+				t.Errorf("id = %d, want 9007199254740993", result.ID)
+			}
+		}
+
+		if err := e.Disconnect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
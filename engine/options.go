@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// Options contains optional settings for engine implementations
+type Options struct {
+	// StrictNumbers makes queries return ErrPrecisionLoss instead of silently losing precision when decoding numbers
+	StrictNumbers bool
+
+	// MaxRequestSize is the maximum size of a request body in bytes; 0 means unlimited
+	MaxRequestSize int
+
+	// MaxResponseSize is the maximum size of a response body in bytes; 0 means unlimited
+	MaxResponseSize int
+
+	// CompressRequests gzip-compresses request bodies which are larger than CompressMinSize
+	CompressRequests bool
+
+	// CompressMinSize is the minimum size of a request body in bytes to be compressed
+	CompressMinSize int
+
+	// LibraryPath is the path to the query engine shared library used by the LibraryEngine
+	LibraryPath string
+
+	// UnixSocket makes the query engine listen on a unix domain socket instead of a localhost port;
+	// if nil, unix sockets are used on all platforms except windows
+	UnixSocket *bool
+
+	// Restart configures how the query engine is restarted when it exits unexpectedly; if nil,
+	// DefaultRestartPolicy is used
+	Restart *RestartPolicy
+
+	// OnEvent is called when the state of the engine changes, e.g. when it was restarted after a crash
+	OnEvent func(Event)
+
+	// LogLevel is the level of the logs written by the query engine, i.e. "error", "warn", "info", "debug" or
+	// "trace"; if empty, "info" is used when the client logger is enabled and "error" otherwise
+	LogLevel string
+
+	// OnLog is called for each log line written by the query engine, e.g. slow query warnings or panics
+	OnLog func(LogEvent)
+
+	// StartupTimeout is how long to wait for the query engine to become ready; if 0, DefaultStartupTimeout is used
+	StartupTimeout time.Duration
+
+	// EngineURL is the url of an externally managed query engine; if set, no query engine is spawned
+	EngineURL string
+
+	// ExpectedVersion is the query engine commit the client was generated for; if empty, binaries.EngineVersion is used
+	ExpectedVersion string
+
+	// Tracer propagates the trace context to the query engine and exports the spans it recorded
+	Tracer Tracer
+
+	// CancelStatements sends queries within transactions, so that their PostgreSQL statement can be cancelled when
+	// their context is cancelled
+	CancelStatements bool
+}
+
+// Option configures an engine
+type Option func(*Options)
+
+// WithStrictNumbers makes the engine return ErrPrecisionLoss for responses containing numbers which can not be
+// represented in Go without losing precision, e.g. integers outside the int64 range
+func WithStrictNumbers() Option {
+	return func(o *Options) {
+		o.StrictNumbers = true
+	}
+}
+
+// WithStatementCancellation cancels the PostgreSQL statement of a query when its context is cancelled, e.g. when the
+// client of an http request disconnects, instead of letting the database finish it. Queries with a context which can
+// be cancelled are sent within an interactive transaction, so that the connection running the statement is known.
+func WithStatementCancellation() Option {
+	return func(o *Options) {
+		o.CancelStatements = true
+	}
+}
+
+// WithMaxPayloadSize limits the size of request and response bodies sent to and received from the engine.
+// Exceeding the limit returns a *PayloadTooLargeError instead of sending the request or reading the response.
+func WithMaxPayloadSize(requestSize, responseSize int) Option {
+	return func(o *Options) {
+		o.MaxRequestSize = requestSize
+		o.MaxResponseSize = responseSize
+	}
+}
+
+// WithRequestCompression gzip-compresses request bodies of at least minSize bytes before sending them to the engine
+func WithRequestCompression(minSize int) Option {
+	return func(o *Options) {
+		o.CompressRequests = true
+		o.CompressMinSize = minSize
+	}
+}
+
+// WithLibraryPath sets the path to the query engine shared library used by the LibraryEngine.
+// If not set, the path is read from the PRISMA_QUERY_ENGINE_LIBRARY env var.
+func WithLibraryPath(path string) Option {
+	return func(o *Options) {
+		o.LibraryPath = path
+	}
+}
+
+// WithUnixSocket sets whether the query engine listens on a unix domain socket, which can't conflict with other
+// processes and is not accessible by other users, or on a localhost port. Unix sockets are enabled by default on all
+// platforms except windows.
+func WithUnixSocket(enabled bool) Option {
+	return func(o *Options) {
+		o.UnixSocket = &enabled
+	}
+}
+
+// WithRestartPolicy configures how the query engine is restarted when it exits unexpectedly, e.g. because it crashed.
+// Use RestartPolicy{} to disable restarts.
+func WithRestartPolicy(policy RestartPolicy) Option {
+	return func(o *Options) {
+		o.Restart = &policy
+	}
+}
+
+// WithEventHandler registers a function which is called when the state of the engine changes, e.g. to record metrics
+// when the query engine crashed and was restarted. The function must not block.
+func WithEventHandler(handler func(Event)) Option {
+	return func(o *Options) {
+		o.OnEvent = handler
+	}
+}
+
+// WithLogLevel sets the level of the logs written by the query engine, i.e. "error", "warn", "info", "debug" or
+// "trace". At "info" and more verbose levels, the engine logs each query including its duration.
+func WithLogLevel(level string) Option {
+	return func(o *Options) {
+		o.LogLevel = level
+	}
+}
+
+// WithLogHandler registers a function which is called for each log line written by the query engine, e.g. to forward
+// slow query warnings or panics to a structured logger. Engine logs are written to the client logger as well.
+// The function must not block.
+func WithLogHandler(handler func(LogEvent)) Option {
+	return func(o *Options) {
+		o.OnLog = handler
+	}
+}
+
+// WithStartupTimeout sets how long Connect waits for the query engine to become ready, e.g. to allow for slow disks
+// or containers with little CPU. Defaults to DefaultStartupTimeout.
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.StartupTimeout = timeout
+	}
+}
+
+// WithEngineURL connects to an externally managed query engine instead of spawning one, e.g. a sidecar container in
+// Kubernetes. The url can be a http(s) url like http://engine:4466 or a unix domain socket like
+// unix:///var/run/prisma/query-engine.sock. The engine must have been started with the same schema as the client.
+func WithEngineURL(url string) Option {
+	return func(o *Options) {
+		o.EngineURL = url
+	}
+}
+
+func (o Options) startupTimeout() time.Duration {
+	if o.StartupTimeout > 0 {
+		return o.StartupTimeout
+	}
+	return DefaultStartupTimeout
+}
+
+func (o Options) logLevel() string {
+	if o.LogLevel != "" {
+		return o.LogLevel
+	}
+	if logger.Enabled {
+		return "info"
+	}
+	return "error"
+}
+
+// logsQueries returns whether the log level is verbose enough to include query logs
+func (o Options) logsQueries() bool {
+	switch o.logLevel() {
+	case "info", "debug", "trace":
+		return true
+	}
+	return false
+}
+
+func (o Options) restartPolicy() RestartPolicy {
+	if o.Restart != nil {
+		return *o.Restart
+	}
+	return DefaultRestartPolicy
+}
+
+func newOptions(options []Option) Options {
+	var o Options
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
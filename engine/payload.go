@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+)
+
+// PayloadTooLargeError is returned when a request or response body exceeds the configured maximum payload size
+type PayloadTooLargeError struct {
+	// Direction is either "request" or "response"
+	Direction string
+	// Size is the size of the payload in bytes; for responses, it is the number of bytes read before aborting
+	Size int
+	// Limit is the configured limit in bytes
+	Limit int
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("%s payload of %d bytes exceeds the maximum size of %d bytes", e.Direction, e.Size, e.Limit)
+}
+
+// IsPayloadTooLarge returns whether err is or wraps a *PayloadTooLargeError
+func IsPayloadTooLarge(err error) bool {
+	var target *PayloadTooLargeError
+	return errors.As(err, &target)
+}
+
+func compress(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(payload); err != nil {
+		return nil, fmt.Errorf("gzip write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("gzip close: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_request_payloadLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip reader: %s", err)
+				return
+			}
+			body = gz
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			t.Errorf("read body: %s", err)
+			return
+		}
+		// echo the request body
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	noop := func(*http.Request) {}
+	large := []byte(strings.Repeat("a", 1000))
+
+	tests := []struct {
+		name    string
+		options Options
+		payload []byte
+		wantErr *PayloadTooLargeError
+	}{{
+		name:    "no limits",
+		payload: large,
+	}, {
+		name:    "compressed",
+		options: Options{CompressRequests: true, CompressMinSize: 100},
+		payload: large,
+	}, {
+		name:    "below compression threshold",
+		options: Options{CompressRequests: true, CompressMinSize: 10000},
+		payload: large,
+	}, {
+		name:    "request too large",
+		options: Options{MaxRequestSize: 999},
+		payload: large,
+		wantErr: &PayloadTooLargeError{Direction: "request", Size: 1000, Limit: 999},
+	}, {
+		name:    "response too large",
+		options: Options{MaxResponseSize: 500},
+		payload: large,
+		wantErr: &PayloadTooLargeError{Direction: "response", Size: 501, Limit: 500},
+	}, {
+		name:    "exact limits",
+		options: Options{MaxRequestSize: 1000, MaxResponseSize: 1000},
+		payload: large,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := request(context.Background(), server.Client(), tt.options, "POST", server.URL, tt.payload, noop)
+			if tt.wantErr != nil {
+				var actual *PayloadTooLargeError
+				if !errors.As(err, &actual) {
+					t.Fatalf("request() error = %v, want %v", err, tt.wantErr)
+				}
+				if *actual != *tt.wantErr {
+					t.Fatalf("request() error = %+v, want %+v", actual, tt.wantErr)
+				}
+				if !IsPayloadTooLarge(err) {
+					t.Fatalf("IsPayloadTooLarge() = false")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("request() error = %v", err)
+			}
+			if string(got) != string(tt.payload) {
+				t.Errorf("request() = %d bytes, want %d bytes", len(got), len(tt.payload))
+			}
+		})
+	}
+}
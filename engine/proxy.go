@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/steebchen/prisma-client-go/binaries"
@@ -18,11 +19,18 @@ import (
 	"github.com/steebchen/prisma-client-go/runtime/types"
 )
 
-func NewDataProxyEngine(schema, connectionURL string) *DataProxyEngine {
+// IsDataProxyURL returns whether the connection string points to a Prisma Data Proxy or Accelerate endpoint,
+// e.g. prisma://aws-us-east-1.prisma-data.com/?api_key=...
+func IsDataProxyURL(connectionURL string) bool {
+	return strings.HasPrefix(connectionURL, "prisma://") || strings.HasPrefix(connectionURL, "prisma+postgres://")
+}
+
+func NewDataProxyEngine(schema, connectionURL string, options ...Option) *DataProxyEngine {
 	return &DataProxyEngine{
 		Schema:        schema,
 		connectionURL: connectionURL,
 		http:          &http.Client{},
+		options:       newOptions(options),
 	}
 }
 
@@ -42,6 +50,9 @@ type DataProxyEngine struct {
 
 	// apiKey contains the parsed prisma data proxy api key from the connection string
 	apiKey string
+
+	// options holds optional engine settings
+	options Options
 }
 
 func (e *DataProxyEngine) Connect() error {
@@ -51,6 +62,10 @@ func (e *DataProxyEngine) Connect() error {
 
 	logger.Debug.Printf("parsing connection string from database url %s", e.connectionURL)
 
+	if !IsDataProxyURL(e.connectionURL) {
+		return fmt.Errorf("the data proxy requires a prisma:// connection string")
+	}
+
 	u, err := url.Parse(e.connectionURL)
 	if err != nil {
 		return fmt.Errorf("parse prisma string: %w", err)
@@ -62,10 +77,21 @@ func (e *DataProxyEngine) Connect() error {
 
 	e.url = getCloudURI(u.Host, hash)
 	logger.Debug.Printf("using %s as remote URI", e.url)
+
+	// skip the upload if this schema was already uploaded in a previous run;
+	// if the remote lost it in the meantime, retryableRequest uploads it again
+	cache := newWarmCache("data-proxy")
+	if _, ok := cache.Get(u.Host + hash); ok {
+		logger.Debug.Printf("schema %s was already uploaded, skipping upload", hash)
+		return nil
+	}
+
 	if err := e.uploadSchema(context.Background()); err != nil {
 		return fmt.Errorf("upload schema: %w", err)
 	}
 
+	cache.Set(u.Host+hash, []byte(hash))
+
 	return nil
 }
 
@@ -94,13 +120,18 @@ func (e *DataProxyEngine) Disconnect() error {
 }
 
 func (e *DataProxyEngine) Do(ctx context.Context, payload interface{}, into interface{}) error {
+	ctx, cancel := withQueryDeadline(ctx)
+	defer cancel()
+
 	startReq := time.Now()
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("payload marshal: %w", err)
 	}
 
+	ctx, trace := e.options.startTrace(ctx)
 	body, err := e.retryableRequest(ctx, "POST", "/graphql", data)
+	trace.finish(ctx, body, err)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -123,6 +154,12 @@ func (e *DataProxyEngine) Do(ctx context.Context, payload interface{}, into inte
 		return fmt.Errorf("pql error: %s", first.RawMessage())
 	}
 
+	if e.options.StrictNumbers {
+		if err := checkPrecision(response.Data.Result); err != nil {
+			return err
+		}
+	}
+
 	if err := json.Unmarshal(response.Data.Result, into); err != nil {
 		return fmt.Errorf("json data result unmarshal: %w", err)
 	}
@@ -138,11 +175,19 @@ func (e *DataProxyEngine) Batch(ctx context.Context, payload interface{}, into i
 		return fmt.Errorf("payload marshal: %w", err)
 	}
 
+	ctx, trace := e.options.startTrace(ctx)
 	body, err := e.retryableRequest(ctx, "POST", "/graphql", data)
+	trace.finish(ctx, body, err)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
+	if e.options.StrictNumbers {
+		if err := checkBatchPrecision(body); err != nil {
+			return err
+		}
+	}
+
 	if err := json.Unmarshal(body, &into); err != nil {
 		return fmt.Errorf("json body unmarshal: %w", err)
 	}
@@ -158,8 +203,12 @@ func (e *DataProxyEngine) request(ctx context.Context, method string, path strin
 	logger.Debug.Printf("requesting %s", e.url+path)
 	auth := func(req *http.Request) {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", e.apiKey))
+		req.Header.Set("Prisma-Engine-Hash", e.options.expectedVersion())
+		for key, value := range traceHeaders(ctx) {
+			req.Header.Set(key, value)
+		}
 	}
-	return request(ctx, e.http, method, e.url+path, payload, auth)
+	return request(ctx, e.http, e.options, method, e.url+path, payload, auth)
 }
 
 func (e *DataProxyEngine) retryableRequest(ctx context.Context, method string, path string, payload []byte) ([]byte, error) {
@@ -2,17 +2,17 @@ package engine
 
 import (
 	"net/http"
-	"os/exec"
 	"sync"
 )
 
-func NewQueryEngine(schema string, hasBinaryTargets bool, datasources string, datasourceURL string) *QueryEngine {
+func NewQueryEngine(schema string, hasBinaryTargets bool, datasources string, datasourceURL string, options ...Option) *QueryEngine {
 	return &QueryEngine{
 		Schema:           schema,
 		hasBinaryTargets: hasBinaryTargets,
 		datasources:      datasources,
 		datasourceURL:    datasourceURL,
 		http:             &http.Client{},
+		options:          newOptions(options),
 	}
 }
 
@@ -20,8 +20,8 @@ type QueryEngine struct {
 	// Schema contains the prisma Schema
 	Schema string
 
-	// cmd holds the prisma binary process
-	cmd *exec.Cmd
+	// proc holds the running query engine process
+	proc *process
 
 	// http is the internal http client
 	http *http.Client
@@ -35,6 +35,9 @@ type QueryEngine struct {
 	// httpURL holds the query-engine httpURL
 	httpURL string
 
+	// socketDir holds the private directory of the unix domain socket, if the engine listens on one
+	socketDir string
+
 	// hasBinaryTargets can be toggled by generated code from Schema.prisma whether binaryTargets
 	// were specified and thus expects binaries in the local path
 	hasBinaryTargets bool
@@ -48,12 +51,21 @@ type QueryEngine struct {
 	// closed keeps track of query engine status
 	closed chan interface{}
 
-	// onEngineError is a listener on the query engine stderr output
-	onEngineError chan string
+	// restarted is closed and replaced after each restart of the query engine, whether it was successful or not
+	restarted chan struct{}
 
 	// lastEngineError contains the last received error
 	lastEngineError string
 
+	// stderrTail contains the last lines the query engine wrote to stderr
+	stderrTail []string
+
+	// options holds optional engine settings
+	options Options
+
+	// inflight tracks requests which are currently sent to the engine
+	inflight sync.WaitGroup
+
 	mu sync.Mutex
 }
 
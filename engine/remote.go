@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// connectRemote connects to an externally managed query engine, e.g. running in a sidecar container, instead of
+// spawning one. The engine must have been started with the same schema as the client.
+func (e *QueryEngine) connectRemote(engineURL string) error {
+	u, err := url.Parse(engineURL)
+	if err != nil {
+		return fmt.Errorf("parse engine url: %w", err)
+	}
+
+	var client *http.Client
+	var httpURL string
+	switch u.Scheme {
+	case "http", "https":
+		client = &http.Client{}
+		httpURL = strings.TrimSuffix(engineURL, "/")
+	case "unix":
+		// the host is ignored as all requests are sent to the socket
+		client = unixSocketClient(u.Path)
+		httpURL = "http://localhost"
+	default:
+		return fmt.Errorf("unsupported engine url scheme %q; use http, https or unix", u.Scheme)
+	}
+
+	logger.Debug.Printf("connecting to remote query engine")
+
+	e.mu.Lock()
+	e.http = client
+	e.httpURL = httpURL
+	e.mu.Unlock()
+
+	// the url may contain credentials, so only the host is reported
+	address := u.Scheme + "://" + u.Host + u.Path
+	if err := e.awaitReady(nil, address); err != nil {
+		return err
+	}
+
+	// remote engines are deployed separately from the client, so their version may drift
+	if e.options.ExpectedVersion != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), e.options.startupTimeout())
+		defer cancel()
+		return e.checkRemoteVersion(ctx, address)
+	}
+
+	return nil
+}
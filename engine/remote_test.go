@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQueryEngine_remote(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		queries = append(queries, r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":{"result":{"id":"abc"}}}`))
+	}))
+	defer srv.Close()
+
+	e := NewQueryEngine("", false, "[]", "", WithEngineURL(srv.URL+"/"))
+	if err := e.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "abc", result.ID)
+	massert.Equal(t, []string{"/"}, queries)
+
+	if err := e.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the remote engine keeps running
+	res, err := http.Get(srv.URL + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+}
+
+func TestQueryEngine_concurrentDisconnect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	e := NewQueryEngine("", false, "[]", "", WithEngineURL(srv.URL+"/"))
+	if err := e.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// all goroutines start at once to make it likely that they stop the supervisor at the same time
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if err := e.Disconnect(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestQueryEngine_remoteUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	e := NewQueryEngine("", false, "[]", "", WithEngineURL(url), WithStartupTimeout(200*time.Millisecond))
+	err := e.Connect()
+
+	var startupErr *StartupError
+	if !errors.As(err, &startupErr) {
+		t.Fatalf("expected a StartupError, got %v", err)
+	}
+	massert.Equal(t, url, startupErr.Address)
+	massert.Equal(t, true, strings.Contains(err.Error(), "query engine was not ready after 200ms"))
+}
+
+func TestQueryEngine_remoteUnsupportedScheme(t *testing.T) {
+	e := NewQueryEngine("", false, "[]", "", WithEngineURL("ftp://engine"))
+	massert.Equal(t, `connect remote engine: unsupported engine url scheme "ftp"; use http, https or unix`, e.Connect().Error())
+}
@@ -17,9 +17,30 @@ var internalDeleteNotFoundMessage = "Error occurred during query execution: Inte
 
 // Do sends the http Request to the query engine and unmarshals the response
 func (e *QueryEngine) Do(ctx context.Context, payload interface{}, v interface{}) error {
+	var s statement
+	if t, ok := queryTimeoutFromContext(ctx); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+		if t.statement {
+			s.timeout = t.timeout
+		}
+	}
+	// a context without a done channel can never be cancelled
+	s.cancel = e.options.CancelStatements && ctx.Done() != nil
+
+	if (s.timeout == 0 && !s.cancel) || transactionIDFromContext(ctx) != "" {
+		return e.do(ctx, payload, v)
+	}
+	return e.doStatement(ctx, s, payload, v)
+}
+
+func (e *QueryEngine) do(ctx context.Context, payload interface{}, v interface{}) error {
 	startReq := time.Now()
 
-	body, err := e.Request(ctx, "POST", "/", payload, true)
+	ctx, trace := e.options.startTrace(ctx)
+	body, err := e.requestRetryingReads(ctx, payload)
+	trace.finish(ctx, body, err)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -48,9 +69,17 @@ func (e *QueryEngine) Do(ctx context.Context, payload interface{}, v interface{}
 		return fmt.Errorf("internal error: %s", e.RawMessage())
 	}
 
-	response.Data.Result, err = transformResponse(response.Data.Result)
-	if err != nil {
-		return fmt.Errorf("transform response: %w", err)
+	if !typedResultsFromContext(ctx) {
+		response.Data.Result, err = transformResponse(response.Data.Result)
+		if err != nil {
+			return fmt.Errorf("transform response: %w", err)
+		}
+	}
+
+	if e.options.StrictNumbers {
+		if err := checkPrecision(response.Data.Result); err != nil {
+			return err
+		}
 	}
 
 	if err := json.Unmarshal(response.Data.Result, v); err != nil {
@@ -64,7 +93,9 @@ func (e *QueryEngine) Do(ctx context.Context, payload interface{}, v interface{}
 
 // Batch sends a batch request to the query engine; used for transactions
 func (e *QueryEngine) Batch(ctx context.Context, payload interface{}, v interface{}) error {
+	ctx, trace := e.options.startTrace(ctx)
 	body, err := e.Request(ctx, "POST", "/", payload, true)
+	trace.finish(ctx, body, err)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -74,6 +105,12 @@ func (e *QueryEngine) Batch(ctx context.Context, payload interface{}, v interfac
 		return fmt.Errorf("transform response: %w", err)
 	}
 
+	if e.options.StrictNumbers {
+		if err := checkBatchPrecision(body); err != nil {
+			return err
+		}
+	}
+
 	if err := json.Unmarshal(body, &v); err != nil {
 		return fmt.Errorf("json body unmarshal: %w", err)
 	}
@@ -87,17 +124,32 @@ func (e *QueryEngine) Request(ctx context.Context, method string, path string, p
 		return nil, fmt.Errorf("client is not connected yet")
 	}
 
+	e.mu.Lock()
 	if e.disconnected {
+		e.mu.Unlock()
 		logger.Info.Printf("A query was executed after Disconnect() was called. Make sure to not send any queries after calling .Prisma.Disconnect() the client.")
 		return nil, fmt.Errorf("client is already disconnected")
 	}
+	// track in-flight requests, so that Disconnect can wait for them
+	e.inflight.Add(1)
+	defer e.inflight.Done()
+
+	// the http client and url change when the query engine is restarted
+	client, httpURL := e.http, e.httpURL
+	e.mu.Unlock()
 
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("payload marshal: %w", err)
 	}
 
-	return request(ctx, e.http, method, e.httpURL+path, requestBody, func(req *http.Request) {
+	return request(ctx, client, e.options, method, httpURL+path, requestBody, func(req *http.Request) {
 		req.Header.Set("content-type", "application/json")
+		if id := transactionIDFromContext(ctx); id != "" {
+			req.Header.Set("X-transaction-id", id)
+		}
+		for key, value := range traceHeaders(ctx) {
+			req.Header.Set(key, value)
+		}
 	})
 }
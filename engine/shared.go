@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// errNotConnected is returned when sending queries through a SharedEngine which is not connected
+var errNotConnected = errors.New("client.Prisma.Connect() needs to be called before sending queries")
+
+// sharedEngines holds the engines which are currently shared, by key
+var sharedEngines = struct {
+	mu      sync.Mutex
+	entries map[string]*sharedEntry
+}{
+	entries: make(map[string]*sharedEntry),
+}
+
+type sharedEntry struct {
+	key    string
+	engine Engine
+
+	mu        sync.Mutex
+	connected bool
+	// closed is set when the last reference was released; the entry must not be used anymore
+	closed bool
+	refs   int
+}
+
+// SharedEngine shares a single engine between all clients which use the same key, usually the schema and datasource
+// url, so that only one query engine process is spawned. The engine is connected when the first client connects and
+// disconnected when the last one disconnects.
+//
+// The engine is created by the first client which connects, so its options apply to all clients using the same key.
+type SharedEngine struct {
+	key    string
+	create func() Engine
+
+	mu    sync.Mutex
+	entry *sharedEntry
+}
+
+// NewSharedEngine creates an engine which shares the engine returned by create with all other shared engines using
+// the same key. create is only called if no engine with this key is connected.
+func NewSharedEngine(key string, create func() Engine) *SharedEngine {
+	return &SharedEngine{
+		key:    key,
+		create: create,
+	}
+}
+
+func (e *SharedEngine) Name() string {
+	return "shared-engine"
+}
+
+// Connect connects the shared engine if this is the first reference to it
+func (e *SharedEngine) Connect() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.entry != nil {
+		return fmt.Errorf("already connected")
+	}
+
+	for {
+		entry := acquireSharedEntry(e.key, e.create)
+
+		entry.mu.Lock()
+		if entry.closed {
+			// the last reference was released concurrently; try again with a new engine
+			entry.mu.Unlock()
+			continue
+		}
+
+		if !entry.connected {
+			logger.Debug.Printf("connecting shared engine")
+			if err := entry.engine.Connect(); err != nil {
+				entry.closed = true
+				removeSharedEntry(entry)
+				entry.mu.Unlock()
+				return err
+			}
+			entry.connected = true
+		}
+
+		entry.refs++
+		entry.mu.Unlock()
+
+		e.entry = entry
+		return nil
+	}
+}
+
+// Disconnect releases the reference to the shared engine, and disconnects it if this was the last reference
+func (e *SharedEngine) Disconnect() error {
+	return e.release(func(engine Engine) error {
+		return engine.Disconnect()
+	})
+}
+
+// DisconnectContext releases the reference to the shared engine, and gracefully disconnects it if this was the last
+// reference
+func (e *SharedEngine) DisconnectContext(ctx context.Context) error {
+	return e.release(func(engine Engine) error {
+		if d, ok := engine.(interface {
+			DisconnectContext(ctx context.Context) error
+		}); ok {
+			return d.DisconnectContext(ctx)
+		}
+		return engine.Disconnect()
+	})
+}
+
+func (e *SharedEngine) release(disconnect func(engine Engine) error) error {
+	e.mu.Lock()
+	entry := e.entry
+	e.entry = nil
+	e.mu.Unlock()
+
+	if entry == nil {
+		return errNotConnected
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	entry.refs--
+	if entry.refs > 0 {
+		return nil
+	}
+
+	entry.closed = true
+	removeSharedEntry(entry)
+
+	logger.Debug.Printf("disconnecting shared engine")
+	return disconnect(entry.engine)
+}
+
+func (e *SharedEngine) Do(ctx context.Context, payload interface{}, into interface{}) error {
+	engine, err := e.current()
+	if err != nil {
+		return err
+	}
+	return engine.Do(ctx, payload, into)
+}
+
+func (e *SharedEngine) Batch(ctx context.Context, payload interface{}, into interface{}) error {
+	engine, err := e.current()
+	if err != nil {
+		return err
+	}
+	return engine.Batch(ctx, payload, into)
+}
+
+func (e *SharedEngine) current() (Engine, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.entry == nil {
+		return nil, errNotConnected
+	}
+	return e.entry.engine, nil
+}
+
+// acquireSharedEntry returns the entry of the given key, creating it if necessary
+func acquireSharedEntry(key string, create func() Engine) *sharedEntry {
+	sharedEngines.mu.Lock()
+	defer sharedEngines.mu.Unlock()
+
+	entry, ok := sharedEngines.entries[key]
+	if !ok {
+		entry = &sharedEntry{
+			key:    key,
+			engine: create(),
+		}
+		sharedEngines.entries[key] = entry
+	}
+	return entry
+}
+
+// removeSharedEntry removes the entry from the registry unless it was already replaced
+func removeSharedEntry(entry *sharedEntry) {
+	sharedEngines.mu.Lock()
+	defer sharedEngines.mu.Unlock()
+
+	if sharedEngines.entries[entry.key] == entry {
+		delete(sharedEngines.entries, entry.key)
+	}
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type countingEngine struct {
+	connects    *int32
+	disconnects *int32
+	connectErr  error
+}
+
+func (e *countingEngine) Connect() error {
+	atomic.AddInt32(e.connects, 1)
+	return e.connectErr
+}
+
+func (e *countingEngine) Disconnect() error {
+	atomic.AddInt32(e.disconnects, 1)
+	return nil
+}
+
+func (e *countingEngine) Do(context.Context, interface{}, interface{}) error {
+	return nil
+}
+
+func (e *countingEngine) Batch(context.Context, interface{}, interface{}) error {
+	return nil
+}
+
+func (e *countingEngine) Name() string {
+	return "counting"
+}
+
+type engineFactory struct {
+	creates, connects, disconnects int32
+	connectErr                     error
+}
+
+func (f *engineFactory) create() Engine {
+	atomic.AddInt32(&f.creates, 1)
+	return &countingEngine{connects: &f.connects, disconnects: &f.disconnects, connectErr: f.connectErr}
+}
+
+func TestSharedEngine(t *testing.T) {
+	f := &engineFactory{}
+	a := NewSharedEngine(t.Name(), f.create)
+	b := NewSharedEngine(t.Name(), f.create)
+
+	if err := a.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, int32(1), f.creates)
+	massert.Equal(t, int32(1), f.connects)
+
+	if err := a.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, int32(0), f.disconnects)
+	massert.Equal(t, errNotConnected, a.Do(context.Background(), nil, nil))
+	massert.Equal(t, nil, b.Do(context.Background(), nil, nil))
+
+	if err := b.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, int32(1), f.disconnects)
+
+	// the engine is created again after all clients disconnected
+	if err := a.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, int32(2), f.creates)
+	if err := a.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSharedEngine_differentKeys(t *testing.T) {
+	f := &engineFactory{}
+	a := NewSharedEngine(t.Name()+"a", f.create)
+	b := NewSharedEngine(t.Name()+"b", f.create)
+
+	if err := a.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, int32(2), f.creates)
+
+	_ = a.Disconnect()
+	_ = b.Disconnect()
+	massert.Equal(t, int32(2), f.disconnects)
+}
+
+func TestSharedEngine_connectError(t *testing.T) {
+	f := &engineFactory{connectErr: errors.New("connect failed")}
+	a := NewSharedEngine(t.Name(), f.create)
+
+	massert.Equal(t, f.connectErr, a.Connect())
+	massert.Equal(t, f.connectErr, a.Connect())
+	// a failed engine is not reused
+	massert.Equal(t, int32(2), f.creates)
+	massert.Equal(t, errNotConnected, a.Disconnect())
+}
+
+func TestSharedEngine_concurrent(t *testing.T) {
+	f := &engineFactory{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := NewSharedEngine(t.Name(), f.create)
+			for j := 0; j < 10; j++ {
+				if err := e.Connect(); err != nil {
+					t.Error(err)
+					return
+				}
+				if err := e.Do(context.Background(), nil, nil); err != nil {
+					t.Error(err)
+				}
+				if err := e.Disconnect(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	massert.Equal(t, atomic.LoadInt32(&f.creates), atomic.LoadInt32(&f.connects))
+	massert.Equal(t, atomic.LoadInt32(&f.connects), atomic.LoadInt32(&f.disconnects))
+}
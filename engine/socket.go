@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+)
+
+// unixSocketName is the file name of the socket in a private temporary directory
+const unixSocketName = "query-engine.sock"
+
+// useUnixSocket returns whether the query engine should listen on a unix domain socket instead of a localhost port
+func (o Options) useUnixSocket() bool {
+	if o.UnixSocket != nil {
+		return *o.UnixSocket
+	}
+	return unixSocketDefault
+}
+
+// newUnixSocket creates a private directory, which is only accessible by the current user, and returns the path of
+// a socket in it
+func newUnixSocket() (dir string, socket string, err error) {
+	dir, err = os.MkdirTemp("", "prisma-")
+	if err != nil {
+		return "", "", fmt.Errorf("create socket dir: %w", err)
+	}
+	return dir, path.Join(dir, unixSocketName), nil
+}
+
+// unixSocketClient returns a http client which sends all requests to the given unix domain socket
+func unixSocketClient(socket string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
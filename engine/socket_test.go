@@ -0,0 +1,70 @@
+//go:build !windows
+
+package engine
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestOptions_useUnixSocket(t *testing.T) {
+	massert.Equal(t, true, newOptions(nil).useUnixSocket())
+	massert.Equal(t, false, newOptions([]Option{WithUnixSocket(false)}).useUnixSocket())
+	massert.Equal(t, true, newOptions([]Option{WithUnixSocket(true)}).useUnixSocket())
+}
+
+func TestUnixSocketClient(t *testing.T) {
+	dir, socket, err := newUnixSocket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})}
+	go server.Serve(l) //nolint:errcheck
+	defer server.Close()
+
+	e := NewQueryEngine("", false, "", "")
+	e.connected = true
+	e.httpURL = "http://localhost"
+	e.http = unixSocketClient(socket)
+
+	body, err := e.Request(context.Background(), "GET", "/status", map[string]interface{}{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `{"status":"ok"}`, string(body))
+}
+
+func TestQueryEngine_removeSocket(t *testing.T) {
+	dir, _, err := newUnixSocket()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewQueryEngine("", false, "", "")
+	e.socketDir = dir
+	e.removeSocket()
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected socket dir to be removed, got %v", err)
+	}
+	massert.Equal(t, "", e.socketDir)
+}
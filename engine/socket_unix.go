@@ -0,0 +1,6 @@
+//go:build !windows
+
+package engine
+
+// unixSocketDefault enables unix domain sockets by default on unix platforms
+const unixSocketDefault = true
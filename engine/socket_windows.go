@@ -0,0 +1,4 @@
+package engine
+
+// unixSocketDefault disables unix domain sockets by default on windows, where the engine listens on a localhost port
+const unixSocketDefault = false
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultStartupTimeout is how long Connect waits for the query engine to become ready
+const DefaultStartupTimeout = 10 * time.Second
+
+// readinessInterval is the interval in which the readiness of the query engine is checked
+const readinessInterval = 100 * time.Millisecond
+
+// stderrTailSize is the number of stderr lines which are included in a StartupError
+const stderrTailSize = 20
+
+// StartupError is returned when the query engine could not be started or did not become ready
+type StartupError struct {
+	// Address is the url or unix socket the query engine was expected to listen on
+	Address string
+
+	// Stderr contains the last lines the query engine wrote to stderr
+	Stderr []string
+
+	// Err is the underlying error
+	Err error
+}
+
+func (e *StartupError) Error() string {
+	msg := fmt.Sprintf("query engine at %s: %s", e.Address, e.Err)
+	if len(e.Stderr) > 0 {
+		msg += "\nlast query engine output:\n" + strings.Join(e.Stderr, "\n")
+	}
+	return msg
+}
+
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+// startupError wraps err with the address and the stderr tail of the query engine
+func (e *QueryEngine) startupError(address string, err error) error {
+	e.mu.Lock()
+	stderr := append([]string(nil), e.stderrTail...)
+	e.mu.Unlock()
+
+	return &StartupError{
+		Address: address,
+		Stderr:  stderr,
+		Err:     err,
+	}
+}
+
+// recordStderr remembers the last lines of stderr; e.mu must be held
+func (e *QueryEngine) recordStderr(line string) {
+	if len(e.stderrTail) == stderrTailSize {
+		e.stderrTail = append(e.stderrTail[:0], e.stderrTail[1:]...)
+	}
+	e.stderrTail = append(e.stderrTail, line)
+}
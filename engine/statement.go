@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// statement describes how the database statement of a query is bounded
+type statement struct {
+	// timeout is the statement_timeout of the query; 0 means no statement timeout
+	timeout time.Duration
+
+	// cancel cancels the statement with pg_cancel_backend when the context of the query is cancelled
+	cancel bool
+}
+
+// statementTimeoutMargin is added to the timeout of the transaction which runs a statement with a deadline, so that the
+// deadline of the request or the statement timeout is reached before the engine expires the transaction
+const statementTimeoutMargin = time.Second
+
+// statementTransactionTimeout is the timeout of the transaction which runs a statement without a deadline
+const statementTransactionTimeout = 10 * time.Minute
+
+// cancelStatementTimeout bounds the request which cancels a statement, as the context of the query is done already
+const cancelStatementTimeout = 5 * time.Second
+
+// transactionTimeout returns how long the engine keeps the transaction of the statement open
+func (s statement) transactionTimeout(ctx context.Context) time.Duration {
+	if s.timeout > 0 {
+		return s.timeout + statementTimeoutMargin
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline) + statementTimeoutMargin
+	}
+	return statementTransactionTimeout
+}
+
+// doStatement sends the query within an interactive transaction, as the engine has no option to limit the duration of
+// a single statement or to cancel it. The transaction pins the connection of the statement, so that its
+// statement_timeout can be set and the backend running it can be cancelled. Queries which are already part of a
+// transaction are sent as they are, as the setting would apply to the rest of the transaction as well.
+func (e *QueryEngine) doStatement(ctx context.Context, s statement, payload interface{}, v interface{}) error {
+	id, err := e.StartTransaction(ctx, TransactionOptions{
+		Timeout: s.transactionTimeout(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	txCtx := WithTransactionID(ctx, id)
+	pid, err := e.setupStatement(txCtx, s)
+	if err == nil {
+		err = e.do(txCtx, payload, v)
+		if err != nil && s.cancel && ctx.Err() != nil {
+			e.cancelStatement(pid)
+		}
+	}
+	if err != nil {
+		// the context may be done already
+		if rollbackErr := e.RollbackTransaction(context.Background(), id); rollbackErr != nil {
+			logger.Debug.Printf("could not roll back transaction %s: %s", id, rollbackErr)
+		}
+		return err
+	}
+
+	return e.CommitTransaction(ctx, id)
+}
+
+// setupStatement sets the statement_timeout of the interactive transaction of ctx and returns the process id of its
+// backend if the statement may be cancelled
+func (e *QueryEngine) setupStatement(ctx context.Context, s statement) (int, error) {
+	ms := s.timeout.Milliseconds()
+	if s.timeout > 0 && ms < 1 {
+		// a statement_timeout of 0 disables the timeout
+		ms = 1
+	}
+
+	if !s.cancel {
+		payload, err := rawRequest("executeRaw", fmt.Sprintf("SET LOCAL statement_timeout = %d", ms))
+		if err != nil {
+			return 0, err
+		}
+		var result json.RawMessage
+		if err := e.do(ctx, payload, &result); err != nil {
+			return 0, fmt.Errorf("set statement timeout: %w", err)
+		}
+		return 0, nil
+	}
+
+	query := "SELECT pg_backend_pid() AS pid"
+	if s.timeout > 0 {
+		query += fmt.Sprintf(", set_config('statement_timeout', '%d', true)", ms)
+	}
+	payload, err := rawRequest("queryRaw", query)
+	if err != nil {
+		return 0, err
+	}
+	var result []struct {
+		PID int `json:"pid"`
+	}
+	if err := e.do(ctx, payload, &result); err != nil {
+		return 0, fmt.Errorf("set up statement: %w", err)
+	}
+	if len(result) != 1 {
+		return 0, fmt.Errorf("set up statement: expected one row, got %d", len(result))
+	}
+	return result[0].PID, nil
+}
+
+// cancelStatement cancels the statement which the backend pid is running. Errors are only logged, as the query failed
+// already.
+func (e *QueryEngine) cancelStatement(pid int) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelStatementTimeout)
+	defer cancel()
+
+	payload, err := rawRequest("queryRaw", "SELECT pg_cancel_backend($1) AS cancelled", pid)
+	if err != nil {
+		logger.Debug.Printf("could not cancel statement of backend %d: %s", pid, err)
+		return
+	}
+	var result json.RawMessage
+	if err := e.do(ctx, payload, &result); err != nil {
+		logger.Debug.Printf("could not cancel statement of backend %d: %s", pid, err)
+		return
+	}
+	logger.Debug.Printf("cancelled statement of backend %d", pid)
+}
+
+// rawRequest returns the request of a raw query with the given parameters
+func rawRequest(method string, query string, params ...interface{}) (protocol.GQLRequest, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return protocol.GQLRequest{}, fmt.Errorf("raw parameters marshal: %w", err)
+	}
+	quotedParams, err := json.Marshal(string(encodedParams))
+	if err != nil {
+		return protocol.GQLRequest{}, fmt.Errorf("raw parameters marshal: %w", err)
+	}
+	quotedQuery, err := json.Marshal(query)
+	if err != nil {
+		return protocol.GQLRequest{}, fmt.Errorf("raw query marshal: %w", err)
+	}
+	return protocol.GQLRequest{
+		Query:     fmt.Sprintf("mutation {result: %s(query: %s, parameters: %s)}", method, quotedQuery, quotedParams),
+		Variables: map[string]interface{}{},
+	}, nil
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// statementServer is a fake query engine which records the requests it receives. Queries containing "slow" block
+// until the request is cancelled.
+type statementServer struct {
+	mu       sync.Mutex
+	requests []string
+	queries  []string
+}
+
+func (s *statementServer) record(r *http.Request) string {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.URL.Path+" "+r.Header.Get("X-transaction-id"))
+	if len(body) > 2 {
+		s.queries = append(s.queries, string(body))
+	}
+	return string(body)
+}
+
+func (s *statementServer) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests, s.queries = nil, nil
+}
+
+func (s *statementServer) recorded() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.queries
+}
+
+func (s *statementServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/status" {
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+		return
+	}
+	body := s.record(r)
+	switch {
+	case r.URL.Path == "/transaction/start":
+		_, _ = w.Write([]byte(`{"id":"tx1"}`))
+	case r.URL.Path != "/":
+		_, _ = w.Write([]byte(`{}`))
+	case strings.Contains(body, "slow"):
+		<-r.Context().Done()
+	case strings.Contains(body, "pg_backend_pid"):
+		_, _ = w.Write([]byte(`{"data":{"result":[{"pid":{"prisma__type":"int","prisma__value":42}}]}}`))
+	default:
+		_, _ = w.Write([]byte(`{"data":{"result":{"id":"abc"}}}`))
+	}
+}
+
+// startStatementEngine connects a query engine to a statementServer
+func startStatementEngine(t *testing.T, options ...Option) (*QueryEngine, *statementServer) {
+	t.Helper()
+	s := &statementServer{}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	e := NewQueryEngine("", false, "[]", "", append(options, WithEngineURL(srv.URL+"/"))...)
+	if err := e.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = e.Disconnect()
+	})
+	return e, s
+}
+
+func TestQueryEngine_statementCancellation(t *testing.T) {
+	e, s := startStatementEngine(t, WithStatementCancellation())
+
+	var result struct {
+		ID string `json:"id"`
+	}
+
+	t.Run("not cancellable", func(t *testing.T) {
+		s.reset()
+		if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+			t.Fatal(err)
+		}
+		requests, _ := s.recorded()
+		massert.Equal(t, []string{"/ "}, requests)
+	})
+
+	t.Run("finished", func(t *testing.T) {
+		s.reset()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := e.Do(ctx, protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+			t.Fatal(err)
+		}
+		requests, queries := s.recorded()
+		massert.Equal(t, []string{
+			"/transaction/start ",
+			"/ tx1",
+			"/ tx1",
+			"/transaction/tx1/commit ",
+		}, requests)
+		massert.Equal(t, []string{
+			`{"timeout":600000}`,
+			`{"query":"mutation {result: queryRaw(query: \"SELECT pg_backend_pid() AS pid\", parameters: \"[]\")}","variables":{}}`,
+			`{"query":"query {}","variables":null}`,
+		}, queries)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		s.reset()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		time.AfterFunc(50*time.Millisecond, cancel)
+		err := e.Do(ctx, protocol.GQLRequest{Query: "query { slow }"}, &result)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context canceled, got %v", err)
+		}
+		requests, queries := s.recorded()
+		massert.Equal(t, []string{
+			"/transaction/start ",
+			"/ tx1",
+			"/ tx1",
+			"/ ",
+			"/transaction/tx1/rollback ",
+		}, requests)
+		massert.Equal(t, `{"query":"mutation {result: queryRaw(query: \"SELECT pg_cancel_backend($1) AS cancelled\", parameters: \"[42]\")}","variables":{}}`, queries[3])
+	})
+
+	t.Run("with timeout", func(t *testing.T) {
+		s.reset()
+		ctx := WithQueryTimeout(context.Background(), 2*time.Second, true)
+		if err := e.Do(ctx, protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+			t.Fatal(err)
+		}
+		_, queries := s.recorded()
+		massert.Equal(t, []string{
+			`{"timeout":3000}`,
+			`{"query":"mutation {result: queryRaw(query: \"SELECT pg_backend_pid() AS pid, set_config('statement_timeout', '2000', true)\", parameters: \"[]\")}","variables":{}}`,
+			`{"query":"query {}","variables":null}`,
+		}, queries)
+	})
+}
@@ -4,61 +4,166 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/steebchen/prisma-client-go/logger"
 )
 
+// Messsage is the format of errors and panics which the query engine writes to stderr
 type Messsage struct {
 	IsPanic bool   `json:"is_panic"`
 	Message string `json:"message"`
 }
 
-func (e *QueryEngine) streamStderr(cmd *exec.Cmd, onError chan<- string) error {
+// LogEvent is a log line written by the query engine, e.g. a slow query warning or a panic
+type LogEvent struct {
+	// Time is when the engine logged the event, or when it was received if the engine didn't include a timestamp
+	Time time.Time
+
+	// Level is the lowercase log level, i.e. "error", "warn", "info", "debug" or "trace"
+	Level string
+
+	// Message is the log message
+	Message string
+
+	// Target is the engine module which logged the event, e.g. "quaint::connector::metrics"
+	Target string
+
+	// IsPanic is true if the engine panicked
+	IsPanic bool
+
+	// Fields contains additional structured data, e.g. the query and its duration for query logs
+	Fields map[string]interface{}
+
+	// Stream is the output the line was written to, i.e. "stdout" or "stderr"
+	Stream string
+
+	// Raw is the unparsed log line
+	Raw string
+}
+
+type engineLogLine struct {
+	Timestamp string                 `json:"timestamp"`
+	Level     string                 `json:"level"`
+	Target    string                 `json:"target"`
+	Fields    map[string]interface{} `json:"fields"`
+	Messsage
+}
+
+// parseLogLine parses a log line of the query engine. Besides JSON log lines, plain text lines are supported as well,
+// which are treated as info messages.
+func parseLogLine(line string, stream string) LogEvent {
+	event := LogEvent{
+		Time:   time.Now(),
+		Level:  "info",
+		Stream: stream,
+		Raw:    line,
+	}
+
+	var parsed engineLogLine
+	if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+		event.Message = line
+		return event
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, parsed.Timestamp); err == nil {
+		event.Time = t
+	}
+	if parsed.Level != "" {
+		event.Level = strings.ToLower(parsed.Level)
+	}
+	event.Target = parsed.Target
+	event.IsPanic = parsed.IsPanic
+	event.Fields = parsed.Fields
+
+	event.Message = parsed.Message
+	if event.Message == "" {
+		if message, ok := parsed.Fields["message"].(string); ok {
+			event.Message = message
+		}
+	} else if parsed.Level == "" {
+		// top level messages are errors which prevent the engine from starting or panics
+		event.Level = "error"
+	}
+
+	return event
+}
+
+// streamStderr captures the stderr output of the query engine and remembers the last error.
+// The returned channel is closed when stderr was read completely, i.e. after the process exited.
+func (e *QueryEngine) streamStderr(cmd *exec.Cmd) (<-chan struct{}, error) {
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return fmt.Errorf("get stderr pipe: %w", err)
+		return nil, fmt.Errorf("get stderr pipe: %w", err)
+	}
+	return e.streamOutput(stderr, "stderr"), nil
+}
+
+// streamStdout captures the stdout output of the query engine.
+// The returned channel is closed when stdout was read completely, i.e. after the process exited.
+func (e *QueryEngine) streamStdout(cmd *exec.Cmd) (<-chan struct{}, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("get stdout pipe: %w", err)
 	}
+	return e.streamOutput(stdout, "stdout"), nil
+}
+
+// streamOutput reads the log lines of the query engine until r is closed and handles them with handleOutput
+func (e *QueryEngine) streamOutput(r io.Reader, stream string) <-chan struct{} {
+	done := make(chan struct{})
 
 	go func() {
-	outer:
+		defer close(done)
+
+		// lines are read without a length limit, as the engine blocks writing its output if the pipe isn't drained,
+		// e.g. after a long query log line
+		reader := bufio.NewReader(r)
 		for {
-			select {
-			case v := <-e.onEngineError:
-				e.mu.Lock()
-				e.lastEngineError = v
-				e.mu.Unlock()
-			case <-e.closed:
-				logger.Debug.Printf("query engine closed")
-				break outer
+			line, err := reader.ReadString('\n')
+			if err != nil && err != io.EOF {
+				logger.Debug.Printf("could not read query engine %s: %s", stream, err)
+				_, _ = io.Copy(io.Discard, r)
+				return
+			}
+			if strings.TrimSpace(line) != "" {
+				e.handleOutput(strings.TrimRight(line, "\r\n"), stream)
+			}
+			if err == io.EOF {
+				return
 			}
 		}
 	}()
 
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		const maxCapacity int = 65536
-		buf := make([]byte, maxCapacity)
-		scanner.Buffer(buf, maxCapacity)
-
-		for scanner.Scan() {
-			contents := scanner.Bytes()
-			var message Messsage
-			if err := json.Unmarshal(contents, &message); err != nil {
-				log.Printf("failed to unmarshal message: %s", err.Error())
-				continue
-			}
+	return done
+}
 
-			if message.Message != "" {
-				onError <- message.Message
-				log.Println(message.Message)
-				continue
-			}
+// handleOutput parses a log line of the query engine, writes it to the client logger depending on its level and passes
+// it to the log handler
+func (e *QueryEngine) handleOutput(line string, stream string) {
+	event := parseLogLine(line, stream)
 
-			log.Println(string(contents))
+	if stream == "stderr" {
+		e.mu.Lock()
+		e.recordStderr(line)
+		// top level messages are errors which e.g. prevent the engine from starting
+		if event.IsPanic || event.Message != "" && event.Level == "error" {
+			e.lastEngineError = event.Message
 		}
-	}()
+		e.mu.Unlock()
+	}
 
-	return nil
+	switch event.Level {
+	case "error", "warn":
+		logger.Info.Printf("query engine %s: %s", event.Level, event.Message)
+	default:
+		logger.Debug.Printf("query engine %s: %s", event.Level, event.Message)
+	}
+
+	if e.options.OnLog != nil {
+		e.options.OnLog(event)
+	}
 }
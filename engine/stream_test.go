@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected LogEvent
+	}{{
+		name: "log line",
+		line: `{"timestamp":"2026-01-02T03:04:05Z","level":"WARN","fields":{"message":"slow query","duration_ms":1500},"target":"quaint::connector::metrics"}`,
+		expected: LogEvent{
+			Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Level:   "warn",
+			Message: "slow query",
+			Target:  "quaint::connector::metrics",
+			Fields:  map[string]interface{}{"message": "slow query", "duration_ms": float64(1500)},
+		},
+	}, {
+		name: "panic",
+		line: `{"is_panic":true,"message":"index out of bounds","backtrace":"..."}`,
+		expected: LogEvent{
+			Level:   "error",
+			Message: "index out of bounds",
+			IsPanic: true,
+		},
+	}, {
+		name: "startup error",
+		line: `{"is_panic":false,"message":"Error in datamodel"}`,
+		expected: LogEvent{
+			Level:   "error",
+			Message: "Error in datamodel",
+		},
+	}, {
+		name: "plain text",
+		line: `listening on port 1234`,
+		expected: LogEvent{
+			Level:   "info",
+			Message: "listening on port 1234",
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := parseLogLine(tt.line, "stderr")
+			if tt.expected.Time.IsZero() {
+				actual.Time = time.Time{}
+			}
+			tt.expected.Stream = "stderr"
+			tt.expected.Raw = tt.line
+			massert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestQueryEngine_streamOutput(t *testing.T) {
+	var events []LogEvent
+	e := NewQueryEngine("", false, "[]", "", WithLogHandler(func(event LogEvent) {
+		events = append(events, event)
+	}))
+
+	output := strings.Join([]string{
+		`{"timestamp":"2026-01-02T03:04:05Z","level":"INFO","fields":{"message":"Started query engine"},"target":"query_engine::server"}`,
+		``,
+		`{"is_panic":true,"message":"index out of bounds"}`,
+	}, "\n")
+	<-e.streamOutput(strings.NewReader(output), "stderr")
+
+	massert.Equal(t, 2, len(events))
+	massert.Equal(t, "Started query engine", events[0].Message)
+	massert.Equal(t, true, events[1].IsPanic)
+	massert.Equal(t, "index out of bounds", e.lastEngineError)
+}
+
+func TestQueryEngine_streamOutput_longLines(t *testing.T) {
+	var events []LogEvent
+	e := NewQueryEngine("", false, "[]", "", WithLogHandler(func(event LogEvent) {
+		events = append(events, event)
+	}))
+
+	long := strings.Repeat("a", 1<<20)
+	output := strings.Join([]string{
+		`{"level":"INFO","fields":{"message":"` + long + `"}}`,
+		`after`,
+	}, "\r\n")
+	<-e.streamOutput(strings.NewReader(output), "stdout")
+
+	massert.Equal(t, 2, len(events))
+	massert.Equal(t, long, events[0].Message)
+	massert.Equal(t, "after", events[1].Message)
+}
+
+func TestQueryEngine_capturesQueryLogs(t *testing.T) {
+	logs := make(chan LogEvent, 10)
+	e := connectFakeEngine(t,
+		WithRestartPolicy(RestartPolicy{}),
+		WithLogLevel("info"),
+		WithLogHandler(func(event LogEvent) {
+			logs <- event
+		}),
+	)
+	defer func() {
+		_ = e.Disconnect()
+	}()
+
+	var response protocol.GQLResponse
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-logs:
+		massert.Equal(t, "stdout", event.Stream)
+		massert.Equal(t, "info", event.Level)
+		massert.Equal(t, "SELECT 1", event.Message)
+		massert.Equal(t, float64(1), event.Fields["duration_ms"])
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for query log")
+	}
+}
+
+func TestQueryEngine_startupTimeout(t *testing.T) {
+	t.Setenv(fakeEngineEnv, "true")
+	t.Setenv(fakeEngineHangEnv, "true")
+
+	e := NewQueryEngine("", false, "[]", "", WithUnixSocket(false), WithStartupTimeout(300*time.Millisecond))
+	e.closed = make(chan interface{})
+
+	start := time.Now()
+	err := e.spawn(os.Args[0])
+
+	var startupErr *StartupError
+	if !errors.As(err, &startupErr) {
+		t.Fatalf("expected a StartupError, got %v", err)
+	}
+	massert.Equal(t, true, time.Since(start) < 5*time.Second)
+	massert.Equal(t, true, strings.HasPrefix(startupErr.Address, "http://localhost:"))
+	massert.Equal(t, []string{`{"timestamp":"2026-01-02T03:04:05Z","level":"INFO","fields":{"message":"loading schema"},"target":"query_engine"}`}, startupErr.Stderr)
+	massert.Equal(t, true, strings.Contains(err.Error(), "query engine was not ready after 300ms"))
+	massert.Equal(t, true, strings.Contains(err.Error(), "loading schema"))
+}
@@ -0,0 +1,259 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// EventType describes what happened to the query engine
+type EventType string
+
+const (
+	// EventEngineExited is emitted when the query engine process exited unexpectedly, e.g. because it crashed
+	EventEngineExited EventType = "engine_exited"
+
+	// EventEngineRestarted is emitted when the query engine was restarted successfully after it exited
+	EventEngineRestarted EventType = "engine_restarted"
+
+	// EventEngineRestartFailed is emitted when an attempt to restart the query engine failed
+	EventEngineRestartFailed EventType = "engine_restart_failed"
+
+	// EventQueryRetried is emitted when a read-only query which failed because the engine crashed is retried
+	EventQueryRetried EventType = "query_retried"
+)
+
+// Event describes a change of the query engine state, which can be observed with WithEventHandler, e.g. for metrics
+type Event struct {
+	Type EventType
+
+	// Time is when the event happened
+	Time time.Time
+
+	// Attempt is the number of the restart attempt, starting at 1, for restart events
+	Attempt int
+
+	// Err is the reason why the engine exited or why a restart failed
+	Err error
+}
+
+// RestartPolicy configures how a query engine which exited unexpectedly is restarted
+type RestartPolicy struct {
+	// MaxAttempts is the maximum number of consecutive restart attempts; 0 disables restarts
+	MaxAttempts int
+
+	// Backoff is the delay before the first attempt, which is doubled after each failed attempt
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+
+	// RetryReads retries read-only queries which failed because the engine crashed, once it was restarted.
+	// Mutations are never retried, as they may have been applied before the crash.
+	RetryReads bool
+}
+
+// DefaultRestartPolicy is used when no restart policy was set
+var DefaultRestartPolicy = RestartPolicy{
+	MaxAttempts: 5,
+	Backoff:     100 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+	RetryReads:  true,
+}
+
+// crashDetectionTimeout is how long to wait for the engine process to exit after a request failed, to tell whether
+// the request failed because the engine crashed
+const crashDetectionTimeout = 500 * time.Millisecond
+
+// process is a running query engine process
+type process struct {
+	cmd *exec.Cmd
+
+	// exited is closed when the process exited
+	exited chan struct{}
+
+	// err holds the result of waiting for the process and must only be read after exited is closed
+	err error
+}
+
+func (e *QueryEngine) emit(event Event) {
+	if e.options.OnEvent == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	e.options.OnEvent(event)
+}
+
+// supervise restarts the query engine whenever it exits unexpectedly, until Disconnect is called
+func (e *QueryEngine) supervise(file string, proc *process) {
+	for {
+		select {
+		case <-e.closed:
+			return
+		case <-proc.exited:
+		}
+
+		// the process may exit because Disconnect was called
+		select {
+		case <-e.closed:
+			return
+		default:
+		}
+
+		logger.Info.Printf("query engine exited unexpectedly: %v", proc.err)
+		e.emit(Event{Type: EventEngineExited, Err: proc.err})
+
+		proc = e.restart(file)
+		if proc == nil {
+			return
+		}
+	}
+}
+
+// restart spawns the query engine again with exponential backoff.
+// It returns nil if all attempts failed or if Disconnect was called in the meantime.
+func (e *QueryEngine) restart(file string) *process {
+	policy := e.options.restartPolicy()
+	backoff := policy.Backoff
+
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		select {
+		case <-e.closed:
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+
+		logger.Info.Printf("restarting query engine (attempt %d of %d)", attempt, policy.MaxAttempts)
+
+		e.mu.Lock()
+		e.lastEngineError = ""
+		e.mu.Unlock()
+		e.removeSocket()
+
+		if err := e.spawn(file); err != nil {
+			logger.Info.Printf("restarting query engine failed: %s", err)
+			e.emit(Event{Type: EventEngineRestartFailed, Attempt: attempt, Err: err})
+			continue
+		}
+
+		e.mu.Lock()
+		proc := e.proc
+		e.notifyRestarted()
+		select {
+		case <-e.closed:
+			// Disconnect was called while the engine was spawned, so it may have stopped the previous process only
+			e.mu.Unlock()
+			logger.Debug.Printf("engine was disconnected during the restart; stopping the new query engine")
+			_ = kill(proc.cmd.Process)
+			<-proc.exited
+			e.removeSocket()
+			return nil
+		default:
+		}
+		e.mu.Unlock()
+
+		logger.Info.Printf("query engine restarted")
+		e.emit(Event{Type: EventEngineRestarted, Attempt: attempt})
+
+		return proc
+	}
+
+	logger.Info.Printf("giving up restarting the query engine after %d attempts", policy.MaxAttempts)
+
+	e.mu.Lock()
+	e.notifyRestarted()
+	e.mu.Unlock()
+
+	return nil
+}
+
+// notifyRestarted wakes up requests waiting for a restart, whether it was successful or not; e.mu must be held
+func (e *QueryEngine) notifyRestarted() {
+	if e.restarted != nil {
+		close(e.restarted)
+	}
+	e.restarted = make(chan struct{})
+}
+
+// requestRetryingReads sends a query to the engine. If it is read-only and failed because the engine crashed, it is
+// sent again once the engine was restarted.
+func (e *QueryEngine) requestRetryingReads(ctx context.Context, payload interface{}) ([]byte, error) {
+	e.mu.Lock()
+	proc, restarted := e.proc, e.restarted
+	e.mu.Unlock()
+
+	body, err := e.Request(ctx, "POST", "/", payload, true)
+	if err == nil || ctx.Err() != nil || proc == nil || restarted == nil {
+		return body, err
+	}
+
+	policy := e.options.restartPolicy()
+	// interactive transactions don't survive a restart of the engine
+	if !policy.RetryReads || policy.MaxAttempts == 0 || !isReadOnly(payload) || transactionIDFromContext(ctx) != "" {
+		return body, err
+	}
+
+	var connErr *connectionError
+	if !errors.As(err, &connErr) {
+		return body, err
+	}
+
+	// the connection may fail slightly before the process exit is noticed
+	select {
+	case <-proc.exited:
+	case <-time.After(crashDetectionTimeout):
+		return body, err
+	}
+
+	logger.Info.Printf("query engine crashed during a read-only query; retrying once it was restarted")
+
+	select {
+	case <-restarted:
+	case <-e.closed:
+		return body, err
+	case <-ctx.Done():
+		return body, err
+	}
+
+	e.mu.Lock()
+	current := e.proc
+	e.mu.Unlock()
+
+	// the restart failed
+	if current == proc {
+		return body, err
+	}
+
+	e.emit(Event{Type: EventQueryRetried})
+
+	return e.Request(ctx, "POST", "/", payload, true)
+}
+
+// isReadOnly returns whether the payload is a single query which doesn't modify data
+func isReadOnly(payload interface{}) bool {
+	request, ok := payload.(protocol.GQLRequest)
+	return ok && strings.HasPrefix(request.Query, "query ")
+}
+
+// currentProcess returns the running query engine process
+func (e *QueryEngine) currentProcess() (*process, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.proc == nil {
+		return nil, fmt.Errorf("query engine is not running")
+	}
+	return e.proc, nil
+}
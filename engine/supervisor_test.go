@@ -0,0 +1,391 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeEngineEnv makes the test binary act as a query engine, so that the process handling can be tested
+// without downloading the real query engine
+const fakeEngineEnv = "PRISMA_CLIENT_GO_FAKE_QUERY_ENGINE"
+
+// fakeEngineIgnoreTermEnv makes the fake engine ignore SIGTERM, so that it has to be killed
+const fakeEngineIgnoreTermEnv = "PRISMA_CLIENT_GO_FAKE_QUERY_ENGINE_IGNORE_TERM"
+
+// fakeEngineCrashFileEnv points to a file; if it exists, the fake engine removes it and crashes on the next query
+const fakeEngineCrashFileEnv = "PRISMA_CLIENT_GO_FAKE_QUERY_ENGINE_CRASH_FILE"
+
+// fakeEngineHangEnv makes the fake engine write a log line to stderr and never become ready
+const fakeEngineHangEnv = "PRISMA_CLIENT_GO_FAKE_QUERY_ENGINE_HANG"
+
+// fakeEngineStartupLogEnv makes the fake engine write a log line to stdout before it listens
+const fakeEngineStartupLogEnv = "PRISMA_CLIENT_GO_FAKE_QUERY_ENGINE_STARTUP_LOG"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeEngineEnv) != "" {
+		runFakeEngine(os.Args[1:])
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeEngine serves the readiness check and answers all other requests with an empty result
+func runFakeEngine(args []string) {
+	if os.Getenv(fakeEngineIgnoreTermEnv) != "" {
+		signal.Ignore(syscall.SIGTERM)
+	}
+
+	if os.Getenv(fakeEngineHangEnv) != "" {
+		fmt.Fprintln(os.Stderr, `{"timestamp":"2026-01-02T03:04:05Z","level":"INFO","fields":{"message":"loading schema"},"target":"query_engine"}`)
+		select {}
+	}
+
+	if os.Getenv(fakeEngineStartupLogEnv) != "" {
+		fmt.Println(`{"timestamp":"2026-01-02T03:04:05Z","level":"INFO","fields":{"message":"starting"},"target":"query_engine"}`)
+	}
+
+	var l net.Listener
+	var err error
+	for i := 0; i < len(args)-1; i++ {
+		switch args[i] {
+		case "-p":
+			l, err = net.Listen("tcp", "localhost:"+args[i+1])
+		case "--unix-path":
+			l, err = net.Listen("unix", args[i+1])
+		}
+	}
+	if err != nil || l == nil {
+		fmt.Fprintf(os.Stderr, `{"is_panic":false,"message":"could not listen: %v"}`+"\n", err)
+		os.Exit(1)
+	}
+	_ = http.Serve(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "slow") {
+			time.Sleep(300 * time.Millisecond)
+		}
+		if file := os.Getenv(fakeEngineCrashFileEnv); file != "" {
+			if err := os.Remove(file); err == nil {
+				os.Exit(2)
+			}
+		}
+		if os.Getenv("PRISMA_LOG_QUERIES") == "y" {
+			fmt.Println(`{"timestamp":"2026-01-02T03:04:05Z","level":"INFO","fields":{"message":"SELECT 1","duration_ms":1},"target":"quaint::connector::metrics"}`)
+		}
+		_, _ = w.Write([]byte(`{"data":{"result":{}}}`))
+	}))
+}
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Event
+	notify chan EventType
+}
+
+func newEventRecorder() *eventRecorder {
+	return &eventRecorder{notify: make(chan EventType, 10)}
+}
+
+func (r *eventRecorder) handle(event Event) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+	r.notify <- event.Type
+}
+
+func (r *eventRecorder) await(t *testing.T, expected EventType) {
+	t.Helper()
+	select {
+	case actual := <-r.notify:
+		massert.Equal(t, expected, actual)
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout waiting for event %s", expected)
+	}
+}
+
+func connectFakeEngine(t *testing.T, options ...Option) *QueryEngine {
+	t.Helper()
+	t.Setenv(fakeEngineEnv, "true")
+
+	e := NewQueryEngine("", false, "[]", "", options...)
+	e.closed = make(chan interface{})
+	e.restarted = make(chan struct{})
+	if err := e.spawn(os.Args[0]); err != nil {
+		t.Fatal(err)
+	}
+	e.connected = true
+	if e.options.restartPolicy().MaxAttempts > 0 {
+		go e.supervise(os.Args[0], e.proc)
+	}
+	return e
+}
+
+func TestQueryEngine_restartsAfterCrash(t *testing.T) {
+	for _, unixSocket := range []bool{true, false} {
+		t.Run(fmt.Sprintf("unix socket %t", unixSocket), func(t *testing.T) {
+			if unixSocket && !unixSocketDefault {
+				t.Skip("unix sockets are not used on this platform")
+			}
+
+			events := newEventRecorder()
+			e := connectFakeEngine(t,
+				WithUnixSocket(unixSocket),
+				WithRestartPolicy(RestartPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+				WithEventHandler(events.handle),
+			)
+
+			first, err := e.currentProcess()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// simulate a crash
+			if err := first.cmd.Process.Kill(); err != nil {
+				t.Fatal(err)
+			}
+
+			events.await(t, EventEngineExited)
+			events.await(t, EventEngineRestarted)
+
+			second, err := e.currentProcess()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first == second {
+				t.Fatal("expected a new process")
+			}
+
+			body, err := e.Request(context.Background(), "GET", "/status", map[string]interface{}{}, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, `{"status":"ok"}`, string(body))
+
+			if err := e.Disconnect(); err != nil && !strings.Contains(err.Error(), "signal") {
+				t.Fatal(err)
+			}
+
+			// a deliberate disconnect must not restart the engine
+			select {
+			case event := <-events.notify:
+				t.Fatalf("unexpected event %s after disconnect", event)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestQueryEngine_restartRacingDisconnect(t *testing.T) {
+	var e *QueryEngine
+	var once sync.Once
+	events := newEventRecorder()
+	e = connectFakeEngine(t,
+		WithRestartPolicy(RestartPolicy{MaxAttempts: 1, Backoff: time.Millisecond}),
+		WithEventHandler(events.handle),
+		WithLogHandler(func(event LogEvent) {
+			// Disconnect is called while the replacement engine starts
+			if event.Message == "starting" {
+				once.Do(func() { close(e.closed) })
+			}
+		}),
+	)
+	first, err := e.currentProcess()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = first.cmd.Process.Kill()
+		<-first.exited
+	}()
+
+	t.Setenv(fakeEngineStartupLogEnv, "true")
+
+	if proc := e.restart(os.Args[0]); proc != nil {
+		t.Fatal("expected no process after a disconnect")
+	}
+
+	second, err := e.currentProcess()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatal("expected a new process")
+	}
+	select {
+	case <-second.exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the new engine process was not stopped")
+	}
+
+	select {
+	case event := <-events.notify:
+		t.Fatalf("unexpected event %s", event)
+	default:
+	}
+}
+
+func TestQueryEngine_restartDisabled(t *testing.T) {
+	events := newEventRecorder()
+	e := connectFakeEngine(t,
+		WithRestartPolicy(RestartPolicy{}),
+		WithEventHandler(events.handle),
+	)
+	defer e.removeSocket()
+
+	proc, err := e.currentProcess()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proc.cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	<-proc.exited
+
+	select {
+	case event := <-events.notify:
+		t.Fatalf("unexpected event %s", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func crashOnNextQuery(t *testing.T) {
+	t.Helper()
+	file := path.Join(t.TempDir(), "crash")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(fakeEngineCrashFileEnv, file)
+}
+
+func TestQueryEngine_retriesReadsAfterCrash(t *testing.T) {
+	crashOnNextQuery(t)
+
+	events := newEventRecorder()
+	e := connectFakeEngine(t,
+		WithRestartPolicy(RestartPolicy{MaxAttempts: 3, Backoff: time.Millisecond, RetryReads: true}),
+		WithEventHandler(events.handle),
+	)
+	defer e.Disconnect() //nolint:errcheck
+
+	var result map[string]interface{}
+	err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {result: findManyUser {id }}"}, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events.await(t, EventEngineExited)
+	events.await(t, EventEngineRestarted)
+	events.await(t, EventQueryRetried)
+}
+
+func TestQueryEngine_doesNotRetryMutations(t *testing.T) {
+	crashOnNextQuery(t)
+
+	events := newEventRecorder()
+	e := connectFakeEngine(t,
+		WithRestartPolicy(RestartPolicy{MaxAttempts: 3, Backoff: time.Millisecond, RetryReads: true}),
+		WithEventHandler(events.handle),
+	)
+	defer e.Disconnect() //nolint:errcheck
+
+	var result map[string]interface{}
+	err := e.Do(context.Background(), protocol.GQLRequest{Query: "mutation {result: deleteManyUser {count }}"}, &result)
+	if err == nil {
+		t.Fatal("expected mutation to fail")
+	}
+
+	events.await(t, EventEngineExited)
+	events.await(t, EventEngineRestarted)
+
+	select {
+	case event := <-events.notify:
+		t.Fatalf("unexpected event %s", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestQueryEngine_doesNotRetryReadsWhenDisabled(t *testing.T) {
+	crashOnNextQuery(t)
+
+	e := connectFakeEngine(t,
+		WithRestartPolicy(RestartPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+	)
+	defer e.Disconnect() //nolint:errcheck
+
+	var result map[string]interface{}
+	err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {result: findManyUser {id }}"}, &result)
+	if err == nil {
+		t.Fatal("expected query to fail")
+	}
+}
+
+func TestQueryEngine_disconnectWaitsForInflightQueries(t *testing.T) {
+	e := connectFakeEngine(t)
+
+	done := make(chan error)
+	go func() {
+		var result map[string]interface{}
+		done <- e.Do(context.Background(), protocol.GQLRequest{Query: "query {result: findManyUser(where:{name:\"slow\"}) {id }}"}, &result)
+	}()
+
+	// wait until the query was sent
+	time.Sleep(100 * time.Millisecond)
+
+	if err := e.DisconnectContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("in-flight query failed: %s", err)
+	}
+
+	var result map[string]interface{}
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {result: findManyUser {id }}"}, &result); err == nil {
+		t.Fatal("expected queries after disconnect to fail")
+	}
+}
+
+func TestQueryEngine_disconnectKillsUnresponsiveEngine(t *testing.T) {
+	t.Setenv(fakeEngineIgnoreTermEnv, "true")
+
+	e := connectFakeEngine(t)
+
+	proc, err := e.currentProcess()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := e.DisconnectContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("disconnect took too long")
+	}
+
+	select {
+	case <-proc.exited:
+	default:
+		t.Fatal("expected engine process to have exited")
+	}
+}
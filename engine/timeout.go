@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+type queryTimeoutKey struct{}
+
+type queryTimeout struct {
+	timeout   time.Duration
+	statement bool
+}
+
+// WithQueryTimeout returns a context which bounds each query sent with it to timeout, independent of the deadline of
+// ctx. If statement is true, the query engine binary also sets the statement_timeout of the query, so that a PostgreSQL
+// or CockroachDB database cancels the statement instead of finishing it after the request was abandoned.
+func WithQueryTimeout(ctx context.Context, timeout time.Duration, statement bool) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, queryTimeout{
+		timeout:   timeout,
+		statement: statement,
+	})
+}
+
+// queryTimeoutFromContext returns the query timeout of ctx, if any
+func queryTimeoutFromContext(ctx context.Context) (queryTimeout, bool) {
+	t, ok := ctx.Value(queryTimeoutKey{}).(queryTimeout)
+	return t, ok && t.timeout > 0
+}
+
+// withQueryDeadline applies the query timeout of ctx, if any, to the request which is about to be sent
+func withQueryDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	t, ok := queryTimeoutFromContext(ctx)
+	if !ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, t.timeout)
+}
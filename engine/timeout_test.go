@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQueryEngine_queryTimeout(t *testing.T) {
+	e, s := startStatementEngine(t)
+
+	var result struct {
+		ID string `json:"id"`
+	}
+
+	t.Run("statement timeout", func(t *testing.T) {
+		s.reset()
+		ctx := WithQueryTimeout(context.Background(), 2*time.Second, true)
+		if err := e.Do(ctx, protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+			t.Fatal(err)
+		}
+		requests, queries := s.recorded()
+		massert.Equal(t, []string{
+			"/transaction/start ",
+			"/ tx1",
+			"/ tx1",
+			"/transaction/tx1/commit ",
+		}, requests)
+		massert.Equal(t, []string{
+			`{"timeout":3000}`,
+			`{"query":"mutation {result: executeRaw(query: \"SET LOCAL statement_timeout = 2000\", parameters: \"[]\")}","variables":{}}`,
+			`{"query":"query {}","variables":null}`,
+		}, queries)
+	})
+
+	t.Run("within transaction", func(t *testing.T) {
+		s.reset()
+		ctx := WithQueryTimeout(context.Background(), 2*time.Second, true)
+		if err := NewTransactionEngine(e, "tx2").Do(ctx, protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+			t.Fatal(err)
+		}
+		requests, _ := s.recorded()
+		massert.Equal(t, []string{"/ tx2"}, requests)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		s.reset()
+		ctx := WithQueryTimeout(context.Background(), 50*time.Millisecond, false)
+		err := e.Do(ctx, protocol.GQLRequest{Query: "query { slow }"}, &result)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		requests, _ := s.recorded()
+		massert.Equal(t, []string{"/ "}, requests)
+	})
+
+	t.Run("rollback after deadline", func(t *testing.T) {
+		s.reset()
+		ctx := WithQueryTimeout(context.Background(), 50*time.Millisecond, true)
+		err := e.Do(ctx, protocol.GQLRequest{Query: "query { slow }"}, &result)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		requests, _ := s.recorded()
+		massert.Equal(t, []string{
+			"/transaction/start ",
+			"/ tx1",
+			"/ tx1",
+			"/transaction/tx1/rollback ",
+		}, requests)
+	})
+}
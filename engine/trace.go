@@ -0,0 +1,229 @@
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// Span is a tracing span recorded by the client or the query engine
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+}
+
+// Tracer links queries to the spans of the application, e.g. by adapting an OpenTelemetry tracer
+type Tracer interface {
+	// TraceParent returns the W3C traceparent header of the current span of ctx, or an empty string if ctx is not
+	// traced
+	TraceParent(ctx context.Context) string
+
+	// Export is called after each traced query with the spans recorded by the client and the query engine. The spans
+	// are children of the span returned by TraceParent.
+	Export(ctx context.Context, spans []Span)
+}
+
+// WithTracer propagates the trace context to the query engine and exports the spans it recorded, so that a single
+// trace shows the time spent in the client, in the query engine and in the database. It is supported by the query
+// engine binary, the library engine and the data proxy.
+func WithTracer(tracer Tracer) Option {
+	return func(o *Options) {
+		o.Tracer = tracer
+	}
+}
+
+type operationKey struct{}
+
+type operation struct {
+	model  string
+	method string
+	start  time.Time
+}
+
+// WithOperation annotates ctx with the query which is about to be sent, so that its build time and name are included
+// in the traced spans
+func WithOperation(ctx context.Context, model, method string, start time.Time) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation{
+		model:  model,
+		method: method,
+		start:  start,
+	})
+}
+
+type traceParentKey struct{}
+
+// traceParentFromContext returns the traceparent header which should be sent to the engine
+func traceParentFromContext(ctx context.Context) string {
+	traceParent, _ := ctx.Value(traceParentKey{}).(string)
+	return traceParent
+}
+
+// traceHeaders returns the headers which make the engine record the spans of a traced query as children of the
+// client span and include them in its response
+func traceHeaders(ctx context.Context) map[string]string {
+	traceParent := traceParentFromContext(ctx)
+	if traceParent == "" {
+		return nil
+	}
+	return map[string]string{
+		"traceparent":         traceParent,
+		"X-capture-telemetry": "spans",
+	}
+}
+
+// trace records the client span of a single query
+type trace struct {
+	tracer   Tracer
+	traceID  string
+	parentID string
+	spanID   string
+	op       operation
+	sent     time.Time
+}
+
+// startTrace starts a client span if a tracer is configured and ctx is traced. The returned context carries the
+// traceparent which makes the engine spans children of the client span.
+func (o Options) startTrace(ctx context.Context) (context.Context, *trace) {
+	if o.Tracer == nil {
+		return ctx, nil
+	}
+
+	// version-traceid-parentid-flags
+	parts := strings.Split(o.Tracer.TraceParent(ctx), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx, nil
+	}
+
+	t := &trace{
+		tracer:   o.Tracer,
+		traceID:  parts[1],
+		parentID: parts[2],
+		spanID:   newSpanID(),
+		sent:     time.Now(),
+	}
+	t.op, _ = ctx.Value(operationKey{}).(operation)
+	if t.op.start.IsZero() {
+		t.op.start = t.sent
+	}
+
+	traceParent := strings.Join([]string{parts[0], t.traceID, t.spanID, parts[3]}, "-")
+	return context.WithValue(ctx, traceParentKey{}, traceParent), t
+}
+
+// finish exports the client span and the engine spans contained in the response body
+func (t *trace) finish(ctx context.Context, body []byte, err error) {
+	if t == nil {
+		return
+	}
+
+	name := "prisma:client:operation"
+	attributes := map[string]interface{}{}
+	if t.op.method != "" {
+		attributes["method"] = t.op.method
+	}
+	if t.op.model != "" {
+		attributes["model"] = t.op.model
+	}
+	if err != nil {
+		attributes["error"] = err.Error()
+	}
+
+	spans := []Span{{
+		TraceID:      t.traceID,
+		SpanID:       t.spanID,
+		ParentSpanID: t.parentID,
+		Name:         name,
+		StartTime:    t.op.start,
+		EndTime:      time.Now(),
+		Attributes:   attributes,
+	}, {
+		TraceID:      t.traceID,
+		SpanID:       newSpanID(),
+		ParentSpanID: t.spanID,
+		Name:         "prisma:client:serialize",
+		StartTime:    t.op.start,
+		EndTime:      t.sent,
+	}}
+
+	engineSpans, parseErr := parseEngineSpans(body)
+	if parseErr != nil {
+		logger.Debug.Printf("could not parse engine spans: %s", parseErr)
+	}
+	spans = append(spans, engineSpans...)
+
+	t.tracer.Export(ctx, spans)
+}
+
+type engineSpan struct {
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id"`
+	Name         string                 `json:"name"`
+	StartTime    spanTime               `json:"start_time"`
+	EndTime      spanTime               `json:"end_time"`
+	Attributes   map[string]interface{} `json:"attributes"`
+}
+
+// spanTime is either a [seconds, nanoseconds] tuple or a RFC3339 timestamp
+type spanTime time.Time
+
+func (t *spanTime) UnmarshalJSON(b []byte) error {
+	var tuple [2]int64
+	if err := json.Unmarshal(b, &tuple); err == nil {
+		*t = spanTime(time.Unix(tuple[0], tuple[1]))
+		return nil
+	}
+	var str time.Time
+	if err := json.Unmarshal(b, &str); err != nil {
+		return fmt.Errorf("invalid span time %s", b)
+	}
+	*t = spanTime(str)
+	return nil
+}
+
+// parseEngineSpans returns the spans which the query engine includes in responses when telemetry is captured
+func parseEngineSpans(body []byte) ([]Span, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	var response struct {
+		Extensions struct {
+			Traces []engineSpan `json:"traces"`
+		} `json:"extensions"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	spans := make([]Span, 0, len(response.Extensions.Traces))
+	for _, s := range response.Extensions.Traces {
+		spans = append(spans, Span{
+			TraceID:      s.TraceID,
+			SpanID:       s.SpanID,
+			ParentSpanID: s.ParentSpanID,
+			Name:         s.Name,
+			StartTime:    time.Time(s.StartTime),
+			EndTime:      time.Time(s.EndTime),
+			Attributes:   s.Attributes,
+		})
+	}
+	return spans, nil
+}
+
+func newSpanID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
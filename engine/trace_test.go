@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type recordingTracer struct {
+	traceParent string
+	spans       []Span
+}
+
+func (t *recordingTracer) TraceParent(context.Context) string {
+	return t.traceParent
+}
+
+func (t *recordingTracer) Export(_ context.Context, spans []Span) {
+	t.spans = append(t.spans, spans...)
+}
+
+const (
+	testTraceID  = "0af7651916cd43dd8448eb211c80319c"
+	testParentID = "b7ad6b7169203331"
+)
+
+func TestQueryEngine_tracing(t *testing.T) {
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		headers = r.Header
+		spanID := strings.Split(r.Header.Get("traceparent"), "-")[2]
+		_, _ = fmt.Fprintf(w, `{"data":{"result":{}},"extensions":{"traces":[{"trace_id":%q,"span_id":"1111111111111111","parent_span_id":%q,"name":"prisma:engine","start_time":[1700000000,5],"end_time":[1700000001,0],"attributes":{}},{"trace_id":%q,"span_id":"2222222222222222","parent_span_id":"1111111111111111","name":"prisma:engine:db_query","start_time":[1700000000,10],"end_time":[1700000000,20],"attributes":{"db.statement":"SELECT 1"}}]}}`, testTraceID, spanID, testTraceID)
+	}))
+	defer srv.Close()
+
+	tracer := &recordingTracer{traceParent: "00-" + testTraceID + "-" + testParentID + "-01"}
+	e := NewQueryEngine("", false, "[]", "", WithEngineURL(srv.URL), WithTracer(tracer))
+	if err := e.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = e.Disconnect()
+	}()
+
+	start := time.Now()
+	ctx := WithOperation(context.Background(), "User", "findMany", start)
+	var result interface{}
+	if err := e.Do(ctx, protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, 4, len(tracer.spans))
+	operation, serialize, engine, query := tracer.spans[0], tracer.spans[1], tracer.spans[2], tracer.spans[3]
+
+	// the engine span is a child of the client span, which is a child of the application span
+	massert.Equal(t, "00-"+testTraceID+"-"+operation.SpanID+"-01", headers.Get("traceparent"))
+	massert.Equal(t, "spans", headers.Get("X-capture-telemetry"))
+	massert.Equal(t, "prisma:client:operation", operation.Name)
+	massert.Equal(t, testTraceID, operation.TraceID)
+	massert.Equal(t, testParentID, operation.ParentSpanID)
+	massert.Equal(t, start, operation.StartTime)
+	massert.Equal(t, map[string]interface{}{"model": "User", "method": "findMany"}, operation.Attributes)
+
+	massert.Equal(t, "prisma:client:serialize", serialize.Name)
+	massert.Equal(t, operation.SpanID, serialize.ParentSpanID)
+
+	massert.Equal(t, "prisma:engine", engine.Name)
+	massert.Equal(t, operation.SpanID, engine.ParentSpanID)
+	massert.Equal(t, time.Unix(1700000000, 5), engine.StartTime)
+
+	massert.Equal(t, "prisma:engine:db_query", query.Name)
+	massert.Equal(t, "SELECT 1", query.Attributes["db.statement"])
+}
+
+func TestQueryEngine_tracingUntraced(t *testing.T) {
+	var traceParent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" {
+			traceParent = r.Header.Get("traceparent")
+		}
+		_, _ = w.Write([]byte(`{"status":"ok","data":{"result":{}}}`))
+	}))
+	defer srv.Close()
+
+	tracer := &recordingTracer{}
+	e := NewQueryEngine("", false, "[]", "", WithEngineURL(srv.URL), WithTracer(tracer))
+	if err := e.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = e.Disconnect()
+	}()
+
+	var result interface{}
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, "", traceParent)
+	massert.Equal(t, 0, len(tracer.spans))
+}
+
+func TestLibraryEngine_tracing(t *testing.T) {
+	lib := &fakeLibrary{
+		response: `{"data":{"result":{}},"extensions":{"traces":[{"trace_id":"` + testTraceID + `","span_id":"1111111111111111","name":"prisma:engine","start_time":[1700000000,5],"end_time":[1700000001,0]}]}}`,
+	}
+	tracer := &recordingTracer{traceParent: "00-" + testTraceID + "-" + testParentID + "-01"}
+	e := newConnectedLibraryEngine(lib, WithTracer(tracer))
+
+	var result interface{}
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, 3, len(tracer.spans))
+	operation := tracer.spans[0]
+	massert.Equal(t, "prisma:client:operation", operation.Name)
+	massert.Equal(t, "prisma:engine", tracer.spans[2].Name)
+	massert.Equal(t, []string{`{"X-capture-telemetry":"spans","traceparent":"00-` + testTraceID + `-` + operation.SpanID + `-01"}`}, lib.traces)
+
+	// untraced queries send no trace headers
+	tracer.traceParent = ""
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "{}", lib.traces[1])
+	massert.Equal(t, 3, len(tracer.spans))
+}
+
+func TestDataProxyEngine_tracing(t *testing.T) {
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		_, _ = fmt.Fprintf(w, `{"data":{"result":{}},"extensions":{"traces":[{"trace_id":%q,"span_id":"1111111111111111","name":"prisma:engine","start_time":[1700000000,5],"end_time":[1700000001,0]}]}}`, testTraceID)
+	}))
+	defer srv.Close()
+
+	tracer := &recordingTracer{traceParent: "00-" + testTraceID + "-" + testParentID + "-01"}
+	e := NewDataProxyEngine("", "prisma://localhost/?api_key=abc", WithTracer(tracer))
+	e.url = srv.URL
+
+	var result interface{}
+	if err := e.Do(context.Background(), protocol.GQLRequest{Query: "query {}"}, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, 3, len(tracer.spans))
+	operation := tracer.spans[0]
+	massert.Equal(t, "00-"+testTraceID+"-"+operation.SpanID+"-01", headers.Get("traceparent"))
+	massert.Equal(t, "spans", headers.Get("X-capture-telemetry"))
+	massert.Equal(t, "prisma:engine", tracer.spans[2].Name)
+}
+
+func TestSpanTime_UnmarshalJSON(t *testing.T) {
+	var s spanTime
+	if err := s.UnmarshalJSON([]byte(`"2026-01-02T03:04:05Z"`)); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), time.Time(s))
+}
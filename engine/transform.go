@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -8,14 +10,31 @@ import (
 	"github.com/steebchen/prisma-client-go/logger"
 )
 
+type typedResultsKey struct{}
+
+// WithTypedResults returns a context which makes the engine return the values of raw queries as the query engine sends
+// them, i.e. as {"prisma__type": ..., "prisma__value": ...} objects in the order of the columns, instead of plain JSON
+// values, e.g. to convert them depending on their type
+func WithTypedResults(ctx context.Context) context.Context {
+	return context.WithValue(ctx, typedResultsKey{}, true)
+}
+
+// typedResultsFromContext returns whether ctx was created with WithTypedResults
+func typedResultsFromContext(ctx context.Context) bool {
+	typed, _ := ctx.Value(typedResultsKey{}).(bool)
+	return typed
+}
+
 // transformResponse for raw queries
 // transforms all custom prisma types into native go types, such as
 // [{"prisma__type":"string","prisma__value":"asdf"},{"prisma__type":"null","prisma__value":null}]
 // ->
 // ["asdf", null]
+//
+// numbers are decoded as json.Number, so values such as int64 beyond 2^53 are not truncated to float64
 func transformResponse(data []byte) ([]byte, error) {
 	var m interface{}
-	if err := json.Unmarshal(data, &m); err != nil {
+	if err := unmarshalNumbers(data, &m); err != nil {
 		return nil, err
 	}
 
@@ -113,3 +132,10 @@ func forEachValue(obj *interface{}, handler func(*string, *int, *interface{}) (i
 	*obj = n
 	// Do nothing for primitives since the handler got them.
 }
+
+// unmarshalNumbers unmarshals data like json.Unmarshal, but keeps numbers as json.Number instead of float64
+func unmarshalNumbers(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"reflect"
 	"testing"
 )
@@ -49,6 +50,18 @@ func Test_transformResponse(t *testing.T) {
 			data: []byte(`5`),
 		},
 		want: []byte(`5`),
+	}, {
+		name: "native number beyond 2^53",
+		args: args{
+			data: []byte(`[9007199254740993,-9223372036854775808]`),
+		},
+		want: []byte(`[9007199254740993,-9223372036854775808]`),
+	}, {
+		name: "bigint beyond 2^53",
+		args: args{
+			data: []byte(`[{"id":{"prisma__type":"bigint","prisma__value":9223372036854775807}}]`),
+		},
+		want: []byte(`[{"id":9223372036854775807}]`),
 	}, { // edge cases which are specifically handled
 		name: "bytes",
 		args: args{
@@ -86,3 +99,12 @@ func Test_transformResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestWithTypedResults(t *testing.T) {
+	if typedResultsFromContext(context.Background()) {
+		t.Error("expected results to be transformed by default")
+	}
+	if !typedResultsFromContext(WithTypedResults(context.Background())) {
+		t.Error("expected typed results")
+	}
+}
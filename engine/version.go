@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/binaries"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// VersionError is returned by Connect when the query engine's version doesn't match the version the client was
+// generated for, e.g. because the generated code was updated but an old query engine binary is still on disk
+type VersionError struct {
+	// Engine is the path or url of the query engine
+	Engine string
+
+	// Expected is the engine commit the client was generated for
+	Expected string
+
+	// Actual is the engine commit reported by the query engine
+	Actual string
+}
+
+func (e *VersionError) Error() string {
+	hint := "refetch the query engine with `go run github.com/steebchen/prisma-client-go prefetch`"
+	if e.Actual == binaries.EngineVersion {
+		// the engine matches the runtime, so the generated code is outdated
+		hint = "the client was generated with a different version of prisma-client-go; regenerate it with `go run github.com/steebchen/prisma-client-go generate`"
+	}
+	return fmt.Sprintf("expected query engine version `%s` but %s is `%s`: %s", e.Expected, e.Engine, e.Actual, hint)
+}
+
+// WithExpectedVersion sets the query engine commit the client was generated for, which is verified on Connect.
+// Defaults to the engine version of the prisma-client-go runtime.
+func WithExpectedVersion(commit string) Option {
+	return func(o *Options) {
+		o.ExpectedVersion = commit
+	}
+}
+
+func (o Options) expectedVersion() string {
+	if o.ExpectedVersion != "" {
+		return o.ExpectedVersion
+	}
+	return binaries.EngineVersion
+}
+
+// checkRemoteVersion compares the commit reported by an externally managed query engine with the expected version.
+// Engines which don't report their commit are accepted.
+func (e *QueryEngine) checkRemoteVersion(ctx context.Context, address string) error {
+	body, err := e.Request(ctx, "GET", "/server_info", map[string]interface{}{}, false)
+	if errors.Is(err, errNotFound) {
+		logger.Debug.Printf("remote query engine does not report its version; skipping version check")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("server info: %w", err)
+	}
+
+	var info struct {
+		Commit string `json:"commit"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return fmt.Errorf("could not unmarshal server info %s: %w", body, err)
+	}
+
+	if info.Commit == "" || info.Commit == e.options.expectedVersion() {
+		return nil
+	}
+
+	return &VersionError{
+		Engine:   address,
+		Expected: e.options.expectedVersion(),
+		Actual:   info.Commit,
+	}
+}
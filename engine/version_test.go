@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/binaries"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQueryEngine_remoteVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverInfo string
+		expected   error
+	}{{
+		name:       "matching",
+		serverInfo: `{"commit":"abc","version":"5.0.0"}`,
+	}, {
+		name:       "mismatch",
+		serverInfo: `{"commit":"def","version":"5.0.0"}`,
+		expected:   &VersionError{Expected: "abc", Actual: "def"},
+	}, {
+		name: "unsupported",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/status":
+					_, _ = w.Write([]byte(`{"status":"ok"}`))
+				case "/server_info":
+					if tt.serverInfo == "" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_, _ = w.Write([]byte(tt.serverInfo))
+				}
+			}))
+			defer srv.Close()
+
+			e := NewQueryEngine("", false, "[]", "", WithEngineURL(srv.URL), WithExpectedVersion("abc"))
+			err := e.Connect()
+
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var versionErr *VersionError
+			if !errors.As(err, &versionErr) {
+				t.Fatalf("expected a VersionError, got %v", err)
+			}
+			versionErr.Engine = ""
+			massert.Equal(t, tt.expected, versionErr)
+		})
+	}
+}
+
+func TestVersionError_Error(t *testing.T) {
+	massert.Equal(t,
+		"expected query engine version `abc` but ./query-engine is `def`: refetch the query engine with `go run github.com/steebchen/prisma-client-go prefetch`",
+		(&VersionError{Engine: "./query-engine", Expected: "abc", Actual: "def"}).Error(),
+	)
+	massert.Equal(t,
+		"expected query engine version `abc` but ./query-engine is `"+binaries.EngineVersion+"`: the client was generated with a different version of prisma-client-go; regenerate it with `go run github.com/steebchen/prisma-client-go generate`",
+		(&VersionError{Engine: "./query-engine", Expected: "abc", Actual: binaries.EngineVersion}).Error(),
+	)
+}
@@ -1,6 +1,11 @@
 package dmmf
 
 import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
 	"github.com/steebchen/prisma-client-go/generator/types"
 )
 
@@ -12,6 +17,10 @@ const (
 	FieldKindScalar FieldKind = "scalar"
 	FieldKindObject FieldKind = "object"
 	FieldKindEnum   FieldKind = "enum"
+
+	// FieldKindComposite is not sent by Prisma, which uses "object" for both relations and composite types.
+	// It is set by Datamodel.ResolveCompositeTypes so that composite type fields are not treated as relations.
+	FieldKindComposite FieldKind = "composite"
 )
 
 // IncludeInStruct shows whether to include a field in a model struct.
@@ -24,6 +33,11 @@ func (v FieldKind) IsRelation() bool {
 	return v == FieldKindObject
 }
 
+// IsComposite returns whether field is a composite type, e.g. an embedded document in MongoDB
+func (v FieldKind) IsComposite() bool {
+	return v == FieldKindComposite
+}
+
 // DatamodelFieldKind describes a scalar, object or enum.
 type DatamodelFieldKind string
 
@@ -51,6 +65,27 @@ type Document struct {
 	Mappings  Mappings  `json:"mappings"`
 }
 
+// Sort orders the models, composite types and enums by name, so that the generated code doesn't change when the
+// order of the schema files or of the blocks in the DMMF changes. Fields and enum values keep their declaration order,
+// as it defines the layout of the generated structs and the field numbers of protobuf messages.
+func (d *Document) Sort() {
+	sortModels := func(models []Model) {
+		sort.SliceStable(models, func(i, j int) bool {
+			return models[i].Name < models[j].Name
+		})
+	}
+	sortModels(d.Datamodel.Models)
+	sortModels(d.Datamodel.Types)
+	sort.SliceStable(d.Datamodel.Enums, func(i, j int) bool {
+		return d.Datamodel.Enums[i].Name < d.Datamodel.Enums[j].Name
+	})
+	for _, enums := range [][]SchemaEnum{d.Schema.EnumTypes.Prisma, d.Schema.EnumTypes.Model} {
+		sort.SliceStable(enums, func(i, j int) bool {
+			return enums[i].Name < enums[j].Name
+		})
+	}
+}
+
 type Mappings struct {
 	ModelOperations []ModelOperation `json:"modelOperations"`
 	OtherOperations struct {
@@ -208,6 +243,24 @@ type Enum struct {
 	Values []EnumValue  `json:"values"`
 	// DBName (optional)
 	DBName types.String `json:"dBName"`
+	// Documentation (optional) contains the content of triple-slash comments
+	Documentation string `json:"documentation"`
+	// IsView is set for database views, which are read-only. The DMMF lists views as models, so it is set by the
+	// generator from the view blocks of the schema.
+	IsView bool `json:"-"`
+}
+
+// DocComment returns the documentation of the enum as Go comment lines
+func (e Enum) DocComment() string {
+	return docComment(e.Documentation)
+}
+
+// TypeName returns the name of the enum type in the database
+func (e Enum) TypeName() string {
+	if e.DBName != "" {
+		return e.DBName.String()
+	}
+	return e.Name.String()
 }
 
 // EnumValue contains detailed information about an enum type.
@@ -221,6 +274,61 @@ type EnumValue struct {
 type Datamodel struct {
 	Models []Model `json:"models"`
 	Enums  []Enum  `json:"enums"`
+	// Types contains composite types, which are only supported by MongoDB
+	Types []Model `json:"types"`
+}
+
+// ResolveCompositeTypes sets the kind of all fields which refer to a composite type to FieldKindComposite.
+func (d *Datamodel) ResolveCompositeTypes() {
+	composites := make(map[types.Type]bool)
+	for _, t := range d.Types {
+		composites[types.Type(t.Name)] = true
+	}
+
+	resolve := func(models []Model) {
+		for i := range models {
+			for j, field := range models[i].Fields {
+				if field.Kind == FieldKindObject && composites[field.Type] {
+					models[i].Fields[j].Kind = FieldKindComposite
+				}
+			}
+		}
+	}
+
+	resolve(d.Models)
+	resolve(d.Types)
+}
+
+// FindModel returns the model with the given name, or an empty model if there is none, e.g. the model of a relation
+// field's type
+func (d Datamodel) FindModel(name types.Type) Model {
+	for _, m := range d.Models {
+		if string(m.Name) == string(name) {
+			return m
+		}
+	}
+	return Model{}
+}
+
+// WritableModels returns the models which are not views, for which write queries are generated
+func (d Datamodel) WritableModels() []Model {
+	var models []Model
+	for _, m := range d.Models {
+		if !m.IsView {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// FindEnum returns the enum with the given name, or an empty enum if there is none
+func (d Datamodel) FindEnum(name types.Type) Enum {
+	for _, e := range d.Enums {
+		if string(e.Name) == string(name) {
+			return e
+		}
+	}
+	return Enum{}
 }
 
 type UniqueIndex struct {
@@ -238,6 +346,74 @@ type Model struct {
 	Fields        []Field       `json:"fields"`
 	UniqueIndexes []UniqueIndex `json:"uniqueIndexes"`
 	PrimaryKey    PrimaryKey    `json:"primaryKey"`
+	// Documentation (optional) contains the content of triple-slash comments
+	Documentation string `json:"documentation"`
+	// IsView is set for database views, which are read-only. The DMMF lists views as models, so it is set by the
+	// generator from the view blocks of the schema.
+	IsView bool `json:"-"`
+}
+
+// DocComment returns the documentation of the model as Go comment lines, without annotations for the generator
+func (m Model) DocComment() string {
+	var lines []string
+	for _, line := range strings.Split(m.Documentation, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), softDeleteAnnotation) {
+			lines = append(lines, line)
+		}
+	}
+	return docComment(strings.Join(lines, "\n"))
+}
+
+// excludeAnnotation is a documentation line which excludes a model from the generated client
+const excludeAnnotation = "@go.exclude"
+
+// IsExcluded returns whether the model is excluded from the generated client with a `/// @go.exclude` comment
+func (m Model) IsExcluded() bool {
+	for _, line := range strings.Split(m.Documentation, "\n") {
+		if strings.TrimSpace(line) == excludeAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// softDeleteAnnotation is the prefix of a documentation line which enables soft deletes for a model
+const softDeleteAnnotation = "@go.softDelete:"
+
+// SoftDeleteFieldName returns the name of the field set with a `/// @go.softDelete: <field>` comment, which stores
+// when a record was deleted, or an empty string
+func (m Model) SoftDeleteFieldName() string {
+	for _, line := range strings.Split(m.Documentation, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, softDeleteAnnotation) {
+			return strings.TrimSpace(strings.TrimPrefix(line, softDeleteAnnotation))
+		}
+	}
+	return ""
+}
+
+// SoftDeleteField returns the field which stores when a record was deleted, or an empty field if the model is not
+// soft-deleted
+func (m Model) SoftDeleteField() Field {
+	name := m.SoftDeleteFieldName()
+	if name == "" {
+		return Field{}
+	}
+	for _, f := range m.Fields {
+		if f.Name.String() == name {
+			return f
+		}
+	}
+	return Field{}
+}
+
+// VersionField returns the field used for optimistic locking, or an empty field if the model has none
+func (m Model) VersionField() Field {
+	for _, f := range m.Fields {
+		if f.IsVersion() {
+			return f
+		}
+	}
+	return Field{}
 }
 
 type PrimaryKey struct {
@@ -254,6 +430,49 @@ func (k PrimaryKey) IsFieldInPrimary(field types.String) bool {
 	return false
 }
 
+// HasGoField returns whether the model has a field which is named like the given Go identifier
+func (m Model) HasGoField(name string) bool {
+	for _, f := range m.Fields {
+		if f.Name.GoCase() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TableName returns the name of the table or collection in the database
+func (m Model) TableName() string {
+	if m.DBName != "" {
+		return m.DBName.String()
+	}
+	return m.Name.String()
+}
+
+// UniqueConstraints returns the number of unique constraints of the model, including the primary key
+func (m Model) UniqueConstraints() int {
+	n := len(m.UniqueIndexes)
+	if len(m.PrimaryKey.Fields) > 0 {
+		n++
+	}
+	for _, f := range m.Fields {
+		if f.IsID || f.IsUnique {
+			n++
+		}
+	}
+	return n
+}
+
+// SingleIDField returns the field of a single-field primary key, or an empty field if the model has a compound or no
+// primary key
+func (m Model) SingleIDField() Field {
+	for _, f := range m.Fields {
+		if f.IsID && f.Kind == FieldKindScalar {
+			return f
+		}
+	}
+	return Field{}
+}
+
 func (m Model) Actions() []string {
 	return []string{"Set", "Equals"}
 }
@@ -270,6 +489,29 @@ func (m Model) RelationFieldsPlusOne() []Field {
 	return fields
 }
 
+// CreateStep is a step of the staged create builder, which sets a field which is required on create
+type CreateStep struct {
+	Field Field
+	// Next is the field of the next step, or nil if this is the last step
+	Next *Field
+}
+
+// CreateSteps returns a step for each field which is required on create, in the order of the fields
+func (m Model) CreateSteps() []CreateStep {
+	var steps []CreateStep
+	for _, field := range m.Fields {
+		if !field.RequiredOnCreate(m.PrimaryKey) {
+			continue
+		}
+		if len(steps) > 0 {
+			field := field
+			steps[len(steps)-1].Next = &field
+		}
+		steps = append(steps, CreateStep{Field: field})
+	}
+	return steps
+}
+
 // Field describes properties of a single model field.
 type Field struct {
 	Kind       FieldKind    `json:"kind"`
@@ -284,6 +526,8 @@ type Field struct {
 	DBName      types.String `json:"dBName"`
 	IsGenerated bool         `json:"isGenerated"`
 	IsUpdatedAt bool         `json:"isUpdatedAt"`
+	// RelationFromFields (optional)
+	RelationFromFields []types.String `json:"relationFromFields"`
 	// RelationToFields (optional)
 	RelationToFields []interface{} `json:"relationToFields"`
 	// RelationOnDelete (optional)
@@ -292,6 +536,257 @@ type Field struct {
 	RelationName types.String `json:"relationName"`
 	// HasDefaultValue
 	HasDefaultValue bool `json:"hasDefaultValue"`
+	// Documentation (optional) contains the content of triple-slash comments
+	Documentation string `json:"documentation"`
+	// NativeType (optional) contains the name and the arguments of the native database type, e.g. ["Uuid", []]
+	NativeType []interface{} `json:"nativeType"`
+	// Default (optional) contains the default value, or the name and the arguments of a default function, e.g.
+	// {"name": "cuid", "args": []}
+	Default interface{} `json:"default"`
+}
+
+// goTypeAnnotation is the prefix of a documentation line which sets the Go type of a field
+const goTypeAnnotation = "@go.type:"
+
+// versionAnnotation is a documentation line which selects the field used for optimistic locking
+const versionAnnotation = "@go.version"
+
+// DocComment returns the documentation of the field as Go comment lines, without annotations for the generator
+func (f Field) DocComment() string {
+	return docComment(f.Doc())
+}
+
+// Doc returns the documentation of the field without annotations for the generator
+func (f Field) Doc() string {
+	var lines []string
+	for _, line := range strings.Split(f.Documentation, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, goTypeAnnotation) && trimmed != versionAnnotation &&
+			trimmed != sensitiveAnnotation && trimmed != sensitiveOptOut {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// GoTypeAnnotation returns the Go type set with a `/// @go.type: <type>` comment, or an empty string
+func (f Field) GoTypeAnnotation() string {
+	for _, line := range strings.Split(f.Documentation, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, goTypeAnnotation) {
+			return strings.TrimSpace(strings.TrimPrefix(line, goTypeAnnotation))
+		}
+	}
+	return ""
+}
+
+// IsVersion returns whether the field is used for optimistic locking, which is set with a `/// @go.version` comment
+func (f Field) IsVersion() bool {
+	for _, line := range strings.Split(f.Documentation, "\n") {
+		if strings.TrimSpace(line) == versionAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// NativeTypeName returns the name of the native database type, e.g. "Uuid" for @db.Uuid, or an empty string
+func (f Field) NativeTypeName() string {
+	if len(f.NativeType) == 0 {
+		return ""
+	}
+	name, _ := f.NativeType[0].(string)
+	return name
+}
+
+// engineDefaults are the default functions which are evaluated by the query engine instead of the database
+var engineDefaults = map[string]bool{"cuid": true, "uuid": true, "nanoid": true, "ulid": true, "now": true}
+
+// EngineDefault returns the name of the default function of the field if it is evaluated by the query engine instead
+// of the database, e.g. "cuid", so that raw SQL doesn't apply it, or an empty string
+func (f Field) EngineDefault() string {
+	def, ok := f.Default.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	name, _ := def["name"].(string)
+	if !engineDefaults[name] {
+		return ""
+	}
+	return name
+}
+
+// IsBatchKey returns whether the field is a required single unique field whose values can identify the records of a
+// batch, i.e. a String, Int or BigInt
+func (f Field) IsBatchKey() bool {
+	if f.Kind != FieldKindScalar || !f.IsRequired || f.IsList || (!f.IsID && !f.IsUnique) {
+		return false
+	}
+	switch f.Type {
+	case "String", "Int", "BigInt":
+		return true
+	}
+	return false
+}
+
+// lengthNativeTypes are the native database types of String fields whose argument is the maximum length
+var lengthNativeTypes = map[string]bool{"Char": true, "VarChar": true, "NChar": true, "NVarChar": true}
+
+// MaxLength returns the maximum number of characters of a String field with a native type such as @db.VarChar(50),
+// or 0 if the length is not limited
+func (f Field) MaxLength() int {
+	if f.Type != "String" || !lengthNativeTypes[f.NativeTypeName()] || len(f.NativeType) < 2 {
+		return 0
+	}
+	args, _ := f.NativeType[1].([]interface{})
+	if len(args) == 0 {
+		return 0
+	}
+	arg, _ := args[0].(string)
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		// e.g. @db.NVarChar(Max)
+		return 0
+	}
+	return n
+}
+
+// docComment turns the content of triple-slash comments into Go comment lines, or returns an empty string if there is
+// no documentation
+func docComment(doc string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			lines[i] = "//"
+		} else {
+			lines[i] = "// " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ColumnName returns the name of the column or document field in the database
+func (f Field) ColumnName() string {
+	if f.DBName != "" {
+		return f.DBName.String()
+	}
+	return f.Name.String()
+}
+
+// ColumnTag returns the struct tag of the field in raw query results, which use the column names of @map
+func (f Field) ColumnTag() string {
+	return types.String(f.ColumnName()).Tag(f.IsRequired)
+}
+
+// sensitiveFieldNames contains the name segments of fields which are redacted by default when logging models, e.g.
+// "password" for passwordHash or "api key" for stripeAPIKey
+var sensitiveFieldNames = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"apikey",
+	"api key",
+	"privatekey",
+	"private key",
+	"salt",
+	"email",
+	"phone",
+	"ssn",
+	"iban",
+}
+
+// metadataFieldNames are last name segments of fields which describe a sensitive value instead of holding it, e.g.
+// tokenCount or passwordUpdatedAt
+var metadataFieldNames = map[string]bool{
+	"at":       true,
+	"count":    true,
+	"enabled":  true,
+	"expires":  true,
+	"expiry":   true,
+	"id":       true,
+	"length":   true,
+	"set":      true,
+	"type":     true,
+	"verified": true,
+}
+
+// sensitiveAnnotation is a documentation line which redacts a field when logging a model, and sensitiveOptOut one which
+// logs a field whose name looks sensitive
+const (
+	sensitiveAnnotation = "@sensitive"
+	sensitiveOptOut     = "@sensitive(false)"
+)
+
+// IsSensitive returns whether a field should be redacted when logging a model. Fields are sensitive when they are
+// documented with a `/// @sensitive` line or when a segment of their name, split at camelCase and underscores, looks
+// like credentials or personal data, e.g. passwordHash or contactEmail but not passwordUpdatedAt or headphones.
+// A `/// @sensitive(false)` line logs the field anyway. ID fields are never sensitive.
+func (f Field) IsSensitive() bool {
+	if f.IsID {
+		return false
+	}
+	for _, line := range strings.Split(f.Documentation, "\n") {
+		switch strings.TrimSpace(line) {
+		case sensitiveAnnotation:
+			return true
+		case sensitiveOptOut:
+			return false
+		}
+	}
+	segments := nameSegments(f.Name.String())
+	if len(segments) > 1 && metadataFieldNames[segments[len(segments)-1]] {
+		return false
+	}
+	name := " " + strings.Join(segments, " ") + " "
+	for _, s := range sensitiveFieldNames {
+		if strings.Contains(name, " "+s+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// nameSegments splits a field name into lowercase words at underscores, digits and camelCase boundaries, e.g.
+// "stripeAPIKey_v2" into stripe, api, key, v and 2
+func nameSegments(name string) []string {
+	var segments []string
+	var current []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(current) > 0 {
+			segments = append(segments, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			flush()
+			continue
+		case unicode.IsUpper(r):
+			// a new word starts at an upper case letter after a lower case one, or before the last letter of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				flush()
+			}
+		case unicode.IsDigit(r):
+			if i > 0 && !unicode.IsDigit(runes[i-1]) {
+				flush()
+			}
+		default:
+			if i > 0 && unicode.IsDigit(runes[i-1]) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return segments
 }
 
 func (f Field) RequiredOnCreate(key PrimaryKey) bool {
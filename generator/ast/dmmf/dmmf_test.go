@@ -0,0 +1,96 @@
+package dmmf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/generator/types"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestField_IsSensitive(t *testing.T) {
+	tests := []struct {
+		name          string
+		documentation string
+		isID          bool
+		expected      bool
+	}{
+		{name: "password", expected: true},
+		{name: "passwordHash", expected: true},
+		{name: "password_hash", expected: true},
+		{name: "accessToken", expected: true},
+		{name: "stripeAPIKey", expected: true},
+		{name: "apiKey", expected: true},
+		{name: "privateKey", expected: true},
+		{name: "clientSecret", expected: true},
+		{name: "tokenCount", expected: false},
+		{name: "passwordUpdatedAt", expected: false},
+		{name: "secretary", expected: false},
+		{name: "headphones", expected: false},
+		{name: "isPhoneVerified", expected: false},
+		{name: "email", expected: true},
+		{name: "phone", expected: true},
+		{name: "contactEmail", expected: true},
+		{name: "phone_number", expected: true},
+		{name: "address", documentation: "@sensitive", expected: true},
+		{name: "address", documentation: "Home address.\n @sensitive ", expected: true},
+		{name: "address", documentation: "Not @sensitive at all", expected: false},
+		{name: "token", documentation: "@sensitive(false)", expected: false},
+		{name: "token", isID: true, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.documentation, func(t *testing.T) {
+			f := Field{Name: types.String(tt.name), Documentation: tt.documentation, IsID: tt.isID}
+			massert.Equal(t, tt.expected, f.IsSensitive())
+		})
+	}
+}
+
+func TestNameSegments(t *testing.T) {
+	massert.Equal(t, []string{"stripe", "api", "key", "v", "2"}, nameSegments("stripeAPIKey_v2"))
+	massert.Equal(t, []string{"password", "hash"}, nameSegments("password_hash"))
+	massert.Equal(t, []string{"id"}, nameSegments("ID"))
+}
+
+func TestField_Doc(t *testing.T) {
+	f := Field{Documentation: "Home address\n@sensitive"}
+	massert.Equal(t, "Home address", f.Doc())
+}
+
+func TestField_EngineDefault(t *testing.T) {
+	var fields []Field
+	if err := json.Unmarshal([]byte(`[
+		{"name": "id", "default": {"name": "cuid", "args": [1]}},
+		{"name": "createdAt", "default": {"name": "now", "args": []}},
+		{"name": "number", "default": {"name": "autoincrement", "args": []}},
+		{"name": "published", "default": false},
+		{"name": "title"}
+	]`), &fields); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "cuid", fields[0].EngineDefault())
+	massert.Equal(t, "now", fields[1].EngineDefault())
+	massert.Equal(t, "", fields[2].EngineDefault())
+	massert.Equal(t, "", fields[3].EngineDefault())
+	massert.Equal(t, "", fields[4].EngineDefault())
+}
+
+func TestField_IsBatchKey(t *testing.T) {
+	massert.Equal(t, true, Field{Kind: FieldKindScalar, Type: "String", IsRequired: true, IsID: true}.IsBatchKey())
+	massert.Equal(t, true, Field{Kind: FieldKindScalar, Type: "Int", IsRequired: true, IsUnique: true}.IsBatchKey())
+	massert.Equal(t, false, Field{Kind: FieldKindScalar, Type: "String", IsUnique: true}.IsBatchKey())
+	massert.Equal(t, false, Field{Kind: FieldKindScalar, Type: "Bytes", IsRequired: true, IsID: true}.IsBatchKey())
+	massert.Equal(t, false, Field{Kind: FieldKindScalar, Type: "String", IsRequired: true}.IsBatchKey())
+}
+
+func TestModel_UniqueConstraints(t *testing.T) {
+	m := Model{
+		Fields: []Field{
+			{Name: "id", IsID: true},
+			{Name: "email", IsUnique: true},
+			{Name: "name"},
+		},
+		UniqueIndexes: []UniqueIndex{{Fields: []types.String{"name", "email"}}},
+	}
+	massert.Equal(t, 3, m.UniqueConstraints())
+}
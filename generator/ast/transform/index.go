@@ -25,17 +25,30 @@ func indexes(m dmmf.Model) []Index {
 		})
 	}
 
-	if len(m.PrimaryKey.Fields) > 0 {
-		idx = append(idx, Index{
-			Name:         getName(concatFieldsToName(m.PrimaryKey.Fields), m.PrimaryKey.Fields),
-			InternalName: concatFieldsToName(m.PrimaryKey.Fields),
-			Fields:       m.PrimaryKey.Fields,
-		})
+	if pk, ok := primaryKey(m); ok {
+		idx = append(idx, pk)
 	}
 
 	return idx
 }
 
+// primaryKey returns the index of a compound primary key. If the @@id has a name, Prisma only accepts the name in
+// unique where inputs.
+func primaryKey(m dmmf.Model) (Index, bool) {
+	if len(m.PrimaryKey.Fields) == 0 {
+		return Index{}, false
+	}
+	internalName := m.PrimaryKey.Name.String()
+	if internalName == "" {
+		internalName = concatFieldsToName(m.PrimaryKey.Fields)
+	}
+	return Index{
+		Name:         getName(m.PrimaryKey.Name.String(), m.PrimaryKey.Fields),
+		InternalName: internalName,
+		Fields:       m.PrimaryKey.Fields,
+	}, true
+}
+
 func concatFieldsToName(fields []types.String) string {
 	var name string
 	for i, f := range fields {
@@ -14,19 +14,16 @@ type Model struct {
 	OldModel dmmf.Model `json:"-"`
 }
 
+// CompoundKeys returns the compound unique indexes and the compound primary key of the model
 func (m Model) CompoundKeys() []Index {
-	var items []Index
-	items = append(items, m.Indexes...)
-
-	if m.OldModel.PrimaryKey.Name != "" {
-		items = append(items, Index{
-			Name:         m.OldModel.PrimaryKey.Name,
-			InternalName: m.OldModel.PrimaryKey.Name.String(),
-			Fields:       m.OldModel.PrimaryKey.Fields,
-		})
-	}
+	return m.Indexes
+}
 
-	return items
+// CompoundPrimaryKey returns the compound primary key of the model, or an empty index if the model has a single-field
+// or no primary key
+func (m Model) CompoundPrimaryKey() Index {
+	pk, _ := primaryKey(m.OldModel)
+	return pk
 }
 
 type Field struct {
@@ -38,6 +35,16 @@ type Field struct {
 	dmmf.Field
 }
 
+// FindModel returns the model with the given name
+func (r *AST) FindModel(name types.Type) Model {
+	for _, m := range r.Models {
+		if string(m.Name) == string(name) {
+			return m
+		}
+	}
+	return Model{}
+}
+
 func (r *AST) models() []Model {
 	var models []Model
 	for _, model := range r.dmmf.Datamodel.Models {
@@ -1,5 +1,9 @@
 package transform
 
+import (
+	"sort"
+)
+
 func (r *AST) scalars() []string {
 	var scalars []string
 	for _, item := range r.dmmf.Schema.InputObjectTypes.Prisma {
@@ -28,5 +32,8 @@ func (r *AST) scalars() []string {
 		}
 	}
 
+	// the order of the input types depends on the schema, so sort the scalars for a stable order of the filters
+	sort.Strings(scalars)
+
 	return scalars
 }
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+)
+
+// Dataloaders returns whether a dataloader is generated for each unique field and relation
+func (r *Root) Dataloaders() bool {
+	return r.Generator.Config.Dataloaders == "true"
+}
+
+// Dataloader is a generated loader which batches lookups of the records of a model by a field
+type Dataloader struct {
+	// Name is the name of the loader, e.g. UserByID or PostsByAuthorID
+	Name string
+	// Field is the field the records are looked up by
+	Field dmmf.Field
+	// KeyType is the Go type of the keys, e.g. string
+	KeyType string
+	// List is true for loaders which return all records with a key, e.g. the posts of an author, instead of a single
+	// record by a unique field
+	List bool
+}
+
+// dataloaderKeyTypes are the scalars which can be keys of a dataloader, as the keys need to be comparable
+var dataloaderKeyTypes = map[string]bool{
+	"String": true,
+	"Int":    true,
+	"BigInt": true,
+}
+
+// ModelDataloaders returns the loaders of a model: one for each required unique field, and one for each foreign key of
+// a relation which returns all records with the key
+func (r *Root) ModelDataloaders(model dmmf.Model) []Dataloader {
+	isKey := func(field dmmf.Field) bool {
+		if field.IsList {
+			return false
+		}
+		return field.Kind == dmmf.FieldKindEnum || (field.Kind == dmmf.FieldKindScalar && dataloaderKeyTypes[field.Type.String()])
+	}
+	name := model.Name.GoCase()
+
+	var loaders []Dataloader
+	seen := make(map[string]bool)
+	add := func(loader Dataloader) {
+		if seen[loader.Name] {
+			return
+		}
+		seen[loader.Name] = true
+		loader.KeyType = r.GoType(loader.Field, loader.Field.Type.Value())
+		loaders = append(loaders, loader)
+	}
+
+	for _, field := range model.Fields {
+		if (field.IsID || field.IsUnique) && field.IsRequired && isKey(field) {
+			add(Dataloader{
+				Name:  name + "By" + field.Name.GoCase(),
+				Field: field,
+			})
+		}
+	}
+
+	for _, relation := range model.Fields {
+		if !relation.Kind.IsRelation() || len(relation.RelationFromFields) != 1 {
+			continue
+		}
+		for _, field := range model.Fields {
+			if field.Name != relation.RelationFromFields[0] || field.IsID || field.IsUnique || !isKey(field) {
+				continue
+			}
+			add(Dataloader{
+				Name:  plural(name) + "By" + field.Name.GoCase(),
+				Field: field,
+				List:  true,
+			})
+		}
+	}
+	return loaders
+}
+
+// plural returns the English plural of a Go name, e.g. Posts for Post and Categories for Category
+func plural(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
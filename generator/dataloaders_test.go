@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestModelDataloaders(t *testing.T) {
+	r := &Root{}
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[
+		{"name":"Post","fields":[
+			{"kind":"scalar","name":"id","type":"String","isRequired":true,"isId":true},
+			{"kind":"scalar","name":"slug","type":"String","isUnique":true},
+			{"kind":"scalar","name":"number","type":"Int","isRequired":true,"isUnique":true},
+			{"kind":"scalar","name":"data","type":"Bytes","isRequired":true,"isUnique":true},
+			{"kind":"object","name":"author","type":"User","isRequired":true,"relationFromFields":["authorID"]},
+			{"kind":"scalar","name":"authorID","type":"String","isRequired":true},
+			{"kind":"object","name":"editor","type":"User","relationFromFields":["editorID"]},
+			{"kind":"scalar","name":"editorID","type":"String"},
+			{"kind":"object","name":"category","type":"Category","relationFromFields":["categoryID"]},
+			{"kind":"scalar","name":"categoryID","type":"String","isUnique":true}
+		]}
+	]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+
+	var actual [][3]interface{}
+	for _, loader := range r.ModelDataloaders(r.DMMF.Datamodel.Models[0]) {
+		actual = append(actual, [3]interface{}{loader.Name, loader.KeyType, loader.List})
+	}
+	// optional unique fields and keys which are not comparable get no loader, and unique foreign keys only get the
+	// loader of the unique field
+	massert.Equal(t, [][3]interface{}{
+		{"PostByID", "string", false},
+		{"PostByNumber", "int", false},
+		{"PostsByAuthorID", "string", true},
+		{"PostsByEditorID", "string", true},
+	}, actual)
+}
+
+func TestPlural(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Post":     "Posts",
+		"Category": "Categories",
+		"Day":      "Days",
+		"Address":  "Addresses",
+		"Box":      "Boxes",
+		"Match":    "Matches",
+	} {
+		massert.Equal(t, expected, plural(name))
+	}
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestRoot_UsesDataProxy(t *testing.T) {
+	t.Setenv("PRISMA_CLIENT_ENGINE_TYPE", "")
+	t.Setenv("TEST_DATABASE_URL", "")
+
+	// .env files are read relative to the working directory, like at runtime
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	root := func(url EnvValue) *Root {
+		return &Root{Datasources: []Datasource{{URL: url}}}
+	}
+
+	massert.Equal(t, false, root(EnvValue{Value: "postgresql://localhost/db"}).UsesDataProxy())
+	massert.Equal(t, true, root(EnvValue{Value: "prisma://accelerate.prisma-data.net/?api_key=x"}).UsesDataProxy())
+
+	env := root(EnvValue{FromEnvVar: "TEST_DATABASE_URL"})
+	massert.Equal(t, false, env.UsesDataProxy())
+
+	if err := os.MkdirAll("prisma", 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("prisma/.env", []byte("TEST_DATABASE_URL=prisma+postgres://localhost/?api_key=x\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, env.UsesDataProxy())
+
+	// the process env takes precedence over .env files
+	t.Setenv("TEST_DATABASE_URL", "postgresql://localhost/db")
+	massert.Equal(t, false, env.UsesDataProxy())
+
+	t.Setenv("TEST_DATABASE_URL", "prisma://accelerate.prisma-data.net/?api_key=x")
+	massert.Equal(t, true, env.UsesDataProxy())
+
+	accelerate := root(EnvValue{Value: "postgresql://localhost/db"})
+	accelerate.Generator.Config.EngineType = "accelerate"
+	massert.Equal(t, true, accelerate.UsesDataProxy())
+}
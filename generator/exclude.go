@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+	"github.com/steebchen/prisma-client-go/generator/ast/transform"
+	"github.com/steebchen/prisma-client-go/generator/types"
+)
+
+// excludeModels removes the models excluded with the excludeModels option or a `/// @go.exclude` comment from the
+// datamodel, along with the relation fields of other models which point to them, and rebuilds the AST.
+// The query engine still receives the full schema, so excluded tables are migrated and introspected as usual.
+func (r *Root) excludeModels() error {
+	excluded := make(map[string]bool)
+	for _, name := range strings.Split(r.Generator.Config.ExcludeModels, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if r.DMMF.Datamodel.FindModel(types.Type(name)).Name == "" {
+			return fmt.Errorf("invalid excludeModels in generator config: model %q does not exist", name)
+		}
+		excluded[name] = true
+	}
+	for _, model := range r.DMMF.Datamodel.Models {
+		if model.IsExcluded() {
+			excluded[model.Name.String()] = true
+		}
+	}
+	if len(excluded) == 0 {
+		return nil
+	}
+
+	var models []dmmf.Model
+	for _, model := range r.DMMF.Datamodel.Models {
+		if excluded[model.Name.String()] {
+			continue
+		}
+		var fields []dmmf.Field
+		for _, field := range model.Fields {
+			if field.Kind.IsRelation() && excluded[field.Type.String()] {
+				if field.IsRequired && !field.IsList {
+					return fmt.Errorf("model %s has a required relation %s to the excluded model %s: exclude %s as well or make the relation optional", model.Name, field.Name, field.Type, model.Name)
+				}
+				continue
+			}
+			fields = append(fields, field)
+		}
+		model.Fields = fields
+		models = append(models, model)
+	}
+	r.DMMF.Datamodel.Models = models
+	r.AST = transform.New(&r.DMMF)
+	return nil
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+const excludeDatamodel = `{"datamodel":{"models":[
+	{"name":"User","fields":[
+		{"kind":"scalar","name":"id","type":"String"},
+		{"kind":"object","name":"posts","type":"Post","isList":true,"relationName":"PostToUser"},
+		{"kind":"object","name":"legacy","type":"LegacyUser","relationName":"LegacyUserToUser"}
+	]},
+	{"name":"Post","fields":[
+		{"kind":"scalar","name":"id","type":"String"},
+		{"kind":"scalar","name":"authorId","type":"String","isRequired":true,"isReadOnly":true},
+		{"kind":"object","name":"author","type":"User","isRequired":true,"relationName":"PostToUser","relationFromFields":["authorId"]}
+	]},
+	{"name":"LegacyUser","documentation":"Kept for introspection.\n@go.exclude","fields":[
+		{"kind":"scalar","name":"id","type":"String"},
+		{"kind":"object","name":"user","type":"User","isList":true,"relationName":"LegacyUserToUser"}
+	]},
+	{"name":"SchemaMigration","fields":[
+		{"kind":"scalar","name":"version","type":"String"}
+	]}
+]}}`
+
+func TestExcludeModels(t *testing.T) {
+	r := &Root{}
+	r.Generator.Config.ExcludeModels = "SchemaMigration, Post"
+	if err := json.Unmarshal([]byte(excludeDatamodel), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.excludeModels(); err != nil {
+		t.Fatal(err)
+	}
+
+	var models []string
+	for _, model := range r.DMMF.Datamodel.Models {
+		models = append(models, model.Name.String())
+	}
+	massert.Equal(t, []string{"User"}, models)
+	massert.Equal(t, 1, len(r.DMMF.Datamodel.Models[0].Fields))
+	massert.Equal(t, 1, len(r.AST.Models))
+}
+
+func TestExcludeModels_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		exclude string
+		want    string
+	}{{
+		name:    "unknown model",
+		exclude: "Migration",
+		want:    `invalid excludeModels in generator config: model "Migration" does not exist`,
+	}, {
+		name:    "required relation",
+		exclude: "User",
+		want:    "model Post has a required relation author to the excluded model User: exclude Post as well or make the relation optional",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Root{}
+			r.Generator.Config.ExcludeModels = tt.exclude
+			if err := json.Unmarshal([]byte(excludeDatamodel), &r.DMMF); err != nil {
+				t.Fatal(err)
+			}
+			err := r.excludeModels()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			massert.Equal(t, tt.want, err.Error())
+		})
+	}
+}
@@ -2,14 +2,23 @@ package generator
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
+	"github.com/joho/godotenv"
+
+	"github.com/steebchen/prisma-client-go/binaries"
 	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
 	"github.com/steebchen/prisma-client-go/generator/ast/transform"
 	"github.com/steebchen/prisma-client-go/generator/types"
+	"github.com/steebchen/prisma-client-go/helpers/gocase"
+	"github.com/steebchen/prisma-client-go/helpers/strcase"
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
 	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/runtime/version"
 )
 
 // Root describes the generator output root.
@@ -26,6 +35,17 @@ type Root struct {
 	// BinaryPaths (optional)
 	BinaryPaths BinaryPaths    `json:"binaryPaths"`
 	AST         *transform.AST `json:"ast"`
+
+	// goTypes contains the custom Go types of fields by their annotation or mapping, see resolveGoTypes
+	goTypes map[string]GoType
+	// goTypeMappings contains the parsed goTypes option
+	goTypeMappings map[string]string
+	// importPath is the import path of the generated package, see resolveImportPath
+	importPath string
+	// modelsImportPath is the import path of the models package, see resolveModelsPackage
+	modelsImportPath string
+	// modelsPass is set while the models package is rendered
+	modelsPass bool
 }
 
 func (r *Root) EscapedDatamodel() string {
@@ -42,6 +62,23 @@ func (r *Root) GetDatasourcesJSON() string {
 	return string(data)
 }
 
+// GetEngineVersion returns the query engine commit the client is generated for
+func (r *Root) GetEngineVersion() string {
+	return binaries.EngineVersion
+}
+
+// GetClientVersion returns the version of prisma-client-go which generates the client, or an empty string if it is
+// unknown, e.g. for local development builds
+func (r *Root) GetClientVersion() string {
+	return version.Current()
+}
+
+// IsMongoDB returns whether the datasource is a MongoDB database
+func (r *Root) IsMongoDB() bool {
+	provider := r.Datasources[0].ActiveProvider
+	return provider == ProviderMongoDB || provider == ProviderMongo
+}
+
 func (r *Root) GetEngineType() string {
 	if str := os.Getenv("PRISMA_CLIENT_ENGINE_TYPE"); str != "" {
 		return str
@@ -52,14 +89,172 @@ func (r *Root) GetEngineType() string {
 	return "binary"
 }
 
+// JSONName returns the JSON name of a model field as configured with jsonCase
+func (r *Root) JSONName(name types.String) string {
+	switch r.Generator.Config.JSONCase {
+	case JSONCaseCamel:
+		return name.CamelCase()
+	case JSONCaseSnake:
+		return strcase.ToSnake(name.String())
+	default:
+		return name.String()
+	}
+}
+
+// omitEmpty returns whether a model field is tagged with omitempty as configured with jsonOmitEmpty
+func (r *Root) omitEmpty(isRequired bool) bool {
+	switch r.Generator.Config.JSONOmitEmpty {
+	case JSONOmitAll:
+		return true
+	case JSONOmitNone:
+		return false
+	}
+	return !isRequired
+}
+
+// JSONTag returns the struct tag of a model field as configured with jsonCase and jsonOmitEmpty
+func (r *Root) JSONTag(name types.String, isRequired bool) string {
+	if r.omitEmpty(isRequired) {
+		return fmt.Sprintf("`json:\"%s,omitempty\"`", r.JSONName(name))
+	}
+	return fmt.Sprintf("`json:\"%s\"`", r.JSONName(name))
+}
+
+// NullableFields returns whether optional fields are represented as types.Nullable instead of pointers
+func (r *Root) NullableFields() bool {
+	return r.Generator.Config.OptionalFields == OptionalFieldsNullable
+}
+
+// OptionalGoType returns the Go type of an optional field which is not a list, either a pointer or a types.Nullable
+// as configured with optionalFields
+func (r *Root) OptionalGoType(field dmmf.Field, fallback string) string {
+	if r.NullableFields() {
+		return "types.Nullable[" + r.GoType(field, fallback) + "]"
+	}
+	return "*" + r.GoType(field, fallback)
+}
+
+// HasCustomJSONNames returns whether the JSON names of model fields differ from the field names the query engine
+// uses, so that the models need to decode both
+func (r *Root) HasCustomJSONNames() bool {
+	switch r.Generator.Config.JSONCase {
+	case "", JSONCaseDeclared:
+		return false
+	}
+	return true
+}
+
+// UsesDataProxy returns whether the client talks to a remote Prisma Data Proxy or Accelerate endpoint only,
+// either because the engine type is set to "dataproxy" or "accelerate", or because the datasource url in the schema is
+// a prisma:// connection string, e.g. env("DATABASE_URL") set to one. In that case, no query engine binaries are
+// fetched or embedded.
+func (r *Root) UsesDataProxy() bool {
+	switch r.GetEngineType() {
+	case "dataproxy", "accelerate":
+		return true
+	}
+	url := r.datasourceURL()
+	return strings.HasPrefix(url, "prisma://") || strings.HasPrefix(url, "prisma+postgres://")
+}
+
+// datasourceURL returns the url of the datasource like the client resolves it at runtime: the url in the schema, or
+// the value of its env var, which may also be set in one of the .env files loaded by the engine
+func (r *Root) datasourceURL() string {
+	ds := r.Datasources[0]
+	if ds.URL.Value != "" || ds.URL.FromEnvVar == "" {
+		return ds.URL.Value
+	}
+	if url := os.Getenv(ds.URL.FromEnvVar); url != "" {
+		return url
+	}
+	for _, file := range []string{".env", "db/.env", "prisma/.env"} {
+		env, err := godotenv.Read(file)
+		if err != nil {
+			continue
+		}
+		if url := env[ds.URL.FromEnvVar]; url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
 // Config describes the options for the Prisma Client Go generator
 type Config struct {
 	EngineType        string       `json:"engineType"`
 	Package           types.String `json:"package"`
 	DisableGitignore  string       `json:"disableGitignore"`
 	DisableGoBinaries string       `json:"disableGoBinaries"`
+	// JSONCase sets the casing of the JSON names of model fields, either "declared", "camelCase" or "snake_case"
+	JSONCase string `json:"jsonCase"`
+	// JSONOmitEmpty sets which model fields are tagged with omitempty, either "optional", "all" or "none"
+	JSONOmitEmpty string `json:"jsonOmitEmpty"`
+	// GoNaming sets how model, field and enum names are converted into Go identifiers, either "idiomatic", which
+	// upper-cases initialisms such as ID and URL, or "camelCase"
+	GoNaming string `json:"goNaming"`
+	// GoInitialisms is a comma-separated list of additional initialisms, e.g. "SKU,ISBN"
+	GoInitialisms string `json:"goInitialisms"`
+	// OptionalFields sets how optional fields of models and composite types are represented, either "pointer" or
+	// "nullable", which uses types.Nullable wrappers with a Valid flag instead of pointers
+	OptionalFields string `json:"optionalFields"`
+	// GoTypes is a comma-separated list of custom Go types for native database types or Prisma scalar types, e.g.
+	// "@db.Uuid=github.com/google/uuid.UUID"
+	GoTypes string `json:"goTypes"`
+	// ExcludeModels is a comma-separated list of models which are not generated, e.g. "SchemaMigration,LegacyUser"
+	ExcludeModels string `json:"excludeModels"`
+	// Gqlgen set to "true" makes the models and enums usable as gqlgen models and writes a gqlgen model mapping
+	Gqlgen string `json:"gqlgen"`
+	// Protobuf set to "true" writes protobuf messages of the models and enums
+	Protobuf string `json:"protobuf"`
+	// ProtobufPackage is the package of the protobuf definitions, which defaults to the Go package name
+	ProtobufPackage string `json:"protobufPackage"`
+	// ProtobufGoPackage is the import path of the Go package generated from the protobuf definitions. If set, the
+	// models get converters to and from the protobuf messages.
+	ProtobufGoPackage string `json:"protobufGoPackage"`
+	// Dataloaders set to "true" generates a dataloader for each unique field and relation, which batches lookups
+	Dataloaders string `json:"dataloaders"`
+	// OpenAPI set to "true" writes OpenAPI component schemas of the models and enums
+	OpenAPI string `json:"openapi"`
+	// SingleFile set to "true" writes the client into a single db_gen.go file instead of a file per model
+	SingleFile string `json:"singleFile"`
+	// Templates is the directory of custom templates, relative to the schema, which override built-in templates,
+	// extend the code of each model or are written into extra files
+	Templates string `json:"templates"`
+	// ModelsOutput is a directory, relative to the schema, into which the models, composite types and enums are
+	// generated as a separate package, so that they can be imported without the query client
+	ModelsOutput string `json:"modelsOutput"`
+	// ModelsPackage is the package name of the models package, which defaults to the last element of ModelsOutput
+	ModelsPackage string `json:"modelsPackage"`
+}
+
+// GoNameConverter returns the converter for Go identifiers as configured with goNaming and goInitialisms
+func (c Config) GoNameConverter() (*gocase.Converter, error) {
+	var initialisms []string
+	if c.GoNaming != GoNamingCamel {
+		initialisms = append(initialisms, gocase.DefaultInitialisms...)
+	}
+	for _, initialism := range strings.Split(c.GoInitialisms, ",") {
+		if initialism = strings.TrimSpace(initialism); initialism != "" {
+			initialisms = append(initialisms, initialism)
+		}
+	}
+	return gocase.New(gocase.WithInitialisms(initialisms...))
 }
 
+// JSON casing, omitempty, naming and optional field values of the generator config
+const (
+	JSONCaseDeclared       = "declared"
+	JSONCaseCamel          = "camelCase"
+	JSONCaseSnake          = "snake_case"
+	JSONOmitOptional       = "optional"
+	JSONOmitAll            = "all"
+	JSONOmitNone           = "none"
+	GoNamingIdiomatic      = "idiomatic"
+	GoNamingCamel          = "camelCase"
+	OptionalFieldsPointer  = "pointer"
+	OptionalFieldsNullable = "nullable"
+)
+
 // Generator describes a generator defined in the Prisma schema.
 type Generator struct {
 	// Output holds the file path of where the client gets generated in.
@@ -90,9 +285,12 @@ type Provider string
 //goland:noinspection GoUnusedConst
 const (
 	ProviderMySQL      Provider = "mysql"
-	ProviderMongo      Provider = "mongo"
+	ProviderMongoDB    Provider = "mongodb"
 	ProviderSQLite     Provider = "sqlite"
 	ProviderPostgreSQL Provider = "postgresql"
+
+	// Deprecated: use ProviderMongoDB, as Prisma uses "mongodb" as the provider name
+	ProviderMongo Provider = "mongo"
 )
 
 // Datasource describes a Prisma data source of any database type.
@@ -131,15 +329,12 @@ func (r *Root) GetSanitizedDatasourceURL() string {
 		panic(err)
 	}
 
-	// get prisma schema path from prisma schema file
-	schemaPath := path.Dir(r.SchemaPath)
+	// get the prisma schema directory, which is the schema path itself for multi-file schemas
+	schemaPath := filepath.ToSlash(schemaengine.SchemaDir(r.SchemaPath))
 
 	// trim /private as it is some kind of symlink on macOS
 	schemaPath = strings.Replace(schemaPath, "/private", "", 1)
 
-	// replace /schema.prisma as we need just the directory
-	schemaPath = strings.Replace(schemaPath, "schema.prisma", "", 1)
-
 	// use the schema path to locate the sqlite file (as the path is relative to the schema)
 	url = path.Join(schemaPath, url)
 
@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+)
+
+// GoType is a custom Go type of a scalar field, set with a `/// @go.type: <type>` comment or the goTypes option
+type GoType struct {
+	// Path is the import path of the package of the type, or empty for a type of the generated package
+	Path string
+	// Alias is the name the package is imported as
+	Alias string
+	// Name is the type as used in the generated code, e.g. uuid.UUID
+	Name string
+}
+
+// reservedImports contains the names of packages imported by the generated client, which custom types can't use. It
+// must list each import of _header.gotpl, which TestReservedImports checks.
+var reservedImports = map[string]bool{
+	"context": true, "json": true, "fmt": true, "io": true, "slog": true, "os": true, "strconv": true, "slices": true, "testing": true,
+	"time": true, "godotenv": true, "pb": true, "timestamppb": true, "decimal": true, "engine": true, "mock": true, "builder": true, "factory": true, "filter": true,
+	"cache": true, "cursor": true, "lifecycle": true, "metadata": true, "pool": true, "raw": true, "sample": true, "schemacheck": true,
+	"transaction": true, "types": true, "rawmodels": true, "upsert": true, "validation": true, "version": true, "dataloader": true,
+}
+
+// headerImports contains the import paths of the generated client which don't need to be imported again
+var headerImports = map[string]string{
+	"context":       "context",
+	"encoding/json": "json",
+	"fmt":           "fmt",
+	"log/slog":      "slog",
+	"os":            "os",
+	"slices":        "slices",
+	"testing":       "testing",
+	"time":          "time",
+}
+
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// splitGoType splits a type such as github.com/google/uuid.UUID into its import path and type name
+func splitGoType(s string) (path, name string, err error) {
+	i := strings.LastIndex(s, ".")
+	if i == -1 {
+		path, name = "", s
+	} else {
+		path, name = s[:i], s[i+1:]
+		if path == "" {
+			return "", "", fmt.Errorf("invalid Go type %q: missing import path", s)
+		}
+	}
+	if !token.IsIdentifier(name) {
+		return "", "", fmt.Errorf("invalid Go type %q: must be a type name, optionally prefixed with its import path, e.g. github.com/google/uuid.UUID", s)
+	}
+	return path, name, nil
+}
+
+// packageAlias returns an identifier for the package at path, which is its last path element without version suffixes
+func packageAlias(path string) string {
+	parts := strings.Split(path, "/")
+	last := parts[len(parts)-1]
+	if versionSuffix.MatchString(last) && len(parts) > 1 {
+		last = parts[len(parts)-2]
+	}
+	// gopkg.in/yaml.v3
+	if i := strings.Index(last, ".v"); i > 0 {
+		last = last[:i]
+	}
+	alias := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return -1
+	}, last)
+	if alias == "" || !token.IsIdentifier(alias) {
+		alias = "pkg" + alias
+	}
+	return alias
+}
+
+// parseGoTypesOption parses the goTypes option, a comma-separated list of mappings such as
+// "@db.Uuid=github.com/google/uuid.UUID", into a map of native database types or Prisma scalar types to Go types
+func parseGoTypesOption(option string) (map[string]string, error) {
+	mappings := make(map[string]string)
+	for _, mapping := range strings.Split(option, ",") {
+		if mapping = strings.TrimSpace(mapping); mapping == "" {
+			continue
+		}
+		key, goType, ok := strings.Cut(mapping, "=")
+		key, goType = strings.TrimSpace(key), strings.TrimSpace(goType)
+		if !ok || key == "" || goType == "" {
+			return nil, fmt.Errorf("invalid mapping %q: must be <type>=<Go type>, e.g. @db.Uuid=github.com/google/uuid.UUID", mapping)
+		}
+		if _, _, err := splitGoType(goType); err != nil {
+			return nil, err
+		}
+		mappings[key] = goType
+	}
+	return mappings, nil
+}
+
+// customGoType returns the Go type of a scalar field as set by its annotation or the goTypes mappings
+func customGoType(field dmmf.Field, mappings map[string]string) string {
+	if t := field.GoTypeAnnotation(); t != "" {
+		return t
+	}
+	if native := field.NativeTypeName(); native != "" {
+		if t, ok := mappings["@db."+native]; ok {
+			return t
+		}
+	}
+	return mappings[field.Type.String()]
+}
+
+// resolveGoTypes resolves the custom Go types of all scalar fields of models and composite types
+func (r *Root) resolveGoTypes() error {
+	mappings, err := parseGoTypesOption(r.Generator.Config.GoTypes)
+	if err != nil {
+		return fmt.Errorf("invalid goTypes in generator config: %w", err)
+	}
+
+	type owner struct {
+		name   string
+		fields []dmmf.Field
+	}
+	var owners []owner
+	for _, model := range r.DMMF.Datamodel.Models {
+		owners = append(owners, owner{model.Name.String(), model.Fields})
+	}
+	for _, t := range r.DMMF.Datamodel.Types {
+		owners = append(owners, owner{t.Name.String(), t.Fields})
+	}
+
+	r.goTypeMappings = mappings
+	r.goTypes = make(map[string]GoType)
+	aliases := make(map[string]string)
+	if r.SeparateModels() {
+		aliases[r.modelsImportPath] = r.ModelsPackage()
+	}
+	for _, o := range owners {
+		for _, field := range o.fields {
+			spec := customGoType(field, mappings)
+			if spec == "" {
+				continue
+			}
+			if field.Kind != dmmf.FieldKindScalar {
+				return fmt.Errorf("invalid Go type of %s.%s: only scalar fields can have a custom Go type", o.name, field.Name)
+			}
+			if field.Type == "Json" {
+				return fmt.Errorf("invalid Go type of %s.%s: Json fields can't have a custom Go type, as the query engine encodes their values as strings", o.name, field.Name)
+			}
+			if _, ok := r.goTypes[spec]; ok {
+				continue
+			}
+			path, name, err := splitGoType(spec)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", o.name, field.Name, err)
+			}
+			if path, err = r.resolveImport(path); err != nil {
+				return fmt.Errorf("invalid Go type of %s.%s: %w", o.name, field.Name, err)
+			}
+			if r.SeparateModels() {
+				// the models are declared in the models package, which can't import the client
+				if path != "" && path == r.importPath {
+					return fmt.Errorf("invalid Go type of %s.%s: types of the client package can't be used with modelsOutput, declare %s in the models package instead", o.name, field.Name, name)
+				}
+				if path == "" || path == r.modelsImportPath {
+					alias := r.ModelsPackage()
+					r.goTypes[spec] = GoType{Path: r.modelsImportPath, Alias: alias, Name: alias + "." + name}
+					continue
+				}
+			}
+			// types of the generated package itself must not be imported
+			if path == "" || path == r.importPath {
+				r.goTypes[spec] = GoType{Name: name}
+				continue
+			}
+			alias, ok := headerImports[path]
+			if !ok {
+				if alias, ok = aliases[path]; !ok {
+					alias = uniqueAlias(packageAlias(path), aliases)
+					aliases[path] = alias
+				}
+			}
+			r.goTypes[spec] = GoType{Path: path, Alias: alias, Name: alias + "." + name}
+		}
+	}
+	return nil
+}
+
+// uniqueAlias returns alias, or alias with a number if it is already used by the generated client or another package
+func uniqueAlias(alias string, used map[string]string) string {
+	taken := func(a string) bool {
+		if reservedImports[a] {
+			return true
+		}
+		for _, u := range used {
+			if u == a {
+				return true
+			}
+		}
+		return false
+	}
+	if !taken(alias) {
+		return alias
+	}
+	for i := 2; ; i++ {
+		if a := fmt.Sprintf("%s%d", alias, i); !taken(a) {
+			return a
+		}
+	}
+}
+
+// GoType returns the custom Go type of a field, or fallback if it has none, e.g.
+// {{ $.GoType $field $field.Type.Value }}
+func (r *Root) GoType(field dmmf.Field, fallback string) string {
+	if t, ok := r.goTypes[customGoType(field, r.goTypeMappings)]; ok {
+		if r.modelsPass && t.Path == r.modelsImportPath {
+			return strings.TrimPrefix(t.Name, t.Alias+".")
+		}
+		return t.Name
+	}
+	return fallback
+}
+
+// ImportsJSON returns whether the generated client uses encoding/json
+func (r *Root) ImportsJSON() bool {
+	if r.HasCustomJSONNames() {
+		return true
+	}
+	for _, t := range r.goTypes {
+		if t.Path == "encoding/json" {
+			return true
+		}
+	}
+	return false
+}
+
+// GoTypeImports returns the packages of custom Go types which need to be imported, sorted by import path
+func (r *Root) GoTypeImports() []GoType {
+	seen := make(map[string]bool)
+	var imports []GoType
+	for _, t := range r.goTypes {
+		// the header imports the models package itself
+		if t.Path == "" || seen[t.Path] || (r.SeparateModels() && t.Path == r.modelsImportPath) {
+			continue
+		}
+		if _, ok := headerImports[t.Path]; ok {
+			continue
+		}
+		seen[t.Path] = true
+		imports = append(imports, t)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Path < imports[j].Path
+	})
+	return imports
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestPackageAlias(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"github.com/google/uuid", "uuid"},
+		{"github.com/jackc/pgx/v5/pgtype", "pgtype"},
+		{"github.com/jackc/pgx/v5", "pgx"},
+		{"gopkg.in/yaml.v3", "yaml"},
+		{"github.com/gofrs/go-uuid", "gouuid"},
+		{"example.com/1pkg", "pkg1pkg"},
+	}
+	for _, tt := range tests {
+		massert.Equal(t, tt.want, packageAlias(tt.path))
+	}
+}
+
+func TestResolveGoTypes(t *testing.T) {
+	r := &Root{}
+	r.Generator.Config.GoTypes = "@db.Uuid=github.com/google/uuid.UUID, DateTime=example.com/types.Time"
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"User","fields":[
+		{"kind":"scalar","name":"id","type":"String","nativeType":["Uuid",[]]},
+		{"kind":"scalar","name":"createdAt","type":"DateTime"},
+		{"kind":"scalar","name":"timeout","type":"Int","documentation":"Timeout.\n@go.type: time.Duration"},
+		{"kind":"scalar","name":"name","type":"String"}
+	]}]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.resolveGoTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := r.DMMF.Datamodel.Models[0].Fields
+	massert.Equal(t, "uuid.UUID", r.GoType(fields[0], "string"))
+	// the alias of the package doesn't conflict with the imports of the generated client
+	massert.Equal(t, "types2.Time", r.GoType(fields[1], "DateTime"))
+	massert.Equal(t, "time.Duration", r.GoType(fields[2], "int"))
+	massert.Equal(t, "string", r.GoType(fields[3], "string"))
+	massert.Equal(t, "// Timeout.", fields[2].DocComment())
+	massert.Equal(t, []GoType{
+		{Path: "example.com/types", Alias: "types2", Name: "types2.Time"},
+		{Path: "github.com/google/uuid", Alias: "uuid", Name: "uuid.UUID"},
+	}, r.GoTypeImports())
+}
+
+func TestResolveGoTypes_reservedImports(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"example.com/app/cache.Key", "cache2.Key"},
+		{"example.com/app/cursor.Token", "cursor2.Token"},
+	}
+	for _, tt := range tests {
+		r := &Root{}
+		r.Generator.Config.GoTypes = "String=" + tt.goType
+		if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"User","fields":[
+			{"kind":"scalar","name":"name","type":"String"}
+		]}]}}`), &r.DMMF); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.resolveGoTypes(); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, tt.want, r.GoType(r.DMMF.Datamodel.Models[0].Fields[0], "string"))
+	}
+}
+
+// TestReservedImports makes sure that each package imported by the header of the generated client is reserved, so that
+// custom types can't use its name as an alias
+func TestReservedImports(t *testing.T) {
+	header, err := templateFS.ReadFile("templates/_header.gotpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := regexp.MustCompile(`(?s)\nimport \((.*?)\n\)`).FindSubmatch(header)
+	if block == nil {
+		t.Fatal("no import block in _header.gotpl")
+	}
+
+	// e.g. `"log/slog"` or `rawmodels "github.com/steebchen/prisma-client-go/runtime/types/raw"`
+	spec := regexp.MustCompile(`^\s*(\w+ )?"([^"]+)"$`)
+	found := 0
+	for _, line := range strings.Split(string(block[1]), "\n") {
+		m := spec.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := path.Base(m[2])
+		if alias := strings.TrimSpace(m[1]); alias != "" {
+			name = alias
+		}
+		if name == "_" {
+			continue
+		}
+		found++
+		if !reservedImports[name] {
+			t.Errorf("%s is imported by _header.gotpl as %s but missing in reservedImports", m[2], name)
+		}
+	}
+	if found == 0 {
+		t.Fatal("no imports found in _header.gotpl")
+	}
+
+	for importPath, name := range headerImports {
+		if !reservedImports[name] {
+			t.Errorf("header import %s is missing in reservedImports as %s", importPath, name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+)
+
+// GqlgenFile is the name of the gqlgen model mapping which is written into the output directory
+const GqlgenFile = "gqlgen_models.yml"
+
+// gqlgenScalars maps Prisma scalars which gqlgen has no builtin binding for to the Go types implementing its marshalers
+var gqlgenScalars = map[string]string{
+	"DateTime": "github.com/99designs/gqlgen/graphql.Time",
+	"BigInt":   ".BigInt",
+	"Json":     ".JSON",
+}
+
+// Gqlgen returns whether the client is generated for use with gqlgen
+func (r *Root) Gqlgen() bool {
+	return r.Generator.Config.Gqlgen == "true"
+}
+
+// GqlgenUsesEnums returns whether the gqlgen marshalers of enums are generated, which need additional imports
+func (r *Root) GqlgenUsesEnums() bool {
+	return r.Gqlgen() && len(r.DMMF.Datamodel.Enums) > 0
+}
+
+// gqlgenModels returns the models section of a gqlgen config, which binds the GraphQL types named like the models,
+// composite types, enums and scalars of the schema to the generated Go types of the package pkg
+func (r *Root) gqlgenModels(pkg string) []byte {
+	var b bytes.Buffer
+	b.WriteString("# Code generated by Prisma Client Go. DO NOT EDIT.\n")
+	b.WriteString("# Add these models to the models section of your gqlgen.yml.\n")
+	b.WriteString("models:\n")
+
+	scalars := make(map[string]bool)
+	collect := func(fields []dmmf.Field) {
+		for _, field := range fields {
+			if _, ok := gqlgenScalars[field.Type.String()]; ok && field.Kind == dmmf.FieldKindScalar && r.GoType(field, "") == "" {
+				scalars[field.Type.String()] = true
+			}
+		}
+	}
+
+	for _, model := range r.DMMF.Datamodel.Models {
+		collect(model.Fields)
+		fmt.Fprintf(&b, "  %s:\n    model: %s.%sModel\n", model.Name, pkg, model.Name.GoCase())
+		var fields bytes.Buffer
+		for _, field := range model.Fields {
+			switch {
+			case field.Kind.IsRelation():
+				// relations are only fetched with .With(), so they are resolved by the server
+				fmt.Fprintf(&fields, "      %s:\n        resolver: true\n", field.Name)
+			case !field.IsRequired:
+				fmt.Fprintf(&fields, "      %s:\n        fieldName: %sPtr\n", field.Name, field.Name.GoCase())
+			}
+		}
+		if fields.Len() > 0 {
+			b.WriteString("    fields:\n")
+			b.Write(fields.Bytes())
+		}
+	}
+	for _, t := range r.DMMF.Datamodel.Types {
+		collect(t.Fields)
+		fmt.Fprintf(&b, "  %s:\n    model: %s.%s\n", t.Name, pkg, t.Name.GoCase())
+	}
+	for _, enum := range r.DMMF.Datamodel.Enums {
+		fmt.Fprintf(&b, "  %s:\n    model: %s.%s\n", enum.Name, pkg, enum.Name.GoCase())
+	}
+	for _, scalar := range []string{"BigInt", "DateTime", "Json"} {
+		if !scalars[scalar] {
+			continue
+		}
+		model := gqlgenScalars[scalar]
+		if strings.HasPrefix(model, ".") {
+			model = pkg + model
+		}
+		fmt.Fprintf(&b, "  %s:\n    model: %s\n", scalar, model)
+	}
+	return b.Bytes()
+}
+
+// generateGqlgen writes the gqlgen model mapping into the output directory
+func generateGqlgen(input *Root) error {
+	if !input.Gqlgen() {
+		return nil
+	}
+	pkg, err := input.requireImportPath()
+	if err != nil {
+		return err
+	}
+	if input.SeparateModels() {
+		// the types and their methods are declared in the models package
+		pkg = input.ModelsImportPath()
+	}
+	file := filepath.Join(input.Generator.Output.Value, GqlgenFile)
+	if err := os.WriteFile(file, input.gqlgenModels(pkg), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestGqlgenModels(t *testing.T) {
+	r := &Root{}
+	r.Generator.Config.GoTypes = "@db.Uuid=github.com/google/uuid.UUID"
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[
+		{"name":"User","fields":[
+			{"kind":"scalar","name":"id","type":"String","isRequired":true,"nativeType":["Uuid",[]]},
+			{"kind":"scalar","name":"name","type":"String"},
+			{"kind":"scalar","name":"meta","type":"Json","isRequired":true},
+			{"kind":"enum","name":"role","type":"Role","isRequired":true},
+			{"kind":"object","name":"posts","type":"Post","isList":true}
+		]}
+	],"enums":[{"name":"Role","values":[{"name":"USER"}]}]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.resolveGoTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, `# Code generated by Prisma Client Go. DO NOT EDIT.
+# Add these models to the models section of your gqlgen.yml.
+models:
+  User:
+    model: example.com/app/db.UserModel
+    fields:
+      name:
+        fieldName: NamePtr
+      posts:
+        resolver: true
+  Role:
+    model: example.com/app/db.Role
+  Json:
+    model: example.com/app/db.JSON
+`, string(r.gqlgenModels("example.com/app/db")))
+}
@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+)
+
+// Hook extends the generated client, e.g. with custom methods, repository wrappers or company-specific boilerplate,
+// without forking the generator. Hooks are registered with RegisterHook in a custom generator program, see Serve.
+// Embed BaseHook to only implement some of the methods.
+type Hook interface {
+	// PreModel returns Go code which is added before the generated code of a model. It can use all packages
+	// which the generated client imports.
+	PreModel(r *Root, model dmmf.Model) (string, error)
+	// PostModel returns Go code which is added after the generated code of a model. It can use all packages
+	// which the generated client imports.
+	PostModel(r *Root, model dmmf.Model) (string, error)
+	// Files returns extra files by their name, which are written into the output directory
+	Files(r *Root) (map[string][]byte, error)
+}
+
+// BaseHook implements Hook without adding any code or files
+type BaseHook struct{}
+
+// PreModel returns no code
+func (BaseHook) PreModel(*Root, dmmf.Model) (string, error) {
+	return "", nil
+}
+
+// PostModel returns no code
+func (BaseHook) PostModel(*Root, dmmf.Model) (string, error) {
+	return "", nil
+}
+
+// Files returns no files
+func (BaseHook) Files(*Root) (map[string][]byte, error) {
+	return nil, nil
+}
+
+var hooks []Hook
+
+// RegisterHook adds a hook which is run each time the client is generated
+func RegisterHook(hook Hook) {
+	hooks = append(hooks, hook)
+}
+
+// Templates returns the templates of the generated client, e.g. to copy one into the templates directory to
+// override it
+func Templates() fs.FS {
+	templates, err := fs.Sub(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+const (
+	// preModelTemplate is the template in the templates directory which is rendered before the code of each model
+	preModelTemplate = "pre_model.gotpl"
+	// postModelTemplate is the template in the templates directory which is rendered after the code of each model
+	postModelTemplate = "post_model.gotpl"
+)
+
+// ModelHookData is passed to the pre_model.gotpl and post_model.gotpl templates
+type ModelHookData struct {
+	*Root
+	// Model is the model whose code the template extends
+	Model dmmf.Model
+}
+
+// templateDir returns the path of the templates directory, which is relative to the schema
+func (r *Root) templateDir() string {
+	dir := r.Generator.Config.Templates
+	if dir == "" || filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(schemaengine.SchemaDir(r.SchemaPath), dir)
+}
+
+// customTemplates are the templates of the templates directory
+type customTemplates struct {
+	// overrides replace the built-in templates with the same path
+	overrides map[string]*template.Template
+	// extra are the remaining templates, which are written into their own files
+	extra []*template.Template
+
+	preModel  *template.Template
+	postModel *template.Template
+}
+
+// loadTemplates parses the templates of the templates directory, if one is configured
+func (r *Root) loadTemplates(builtin []string) (*customTemplates, error) {
+	t := &customTemplates{overrides: make(map[string]*template.Template)}
+	dir := r.templateDir()
+	if dir == "" {
+		return t, nil
+	}
+
+	isBuiltin := make(map[string]bool)
+	for _, name := range builtin {
+		isBuiltin[name+".gotpl"] = true
+	}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".gotpl") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		tpl, err := template.ParseFiles(path)
+		if err != nil {
+			return fmt.Errorf("could not parse template %s: %w", rel, err)
+		}
+		switch {
+		case isBuiltin[rel]:
+			t.overrides[rel] = tpl
+		case rel == preModelTemplate:
+			t.preModel = tpl
+		case rel == postModelTemplate:
+			t.postModel = tpl
+		case strings.Contains(rel, "/"):
+			return fmt.Errorf("template %s does not override a built-in template", rel)
+		default:
+			t.extra = append(t.extra, tpl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load templates from %s: %w", dir, err)
+	}
+	return t, nil
+}
+
+// modelHooks renders the code which the pre_model.gotpl or post_model.gotpl template and the hooks add to each model,
+// marked with BeginModel and EndModel
+func (r *Root) modelHooks(tpl *template.Template, hook func(h Hook, r *Root, model dmmf.Model) (string, error)) ([]byte, error) {
+	if tpl == nil && len(hooks) == 0 {
+		return nil, nil
+	}
+	var b bytes.Buffer
+	for _, model := range r.DMMF.Datamodel.Models {
+		b.WriteString(r.BeginModel(model.Name))
+		if tpl != nil {
+			if err := tpl.Execute(&b, ModelHookData{Root: r, Model: model}); err != nil {
+				return nil, fmt.Errorf("could not execute template %s for model %s: %w", tpl.Name(), model.Name, err)
+			}
+		}
+		for _, h := range hooks {
+			code, err := hook(h, r, model)
+			if err != nil {
+				return nil, fmt.Errorf("hook for model %s: %w", model.Name, err)
+			}
+			b.WriteString("\n" + code + "\n")
+		}
+		b.WriteString(r.EndModel())
+	}
+	return b.Bytes(), nil
+}
+
+// hookFiles returns the files of the hooks
+func (r *Root) hookFiles() (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, h := range hooks {
+		hookFiles, err := h.Files(r)
+		if err != nil {
+			return nil, fmt.Errorf("hook files: %w", err)
+		}
+		for name, content := range hookFiles {
+			if name != filepath.Base(name) {
+				return nil, fmt.Errorf("hook file %s must be a file name without directories", name)
+			}
+			if _, ok := files[name]; ok {
+				return nil, fmt.Errorf("hook file %s is returned by multiple hooks", name)
+			}
+			files[name] = content
+		}
+	}
+	return files, nil
+}
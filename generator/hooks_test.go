@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"models.gotpl":       "// custom models",
+		"actions/find.gotpl": "// custom find",
+		"pre_model.gotpl":    "// before {{ $.Model.Name }}",
+		"post_model.gotpl":   "// after {{ $.Model.Name }}",
+		"repository.gotpl":   "// repository",
+		"README.md":          "not a template",
+	} {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Root{SchemaPath: filepath.Join(dir, "schema.prisma")}
+	r.Generator.Config.Templates = "."
+	custom, err := r.loadTemplates([]string{"models", "actions/find", "query"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, 2, len(custom.overrides))
+	massert.Equal(t, "find.gotpl", custom.overrides["actions/find.gotpl"].Name())
+	massert.Equal(t, "pre_model.gotpl", custom.preModel.Name())
+	massert.Equal(t, "post_model.gotpl", custom.postModel.Name())
+	massert.Equal(t, 1, len(custom.extra))
+	massert.Equal(t, "repository.gotpl", custom.extra[0].Name())
+
+	if err := os.WriteFile(filepath.Join(dir, "actions", "custom.gotpl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.loadTemplates([]string{"models", "actions/find", "query"})
+	massert.Equal(t, true, strings.HasSuffix(err.Error(), "template actions/custom.gotpl does not override a built-in template"))
+}
+
+type tableHook struct {
+	BaseHook
+}
+
+func (tableHook) PostModel(r *Root, model dmmf.Model) (string, error) {
+	return "const " + model.Name.GoCase() + "Table = \"" + model.Name.String() + "\"", nil
+}
+
+func TestModelHooks(t *testing.T) {
+	defer func(registered []Hook) {
+		hooks = registered
+	}(hooks)
+	RegisterHook(tableHook{})
+
+	r := &Root{}
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"User"}]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+
+	pre, err := r.modelHooks(nil, Hook.PreModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "\n// --- model User ---\n\n\n\n// --- end model ---\n", string(pre))
+
+	post, err := r.modelHooks(nil, Hook.PostModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "\n// --- model User ---\n\nconst UserTable = \"User\"\n\n// --- end model ---\n", string(post))
+}
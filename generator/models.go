@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+)
+
+// modelsTemplates are the templates which are rendered into the models package, in this order
+var modelsTemplates = []string{
+	"_header",
+	"enums",
+	"models",
+	"composites",
+	"gqlgen",
+	"protobuf",
+}
+
+// ModelsSingleFileName is the name of the file the models package is written to when singleFile is set
+const ModelsSingleFileName = "models_gen.go"
+
+// SeparateModels returns whether the models, composite types and enums are generated into a separate package, which
+// the client refers to with type aliases
+func (r *Root) SeparateModels() bool {
+	return r.Generator.Config.ModelsOutput != ""
+}
+
+// GeneratesModels returns whether the code of the models, composite types and enums is rendered, which is the case
+// for the client unless they are generated into a separate package, and for the models package
+func (r *Root) GeneratesModels() bool {
+	return !r.SeparateModels() || r.modelsPass
+}
+
+// GeneratesClient returns whether the code of the query client is rendered, which is the case unless the models
+// package is rendered
+func (r *Root) GeneratesClient() bool {
+	return !r.modelsPass
+}
+
+// ModelsPackage returns the package name of the models package
+func (r *Root) ModelsPackage() string {
+	return r.Generator.Config.ModelsPackage
+}
+
+// ModelsImportPath returns the import path of the models package
+func (r *Root) ModelsImportPath() string {
+	return r.modelsImportPath
+}
+
+// PackageName returns the name of the package which is rendered, which is the models package while it is rendered
+func (r *Root) PackageName() string {
+	if r.modelsPass {
+		return r.ModelsPackage()
+	}
+	return r.Generator.Config.Package.String()
+}
+
+// modelsDir returns the output directory of the models package, which is relative to the schema
+func (r *Root) modelsDir() string {
+	dir := r.Generator.Config.ModelsOutput
+	if dir == "" || filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(schemaengine.SchemaDir(r.SchemaPath), dir)
+}
+
+// resolveModelsPackage validates the models package options and determines the import path of the models package
+func (r *Root) resolveModelsPackage() error {
+	if !r.SeparateModels() {
+		return nil
+	}
+	dir, err := filepath.Abs(r.modelsDir())
+	if err != nil {
+		return err
+	}
+	output, err := filepath.Abs(r.Generator.Output.Value)
+	if err != nil {
+		return err
+	}
+	if dir == output {
+		return fmt.Errorf("invalid modelsOutput in generator config: must be a different directory than the output of the client")
+	}
+
+	if r.Generator.Config.ModelsPackage == "" {
+		r.Generator.Config.ModelsPackage = filepath.Base(dir)
+	}
+	pkg := r.Generator.Config.ModelsPackage
+	if !token.IsIdentifier(pkg) {
+		return fmt.Errorf("invalid modelsPackage %q in generator config: must be a valid Go identifier, e.g. \"models\"", pkg)
+	}
+	if reservedImports[pkg] {
+		return fmt.Errorf("invalid modelsPackage %q in generator config: conflicts with a package imported by the generated client", pkg)
+	}
+
+	if r.modelsImportPath, err = packageImportPath(dir); err != nil {
+		return fmt.Errorf("could not determine the import path of modelsOutput: %w", err)
+	}
+	return nil
+}
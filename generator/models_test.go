@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestResolveModelsPackage(t *testing.T) {
+	dir := writeModule(t)
+
+	r := &Root{}
+	r.SchemaPath = filepath.Join(dir, "prisma", "schema.prisma")
+	r.Generator.Output = &Value{Value: filepath.Join(dir, "internal", "db")}
+	r.Generator.Config.ModelsOutput = "../internal/models"
+	r.Generator.Config.GoTypes = "@db.Uuid=ID, Decimal=../internal/models.Money"
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"Order","fields":[
+		{"kind":"scalar","name":"id","type":"String","nativeType":["Uuid",[]]},
+		{"kind":"scalar","name":"total","type":"Decimal"}
+	]}]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.resolveImportPath(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.resolveModelsPackage(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.resolveGoTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, "models", r.ModelsPackage())
+	massert.Equal(t, "example.com/app/internal/models", r.ModelsImportPath())
+	massert.Equal(t, filepath.Join(dir, "internal", "models"), r.modelsDir())
+
+	// types without a package are declared in the models package, which the client imports itself
+	fields := r.DMMF.Datamodel.Models[0].Fields
+	massert.Equal(t, "models.ID", r.GoType(fields[0], "string"))
+	massert.Equal(t, "models.Money", r.GoType(fields[1], "Decimal"))
+	massert.Equal(t, []GoType(nil), r.GoTypeImports())
+
+	r.modelsPass = true
+	massert.Equal(t, "ID", r.GoType(fields[0], "string"))
+	massert.Equal(t, "models", r.PackageName())
+	massert.Equal(t, true, r.GeneratesModels())
+	massert.Equal(t, false, r.GeneratesClient())
+}
+
+func TestResolveModelsPackage_invalid(t *testing.T) {
+	dir := writeModule(t)
+
+	tests := []struct {
+		name    string
+		output  string
+		pkg     string
+		goTypes string
+		err     string
+	}{{
+		name:   "same directory as the client",
+		output: "../db",
+		err:    "must be a different directory",
+	}, {
+		name:   "invalid package name",
+		output: "../models",
+		pkg:    "my-models",
+		err:    "must be a valid Go identifier",
+	}, {
+		name:   "reserved package name",
+		output: "../types",
+		err:    "conflicts with a package imported by the generated client",
+	}, {
+		name:    "type of the client package",
+		output:  "../models",
+		goTypes: "String=example.com/app/db.Name",
+		err:     "types of the client package can't be used with modelsOutput",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Root{}
+			r.SchemaPath = filepath.Join(dir, "prisma", "schema.prisma")
+			r.Generator.Output = &Value{Value: filepath.Join(dir, "db")}
+			r.Generator.Config.ModelsOutput = tt.output
+			r.Generator.Config.ModelsPackage = tt.pkg
+			r.Generator.Config.GoTypes = tt.goTypes
+			if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"User","fields":[
+				{"kind":"scalar","name":"name","type":"String"}
+			]}]}}`), &r.DMMF); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.resolveImportPath(); err != nil {
+				t.Fatal(err)
+			}
+			err := r.resolveModelsPackage()
+			if err == nil {
+				err = r.resolveGoTypes()
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+)
+
+// errNoModule is returned by packageImportPath if a directory is not part of a Go module
+var errNoModule = errors.New("no go.mod found")
+
+// ImportPath returns the import path of the generated package, e.g. example.com/app/db, as determined by the go.mod of
+// the module containing the output directory, or an empty string if the output directory is not part of a module
+func (r *Root) ImportPath() string {
+	return r.importPath
+}
+
+// resolveImportPath determines the import path of the generated package from the enclosing go.mod and resolves the
+// relative import paths of the generator config
+func (r *Root) resolveImportPath() error {
+	importPath, err := packageImportPath(r.Generator.Output.Value)
+	if err != nil && !errors.Is(err, errNoModule) {
+		return fmt.Errorf("could not determine the import path of the output directory: %w", err)
+	}
+	r.importPath = importPath
+
+	if r.Generator.Config.ProtobufGoPackage, err = r.resolveImport(r.Generator.Config.ProtobufGoPackage); err != nil {
+		return fmt.Errorf("invalid protobufGoPackage in generator config: %w", err)
+	}
+	return nil
+}
+
+// requireImportPath returns the import path of the generated package, or an error if it is not part of a module
+func (r *Root) requireImportPath() (string, error) {
+	if r.importPath == "" {
+		return "", fmt.Errorf("could not determine the import path of the output directory: %w in %s or any parent directory", errNoModule, r.Generator.Output.Value)
+	}
+	return r.importPath, nil
+}
+
+// isRelativeImport returns whether an import path of the generator config is a directory relative to the schema, e.g.
+// ./internal/ids
+func isRelativeImport(path string) bool {
+	return path == "." || path == ".." || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// resolveImport returns the import path of a package of the generator config, where a directory relative to the
+// schema, e.g. ./internal/ids, is resolved with the go.mod of its module, so that it doesn't depend on the location of
+// the output directory. Other paths are returned as they are.
+func (r *Root) resolveImport(path string) (string, error) {
+	if !isRelativeImport(path) {
+		return path, nil
+	}
+	dir := filepath.Join(schemaengine.SchemaDir(r.SchemaPath), filepath.FromSlash(path))
+	importPath, err := packageImportPath(dir)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %q: %w", path, err)
+	}
+	return importPath, nil
+}
+
+// packageImportPath returns the import path of the package in dir, based on the module path of the enclosing go.mod
+func packageImportPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for current := dir; ; {
+		data, err := os.ReadFile(filepath.Join(current, "go.mod"))
+		if err == nil {
+			module := modulePath(data)
+			if module == "" {
+				return "", fmt.Errorf("no module directive in %s", filepath.Join(current, "go.mod"))
+			}
+			rel, err := filepath.Rel(current, dir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return module, nil
+			}
+			return module + "/" + filepath.ToSlash(rel), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", fmt.Errorf("%w in %s or any parent directory", errNoModule, dir)
+		}
+		current = parent
+	}
+}
+
+// modulePath returns the module path of a go.mod file
+func modulePath(gomod []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(gomod))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// writeModule writes a go.mod of the module example.com/app into a new directory and returns the directory
+func writeModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("// app\nmodule \"example.com/app\"\n\ngo 1.21\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestPackageImportPath(t *testing.T) {
+	dir := writeModule(t)
+	out := filepath.Join(dir, "internal", "db")
+	if err := os.MkdirAll(out, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := packageImportPath(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "example.com/app/internal/db", pkg)
+
+	pkg, err = packageImportPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "example.com/app", pkg)
+
+	_, err = packageImportPath(t.TempDir())
+	massert.Equal(t, true, errors.Is(err, errNoModule))
+}
+
+func TestResolveImportPath(t *testing.T) {
+	dir := writeModule(t)
+
+	r := &Root{}
+	r.SchemaPath = filepath.Join(dir, "prisma", "schema.prisma")
+	r.Generator.Output = &Value{Value: filepath.Join(dir, "internal", "store")}
+	r.Generator.Config.ProtobufGoPackage = "../gen/pb"
+	r.Generator.Config.GoTypes = "@db.Uuid=../internal/ids.ID, Decimal=example.com/app/internal/store.Money"
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"Order","fields":[
+		{"kind":"scalar","name":"id","type":"String","nativeType":["Uuid",[]]},
+		{"kind":"scalar","name":"total","type":"Decimal"}
+	]}]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.resolveImportPath(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.resolveGoTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, "example.com/app/internal/store", r.ImportPath())
+	// relative paths are resolved relative to the schema
+	massert.Equal(t, "example.com/app/gen/pb", r.Generator.Config.ProtobufGoPackage)
+	// types of the generated package are not imported
+	fields := r.DMMF.Datamodel.Models[0].Fields
+	massert.Equal(t, "ids.ID", r.GoType(fields[0], "string"))
+	massert.Equal(t, "Money", r.GoType(fields[1], "Decimal"))
+	massert.Equal(t, []GoType{
+		{Path: "example.com/app/internal/ids", Alias: "ids", Name: "ids.ID"},
+	}, r.GoTypeImports())
+}
+
+func TestResolveImportPath_noModule(t *testing.T) {
+	r := &Root{}
+	r.Generator.Output = &Value{Value: t.TempDir()}
+	if err := r.resolveImportPath(); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "", r.ImportPath())
+
+	// only options which need the import path fail
+	_, err := r.requireImportPath()
+	massert.Equal(t, true, errors.Is(err, errNoModule))
+
+	r.Generator.Config.ProtobufGoPackage = "./pb"
+	r.SchemaPath = filepath.Join(r.Generator.Output.Value, "schema.prisma")
+	err = r.resolveImportPath()
+	massert.Equal(t, true, errors.Is(err, errNoModule))
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+)
+
+// OpenAPIFile is the name of the OpenAPI document which is written into the output directory
+const OpenAPIFile = "openapi.json"
+
+// openAPIScalars contains the schemas of Prisma scalars as the generated models encode them into JSON
+var openAPIScalars = map[string]openAPISchema{
+	"String":   {Type: "string"},
+	"Boolean":  {Type: "boolean"},
+	"Int":      {Type: "integer", Format: "int32"},
+	"BigInt":   {Type: "string", Format: "int64"},
+	"Float":    {Type: "number", Format: "double"},
+	"DateTime": {Type: "string", Format: "date-time"},
+	"Json":     {Type: "string", Description: "JSON encoded value"},
+	"Bytes":    {Type: "string", Format: "byte"},
+	"Decimal":  {Type: "string", Format: "decimal"},
+}
+
+// openAPISchema is an OpenAPI 3.0 schema object
+type openAPISchema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	AllOf       []openAPISchema    `json:"allOf,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Items       *openAPISchema     `json:"items,omitempty"`
+	Properties  *openAPIProperties `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// openAPIProperties are named schemas which are encoded in the order they were added
+type openAPIProperties struct {
+	names   []string
+	schemas map[string]openAPISchema
+}
+
+func (p *openAPIProperties) add(name string, schema openAPISchema) {
+	if p.schemas == nil {
+		p.schemas = make(map[string]openAPISchema)
+	}
+	p.names = append(p.names, name)
+	p.schemas[name] = schema
+}
+
+// MarshalJSON encodes the properties as an object
+func (p *openAPIProperties) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, name := range p.names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// OpenAPI returns whether OpenAPI component schemas are generated
+func (r *Root) OpenAPI() bool {
+	return r.Generator.Config.OpenAPI == "true"
+}
+
+// openAPIRef returns a reference to the component schema with the given name
+func openAPIRef(name string) openAPISchema {
+	return openAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+// openAPIField returns the schema of a field and whether it is required
+func (r *Root) openAPIField(field dmmf.Field) (openAPISchema, bool) {
+	var schema openAPISchema
+	switch field.Kind {
+	case dmmf.FieldKindScalar:
+		schema = openAPIScalars[field.Type.String()]
+	default:
+		schema = openAPIRef(field.Type.String())
+	}
+	if field.IsList {
+		items := schema
+		schema = openAPISchema{Type: "array", Items: &items}
+	}
+
+	// relations are only set when they were fetched
+	isRequired := field.IsRequired && !field.Kind.IsRelation()
+	omit := r.omitEmpty(isRequired)
+	if r.NullableFields() && !isRequired && !field.IsList && !field.Kind.IsRelation() {
+		// omitempty has no effect on types.Nullable, which is encoded as null if it isn't set
+		omit = false
+	}
+	if !isRequired && !omit {
+		// the field is encoded as null if it isn't set
+		if schema.Ref != "" {
+			// siblings of $ref are ignored in OpenAPI 3.0
+			schema = openAPISchema{AllOf: []openAPISchema{schema}}
+		}
+		schema.Nullable = true
+	}
+	return schema, !omit
+}
+
+// openAPIObject returns the schema of a model or composite type
+func (r *Root) openAPIObject(model dmmf.Model) openAPISchema {
+	schema := openAPISchema{
+		Type:        "object",
+		Description: strings.TrimSpace(model.Documentation),
+		Properties:  &openAPIProperties{},
+	}
+	for _, field := range model.Fields {
+		property, required := r.openAPIField(field)
+		if doc := field.Doc(); doc != "" {
+			if property.Ref != "" {
+				property = openAPISchema{AllOf: []openAPISchema{property}}
+			}
+			property.Description = doc
+		}
+		name := r.JSONName(field.Name)
+		schema.Properties.add(name, property)
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// openAPIDocument returns an OpenAPI 3.0 document with the component schemas of the models, composite types and enums
+func (r *Root) openAPIDocument() ([]byte, error) {
+	schemas := &openAPIProperties{}
+	for _, model := range r.DMMF.Datamodel.Models {
+		schemas.add(model.Name.String(), r.openAPIObject(model))
+	}
+	for _, t := range r.DMMF.Datamodel.Types {
+		schemas.add(t.Name.String(), r.openAPIObject(t))
+	}
+	for _, enum := range r.DMMF.Datamodel.Enums {
+		schema := openAPISchema{Type: "string", Description: strings.TrimSpace(enum.Documentation)}
+		for _, v := range enum.Values {
+			schema.Enum = append(schema.Enum, v.Name.String())
+		}
+		schemas.add(enum.Name.String(), schema)
+	}
+
+	document := struct {
+		OpenAPI    string            `json:"openapi"`
+		Info       map[string]string `json:"info"`
+		Paths      struct{}          `json:"paths"`
+		Components struct {
+			Schemas *openAPIProperties `json:"schemas"`
+		} `json:"components"`
+	}{
+		OpenAPI: "3.0.3",
+		// the document is meant to be merged into the spec of the API, which has its own info
+		Info: map[string]string{
+			"title":   "Prisma Client Go models",
+			"version": "1.0.0",
+		},
+	}
+	document.Components.Schemas = schemas
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// generateOpenAPI writes the OpenAPI document into the output directory
+func generateOpenAPI(input *Root) error {
+	if !input.OpenAPI() {
+		return nil
+	}
+	data, err := input.openAPIDocument()
+	if err != nil {
+		return fmt.Errorf("could not encode OpenAPI document: %w", err)
+	}
+	file := filepath.Join(input.Generator.Output.Value, OpenAPIFile)
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", file, err)
+	}
+	return nil
+}
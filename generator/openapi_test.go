@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	r := &Root{}
+	r.Generator.Config.JSONCase = JSONCaseSnake
+	r.Generator.Config.JSONOmitEmpty = JSONOmitNone
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[
+		{"name":"User","documentation":"A user.","fields":[
+			{"kind":"scalar","name":"id","type":"String","isRequired":true},
+			{"kind":"scalar","name":"displayName","type":"String","documentation":"The public name.\n@go.type: Name"},
+			{"kind":"scalar","name":"createdAt","type":"DateTime","isRequired":true},
+			{"kind":"scalar","name":"tags","type":"String","isRequired":true,"isList":true},
+			{"kind":"enum","name":"role","type":"Role"},
+			{"kind":"object","name":"posts","type":"Post","isList":true}
+		]}
+	],"enums":[{"name":"Role","values":[{"name":"USER"},{"name":"ADMIN"}]}]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := r.openAPIDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document struct {
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+		"Role": {"type": "string", "enum": ["USER", "ADMIN"]},
+		"User": {
+			"type": "object",
+			"description": "A user.",
+			"properties": {
+				"id": {"type": "string"},
+				"display_name": {"type": "string", "nullable": true, "description": "The public name."},
+				"created_at": {"type": "string", "format": "date-time"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"role": {"allOf": [{"$ref": "#/components/schemas/Role"}], "nullable": true},
+				"posts": {"type": "array", "items": {"$ref": "#/components/schemas/Post"}, "nullable": true}
+			},
+			"required": ["id", "display_name", "created_at", "tags", "role", "posts"]
+		}
+	}`
+	var want map[string]interface{}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, want, document.Components.Schemas)
+}
+
+func TestOpenAPIDocument_nullableFields(t *testing.T) {
+	r := &Root{}
+	r.Generator.Config.OptionalFields = OptionalFieldsNullable
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[
+		{"name":"User","fields":[
+			{"kind":"scalar","name":"id","type":"String","isRequired":true},
+			{"kind":"scalar","name":"name","type":"String"},
+			{"kind":"object","name":"posts","type":"Post","isList":true}
+		]}
+	]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+
+	user := r.openAPIObject(r.DMMF.Datamodel.Models[0])
+	// nullable fields are encoded as null instead of being omitted, while relations are still omitted
+	massert.Equal(t, []string{"id", "name"}, user.Required)
+	massert.Equal(t, openAPISchema{Type: "string", Nullable: true}, user.Properties.schemas["name"])
+}
@@ -0,0 +1,420 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+	"github.com/steebchen/prisma-client-go/helpers/strcase"
+)
+
+// ProtobufFile is the name of the protobuf definitions which are written into the output directory
+const ProtobufFile = "models.proto"
+
+// protoScalars maps Prisma scalars to protobuf types
+var protoScalars = map[string]string{
+	"String":   "string",
+	"Boolean":  "bool",
+	"Int":      "int32",
+	"BigInt":   "int64",
+	"Float":    "double",
+	"DateTime": "google.protobuf.Timestamp",
+	"Json":     "string",
+	"Bytes":    "bytes",
+	"Decimal":  "string",
+}
+
+// protoConversions contains the Go expressions which convert a client value into a protobuf value and back, where
+// %s is the value; scalars which are not listed are the same type in both
+var protoConversions = map[string][2]string{
+	"Int":      {"int32(%s)", "int(%s)"},
+	"BigInt":   {"int64(%s)", "BigInt(%s)"},
+	"DateTime": {"timestamppb.New(%s)", "%s.AsTime()"},
+	"Json":     {"string(%s)", "JSON(%s)"},
+	"Decimal":  {"%s.String()", "decimal.NewFromString(%s)"},
+}
+
+// ProtoMessage is a protobuf message of a model or composite type
+type ProtoMessage struct {
+	// Name is the name of the message, e.g. UserAccount for the user_accounts model
+	Name string
+	// GoName is the name of the Go struct generated by protoc-gen-go
+	GoName string
+	// Doc contains the documentation of the model as comment lines
+	Doc    string
+	Fields []ProtoField
+}
+
+// ProtoField is a field of a protobuf message
+type ProtoField struct {
+	Field dmmf.Field
+	// Name is the snake_case name of the field
+	Name string
+	// GoName is the name of the Go struct field generated by protoc-gen-go
+	GoName string
+	// Type is the protobuf type, e.g. int32 or google.protobuf.Timestamp
+	Type string
+	// Number is the field number, which is the position of the field in the model
+	Number int
+
+	// src is the Go expression of the client value, e.g. r.InnerUser.Name
+	src string
+	// dst is the Go expression which is assigned when converting from protobuf, e.g. out.InnerUser.Name
+	dst string
+	// convert is false for fields with a custom Go type, which are not converted
+	convert bool
+	// nullable is true for optional fields which are a types.Nullable instead of a pointer
+	nullable bool
+}
+
+// ProtoEnum is a protobuf enum of an enum
+type ProtoEnum struct {
+	Name   string
+	GoName string
+	Enum   dmmf.Enum
+	// Unspecified is the value 0, which protobuf enums require
+	Unspecified string
+	Values      []ProtoEnumValue
+}
+
+// ProtoEnumValue is a value of a protobuf enum
+type ProtoEnumValue struct {
+	// Name is the prefixed value, e.g. ROLE_ADMIN
+	Name   string
+	Number int
+	Value  dmmf.EnumValue
+}
+
+// Protobuf returns whether protobuf definitions are generated
+func (r *Root) Protobuf() bool {
+	return r.Generator.Config.Protobuf == "true"
+}
+
+// ProtobufConverters returns whether converters between the models and the protobuf messages are generated
+func (r *Root) ProtobufConverters() bool {
+	return r.Protobuf() && r.Generator.Config.ProtobufGoPackage != ""
+}
+
+// protoName returns the name of a message or enum
+func protoName(name string) string {
+	return strcase.ToUpperCamel(name)
+}
+
+// ProtoEnums returns the protobuf enums of the enums of the schema
+func (r *Root) ProtoEnums() []ProtoEnum {
+	var enums []ProtoEnum
+	for _, enum := range r.DMMF.Datamodel.Enums {
+		name := protoName(enum.Name.String())
+		prefix := strings.ToUpper(strcase.ToSnake(name)) + "_"
+		e := ProtoEnum{
+			Name:        name,
+			GoName:      goCamelCase(name),
+			Enum:        enum,
+			Unspecified: prefix + "UNSPECIFIED",
+		}
+		for i, v := range enum.Values {
+			e.Values = append(e.Values, ProtoEnumValue{
+				Name:   prefix + strings.ToUpper(strcase.ToSnake(v.Name.String())),
+				Number: i + 1,
+				Value:  v,
+			})
+		}
+		enums = append(enums, e)
+	}
+	return enums
+}
+
+// ProtoMessages returns the protobuf messages of the models
+func (r *Root) ProtoMessages() []ProtoMessage {
+	var messages []ProtoMessage
+	for _, model := range r.DMMF.Datamodel.Models {
+		inner := "Inner" + model.Name.GoCase()
+		relations := "Relations" + model.Name.GoCase()
+		messages = append(messages, r.protoMessage(model, "r", func(field dmmf.Field) string {
+			if field.Kind.IsRelation() {
+				return relations
+			}
+			return inner
+		}))
+	}
+	return messages
+}
+
+// ProtoCompositeMessages returns the protobuf messages of the composite types
+func (r *Root) ProtoCompositeMessages() []ProtoMessage {
+	var messages []ProtoMessage
+	for _, t := range r.DMMF.Datamodel.Types {
+		messages = append(messages, r.protoMessage(t, "v", func(dmmf.Field) string {
+			return ""
+		}))
+	}
+	return messages
+}
+
+// protoMessage returns the message of a model or composite type, where receiver is the name of the value in the
+// converter and embedded returns the embedded struct which contains a field, or an empty string if the field is
+// declared in the struct itself
+func (r *Root) protoMessage(model dmmf.Model, receiver string, embedded func(dmmf.Field) string) ProtoMessage {
+	name := protoName(model.Name.String())
+	m := ProtoMessage{
+		Name:   name,
+		GoName: goCamelCase(name),
+		Doc:    model.DocComment(),
+	}
+	for i, field := range model.Fields {
+		f := ProtoField{
+			Field:   field,
+			Name:    strcase.ToSnake(field.Name.String()),
+			Number:  i + 1,
+			convert: r.GoType(field, "") == "",
+		}
+		f.GoName = goCamelCase(f.Name)
+		f.nullable = r.NullableFields() && !field.IsRequired && !field.IsList && !field.Kind.IsRelation()
+		switch field.Kind {
+		case dmmf.FieldKindScalar:
+			f.Type = protoScalars[field.Type.String()]
+		default:
+			f.Type = protoName(field.Type.String())
+		}
+		path := field.Name.GoCase()
+		if e := embedded(field); e != "" {
+			path = e + "." + path
+		}
+		f.src, f.dst = receiver+"."+path, "out."+path
+		m.Fields = append(m.Fields, f)
+	}
+	return m
+}
+
+// Label returns the label of the field, which is optional for nullable scalars and enums, or repeated for lists
+func (f ProtoField) Label() string {
+	switch {
+	case f.Field.IsList:
+		return "repeated "
+	case !f.Field.IsRequired && !f.isMessage():
+		return "optional "
+	}
+	return ""
+}
+
+// isMessage returns whether the field is a message, which protoc-gen-go generates as a pointer
+func (f ProtoField) isMessage() bool {
+	return f.Field.Kind.IsRelation() || f.Field.Kind.IsComposite() || f.Field.Type == "DateTime"
+}
+
+// toProto returns the Go expression which converts the client value v into the protobuf value
+func (f ProtoField) toProto(v string) string {
+	switch f.Field.Kind {
+	case dmmf.FieldKindScalar:
+		if c, ok := protoConversions[f.Field.Type.String()]; ok {
+			if strings.HasPrefix(c[0], "%s.") {
+				// methods can be called on pointers
+				v = strings.TrimPrefix(v, "*")
+			}
+			return fmt.Sprintf(c[0], v)
+		}
+		return v
+	default:
+		return strings.TrimPrefix(v, "*") + ".ToProto()"
+	}
+}
+
+// fromProto returns the Go expression which converts the protobuf value x into the client value, and whether the
+// expression also returns an error
+func (f ProtoField) fromProto(x string) (string, bool) {
+	switch f.Field.Kind {
+	case dmmf.FieldKindScalar:
+		if c, ok := protoConversions[f.Field.Type.String()]; ok {
+			return fmt.Sprintf(c[1], x), f.Field.Type == "Decimal"
+		}
+		return x, false
+	default:
+		return fmt.Sprintf("%sFromProto(%s)", f.Field.Type.GoCase(), x), true
+	}
+}
+
+// ToProto returns the Go statements which set the field of the protobuf message m
+func (f ProtoField) ToProto() string {
+	if !f.convert {
+		return ""
+	}
+	dst := "m." + f.GoName
+	isSet, value := f.src+" != nil", "*"+f.src
+	if f.nullable {
+		isSet, value = f.src+".Valid", f.src+".Value"
+	}
+	switch {
+	case f.Field.IsList && f.toProto("v") == "v":
+		return fmt.Sprintf("%s = %s", dst, f.src)
+	case f.Field.IsList:
+		return fmt.Sprintf("for _, v := range %s {\n%s = append(%s, %s)\n}", f.src, dst, dst, f.toProto("v"))
+	case f.Field.Kind.IsRelation(), !f.Field.IsRequired && f.isMessage():
+		return fmt.Sprintf("if %s {\n%s = %s\n}", isSet, dst, f.toProto(value))
+	case !f.Field.IsRequired:
+		return fmt.Sprintf("if %s {\n%s = types.Ptr(%s)\n}", isSet, dst, f.toProto(value))
+	}
+	return fmt.Sprintf("%s = %s", dst, f.toProto(f.src))
+}
+
+// FromProto returns the Go statements which set the field of the value out from the protobuf message m
+func (f ProtoField) FromProto() string {
+	if !f.convert {
+		return ""
+	}
+	src := "m." + f.GoName
+	set := "&v"
+	if f.nullable {
+		set = "types.NullableOf(v)"
+	}
+	convert := func(x string) string {
+		expr, fails := f.fromProto(x)
+		if !fails {
+			return fmt.Sprintf("v := %s", expr)
+		}
+		return fmt.Sprintf("v, err := %s\nif err != nil {\nreturn out, fmt.Errorf(\"%s: %%w\", err)\n}", expr, f.Field.Name)
+	}
+	switch {
+	case f.Field.IsList && convert("x") == "v := x":
+		return fmt.Sprintf("%s = %s", f.dst, src)
+	case f.Field.IsList:
+		return fmt.Sprintf("for _, x := range %s {\n%s\n%s = append(%s, v)\n}", src, convert("x"), f.dst, f.dst)
+	case f.Field.Kind.IsRelation(), !f.Field.IsRequired && f.isMessage():
+		return fmt.Sprintf("if %s != nil {\n%s\n%s = %s\n}", src, convert(src), f.dst, set)
+	case !f.Field.IsRequired:
+		return fmt.Sprintf("if %s != nil {\n%s\n%s = %s\n}", src, convert("*"+src), f.dst, set)
+	}
+	if expr, fails := f.fromProto(src); !fails {
+		return fmt.Sprintf("%s = %s", f.dst, expr)
+	}
+	return fmt.Sprintf("{\n%s\n%s = v\n}", convert(src), f.dst)
+}
+
+// protoUses returns whether a converted field of a model or composite type has the given scalar type
+func (r *Root) protoUses(scalar string) bool {
+	for _, m := range append(r.ProtoMessages(), r.ProtoCompositeMessages()...) {
+		for _, f := range m.Fields {
+			if f.convert && f.Field.Kind == dmmf.FieldKindScalar && f.Field.Type.String() == scalar {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ProtobufImportsTimestamp returns whether the converters use the timestamppb package
+func (r *Root) ProtobufImportsTimestamp() bool {
+	return r.ProtobufConverters() && r.protoUses("DateTime")
+}
+
+// ProtobufImportsDecimal returns whether the converters parse decimals
+func (r *Root) ProtobufImportsDecimal() bool {
+	return r.ProtobufConverters() && r.protoUses("Decimal")
+}
+
+// protoFile returns the protobuf definitions of the models, composite types and enums
+func (r *Root) protoFile() []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by Prisma Client Go. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	pkg := r.Generator.Config.ProtobufPackage
+	if pkg == "" {
+		pkg = r.Generator.Config.Package.String()
+	}
+	fmt.Fprintf(&b, "package %s;\n", pkg)
+
+	messages := append(r.ProtoMessages(), r.ProtoCompositeMessages()...)
+	if usesTimestamp(messages) {
+		b.WriteString("\nimport \"google/protobuf/timestamp.proto\";\n")
+	}
+	if goPackage := r.Generator.Config.ProtobufGoPackage; goPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", goPackage)
+	}
+
+	for _, m := range messages {
+		b.WriteString("\n")
+		if m.Doc != "" {
+			b.WriteString(m.Doc + "\n")
+		}
+		fmt.Fprintf(&b, "message %s {\n", m.Name)
+		for _, f := range m.Fields {
+			if doc := f.Field.DocComment(); doc != "" {
+				b.WriteString("  " + strings.ReplaceAll(doc, "\n", "\n  ") + "\n")
+			}
+			fmt.Fprintf(&b, "  %s%s %s = %d;\n", f.Label(), f.Type, f.Name, f.Number)
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, e := range r.ProtoEnums() {
+		b.WriteString("\n")
+		if doc := e.Enum.DocComment(); doc != "" {
+			b.WriteString(doc + "\n")
+		}
+		fmt.Fprintf(&b, "enum %s {\n  %s = 0;\n", e.Name, e.Unspecified)
+		for _, v := range e.Values {
+			fmt.Fprintf(&b, "  %s = %d;\n", v.Name, v.Number)
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+// usesTimestamp returns whether a field of the messages is a timestamp
+func usesTimestamp(messages []ProtoMessage) bool {
+	for _, m := range messages {
+		for _, f := range m.Fields {
+			if f.Type == protoScalars["DateTime"] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateProtobuf writes the protobuf definitions into the output directory
+func generateProtobuf(input *Root) error {
+	if !input.Protobuf() {
+		return nil
+	}
+	file := filepath.Join(input.Generator.Output.Value, ProtobufFile)
+	if err := os.WriteFile(file, input.protoFile(), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", file, err)
+	}
+	return nil
+}
+
+// goCamelCase returns the Go name which protoc-gen-go generates for a protobuf name, e.g. AuthorId for author_id
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// skip the underscore, the following letter is upper-cased
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestGoCamelCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"id", "Id"},
+		{"author_id", "AuthorId"},
+		{"created_at", "CreatedAt"},
+		{"address_2", "Address_2"},
+		{"_internal", "XInternal"},
+		{"UserAccount", "UserAccount"},
+	}
+	for _, tt := range tests {
+		massert.Equal(t, tt.want, goCamelCase(tt.name))
+	}
+}
+
+func TestProtoFile(t *testing.T) {
+	r := &Root{}
+	r.Generator.Config.Package = "db"
+	r.Generator.Config.ProtobufGoPackage = "example.com/app/pb"
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[
+		{"name":"user_accounts","documentation":"An account.","fields":[
+			{"kind":"scalar","name":"id","type":"String","isRequired":true},
+			{"kind":"scalar","name":"displayName","type":"String","documentation":"The public name."},
+			{"kind":"scalar","name":"balance","type":"Decimal","isRequired":true},
+			{"kind":"scalar","name":"createdAt","type":"DateTime","isRequired":true},
+			{"kind":"scalar","name":"tags","type":"String","isRequired":true,"isList":true},
+			{"kind":"enum","name":"role","type":"Role"},
+			{"kind":"object","name":"posts","type":"Post","isList":true}
+		]}
+	],"enums":[{"name":"Role","values":[{"name":"USER"},{"name":"superAdmin"}]}]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, `// Code generated by Prisma Client Go. DO NOT EDIT.
+
+syntax = "proto3";
+
+package db;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/app/pb";
+
+// An account.
+message UserAccounts {
+  string id = 1;
+  // The public name.
+  optional string display_name = 2;
+  string balance = 3;
+  google.protobuf.Timestamp created_at = 4;
+  repeated string tags = 5;
+  optional Role role = 6;
+  repeated Post posts = 7;
+}
+
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+  ROLE_USER = 1;
+  ROLE_SUPER_ADMIN = 2;
+}
+`, string(r.protoFile()))
+}
+
+func TestProtoField_nullable(t *testing.T) {
+	r := &Root{}
+	r.Generator.Config.OptionalFields = OptionalFieldsNullable
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[
+		{"name":"User","fields":[
+			{"kind":"scalar","name":"name","type":"String"},
+			{"kind":"scalar","name":"deletedAt","type":"DateTime"}
+		]}
+	]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := r.ProtoMessages()[0].Fields
+	massert.Equal(t, "if r.InnerUser.Name.Valid {\nm.Name = types.Ptr(r.InnerUser.Name.Value)\n}", fields[0].ToProto())
+	massert.Equal(t, "if m.Name != nil {\nv := *m.Name\nout.InnerUser.Name = types.NullableOf(v)\n}", fields[0].FromProto())
+	massert.Equal(t, "if r.InnerUser.DeletedAt.Valid {\nm.DeletedAt = timestamppb.New(r.InnerUser.DeletedAt.Value)\n}", fields[1].ToProto())
+	massert.Equal(t, "if m.DeletedAt != nil {\nv := m.DeletedAt.AsTime()\nout.InnerUser.DeletedAt = types.NullableOf(v)\n}", fields[1].FromProto())
+}
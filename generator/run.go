@@ -6,6 +6,7 @@ import (
 	"embed"
 	"fmt"
 	"go/format"
+	"go/token"
 	"os"
 	"path"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/steebchen/prisma-client-go/binaries"
 	"github.com/steebchen/prisma-client-go/binaries/bindata"
 	"github.com/steebchen/prisma-client-go/binaries/platform"
+	"github.com/steebchen/prisma-client-go/generator/types"
 	"github.com/steebchen/prisma-client-go/logger"
 )
 
@@ -39,6 +41,64 @@ func addDefaults(input *Root) {
 func Run(input *Root) error {
 	addDefaults(input)
 
+	if pkg := input.Generator.Config.Package.String(); !token.IsIdentifier(pkg) {
+		return fmt.Errorf("invalid package name %q in generator config: must be a valid Go identifier, e.g. \"db\"", pkg)
+	}
+
+	switch c := input.Generator.Config.JSONCase; c {
+	case "", JSONCaseDeclared, JSONCaseCamel, JSONCaseSnake:
+	default:
+		return fmt.Errorf("invalid jsonCase %q in generator config: must be %q, %q or %q", c, JSONCaseDeclared, JSONCaseCamel, JSONCaseSnake)
+	}
+
+	switch c := input.Generator.Config.JSONOmitEmpty; c {
+	case "", JSONOmitOptional, JSONOmitAll, JSONOmitNone:
+	default:
+		return fmt.Errorf("invalid jsonOmitEmpty %q in generator config: must be %q, %q or %q", c, JSONOmitOptional, JSONOmitAll, JSONOmitNone)
+	}
+
+	switch o := input.Generator.Config.OptionalFields; o {
+	case "", OptionalFieldsPointer, OptionalFieldsNullable:
+	default:
+		return fmt.Errorf("invalid optionalFields %q in generator config: must be %q or %q", o, OptionalFieldsPointer, OptionalFieldsNullable)
+	}
+
+	switch n := input.Generator.Config.GoNaming; n {
+	case "", GoNamingIdiomatic, GoNamingCamel:
+	default:
+		return fmt.Errorf("invalid goNaming %q in generator config: must be %q or %q", n, GoNamingIdiomatic, GoNamingCamel)
+	}
+
+	converter, err := input.Generator.Config.GoNameConverter()
+	if err != nil {
+		return fmt.Errorf("invalid goInitialisms %q in generator config: %w", input.Generator.Config.GoInitialisms, err)
+	}
+	types.SetConverter(converter)
+
+	if err := input.excludeModels(); err != nil {
+		return err
+	}
+
+	if err := input.validateSoftDelete(); err != nil {
+		return err
+	}
+
+	if err := input.validateVersion(); err != nil {
+		return err
+	}
+
+	if err := input.resolveImportPath(); err != nil {
+		return err
+	}
+
+	if err := input.resolveModelsPackage(); err != nil {
+		return err
+	}
+
+	if err := input.resolveGoTypes(); err != nil {
+		return err
+	}
+
 	if input.Version != binaries.EngineVersion {
 		fmt.Printf("\nwarning: prisma CLI version mismatch detected. CLI version: %s, internal version: %s (%s); please see https://github.com/steebchen/prisma-client-go/issues/1099 for details\n\n", input.Version, binaries.EngineVersion, binaries.PrismaVersion)
 	}
@@ -47,11 +107,17 @@ func Run(input *Root) error {
 		logger.Debug.Printf("writing gitignore file")
 		// generate a gitignore into the folder
 		var gitignore = "# gitignore generated by Prisma Client Go. DO NOT EDIT.\n*_gen.go\n"
-		if err := os.MkdirAll(input.Generator.Output.Value, os.ModePerm); err != nil {
-			return fmt.Errorf("could not create output directory: %w", err)
+		dirs := []string{input.Generator.Output.Value}
+		if input.SeparateModels() {
+			dirs = append(dirs, input.modelsDir())
 		}
-		if err := os.WriteFile(path.Join(input.Generator.Output.Value, ".gitignore"), []byte(gitignore), 0644); err != nil {
-			return fmt.Errorf("could not write .gitignore: %w", err)
+		for _, dir := range dirs {
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				return fmt.Errorf("could not create output directory: %w", err)
+			}
+			if err := os.WriteFile(path.Join(dir, ".gitignore"), []byte(gitignore), 0644); err != nil {
+				return fmt.Errorf("could not write .gitignore: %w", err)
+			}
 		}
 	}
 
@@ -59,6 +125,18 @@ func Run(input *Root) error {
 		return fmt.Errorf("generate client: %w", err)
 	}
 
+	if err := generateGqlgen(input); err != nil {
+		return fmt.Errorf("generate gqlgen models: %w", err)
+	}
+
+	if err := generateProtobuf(input); err != nil {
+		return fmt.Errorf("generate protobuf: %w", err)
+	}
+
+	if err := generateOpenAPI(input); err != nil {
+		return fmt.Errorf("generate OpenAPI schemas: %w", err)
+	}
+
 	if err := generateBinaries(input); err != nil {
 		return fmt.Errorf("generate binaries: %w", err)
 	}
@@ -70,8 +148,6 @@ func Run(input *Root) error {
 var templateFS embed.FS
 
 func generateClient(input *Root) error {
-	var buf bytes.Buffer
-
 	// manually define the order of the templates for consistent output
 	files := []string{
 		"_header",
@@ -80,58 +156,206 @@ func generateClient(input *Root) error {
 		"errors",
 		"fields",
 		"mock",
+		"interfaces",
+		"factories",
 		"models",
+		"composites",
+		"gqlgen",
+		"protobuf",
+		"dataloaders",
+		"metadata",
 		"query",
 		"actions/actions",
 		"actions/create",
 		"actions/find",
+		"actions/raw",
 		"actions/transaction",
 		"actions/upsert",
 	}
 
+	custom, err := input.loadTemplates(files)
+	if err != nil {
+		return err
+	}
+
+	output := input.Generator.Output.Value
+
+	if strings.HasSuffix(output, ".go") {
+		return fmt.Errorf("generator output should be a directory")
+	}
+
+	sources, header, err := input.renderPackage(files, custom)
+	if err != nil {
+		return err
+	}
+
+	if len(custom.extra) > 0 {
+		prelude, _, err := splitHeader(header)
+		if err != nil {
+			return err
+		}
+		for _, tpl := range custom.extra {
+			var body bytes.Buffer
+			if err := tpl.Execute(&body, input); err != nil {
+				return fmt.Errorf("could not write template file %s: %w", tpl.Name(), err)
+			}
+			name := strings.TrimSuffix(tpl.Name(), ".gotpl") + "_gen.go"
+			if _, ok := sources[name]; ok {
+				return fmt.Errorf("template %s conflicts with the generated file %s", tpl.Name(), name)
+			}
+			content, err := buildFile(prelude, body.Bytes(), false)
+			if err != nil {
+				return fmt.Errorf("could not build %s from template %s: %w", name, tpl.Name(), err)
+			}
+			sources[name] = content
+		}
+	}
+
+	extra, err := input.hookFiles()
+	if err != nil {
+		return err
+	}
+	for name, content := range extra {
+		if _, ok := sources[name]; ok {
+			return fmt.Errorf("hook file %s conflicts with a generated file", name)
+		}
+		sources[name] = content
+	}
+
+	if err := writeSources(output, sources); err != nil {
+		return err
+	}
+
+	if input.SeparateModels() {
+		if err := generateModels(input, custom); err != nil {
+			return fmt.Errorf("generate models package: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// generateModels writes the models package into the directory of the modelsOutput option
+func generateModels(input *Root, custom *customTemplates) error {
+	input.modelsPass = true
+	defer func() {
+		input.modelsPass = false
+	}()
+
+	sources, _, err := input.renderPackage(modelsTemplates, custom)
+	if err != nil {
+		return err
+	}
+	return writeSources(input.modelsDir(), sources)
+}
+
+// renderPackage renders the templates into the files of a package, and returns them with the rendered header
+func (r *Root) renderPackage(files []string, custom *customTemplates) (map[string][]byte, []byte, error) {
 	var templates []*template.Template
 	for _, file := range files {
+		if t, ok := custom.overrides[file+".gotpl"]; ok {
+			templates = append(templates, t)
+			continue
+		}
 		t, err := template.ParseFS(templateFS, "templates/"+file+".gotpl")
 		if err != nil {
-			return fmt.Errorf("could not parse template fs: %w", err)
+			return nil, nil, fmt.Errorf("could not parse template fs: %w", err)
 		}
 		templates = append(templates, t)
 	}
 
-	// Then process all remaining templates
-	for _, tpl := range templates {
+	// the models package only contains the generated code of the models, without the code added by hooks
+	withHooks := r.GeneratesClient()
+
+	var buf, header bytes.Buffer
+	for i, tpl := range templates {
 		buf.Write([]byte(fmt.Sprintf("// --- template %s ---\n", tpl.Name())))
 
-		if err := tpl.Execute(&buf, input); err != nil {
-			return fmt.Errorf("could not write template file %s: %w", tpl.Name(), err)
+		out := &buf
+		if i == 0 {
+			out = &header
+		}
+		if err := tpl.Execute(out, r); err != nil {
+			return nil, nil, fmt.Errorf("could not write template file %s: %w", tpl.Name(), err)
+		}
+
+		if i == 0 {
+			buf.Write(header.Bytes())
+
+			if withHooks {
+				// code which is added before the code of each model
+				pre, err := r.modelHooks(custom.preModel, Hook.PreModel)
+				if err != nil {
+					return nil, nil, err
+				}
+				buf.Write(pre)
+			}
 		}
 
 		if _, err := format.Source(buf.Bytes()); err != nil {
-			return fmt.Errorf("could not format source %s from file %s %s: %w", buf.String(), tpl.Name(), input.SchemaPath, err)
+			return nil, nil, fmt.Errorf("could not format source %s from file %s %s: %w", buf.String(), tpl.Name(), r.SchemaPath, err)
 		}
 	}
 
-	formatted, err := format.Source(buf.Bytes())
-	if err != nil {
-		return fmt.Errorf("could not format final source: %w", err)
+	if withHooks {
+		// code which is added after the code of each model
+		post, err := r.modelHooks(custom.postModel, Hook.PostModel)
+		if err != nil {
+			return nil, nil, err
+		}
+		buf.Write(post)
 	}
 
-	output := input.Generator.Output.Value
+	shared, singleFile := "client", SingleFileName
+	if r.modelsPass {
+		shared, singleFile = "models", ModelsSingleFileName
+	}
 
-	if strings.HasSuffix(output, ".go") {
-		return fmt.Errorf("generator output should be a directory")
+	sources := make(map[string][]byte)
+	if r.SingleFile() {
+		src := stripModelMarkers(buf.Bytes())
+		if r.SeparateModels() {
+			// the header imports the packages of both the client and the models package, so the unused ones are removed
+			prelude, rest, err := splitHeader(src)
+			if err != nil {
+				return nil, nil, err
+			}
+			if src, err = buildFile(prelude, rest, true); err != nil {
+				return nil, nil, fmt.Errorf("could not format final source: %w", err)
+			}
+		}
+		formatted, err := format.Source(src)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not format final source: %w", err)
+		}
+		sources[singleFile] = formatted
+	} else {
+		split, err := splitClient(buf.Bytes(), shared)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not split source into files: %w", err)
+		}
+		sources = split
 	}
+	return sources, header.Bytes(), nil
+}
 
-	if err := os.MkdirAll(output, os.ModePerm); err != nil {
-		return fmt.Errorf("could not run MkdirAll on path %s: %w", output, err)
+// writeSources writes the generated files into dir and removes the files which were generated before but are no
+// longer needed
+func writeSources(dir string, sources map[string][]byte) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("could not run MkdirAll on path %s: %w", dir, err)
 	}
 
-	// TODO make this configurable
-	outFile := path.Join(output, "db_gen.go")
-	if err := os.WriteFile(outFile, formatted, 0644); err != nil {
-		return fmt.Errorf("could not write template data to file writer %s: %w", outFile, err)
+	if err := removeStaleFiles(dir, sources); err != nil {
+		return fmt.Errorf("could not remove previously generated files: %w", err)
 	}
 
+	for name, content := range sources {
+		outFile := path.Join(dir, name)
+		if err := os.WriteFile(outFile, content, 0644); err != nil {
+			return fmt.Errorf("could not write template data to file writer %s: %w", outFile, err)
+		}
+	}
 	return nil
 }
 
@@ -140,7 +364,7 @@ func generateBinaries(input *Root) error {
 		return nil
 	}
 
-	if input.GetEngineType() == "dataproxy" {
+	if input.UsesDataProxy() {
 		logger.Debug.Printf("using data proxy; not fetching any engines")
 		return nil
 	}
@@ -197,7 +421,10 @@ func generateQueryEngineFiles(binaryTargets []string, pkg, outputDir string) err
 
 		name = TransformBinaryTarget(name)
 
-		enginePath := binaries.GetEnginePath(binaries.GlobalCacheDir(), "query-engine", name)
+		enginePath, err := binaries.ResolveEngine(binaries.GetEnginePath(binaries.GlobalCacheDir(), "query-engine", name))
+		if err != nil {
+			return fmt.Errorf("resolve query engine: %w", err)
+		}
 
 		filename := fmt.Sprintf("query-engine-%s_gen.go", name)
 		to := path.Join(outputDir, filename)
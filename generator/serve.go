@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+
+	"github.com/steebchen/prisma-client-go/jsonrpc"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+const DmmfWriteKey = "PRISMA_CLIENT_GO_WRITE_DMMF_FILE"
+
+var writeDebugFile = os.Getenv(DmmfWriteKey) != ""
+
+// Serve answers the generator requests of the Prisma CLI, which are read from r, by generating the client, and writes
+// the responses to w. It allows custom generator programs to register hooks before serving, e.g.
+//
+//	func main() {
+//		generator.RegisterHook(repositoryHook{})
+//		if err := generator.Serve(os.Stdin, os.Stderr); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func Serve(r io.Reader, w io.Writer) error {
+	if logger.Enabled || writeDebugFile {
+		dir, _ := os.Getwd()
+		log.Printf("current working dir: %s", dir)
+	}
+
+	return jsonrpc.Serve(r, w, handleRequest)
+}
+
+func handleRequest(input jsonrpc.Request) (interface{}, error) {
+	if writeDebugFile {
+		content, err := json.Marshal(input)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal request: %w", err)
+		}
+		if err := os.WriteFile("dmmf.json", content, 0600); err != nil {
+			return nil, fmt.Errorf("could not write dmmf.json: %w", err)
+		}
+	}
+
+	switch input.Method {
+	case "getManifest":
+		return jsonrpc.ManifestResponse{
+			Manifest: jsonrpc.Manifest{
+				DefaultOutput: path.Join(".", "db"),
+				PrettyName:    "Prisma Client Go",
+			},
+		}, nil
+
+	case "generate":
+		var params Root
+
+		if err := json.Unmarshal(input.Params, &params); err != nil {
+			dir, _ := os.Getwd()
+			return nil, fmt.Errorf("could not unmarshal params into generator.Root type at %s: %w", dir, err)
+		}
+
+		Transform(&params)
+
+		if err := Run(&params); err != nil {
+			return nil, fmt.Errorf("could not generate code. %w", err)
+		}
+
+		return nil, nil // success
+	default:
+		return nil, &jsonrpc.Error{
+			Code:    jsonrpc.CodeMethodNotFound,
+			Message: fmt.Sprintf("no such method %s", input.Method),
+		}
+	}
+}
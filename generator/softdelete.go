@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+)
+
+// validateSoftDelete checks the fields selected with a `/// @go.softDelete: <field>` comment, which must be optional
+// DateTime fields, as a record is deleted by setting the field to the current time
+func (r *Root) validateSoftDelete() error {
+	for _, model := range r.DMMF.Datamodel.Models {
+		name := model.SoftDeleteFieldName()
+		if name == "" {
+			continue
+		}
+		if model.IsView {
+			return fmt.Errorf("invalid @go.softDelete on view %s: views are read-only", model.Name)
+		}
+		field := model.SoftDeleteField()
+		if field.Name == "" {
+			return fmt.Errorf("invalid @go.softDelete on model %s: field %q does not exist", model.Name, name)
+		}
+		if field.Kind != dmmf.FieldKindScalar || field.Type != "DateTime" || field.IsRequired || field.IsList {
+			return fmt.Errorf("invalid @go.softDelete on model %s: field %s must be an optional DateTime field, e.g. `%s DateTime?`", model.Name, name, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestValidateSoftDelete(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		field string
+		err   string
+	}{{
+		name:  "optional DateTime",
+		doc:   "users\n@go.softDelete: deletedAt",
+		field: `{"kind":"scalar","name":"deletedAt","type":"DateTime"}`,
+	}, {
+		name:  "unknown field",
+		doc:   "@go.softDelete: removedAt",
+		field: `{"kind":"scalar","name":"deletedAt","type":"DateTime"}`,
+		err:   `field "removedAt" does not exist`,
+	}, {
+		name:  "required field",
+		doc:   "@go.softDelete: deletedAt",
+		field: `{"kind":"scalar","name":"deletedAt","type":"DateTime","isRequired":true}`,
+		err:   "must be an optional DateTime field",
+	}, {
+		name:  "other type",
+		doc:   "@go.softDelete: deleted",
+		field: `{"kind":"scalar","name":"deleted","type":"Boolean"}`,
+		err:   "must be an optional DateTime field",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Root{}
+			doc, _ := json.Marshal(tt.doc)
+			if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"User","documentation":`+string(doc)+`,"fields":[
+				{"kind":"scalar","name":"id","type":"String","isId":true},
+				`+tt.field+`
+			]}]}}`), &r.DMMF); err != nil {
+				t.Fatal(err)
+			}
+
+			err := r.validateSoftDelete()
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				model := r.DMMF.Datamodel.Models[0]
+				massert.Equal(t, "deletedAt", model.SoftDeleteField().Name.String())
+				massert.Equal(t, "// users", model.DocComment())
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/generator/types"
+	"github.com/steebchen/prisma-client-go/helpers/strcase"
+)
+
+// SingleFileName is the name of the file the client is written to when singleFile is set
+const SingleFileName = "db_gen.go"
+
+const (
+	templateMarker = "// --- template "
+	modelMarker    = "// --- model "
+	endModelMarker = "// --- end model ---"
+	generatedLine  = "// Code generated by Prisma Client Go. DO NOT EDIT."
+)
+
+// SingleFile returns whether the client is written into a single file instead of a file per model
+func (r *Root) SingleFile() bool {
+	return r.Generator.Config.SingleFile == "true"
+}
+
+// BeginModel marks the start of the code of a model, which is written into the file of the model, e.g.
+// {{ $.BeginModel $model.Name }}
+func (r *Root) BeginModel(name types.String) string {
+	return "\n" + modelMarker + name.String() + " ---\n"
+}
+
+// EndModel marks the end of the code of a model started with BeginModel
+func (r *Root) EndModel() string {
+	return "\n" + endModelMarker + "\n"
+}
+
+// isModelMarker returns whether line marks the start or the end of the code of a model
+func isModelMarker(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, modelMarker) || line == endModelMarker
+}
+
+// stripModelMarkers removes the markers of BeginModel and EndModel from the generated source
+func stripModelMarkers(src []byte) []byte {
+	var b bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(nil, len(src)+1)
+	for scanner.Scan() {
+		if isModelMarker(scanner.Text()) {
+			continue
+		}
+		b.Write(scanner.Bytes())
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// splitClient splits the generated source into a file for each model and a file for the remaining code of each
+// template, which shares the package clause and the imports of the header. The declarations of the header go into the
+// file of the shared template, e.g. client_gen.go. Files without code are left out.
+func splitClient(src []byte, shared string) (map[string][]byte, error) {
+	templateFiles := make(map[string]string)
+	var fileNames []string
+	bodies := make(map[string]*bytes.Buffer)
+	write := func(file, line string) {
+		body, ok := bodies[file]
+		if !ok {
+			body = &bytes.Buffer{}
+			bodies[file] = body
+			fileNames = append(fileNames, file)
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+
+	var file, model string
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(nil, len(src)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, templateMarker):
+			name := strings.TrimSuffix(strings.TrimPrefix(trimmed, templateMarker), " ---")
+			name = strings.TrimSuffix(path.Base(name), ".gotpl")
+			if name == "_header" {
+				// the header declarations are shared, so they go into the shared file
+				name = shared
+			}
+			file = name + "_gen.go"
+			templateFiles[file] = name
+		case strings.HasPrefix(trimmed, modelMarker):
+			model = strings.TrimSuffix(strings.TrimPrefix(trimmed, modelMarker), " ---")
+		case trimmed == endModelMarker:
+			model = ""
+		case model != "":
+			write(model, line)
+		default:
+			write(file, line)
+		}
+	}
+
+	sharedFile := shared + "_gen.go"
+	header, ok := bodies[sharedFile]
+	if !ok {
+		return nil, fmt.Errorf("generated source has no header")
+	}
+	prelude, rest, err := splitHeader(header.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	bodies[sharedFile] = bytes.NewBuffer(rest)
+
+	files := make(map[string][]byte)
+	for _, name := range fileNames {
+		if len(bytes.TrimSpace(bodies[name].Bytes())) == 0 {
+			continue
+		}
+		fileName := name
+		if _, isTemplate := templateFiles[name]; !isTemplate {
+			// name is a model
+			fileName = strcase.ToSnake(name) + "_gen.go"
+			if _, taken := templateFiles[fileName]; taken {
+				fileName = strcase.ToSnake(name) + "_model_gen.go"
+			}
+		}
+		content, err := buildFile(prelude, bodies[name].Bytes(), fileName == sharedFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not build %s: %w", fileName, err)
+		}
+		files[fileName] = content
+	}
+	return files, nil
+}
+
+// splitHeader splits the generated header into the prelude, which contains the package clause and the imports, and
+// the remaining declarations
+func splitHeader(header []byte) ([]byte, []byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", header, parser.ParseComments|parser.ImportsOnly)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse header: %w", err)
+	}
+	if len(file.Decls) == 0 {
+		return nil, nil, fmt.Errorf("header has no imports")
+	}
+	end := fset.Position(file.Decls[len(file.Decls)-1].End()).Offset
+	return header[:end:end], header[end:], nil
+}
+
+// buildFile returns a formatted file of the prelude of the header followed by body, without the unused imports
+func buildFile(prelude, body []byte, keepBlank bool) ([]byte, error) {
+	source := append(append(append([]byte{}, prelude...), '\n'), body...)
+	source, err := pruneImports(source, keepBlank)
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(source)
+}
+
+// pruneImports removes the imports which the source doesn't use, as each split file gets all imports of the header.
+// Blank imports are only kept if keepBlank is set, so that they are not repeated in each file.
+func pruneImports(src []byte, keepBlank bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var decl *ast.GenDecl
+	for _, d := range file.Decls {
+		if d, ok := d.(*ast.GenDecl); ok && d.Tok == token.IMPORT && d.Lparen.IsValid() {
+			decl = d
+			break
+		}
+	}
+	if decl == nil {
+		return src, nil
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			// identifiers which are not declared in the file may refer to an import
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	line := func(pos token.Pos) int {
+		return fset.Position(pos).Line
+	}
+	removed := make(map[int]bool)
+	kept := make(map[int]bool)
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ImportSpec)
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		lines := removed
+		if (name == "_" && keepBlank) || used[name] {
+			lines = kept
+		}
+		start := line(spec.Pos())
+		if spec.Doc != nil {
+			start = line(spec.Doc.Pos())
+		}
+		for l := start; l <= line(spec.End()); l++ {
+			lines[l] = true
+		}
+	}
+
+	// rebuild the import block from the groups of imports which are still used
+	lines := strings.SplitAfter(string(src), "\n")
+	var groups []string
+	var group strings.Builder
+	var hasSpec bool
+	flush := func() {
+		if hasSpec {
+			groups = append(groups, group.String())
+		}
+		group.Reset()
+		hasSpec = false
+	}
+	for l := line(decl.Lparen) + 1; l < line(decl.Rparen); l++ {
+		text := lines[l-1]
+		switch {
+		case strings.TrimSpace(text) == "":
+			flush()
+		case removed[l]:
+		default:
+			group.WriteString(text)
+			hasSpec = hasSpec || kept[l]
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, text := range lines[:line(decl.Pos())-1] {
+		b.WriteString(text)
+	}
+	if len(groups) > 0 {
+		b.WriteString("import (\n")
+		b.WriteString(strings.Join(groups, "\n"))
+		b.WriteString(")\n")
+	}
+	for _, text := range lines[line(decl.End()):] {
+		b.WriteString(text)
+	}
+	return []byte(b.String()), nil
+}
+
+// importName returns the default name of an imported package, e.g. yaml for gopkg.in/yaml.v3 and chi for
+// github.com/go-chi/chi/v5
+func importName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elements[len(elements)-2]
+	}
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// removeStaleFiles removes client files from output which were generated before, but are not part of files, e.g.
+// when a model was removed or singleFile was changed
+func removeStaleFiles(output string, files map[string][]byte) error {
+	entries, err := os.ReadDir(output)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, "_gen.go") || strings.HasPrefix(name, "query-engine-") {
+			continue
+		}
+		if _, ok := files[name]; ok {
+			continue
+		}
+		file := filepath.Join(output, name)
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if len(content) > 512 {
+			content = content[:512]
+		}
+		if !bytes.Contains(content, []byte(generatedLine)) {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
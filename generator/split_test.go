@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+const splitSource = `// --- template _header.gotpl ---
+// Code generated by Prisma Client Go. DO NOT EDIT.
+
+package db
+
+import (
+	"context"
+	"fmt"
+
+	// no-op import for go modules
+	_ "github.com/joho/godotenv"
+
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+type DateTime = types.DateTime
+// --- template client.gotpl ---
+func connect(ctx context.Context) {}
+// --- template models.gotpl ---
+
+// --- model User ---
+type UserModel struct {
+	CreatedAt DateTime
+}
+
+func (r UserModel) String() string {
+	return fmt.Sprint(r.CreatedAt)
+}
+// --- end model ---
+
+// --- model Client ---
+type ClientModel struct{}
+// --- end model ---
+// --- template enums.gotpl ---
+`
+
+func TestSplitClient(t *testing.T) {
+	files, err := splitClient([]byte(splitSource), "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	massert.Equal(t, []string{"client_gen.go", "client_model_gen.go", "user_gen.go"}, names)
+
+	massert.Equal(t, `// Code generated by Prisma Client Go. DO NOT EDIT.
+
+package db
+
+import (
+	"context"
+
+	// no-op import for go modules
+	_ "github.com/joho/godotenv"
+
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+type DateTime = types.DateTime
+
+func connect(ctx context.Context) {}
+`, string(files["client_gen.go"]))
+
+	massert.Equal(t, `// Code generated by Prisma Client Go. DO NOT EDIT.
+
+package db
+
+import (
+	"fmt"
+)
+
+type UserModel struct {
+	CreatedAt DateTime
+}
+
+func (r UserModel) String() string {
+	return fmt.Sprint(r.CreatedAt)
+}
+`, string(files["user_gen.go"]))
+
+	massert.Equal(t, `// Code generated by Prisma Client Go. DO NOT EDIT.
+
+package db
+
+type ClientModel struct{}
+`, string(files["client_model_gen.go"]))
+}
+
+func TestStripModelMarkers(t *testing.T) {
+	src := "type A struct{}\n\n// --- model User ---\ntype B struct{}\n\t// --- end model ---\n"
+	massert.Equal(t, "type A struct{}\n\ntype B struct{}\n", string(stripModelMarkers([]byte(src))))
+}
+
+func TestImportName(t *testing.T) {
+	massert.Equal(t, "json", importName("encoding/json"))
+	massert.Equal(t, "yaml", importName("gopkg.in/yaml.v3"))
+	massert.Equal(t, "chi", importName("github.com/go-chi/chi/v5"))
+	massert.Equal(t, "go_cmp", importName("example.com/go-cmp"))
+}
+
+func TestRemoveStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("db_gen.go", generatedLine+"\npackage db\n")
+	write("user_gen.go", generatedLine+"\npackage db\n")
+	write("post_gen.go", generatedLine+"\npackage db\n")
+	write("query-engine-debian-openssl-3.0.x_gen.go", generatedLine+"\npackage db\n")
+	write("custom_gen.go", "package db\n")
+	write("helpers.go", "package db\n")
+
+	if err := removeStaleFiles(dir, map[string][]byte{"user_gen.go": nil}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	massert.Equal(t, []string{"custom_gen.go", "helpers.go", "query-engine-debian-openssl-3.0.x_gen.go", "user_gen.go"}, names)
+}
@@ -10,6 +10,9 @@ import (
 
 // Transform builds the AST from the flat DMMF so it can be used properly in templates
 func Transform(input *Root) {
+	input.DMMF.Sort()
+	input.markViews()
+	input.DMMF.Datamodel.ResolveCompositeTypes()
 	input.AST = transform.New(&input.DMMF)
 	if os.Getenv("DEBUG") != "" {
 		d, _ := json.MarshalIndent(input.AST, "", "  ")
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+const unsortedDocument = `{
+	"datamodel":{
+		"models":[
+			{"name":"User","fields":[{"kind":"scalar","name":"id","type":"String"},{"kind":"scalar","name":"email","type":"String"}]},
+			{"name":"Post","fields":[{"kind":"scalar","name":"id","type":"String"}]}
+		],
+		"enums":[
+			{"name":"Status","values":[{"name":"DRAFT"},{"name":"PUBLISHED"}]},
+			{"name":"Role","values":[{"name":"USER"},{"name":"ADMIN"}]}
+		]
+	},
+	"schema":{
+		"inputObjectTypes":{"prisma":[
+			{"name":"UserWhereInput","fields":[{"name":"id","inputTypes":[{"type":"String","location":"scalar"}]}]},
+			{"name":"PostWhereInput","fields":[{"name":"views","inputTypes":[{"type":"Int","location":"scalar"}]}]},
+			{"name":"DateTimeFilter","fields":[{"name":"equals","inputTypes":[{"type":"DateTime","location":"scalar"}]}]}
+		]},
+		"enumTypes":{"model":[
+			{"name":"Status","values":["DRAFT","PUBLISHED"]},
+			{"name":"Role","values":["USER","ADMIN"]}
+		]}
+	}
+}`
+
+func TestTransform_sorted(t *testing.T) {
+	r := &Root{}
+	if err := json.Unmarshal([]byte(unsortedDocument), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+	Transform(r)
+
+	var models, fields, enums, values []string
+	for _, model := range r.DMMF.Datamodel.Models {
+		models = append(models, model.Name.String())
+	}
+	for _, field := range r.DMMF.Datamodel.Models[1].Fields {
+		fields = append(fields, field.Name.String())
+	}
+	for _, enum := range r.AST.Enums {
+		enums = append(enums, enum.Name.String())
+	}
+	for _, value := range r.AST.Enums[0].Values {
+		values = append(values, value.String())
+	}
+
+	massert.Equal(t, []string{"Post", "User"}, models)
+	massert.Equal(t, []string{"id", "email"}, fields)
+	massert.Equal(t, []string{"Role", "Status"}, enums)
+	massert.Equal(t, []string{"USER", "ADMIN"}, values)
+	massert.Equal(t, []string{"DateTime", "Int", "String"}, r.AST.Scalars)
+}
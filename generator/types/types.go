@@ -7,6 +7,22 @@ import (
 	"github.com/steebchen/prisma-client-go/helpers/strcase"
 )
 
+// converter converts names into Go identifiers. It uses the default initialisms unless the generator configures the
+// naming with SetConverter.
+var converter, _ = gocase.New()
+
+// defaultConverter converts builtin type names, which always refer to the same Go types regardless of the naming
+var defaultConverter = converter
+
+// SetConverter sets how names are converted into Go identifiers by GoCase and GoLowerCase, e.g. to add initialisms.
+// It is called by the generator before generating a client; nil restores the default.
+func SetConverter(c *gocase.Converter) {
+	if c == nil {
+		c, _ = gocase.New()
+	}
+	converter = c
+}
+
 // String acts as a builtin string but provides useful casing methods.
 type String string
 
@@ -16,12 +32,12 @@ func (s String) String() string {
 
 // GoCase transforms strings into Go-style casing, meaning uppercase including Go casing edge cases.
 func (s String) GoCase() string {
-	return gocase.ToUpper(string(s))
+	return converter.To(string(s), true)
 }
 
 // GoLowerCase transforms strings into Go-style lowercase casing. It is like GoCase but used for private fields.
 func (s String) GoLowerCase() string {
-	return gocase.ToLower(string(s))
+	return converter.To(string(s), false)
 }
 
 // CamelCase transforms strings into camelCase casing. It is often used for json mappings.
@@ -75,17 +91,25 @@ func (t Type) Value() string {
 		return v
 	}
 
-	return gocase.ToUpper(strcase.ToUpperCamel(str))
+	return converter.To(strcase.ToUpperCamel(str), true)
 }
 
 // GoCase transforms strings into Go-style lowercase casing. It is like GoCase but used for private fields.
 func (t Type) GoCase() string {
-	return gocase.ToUpper(string(t))
+	return t.converter().To(string(t), true)
 }
 
 // GoLowerCase transforms strings into Go-style lowercase casing. It is like GoCase but used for private fields.
 func (t Type) GoLowerCase() string {
-	return gocase.ToLower(string(t))
+	return t.converter().To(string(t), false)
+}
+
+// converter returns the converter for the type name, which is the default one for builtin types
+func (t Type) converter() *gocase.Converter {
+	if _, ok := builtin[string(t)]; ok {
+		return defaultConverter
+	}
+	return converter
 }
 
 // CamelCase transforms strings into camelCase casing. It is often used for json mappings.
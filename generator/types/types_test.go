@@ -3,6 +3,8 @@ package types
 import (
 	"fmt"
 	"testing"
+
+	"github.com/steebchen/prisma-client-go/helpers/gocase"
 )
 
 func TestString_GoCase(t *testing.T) {
@@ -30,3 +32,24 @@ func TestString_GoCase(t *testing.T) {
 		})
 	}
 }
+
+func TestSetConverter(t *testing.T) {
+	c, err := gocase.New(gocase.WithInitialisms("SKU"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetConverter(c)
+	defer SetConverter(nil)
+
+	if got := String("product_sku_id").GoCase(); got != "ProductSKUId" {
+		t.Errorf("GoCase() = %v, want %v", got, "ProductSKUId")
+	}
+	if got := Type("Json").GoCase(); got != "JSON" {
+		t.Errorf("GoCase() = %v, want %v", got, "JSON")
+	}
+
+	SetConverter(nil)
+	if got := String("product_sku_id").GoCase(); got != "ProductSkuID" {
+		t.Errorf("GoCase() = %v, want %v", got, "ProductSkuID")
+	}
+}
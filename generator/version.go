@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+)
+
+// versionTypes are the types of fields which can be used for optimistic locking
+var versionTypes = map[string]bool{
+	"Int":      true,
+	"BigInt":   true,
+	"DateTime": true,
+}
+
+// validateVersion checks the fields selected with a `/// @go.version` comment, which must be required Int, BigInt or
+// DateTime fields, as they are bumped on each update
+func (r *Root) validateVersion() error {
+	for _, model := range r.DMMF.Datamodel.Models {
+		var version dmmf.Field
+		for _, field := range model.Fields {
+			if !field.IsVersion() {
+				continue
+			}
+			if version.Name != "" {
+				return fmt.Errorf("invalid @go.version on model %s: only one field can be the version, but both %s and %s are", model.Name, version.Name, field.Name)
+			}
+			if model.IsView {
+				return fmt.Errorf("invalid @go.version on view %s: views are read-only", model.Name)
+			}
+			if field.Kind != dmmf.FieldKindScalar || !versionTypes[field.Type.String()] || !field.IsRequired || field.IsList || field.IsID {
+				return fmt.Errorf("invalid @go.version on field %s.%s: must be a required Int, BigInt or DateTime field which is not the id", model.Name, field.Name)
+			}
+			version = field
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestValidateVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		err    string
+	}{{
+		name:   "Int",
+		fields: `{"kind":"scalar","name":"version","type":"Int","isRequired":true,"documentation":"@go.version"}`,
+	}, {
+		name:   "DateTime",
+		fields: `{"kind":"scalar","name":"updatedAt","type":"DateTime","isRequired":true,"isUpdatedAt":true,"documentation":"last change\n@go.version"}`,
+	}, {
+		name:   "optional field",
+		fields: `{"kind":"scalar","name":"version","type":"Int","documentation":"@go.version"}`,
+		err:    "must be a required Int, BigInt or DateTime field",
+	}, {
+		name:   "other type",
+		fields: `{"kind":"scalar","name":"version","type":"String","isRequired":true,"documentation":"@go.version"}`,
+		err:    "must be a required Int, BigInt or DateTime field",
+	}, {
+		name: "several fields",
+		fields: `{"kind":"scalar","name":"version","type":"Int","isRequired":true,"documentation":"@go.version"},
+			{"kind":"scalar","name":"updatedAt","type":"DateTime","isRequired":true,"documentation":"@go.version"}`,
+		err: "only one field can be the version",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Root{}
+			if err := json.Unmarshal([]byte(`{"datamodel":{"models":[{"name":"Post","fields":[
+				{"kind":"scalar","name":"id","type":"String","isRequired":true,"isId":true},
+				`+tt.fields+`
+			]}]}}`), &r.DMMF); err != nil {
+				t.Fatal(err)
+			}
+
+			err := r.validateVersion()
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				version := r.DMMF.Datamodel.Models[0].VersionField()
+				massert.Equal(t, true, version.IsVersion())
+				massert.Equal(t, false, strings.Contains(version.Doc(), "@go.version"))
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package generator
+
+import "regexp"
+
+var viewPattern = regexp.MustCompile(`(?m)^\s*view\s+(\w+)\s*\{`)
+
+// markViews sets IsView for the models which are declared as views in the schema, so that only read queries are
+// generated for them
+func (r *Root) markViews() {
+	views := make(map[string]bool)
+	for _, match := range viewPattern.FindAllStringSubmatch(r.Datamodel, -1) {
+		views[match[1]] = true
+	}
+	for i, model := range r.DMMF.Datamodel.Models {
+		r.DMMF.Datamodel.Models[i].IsView = views[model.Name.String()]
+	}
+}
@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestMarkViews(t *testing.T) {
+	r := &Root{}
+	r.Datamodel = `model User {
+  id String @id
+  // view Commented {
+}
+
+/// statistics of the users
+view UserStats {
+  userId String @unique
+}
+
+  view	Indented{
+  id String @unique
+}
+`
+	if err := json.Unmarshal([]byte(`{"datamodel":{"models":[
+		{"name":"User","fields":[]},
+		{"name":"UserStats","fields":[]},
+		{"name":"Indented","fields":[]},
+		{"name":"Commented","fields":[]}
+	]}}`), &r.DMMF); err != nil {
+		t.Fatal(err)
+	}
+	r.markViews()
+
+	views := make(map[string]bool)
+	for _, model := range r.DMMF.Datamodel.Models {
+		views[model.Name.String()] = model.IsView
+	}
+	massert.Equal(t, map[string]bool{
+		"User":      false,
+		"UserStats": true,
+		"Indented":  true,
+		"Commented": false,
+	}, views)
+	massert.Equal(t, 2, len(r.DMMF.Datamodel.WritableModels()))
+}
@@ -0,0 +1,36 @@
+package strcase
+
+import (
+	"strings"
+)
+
+// ToSnake converts a string to snake_case, e.g. "userID" to "user_id" and "HTTPServer" to "http_server"
+func ToSnake(s string) string {
+	s = strings.TrimSpace(s)
+
+	n := strings.Builder{}
+	n.Grow(len(s) + 4)
+	b := []byte(s)
+	for i, v := range b {
+		vIsCap := v >= 'A' && v <= 'Z'
+		if v == ' ' || v == '-' || v == '.' {
+			v = '_'
+		}
+		if vIsCap && i > 0 && b[i-1] != '_' {
+			prev := b[i-1]
+			prevIsLow := prev >= 'a' && prev <= 'z'
+			prevIsNum := prev >= '0' && prev <= '9'
+			prevIsCap := prev >= 'A' && prev <= 'Z'
+			nextIsLow := i+1 < len(b) && b[i+1] >= 'a' && b[i+1] <= 'z'
+			if prevIsLow || prevIsNum || (prevIsCap && nextIsLow) {
+				n.WriteByte('_')
+			}
+		}
+		if vIsCap {
+			v += 'a'
+			v -= 'A'
+		}
+		n.WriteByte(v)
+	}
+	return n.String()
+}
@@ -0,0 +1,30 @@
+package strcase
+
+import (
+	"testing"
+)
+
+func TestToSnake(t *testing.T) {
+	cases := [][]string{
+		{"testCase", "test_case"},
+		{"TestCase", "test_case"},
+		{"test_case", "test_case"},
+		{"test", "test"},
+		{"id", "id"},
+		{"ID", "id"},
+		{"userID", "user_id"},
+		{"HTTPServer", "http_server"},
+		{"createdAt", "created_at"},
+		{"address2Line", "address2_line"},
+		{"odd-fix", "odd_fix"},
+		{"", ""},
+	}
+	for _, i := range cases {
+		in := i[0]
+		out := i[1]
+		result := ToSnake(in)
+		if result != out {
+			t.Errorf("%q (%q != %q)", in, result, out)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package queryengine runs commands of the Prisma query engine binary which don't need a database, e.g. to parse a
+// schema, which is shared by the introspect and schema packages.
+package queryengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/binaries"
+	"github.com/steebchen/prisma-client-go/binaries/platform"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// Error is returned when the query engine rejected a schema
+type Error struct {
+	// Code is the Prisma error code, e.g. "P1012" for schema validation errors
+	Code string
+
+	// Message describes the error, including the location of each problem in the schema
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return e.Message
+}
+
+// Binary returns the path of the query engine, which is downloaded to the cache if necessary
+func Binary() (string, error) {
+	if file := os.Getenv(binaries.QueryEngineEnv); file != "" {
+		logger.Debug.Printf("%s is defined, using %s", binaries.QueryEngineEnv, file)
+		return file, nil
+	}
+
+	dir := binaries.GlobalCacheDir()
+	name := platform.BinaryPlatformNameStatic()
+	if err := binaries.FetchEngine(dir, "query-engine", name); err != nil {
+		return "", fmt.Errorf("fetch query engine: %w", err)
+	}
+	return binaries.ResolveEngine(binaries.GetEnginePath(dir, "query-engine", name))
+}
+
+// DMMF parses schema and returns its DMMF document as JSON. env is added to the environment of the query engine, e.g.
+// to set the datasource url. If the schema is invalid, an *Error is returned.
+func DMMF(ctx context.Context, schema string, env []string) ([]byte, error) {
+	file, err := Binary()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, file, "cli", "dmmf") //nolint:gosec
+	cmd.Env = append(os.Environ(), "PRISMA_DML="+base64.StdEncoding.EncodeToString([]byte(schema)))
+	cmd.Env = append(cmd.Env, env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("get dmmf: %w", err)
+		}
+		if engineErr := parseError(stderr.String()); engineErr != nil {
+			return nil, engineErr
+		}
+		return nil, fmt.Errorf("get dmmf: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseError returns the error the query engine printed as JSON, or nil if output contains no such error
+func parseError(output string) *Error {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		var parsed struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(lines[i]), &parsed); err != nil || parsed.Message == "" {
+			continue
+		}
+		return &Error{
+			Code:    parsed.ErrorCode,
+			Message: parsed.Message,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package schemaengine
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File is a file of a Prisma schema
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ReadSchema reads the schema at path, which is either a single file or a directory. In a directory, all .prisma files
+// including those in sub directories form the schema, like with the prismaSchemaFolder preview feature of Prisma.
+func ReadSchema(path string) ([]File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+
+	if !info.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read schema: %w", err)
+		}
+		return []File{{Path: path, Content: string(content)}}, nil
+	}
+
+	var files []File
+	err = filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(name) != ".prisma" {
+			return nil
+		}
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: name, Content: string(content)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("read schema: no .prisma files in %s", path)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+// JoinSchema returns the content of all schema files, e.g. to look up the datasource
+func JoinSchema(files []File) string {
+	parts := make([]string, len(files))
+	for i, file := range files {
+		parts[i] = file.Content
+	}
+	return strings.Join(parts, "\n")
+}
+
+// SchemaDir returns the directory of the schema at path, which is the directory itself for multi-file schemas. Paths
+// in the schema such as SQLite urls, and the migrations directory, are relative to it.
+func SchemaDir(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path
+	}
+	return filepath.Dir(path)
+}
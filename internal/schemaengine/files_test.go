@@ -0,0 +1,67 @@
+package schemaengine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadSchema_file(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.prisma")
+	writeFile(t, path, schema)
+
+	files, err := ReadSchema(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []File{{Path: path, Content: schema}}, files)
+	massert.Equal(t, dir, SchemaDir(path))
+}
+
+func TestReadSchema_dir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "schema.prisma"), schema)
+	writeFile(t, filepath.Join(dir, "models", "user.prisma"), "model User {\n  id String @id\n}\n")
+	writeFile(t, filepath.Join(dir, "account.prisma"), "model Account {\n  id String @id\n}\n")
+	writeFile(t, filepath.Join(dir, "migrations", "migration_lock.toml"), `provider = "postgresql"`)
+
+	files, err := ReadSchema(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	massert.Equal(t, []string{
+		filepath.Join(dir, "account.prisma"),
+		filepath.Join(dir, "models", "user.prisma"),
+		filepath.Join(dir, "schema.prisma"),
+	}, paths)
+	massert.Equal(t, dir, SchemaDir(dir))
+
+	// the datasource is found in any of the files
+	name, ok := DatasourceEnv(JoinSchema(files))
+	massert.Equal(t, true, ok)
+	massert.Equal(t, "POSTGRES_URL", name)
+}
+
+func TestReadSchema_empty(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ReadSchema(dir)
+	massert.Equal(t, "read schema: no .prisma files in "+dir, err.Error())
+}
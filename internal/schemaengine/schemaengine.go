@@ -0,0 +1,520 @@
+// Package schemaengine runs the Prisma schema engine and sends JSON RPC requests to it, which is shared by the
+// migrate and introspect packages.
+package schemaengine
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/steebchen/prisma-client-go/binaries"
+	"github.com/steebchen/prisma-client-go/binaries/platform"
+	"github.com/steebchen/prisma-client-go/jsonrpc"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// BinaryEnv overrides the path of the schema engine binary
+const BinaryEnv = "PRISMA_SCHEMA_ENGINE_BINARY"
+
+// Error is returned when the schema engine failed to execute a command, e.g. because a migration failed
+type Error struct {
+	// Code is the Prisma error code, e.g. "P3009", if the error is a known error
+	Code string
+
+	// Message describes the error
+	Message string
+
+	// Meta contains additional information, depending on the error code
+	Meta json.RawMessage
+
+	// IsPanic is true if the schema engine panicked
+	IsPanic bool
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return e.Message
+}
+
+// Engine is a running schema engine process, which receives newline-delimited JSON RPC requests on stdin and
+// answers them on stdout
+type Engine struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr sync.WaitGroup
+
+	onLog   func(level, message string)
+	nextID  int
+	printed strings.Builder
+
+	// tempDir contains a copy of the schema which is removed on Close
+	tempDir string
+
+	// lastError contains the last error the schema engine logged, which explains why it exited
+	mu        sync.Mutex
+	lastError string
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Binary returns the path of the schema engine, which is downloaded to the cache if necessary
+func Binary() (string, error) {
+	if file := os.Getenv(BinaryEnv); file != "" {
+		logger.Debug.Printf("%s is defined, using %s", BinaryEnv, file)
+		return file, nil
+	}
+
+	dir := binaries.GlobalCacheDir()
+	name := platform.BinaryPlatformNameStatic()
+	if err := binaries.FetchEngine(dir, "schema-engine", name); err != nil {
+		return "", fmt.Errorf("fetch schema engine: %w", err)
+	}
+	return binaries.ResolveEngine(binaries.GetEnginePath(dir, "schema-engine", name))
+}
+
+var envURL = regexp.MustCompile(`(?s)datasource\s+\w+\s*\{[^}]*?\burl\s*=\s*env\(\s*"([^"]+)"\s*\)`)
+
+// DatasourceEnv returns the environment variable of the datasource url in the schema
+func DatasourceEnv(schema string) (string, bool) {
+	match := envURL.FindStringSubmatch(schema)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+var providerPattern = regexp.MustCompile(`(?s)datasource\s+\w+\s*\{[^}]*?\bprovider\s*=\s*"([^"]+)"`)
+
+// DatasourceProvider returns the provider of the datasource in the schema, e.g. "postgresql"
+func DatasourceProvider(schema string) (string, bool) {
+	match := providerPattern.FindStringSubmatch(schema)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// DatabaseEnv returns the environment variables which override the datasource url of the schema with databaseURL.
+// If databaseURL is empty, the url in the schema is used as is.
+func DatabaseEnv(schemaPath string, schema string, databaseURL string) ([]string, error) {
+	if databaseURL == "" {
+		return nil, nil
+	}
+	name, ok := DatasourceEnv(schema)
+	if !ok {
+		return nil, fmt.Errorf("the datasource url in %s must be set with env() to be overridden", schemaPath)
+	}
+	return []string{name + "=" + databaseURL}, nil
+}
+
+var shadowEnvURL = regexp.MustCompile(`(?s)datasource\s+\w+\s*\{[^}]*?\bshadowDatabaseUrl\s*=\s*(env\(\s*"([^"]+)"\s*\)|"[^"]*")`)
+
+var datasourceEnd = regexp.MustCompile(`(?s)datasource\s+\w+\s*\{[^}]*()\}`)
+
+// shadowEnv is set to the shadow database url if the schema doesn't configure one
+const shadowEnv = "PRISMA_CLIENT_GO_SHADOW_DATABASE_URL"
+
+// ShadowDatabaseEnv returns the environment variables which set the shadow database url of the schema to
+// shadowDatabaseURL. If the schema doesn't configure a shadow database url, schema is returned with a
+// shadowDatabaseUrl read from an environment variable added to its datasource. If shadowDatabaseURL is empty, the
+// schema is returned as is.
+func ShadowDatabaseEnv(schemaPath string, schema string, shadowDatabaseURL string) ([]string, string, error) {
+	if shadowDatabaseURL == "" {
+		return nil, schema, nil
+	}
+	if match := shadowEnvURL.FindStringSubmatch(schema); match != nil {
+		if match[2] == "" {
+			return nil, "", fmt.Errorf("the shadow database url in %s must be set with env() to be overridden", schemaPath)
+		}
+		return []string{match[2] + "=" + shadowDatabaseURL}, schema, nil
+	}
+	loc := datasourceEnd.FindStringSubmatchIndex(schema)
+	if loc == nil {
+		return nil, "", fmt.Errorf("%s doesn't contain a datasource", schemaPath)
+	}
+	schema = schema[:loc[2]] + fmt.Sprintf("  shadowDatabaseUrl = env(%q)\n", shadowEnv) + schema[loc[2]:]
+	return []string{shadowEnv + "=" + shadowDatabaseURL}, schema, nil
+}
+
+// Config configures the schema engine
+type Config struct {
+	// SchemaPath is the path of the schema. If it is empty, the schema engine is started without a schema, which is
+	// sufficient for commands which receive their inputs as params, e.g. diff.
+	SchemaPath string
+
+	// DatabaseURL overrides the url of the datasource if it is not empty, which must be read from an environment
+	// variable
+	DatabaseURL string
+
+	// ShadowDatabaseURL overrides or sets the shadow database url of the datasource if it is not empty, which is used
+	// by commands which need a temporary database, e.g. to detect drift
+	ShadowDatabaseURL string
+
+	// OnLog is called with the log lines of the schema engine from a separate goroutine
+	OnLog func(level, message string)
+}
+
+// Start starts the schema engine with the given config
+func Start(ctx context.Context, config Config) (*Engine, error) {
+	var args, env []string
+	var tempDir string
+	if config.SchemaPath != "" {
+		files, err := ReadSchema(config.SchemaPath)
+		if err != nil {
+			return nil, err
+		}
+		schema := JoinSchema(files)
+
+		env, err = DatabaseEnv(config.SchemaPath, schema, config.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// the datasource is in one of the files, so the shadow database url is added to it
+		changed, found := false, false
+		for i, file := range files {
+			if _, ok := DatasourceProvider(file.Content); !ok {
+				continue
+			}
+			found = true
+			shadow, shadowSchema, err := ShadowDatabaseEnv(config.SchemaPath, file.Content, config.ShadowDatabaseURL)
+			if err != nil {
+				return nil, err
+			}
+			env = append(env, shadow...)
+			if shadowSchema != file.Content {
+				files[i].Content = shadowSchema
+				changed = true
+			}
+			break
+		}
+		if !found {
+			if _, _, err := ShadowDatabaseEnv(config.SchemaPath, schema, config.ShadowDatabaseURL); err != nil {
+				return nil, err
+			}
+		}
+
+		if changed {
+			// the shadow database url was added to the schema, so the schema engine is started with a copy
+			tempDir, err = os.MkdirTemp("", "prisma-schema-")
+			if err != nil {
+				return nil, fmt.Errorf("create temp dir: %w", err)
+			}
+			dir := SchemaDir(config.SchemaPath)
+			for i, file := range files {
+				rel, err := filepath.Rel(dir, file.Path)
+				if err != nil {
+					_ = os.RemoveAll(tempDir)
+					return nil, fmt.Errorf("write schema: %w", err)
+				}
+				files[i].Path = filepath.Join(tempDir, rel)
+				if err := os.MkdirAll(filepath.Dir(files[i].Path), 0700); err != nil {
+					_ = os.RemoveAll(tempDir)
+					return nil, fmt.Errorf("write schema: %w", err)
+				}
+				if err := os.WriteFile(files[i].Path, []byte(file.Content), 0600); err != nil {
+					_ = os.RemoveAll(tempDir)
+					return nil, fmt.Errorf("write schema: %w", err)
+				}
+			}
+		}
+
+		if info, err := os.Stat(config.SchemaPath); err == nil && info.IsDir() {
+			args = []string{"--datamodels"}
+			for _, file := range files {
+				args = append(args, file.Path)
+			}
+		} else {
+			args = []string{"--datamodel", files[0].Path}
+		}
+	}
+
+	e, err := start(ctx, args, env, config.OnLog)
+	if err != nil {
+		if tempDir != "" {
+			_ = os.RemoveAll(tempDir)
+		}
+		return nil, err
+	}
+	e.tempDir = tempDir
+	return e, nil
+}
+
+func start(ctx context.Context, args []string, env []string, onLog func(level, message string)) (*Engine, error) {
+	file, err := Binary()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, file, args...) //nolint:gosec
+	cmd.Env = append(os.Environ(), "RUST_LOG=info")
+	cmd.Env = append(cmd.Env, env...)
+
+	e := &Engine{
+		cmd:   cmd,
+		onLog: onLog,
+	}
+
+	e.stdin, err = cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stdout pipe: %w", err)
+	}
+	e.stdout = bufio.NewReader(stdout)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stderr pipe: %w", err)
+	}
+
+	logger.Debug.Printf("starting schema engine %s", file)
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start schema engine: %w", err)
+	}
+
+	e.stderr.Add(1)
+	go func() {
+		defer e.stderr.Done()
+		e.streamStderr(stderr)
+	}()
+
+	return e, nil
+}
+
+type logLine struct {
+	Level  string `json:"level"`
+	Fields struct {
+		Message string `json:"message"`
+	} `json:"fields"`
+	IsPanic bool   `json:"is_panic"`
+	Message string `json:"message"`
+}
+
+// streamStderr reports the log lines of the schema engine
+func (e *Engine) streamStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		logger.Debug.Printf("schema engine: %s", line)
+
+		var parsed logLine
+		if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+			e.log("info", line)
+			continue
+		}
+
+		message := parsed.Fields.Message
+		if message == "" {
+			message = parsed.Message
+		}
+		level := strings.ToLower(parsed.Level)
+		if parsed.IsPanic || level == "error" {
+			e.mu.Lock()
+			e.lastError = message
+			e.mu.Unlock()
+		}
+		e.log(level, message)
+	}
+}
+
+func (e *Engine) log(level, message string) {
+	if e.onLog != nil {
+		e.onLog(level, message)
+	}
+}
+
+type rpcResponse struct {
+	ID int `json:"id"`
+	// Method is set for requests of the schema engine to the client, e.g. print
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    *struct {
+			IsPanic   bool            `json:"is_panic"`
+			Message   string          `json:"message"`
+			ErrorCode string          `json:"error_code"`
+			Meta      json.RawMessage `json:"meta"`
+		} `json:"data"`
+	} `json:"error"`
+}
+
+// Call sends a JSON RPC request to the schema engine and decodes its result into result. Errors of the schema engine
+// are returned as *Error.
+func (e *Engine) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("marshal params: %w", err)
+	}
+
+	e.nextID++
+	req, err := json.Marshal(jsonrpc.Request{
+		JSONRPC: "2.0",
+		ID:      e.nextID,
+		Method:  method,
+		Params:  rawParams,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	logger.Debug.Printf("schema engine request %s", req)
+
+	if _, err := e.stdin.Write(append(req, '\n')); err != nil {
+		return e.exitError(fmt.Errorf("write request: %w", err))
+	}
+
+	var response rpcResponse
+	for {
+		line, err := e.read(ctx)
+		if err != nil {
+			return err
+		}
+
+		logger.Debug.Printf("schema engine response %s", line)
+
+		response = rpcResponse{}
+		if err := json.Unmarshal(line, &response); err != nil {
+			return fmt.Errorf("unmarshal response: %w", err)
+		}
+		if response.Method == "" {
+			break
+		}
+		if err := e.answer(response); err != nil {
+			return err
+		}
+	}
+
+	if response.Error != nil {
+		rpcErr := &Error{Message: response.Error.Message}
+		if data := response.Error.Data; data != nil {
+			rpcErr.Code = data.ErrorCode
+			rpcErr.Meta = data.Meta
+			rpcErr.IsPanic = data.IsPanic
+			if data.Message != "" {
+				rpcErr.Message = data.Message
+			}
+		}
+		return rpcErr
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
+	}
+	return nil
+}
+
+// read reads the next line the schema engine writes to stdout
+func (e *Engine) read(ctx context.Context) ([]byte, error) {
+	type read struct {
+		line []byte
+		err  error
+	}
+	done := make(chan read, 1)
+	go func() {
+		line, err := e.stdout.ReadBytes('\n')
+		done <- read{line, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, e.exitError(fmt.Errorf("read response: %w", r.err))
+		}
+		return r.line, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// answer handles a request of the schema engine to the client. The schema engine sends the output of some commands,
+// e.g. the script of diff, with print requests.
+func (e *Engine) answer(request rpcResponse) error {
+	switch request.Method {
+	case "print":
+		var params struct {
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return fmt.Errorf("unmarshal print params: %w", err)
+		}
+		e.printed.WriteString(params.Content)
+	default:
+		logger.Debug.Printf("ignoring schema engine request %s", request.Method)
+	}
+
+	response, err := json.Marshal(jsonrpc.NewResponse(request.ID, struct{}{}))
+	if err != nil {
+		return fmt.Errorf("marshal response: %w", err)
+	}
+	if _, err := e.stdin.Write(append(response, '\n')); err != nil {
+		return e.exitError(fmt.Errorf("write response: %w", err))
+	}
+	return nil
+}
+
+// Printed returns the content the schema engine printed since the last call
+func (e *Engine) Printed() string {
+	content := e.printed.String()
+	e.printed.Reset()
+	return content
+}
+
+// exitError adds the last error the schema engine logged before exiting to err
+func (e *Engine) exitError(err error) error {
+	e.stderr.Wait()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.lastError != "" {
+		return fmt.Errorf("schema engine exited: %s: %w", e.lastError, err)
+	}
+	return err
+}
+
+// Close stops the schema engine by closing its stdin and waits until all of its logs were reported. It may be called
+// multiple times.
+func (e *Engine) Close() error {
+	e.closeOnce.Do(func() {
+		e.closeErr = e.stop()
+	})
+	return e.closeErr
+}
+
+func (e *Engine) stop() error {
+	if e.tempDir != "" {
+		defer os.RemoveAll(e.tempDir) //nolint:errcheck
+	}
+	_ = e.stdin.Close()
+	e.stderr.Wait()
+	if err := e.cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			logger.Debug.Printf("schema engine exited with %s", err)
+			return nil
+		}
+		return fmt.Errorf("wait for schema engine: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package schemaengine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+const schema = `
+generator db {
+  provider = "go run github.com/steebchen/prisma-client-go"
+}
+
+datasource db {
+  provider  = "postgresql"
+  directUrl = env("DIRECT_URL")
+  url       = env( "POSTGRES_URL" )
+}
+`
+
+func TestDatasourceEnv(t *testing.T) {
+	name, ok := DatasourceEnv(schema)
+	massert.Equal(t, true, ok)
+	massert.Equal(t, "POSTGRES_URL", name)
+
+	_, ok = DatasourceEnv(`datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}`)
+	massert.Equal(t, false, ok)
+}
+
+func TestDatasourceProvider(t *testing.T) {
+	provider, ok := DatasourceProvider(schema)
+	massert.Equal(t, true, ok)
+	massert.Equal(t, "postgresql", provider)
+}
+
+func TestDatabaseEnv(t *testing.T) {
+	env, err := DatabaseEnv("schema.prisma", schema, "postgresql://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"POSTGRES_URL=postgresql://localhost"}, env)
+
+	env, err = DatabaseEnv("schema.prisma", schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 0, len(env))
+
+	_, err = DatabaseEnv("schema.prisma", `datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}`, "file:test.db")
+	massert.Equal(t, "the datasource url in schema.prisma must be set with env() to be overridden", err.Error())
+}
+
+func TestShadowDatabaseEnv(t *testing.T) {
+	env, got, err := ShadowDatabaseEnv("schema.prisma", schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 0, len(env))
+	massert.Equal(t, schema, got)
+
+	// the shadow database url is added to the datasource
+	env, got, err = ShadowDatabaseEnv("schema.prisma", "datasource db {\n  provider = \"postgresql\"\n  url      = env(\"DATABASE_URL\")\n}\n", "postgresql://shadow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"PRISMA_CLIENT_GO_SHADOW_DATABASE_URL=postgresql://shadow"}, env)
+	massert.Equal(t, "datasource db {\n  provider = \"postgresql\"\n  url      = env(\"DATABASE_URL\")\n  shadowDatabaseUrl = env(\"PRISMA_CLIENT_GO_SHADOW_DATABASE_URL\")\n}\n", got)
+
+	// an existing shadow database url is overridden
+	withShadow := "datasource db {\n  provider          = \"postgresql\"\n  url               = env(\"DATABASE_URL\")\n  shadowDatabaseUrl = env(\"SHADOW_URL\")\n}\n"
+	env, got, err = ShadowDatabaseEnv("schema.prisma", withShadow, "postgresql://shadow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"SHADOW_URL=postgresql://shadow"}, env)
+	massert.Equal(t, withShadow, got)
+
+	_, _, err = ShadowDatabaseEnv("schema.prisma", strings.Replace(withShadow, `env("SHADOW_URL")`, `"postgresql://other"`, 1), "postgresql://shadow")
+	massert.Equal(t, "the shadow database url in schema.prisma must be set with env() to be overridden", err.Error())
+}
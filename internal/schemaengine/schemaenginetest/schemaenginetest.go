@@ -0,0 +1,119 @@
+// Package schemaenginetest lets a test binary act as a schema engine, so that packages using the schema engine can be
+// tested without downloading the real schema engine or running a database
+package schemaenginetest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/jsonrpc"
+)
+
+// Env makes the test binary act as a schema engine when it is set
+const Env = "PRISMA_CLIENT_GO_FAKE_SCHEMA_ENGINE"
+
+// ErrDatabaseUnreachable is the error of the schema engine when it can't connect to the database
+var ErrDatabaseUnreachable = &schemaengine.Error{Code: "P1001", Message: "Can't reach database server"}
+
+// Request is a JSON RPC request the client sent to the fake schema engine
+type Request struct {
+	jsonrpc.Request
+
+	reader *bufio.Reader
+}
+
+// Decode unmarshals the params of the request into v
+func (r *Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Params, v)
+}
+
+// Print sends a print request to the client like the schema engine does for scripts and waits for the answer
+func (r *Request) Print(content string) {
+	write(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1000 + r.ID,
+		"method":  "print",
+		"params":  map[string]interface{}{"content": content},
+	})
+	if _, err := r.reader.ReadBytes('\n'); err != nil {
+		os.Exit(1)
+	}
+}
+
+// Handler answers a request of a single method. A *schemaengine.Error is sent with its Prisma error code, any other
+// error as a JSON RPC server error.
+type Handler func(req *Request) (interface{}, error)
+
+// Main answers the JSON RPC requests on stdin with the handlers of their methods and returns true if Env is set.
+// Call it at the beginning of TestMain and return if it returns true.
+func Main(handlers map[string]Handler) bool {
+	if os.Getenv(Env) == "" {
+		return false
+	}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return true
+		}
+		req := &Request{reader: reader}
+		if err := json.Unmarshal(line, &req.Request); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid request: %s\n", err)
+			os.Exit(1)
+		}
+
+		handler, ok := handlers[req.Method]
+		if !ok {
+			write(jsonrpc.NewErrorResponse(req.ID, &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: "Method not found"}))
+			continue
+		}
+		result, err := handler(req)
+		if err != nil {
+			write(jsonrpc.NewErrorResponse(req.ID, rpcError(err)))
+			continue
+		}
+		write(jsonrpc.NewResponse(req.ID, result))
+	}
+}
+
+// Setup makes the schema engine of the test run the test binary as a fake schema engine
+func Setup(t testing.TB) {
+	t.Setenv(Env, "true")
+	t.Setenv(schemaengine.BinaryEnv, os.Args[0])
+}
+
+// rpcError converts an error of a handler into the error the schema engine would send
+func rpcError(err error) *jsonrpc.Error {
+	var engineErr *schemaengine.Error
+	if !errors.As(err, &engineErr) {
+		return &jsonrpc.Error{Code: jsonrpc.CodeServerError, Message: err.Error()}
+	}
+	meta := engineErr.Meta
+	if meta == nil {
+		meta = json.RawMessage("{}")
+	}
+	return &jsonrpc.Error{
+		Code:    4466,
+		Message: "An error happened. Check the data field for details.",
+		Data: map[string]interface{}{
+			"is_panic":   engineErr.IsPanic,
+			"message":    engineErr.Message,
+			"error_code": engineErr.Code,
+			"meta":       meta,
+		},
+	}
+}
+
+func write(v interface{}) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "marshal response: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(out))
+}
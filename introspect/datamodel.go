@@ -0,0 +1,104 @@
+package introspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/generator/ast/dmmf"
+	"github.com/steebchen/prisma-client-go/internal/queryengine"
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+)
+
+// datamodel parses schema with the query engine and returns its datamodel
+func datamodel(ctx context.Context, schema string, env []string) (*metadata.Schema, error) {
+	out, err := queryengine.DMMF(ctx, schema, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var document dmmf.Document
+	if err := json.Unmarshal(out, &document); err != nil {
+		return nil, fmt.Errorf("unmarshal dmmf: %w", err)
+	}
+	document.Datamodel.ResolveCompositeTypes()
+
+	provider, _ := schemaengine.DatasourceProvider(schema)
+	return convert(provider, document.Datamodel), nil
+}
+
+// convert describes a datamodel in the same way as the schema metadata of generated clients
+func convert(provider string, datamodel dmmf.Datamodel) *metadata.Schema {
+	schema := &metadata.Schema{
+		Provider: provider,
+	}
+	for _, model := range datamodel.Models {
+		schema.Models = append(schema.Models, convertModel(model))
+	}
+	for _, t := range datamodel.Types {
+		schema.Types = append(schema.Types, convertModel(t))
+	}
+	for _, enum := range datamodel.Enums {
+		e := metadata.Enum{
+			Name: enum.Name.String(),
+		}
+		for _, value := range enum.Values {
+			e.Values = append(e.Values, value.Name.String())
+		}
+		schema.Enums = append(schema.Enums, e)
+	}
+	return schema
+}
+
+func convertModel(model dmmf.Model) metadata.Model {
+	m := metadata.Model{
+		Name:   model.Name.String(),
+		DBName: model.TableName(),
+	}
+	for _, field := range model.Fields {
+		f := metadata.Field{
+			Name:            field.Name.String(),
+			Type:            field.Type.String(),
+			IsList:          field.IsList,
+			IsRequired:      field.IsRequired,
+			IsUnique:        field.IsUnique,
+			IsID:            field.IsID,
+			IsUpdatedAt:     field.IsUpdatedAt,
+			HasDefaultValue: field.HasDefaultValue,
+			RelationName:    field.RelationName.String(),
+			Documentation:   field.Documentation,
+		}
+		switch {
+		case field.Kind.IsRelation():
+			f.Kind = metadata.FieldKindRelation
+		case field.Kind.IsComposite():
+			f.Kind = metadata.FieldKindComposite
+			f.DBName = field.ColumnName()
+		case field.Kind == dmmf.FieldKindEnum:
+			f.Kind = metadata.FieldKindEnum
+			f.DBName = field.ColumnName()
+		default:
+			f.Kind = metadata.FieldKindScalar
+			f.DBName = field.ColumnName()
+		}
+		for _, from := range field.RelationFromFields {
+			f.RelationFromFields = append(f.RelationFromFields, from.String())
+		}
+		for _, to := range field.RelationToFields {
+			f.RelationToFields = append(f.RelationToFields, fmt.Sprint(to))
+		}
+		m.Fields = append(m.Fields, f)
+	}
+	for _, name := range model.PrimaryKey.Fields {
+		m.PrimaryKey = append(m.PrimaryKey, name.String())
+	}
+	for _, index := range model.UniqueIndexes {
+		var fields []string
+		for _, name := range index.Fields {
+			fields = append(fields, name.String())
+		}
+		m.UniqueIndexes = append(m.UniqueIndexes, fields)
+	}
+	return m
+}
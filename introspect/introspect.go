@@ -0,0 +1,161 @@
+// Package introspect reads the structure of an existing database with the Prisma schema engine, like `prisma db pull`,
+// and returns both the resulting Prisma schema and a structured description of its datamodel, e.g. to detect schema
+// drift or to adopt Prisma for an existing database.
+//
+// Example:
+//
+//	result, err := introspect.Database(ctx, "postgresql", os.Getenv("DATABASE_URL"))
+//	if err != nil {
+//	  log.Fatal(err)
+//	}
+//	for _, model := range result.Datamodel.Models {
+//	  log.Printf("table %s has %d fields", model.DBName, len(model.Fields))
+//	}
+package introspect
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+)
+
+// Error is returned when the schema engine failed to introspect the database, e.g. with code P4001 if the database
+// is empty
+type Error = schemaengine.Error
+
+// File is a file of a Prisma schema
+type File = schemaengine.File
+
+// Result contains the introspected schema
+type Result struct {
+	// Schema is the content of the introspected Prisma schema, including the datasource and generator blocks
+	Schema string
+
+	// Files contains the introspected schema files. For a multi-file schema, there is one file for each existing file,
+	// and new models are added to the file with the datasource; otherwise, it contains one file with Schema.
+	Files []File
+
+	// Datamodel describes the models and enums of Schema
+	Datamodel *metadata.Schema
+
+	// Warnings describes parts of the database which could not be fully introspected, e.g. unsupported column types
+	Warnings string
+}
+
+// Option configures the introspection
+type Option func(*options)
+
+type options struct {
+	force              bool
+	compositeTypeDepth int
+}
+
+// Force ignores the models of the existing schema and overwrites them, like `prisma db pull --force`. By default,
+// manual changes of the existing schema such as @map attributes are kept.
+func Force(force bool) Option {
+	return func(o *options) {
+		o.force = force
+	}
+}
+
+// WithCompositeTypeDepth sets how deep embedded documents are introspected as composite types on MongoDB. Defaults to
+// -1, which introspects all levels; 0 disables composite types.
+func WithCompositeTypeDepth(depth int) Option {
+	return func(o *options) {
+		o.compositeTypeDepth = depth
+	}
+}
+
+// Pull introspects the database of the datasource in the schema file and returns the updated schema. The schema file
+// itself is not changed. If databaseURL is not empty, it overrides the url of the datasource in the schema, which must
+// then be set with env().
+func Pull(ctx context.Context, schemaPath string, databaseURL string, opts ...Option) (*Result, error) {
+	o := options{
+		compositeTypeDepth: -1,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	files, err := schemaengine.ReadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	schema := schemaengine.JoinSchema(files)
+
+	e, err := schemaengine.Start(ctx, schemaengine.Config{
+		SchemaPath:  schemaPath,
+		DatabaseURL: databaseURL,
+		OnLog: func(level, message string) {
+			logger.Debug.Printf("introspection %s: %s", level, message)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	var result struct {
+		Schema   schemaFiles `json:"schema"`
+		Warnings *string     `json:"warnings"`
+	}
+	params := map[string]interface{}{
+		"schema":             schemaFiles{Files: files},
+		"baseDirectoryPath":  schemaengine.SchemaDir(schemaPath),
+		"force":              o.force,
+		"compositeTypeDepth": o.compositeTypeDepth,
+	}
+	if err := e.Call(ctx, "introspect", params, &result); err != nil {
+		return nil, fmt.Errorf("introspect: %w", err)
+	}
+
+	r := &Result{
+		Schema: schemaengine.JoinSchema(result.Schema.Files),
+		Files:  result.Schema.Files,
+	}
+	if result.Warnings != nil {
+		r.Warnings = *result.Warnings
+	}
+
+	env, err := schemaengine.DatabaseEnv(schemaPath, schema, databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	r.Datamodel, err = datamodel(ctx, r.Schema, env)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Database introspects a database without an existing schema. provider is the datasource provider, e.g. "postgresql".
+func Database(ctx context.Context, provider string, databaseURL string, opts ...Option) (*Result, error) {
+	dir, err := os.MkdirTemp("", "prisma-introspect-")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(dir)
+
+	schemaPath := filepath.Join(dir, "schema.prisma")
+	schema := fmt.Sprintf("datasource db {\n  provider = %q\n  url      = env(\"DATABASE_URL\")\n}\n", provider)
+	if err := os.WriteFile(schemaPath, []byte(schema), 0600); err != nil {
+		return nil, fmt.Errorf("write schema: %w", err)
+	}
+
+	return Pull(ctx, schemaPath, databaseURL, opts...)
+}
+
+type schemaFiles struct {
+	Files []File `json:"files"`
+}
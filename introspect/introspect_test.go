@@ -0,0 +1,154 @@
+package introspect
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/binaries"
+	"github.com/steebchen/prisma-client-go/internal/schemaengine/schemaenginetest"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeQueryEngineEnv makes the test binary act as the query engine, so that the introspected schema can be parsed
+// without downloading the query engine
+const fakeQueryEngineEnv = "PRISMA_CLIENT_GO_FAKE_QUERY_ENGINE"
+
+// fakeDatabaseURL is the only database url the fake schema engine accepts; the database contains a single users table
+const fakeDatabaseURL = "postgresql://fake"
+
+const introspectedModel = `
+model users {
+  id    Int    @id
+  email String @unique
+}
+`
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeQueryEngineEnv) != "" && len(os.Args) == 3 && os.Args[1] == "cli" && os.Args[2] == "dmmf" {
+		runFakeDMMF()
+		return
+	}
+	if schemaenginetest.Main(map[string]schemaenginetest.Handler{"introspect": fakeIntrospect}) {
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func runFakeDMMF() {
+	schema, err := base64.StdEncoding.DecodeString(os.Getenv("PRISMA_DML"))
+	if err != nil || !strings.Contains(string(schema), "model users") {
+		fmt.Fprintln(os.Stderr, "invalid schema")
+		os.Exit(1)
+	}
+	fmt.Println(`{"datamodel": {"models": [{
+		"name": "users",
+		"fields": [
+			{"kind": "scalar", "name": "id", "type": "Int", "isRequired": true, "isId": true},
+			{"kind": "scalar", "name": "email", "type": "String", "isRequired": true, "isUnique": true}
+		],
+		"primaryKey": null,
+		"uniqueIndexes": []
+	}], "enums": [], "types": []}}`)
+}
+
+// fakeIntrospect appends the users model to the schema
+func fakeIntrospect(req *schemaenginetest.Request) (interface{}, error) {
+	if os.Getenv("DATABASE_URL") != fakeDatabaseURL {
+		return nil, schemaenginetest.ErrDatabaseUnreachable
+	}
+	var params struct {
+		Schema schemaFiles `json:"schema"`
+	}
+	if err := req.Decode(&params); err != nil {
+		return nil, err
+	}
+	params.Schema.Files[0].Content += introspectedModel
+	return map[string]interface{}{"schema": params.Schema, "warnings": nil}, nil
+}
+
+func setupFakeEngines(t *testing.T) {
+	schemaenginetest.Setup(t)
+	t.Setenv(fakeQueryEngineEnv, "true")
+	t.Setenv(binaries.QueryEngineEnv, os.Args[0])
+	t.Setenv("DATABASE_URL", "")
+}
+
+func TestDatabase(t *testing.T) {
+	setupFakeEngines(t)
+
+	result, err := Database(context.Background(), "postgresql", fakeDatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, strings.Contains(result.Schema, `provider = "postgresql"`))
+	massert.Equal(t, true, strings.HasSuffix(result.Schema, introspectedModel))
+	massert.Equal(t, "", result.Warnings)
+
+	massert.Equal(t, "postgresql", result.Datamodel.Provider)
+	massert.Equal(t, 1, len(result.Datamodel.Models))
+	users, ok := result.Datamodel.Model("users")
+	massert.Equal(t, true, ok)
+	massert.Equal(t, "users", users.DBName)
+	massert.Equal(t, metadata.Field{
+		Name:       "email",
+		DBName:     "email",
+		Kind:       metadata.FieldKindScalar,
+		Type:       "String",
+		IsRequired: true,
+		IsUnique:   true,
+	}, users.Fields[1])
+	massert.Equal(t, []string{"id"}, names(users.IDFields()))
+}
+
+func TestDatabase_error(t *testing.T) {
+	setupFakeEngines(t)
+
+	_, err := Database(context.Background(), "postgresql", "postgresql://other")
+	var introspectErr *Error
+	if !errors.As(err, &introspectErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	massert.Equal(t, "P1001", introspectErr.Code)
+}
+
+func TestPull_multiFile(t *testing.T) {
+	setupFakeEngines(t)
+
+	dir := t.TempDir()
+	datasource := "datasource db {\n  provider = \"postgresql\"\n  url      = env(\"DATABASE_URL\")\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "a.prisma"), []byte(datasource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "models"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "models", "post.prisma"), []byte("// posts\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Pull(context.Background(), dir, fakeDatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []File{
+		{Path: filepath.Join(dir, "a.prisma"), Content: datasource + introspectedModel},
+		{Path: filepath.Join(dir, "models", "post.prisma"), Content: "// posts\n"},
+	}, result.Files)
+	massert.Equal(t, datasource+introspectedModel+"\n// posts\n", result.Schema)
+	massert.Equal(t, 1, len(result.Datamodel.Models))
+}
+
+func names(fields []metadata.Field) []string {
+	var result []string
+	for _, f := range fields {
+		result = append(result, f.Name)
+	}
+	return result
+}
@@ -35,3 +35,44 @@ func NewResponse(id int, result interface{}) Response {
 		Result:  result,
 	}
 }
+
+// Error codes used in error responses, as defined by the JSON RPC spec
+const (
+	// CodeMethodNotFound is returned when the method is not supported
+	CodeMethodNotFound = -32601
+	// CodeServerError is returned when a method failed, e.g. when the code generation returned an error
+	CodeServerError = -32000
+)
+
+// Error describes why a request failed.
+type Error struct {
+	// Code is a number indicating the type of the error, e.g. CodeServerError.
+	Code int `json:"code"`
+	// Message contains a short description of the error, which is shown by the Prisma CLI.
+	Message string `json:"message"`
+	// Data contains optional additional information about the error.
+	Data interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorResponse is sent instead of a Response when a request failed.
+type ErrorResponse struct {
+	// JSONRPC describes the version of the JSON RPC protocol. Defaults to `2.0`.
+	JSONRPC string `json:"jsonrpc"`
+	// ID identifies the request which failed.
+	ID int `json:"id"`
+	// Error describes why the request failed.
+	Error *Error `json:"error"`
+}
+
+// NewErrorResponse forms a new JSON RPC error response to reply to the Prisma CLI commands
+func NewErrorResponse(id int, err *Error) ErrorResponse {
+	return ErrorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   err,
+	}
+}
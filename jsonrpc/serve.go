@@ -0,0 +1,86 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// Handler processes a single request and returns its result.
+// If the returned error is a *Error, it is sent as is, otherwise it is sent as an error with code CodeServerError.
+type Handler func(req Request) (interface{}, error)
+
+// Serve reads newline-delimited JSON RPC requests from r, as sent by the Prisma CLI to generators, calls handle for
+// each of them and writes the responses to w. Failed requests are answered with an error response, so that the Prisma
+// CLI can show the error message. Serve returns when r is closed.
+func Serve(r io.Reader, w io.Writer, handle Handler) error {
+	reader := bufio.NewReader(r)
+
+	for {
+		content, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			logger.Debug.Printf("warning: ignoring EOF error. stdin: `%s`", content)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read bytes from stdin: %w", err)
+		}
+
+		var req Request
+		if err := json.Unmarshal(content, &req); err != nil {
+			return fmt.Errorf("could not parse request: %w", err)
+		}
+
+		var response interface{}
+
+		result, err := call(handle, req)
+		if err != nil {
+			logger.Debug.Printf("request %d %s failed: %s", req.ID, req.Method, err)
+
+			var rpcErr *Error
+			if !errors.As(err, &rpcErr) {
+				rpcErr = &Error{
+					Code:    CodeServerError,
+					Message: err.Error(),
+				}
+			}
+			response = NewErrorResponse(req.ID, rpcErr)
+		} else {
+			response = NewResponse(req.ID, result)
+		}
+
+		if err := reply(w, response); err != nil {
+			return fmt.Errorf("could not reply: %w", err)
+		}
+	}
+}
+
+// call invokes the handler and turns panics into errors, so that the Prisma CLI still receives a response
+func call(handle Handler, req Request) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return handle(req)
+}
+
+func reply(w io.Writer, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("could not marshal data %w", err)
+	}
+
+	b = append(b, byte('\n'))
+
+	if _, err = w.Write(b); err != nil {
+		return fmt.Errorf("could not write data %w", err)
+	}
+
+	return nil
+}
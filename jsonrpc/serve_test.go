@@ -0,0 +1,69 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestServe(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"getManifest","params":{}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"generate","params":{"fail":true}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"unknown","params":{}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"generate","params":{}}`,
+	}, "\n") + "\n")
+	var out bytes.Buffer
+
+	err := Serve(in, &out, func(req Request) (interface{}, error) {
+		switch req.Method {
+		case "getManifest":
+			return ManifestResponse{Manifest: Manifest{PrettyName: "Test"}}, nil
+		case "generate":
+			if strings.Contains(string(req.Params), "fail") {
+				return nil, errors.New("generation failed")
+			}
+			return nil, nil
+		default:
+			return nil, &Error{Code: CodeMethodNotFound, Message: "no such method " + req.Method}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"result":{"manifest":{"prettyName":"Test","defaultOutput":"","denylist":null,"requiresGenerators":null,"requiresEngines":null}}}`,
+		`{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"generation failed"}}`,
+		`{"jsonrpc":"2.0","id":3,"error":{"code":-32601,"message":"no such method unknown"}}`,
+		`{"jsonrpc":"2.0","id":4,"result":null}`,
+	}, "\n") + "\n"
+	massert.Equal(t, expected, out.String())
+}
+
+func TestServe_panic(t *testing.T) {
+	var out bytes.Buffer
+	err := Serve(strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"generate","params":{}}`+"\n"), &out, func(req Request) (interface{}, error) {
+		panic("boom")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"panic: boom`) {
+		t.Fatalf("unexpected response %s", out.String())
+	}
+}
+
+func TestServe_invalidRequest(t *testing.T) {
+	var out bytes.Buffer
+	err := Serve(strings.NewReader("not json\n"), &out, func(req Request) (interface{}, error) {
+		t.Fatal("handler must not be called")
+		return nil, nil
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
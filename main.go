@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -23,6 +24,25 @@ func main() {
 			}
 			os.Exit(0)
 			return
+		case "seed":
+			if err := cli.Seed(context.Background(), args[1:], os.Stdout, os.Stderr); err != nil {
+				logger.Info.Printf("%s", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+			return
+		case "generate":
+			if !cli.HasWatchFlag(args[1:]) {
+				// the prisma CLI generates the client once
+				break
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if err := cli.Watch(ctx, args[1:], os.Stdout, os.Stderr); err != nil {
+				logger.Info.Printf("%s", err)
+				os.Exit(1)
+			}
+			return
 		case "init":
 			// override default init flags
 			args = append(args, "--generator-provider", "go run github.com/steebchen/prisma-client-go")
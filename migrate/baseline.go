@@ -0,0 +1,93 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// BaselineResult describes the outcome of Baseline
+type BaselineResult struct {
+	// Marked contains the names of the migrations which were marked as applied, in order
+	Marked []string
+}
+
+// Baseline marks the migration with the given name and all migrations before it as applied without running them, like
+// `prisma migrate resolve --applied` for each of them. This is used to adopt migrations for an existing database:
+// create an initial migration which describes the current database, baseline it, and Deploy applies only later
+// migrations. Migrations which were already applied are skipped. If databaseURL is not empty, it overrides the url of
+// the datasource in the schema, which must then be set with env().
+func Baseline(ctx context.Context, schemaPath string, databaseURL string, migrationName string, opts ...Option) (*BaselineResult, error) {
+	o := newOptions(opts)
+
+	dir, err := migrationsDir(schemaPath, o)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := localMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline []string
+	for i, name := range migrations {
+		if name == migrationName {
+			baseline = migrations[:i+1]
+			break
+		}
+	}
+	if baseline == nil {
+		return nil, fmt.Errorf("baseline: migration %s doesn't exist in %s", migrationName, dir)
+	}
+
+	e, err := startEngine(ctx, schemaPath, databaseURL, o)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	var history struct {
+		History *struct {
+			UnappliedMigrationNames []string `json:"unappliedMigrationNames"`
+		} `json:"history"`
+	}
+	params := map[string]interface{}{
+		"migrationsDirectoryPath": dir,
+		"optInToShadowDatabase":   false,
+	}
+	if err := e.Call(ctx, "diagnoseMigrationHistory", params, &history); err != nil {
+		return nil, fmt.Errorf("diagnose migration history: %w", err)
+	}
+
+	pending := make(map[string]bool)
+	if history.History != nil {
+		for _, name := range history.History.UnappliedMigrationNames {
+			pending[name] = true
+		}
+	}
+
+	result := &BaselineResult{
+		Marked: []string{},
+	}
+	for _, name := range baseline {
+		if !pending[name] {
+			continue
+		}
+		params := map[string]interface{}{
+			"migrationName":           name,
+			"migrationsDirectoryPath": dir,
+		}
+		if err := e.Call(ctx, "markMigrationApplied", params, nil); err != nil {
+			return nil, fmt.Errorf("mark migration %s as applied: %w", name, err)
+		}
+		result.Marked = append(result.Marked, name)
+	}
+
+	return result, nil
+}
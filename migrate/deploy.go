@@ -0,0 +1,133 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// DeployResult describes the outcome of Deploy
+type DeployResult struct {
+	// Applied contains the names of the migrations which were applied, in order
+	Applied []string
+}
+
+// Deploy applies all pending migrations to the database, like `prisma migrate deploy`. If databaseURL is not empty,
+// it overrides the url of the datasource in the schema, which must then be set with env().
+// Migrations which were already applied are skipped; failed migrations return an *Error, e.g. with code P3009 if a
+// previous migration failed and has to be resolved first. If another process is applying migrations at the same time,
+// Deploy waits until it's done, see WithLockTimeout.
+func Deploy(ctx context.Context, schemaPath string, databaseURL string, opts ...Option) (*DeployResult, error) {
+	o := newOptions(opts)
+
+	dir, err := migrationsDir(schemaPath, o)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := localMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := startEngine(ctx, schemaPath, databaseURL, o)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	e.progress(Event{Type: EventStarted, Migrations: migrations})
+
+	applied, err := e.applyMigrations(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// report the remaining logs of the schema engine first
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range applied {
+		e.progress(Event{Type: EventApplied, Migration: name})
+	}
+
+	return &DeployResult{
+		Applied: applied,
+	}, nil
+}
+
+// applyMigrations applies the pending migrations in dir and returns the names of the applied migrations
+func (e *schemaEngine) applyMigrations(ctx context.Context, dir string) ([]string, error) {
+	var result struct {
+		AppliedMigrationNames []string `json:"appliedMigrationNames"`
+	}
+	params := map[string]interface{}{
+		"migrationsDirectoryPath": dir,
+	}
+	deadline := time.Now().Add(e.options.lockTimeout)
+	for {
+		err := e.Call(ctx, "applyMigrations", params, &result)
+		if err == nil {
+			return result.AppliedMigrationNames, nil
+		}
+		// the schema engine gives up waiting for the advisory lock after a few seconds, so try again until the
+		// replica holding the lock is done
+		var migrateErr *Error
+		if !errors.As(err, &migrateErr) || !isLockTimeout(migrateErr) || time.Now().After(deadline) {
+			return nil, fmt.Errorf("apply migrations: %w", err)
+		}
+		e.progress(Event{Type: EventWaitingForLock, Message: migrateErr.Message})
+	}
+}
+
+// isLockTimeout returns true if the schema engine timed out waiting for the advisory lock of another process
+func isLockTimeout(err *Error) bool {
+	return err.Code == "P1002" && strings.Contains(err.Message, "advisory lock")
+}
+
+// migrationsDir returns the absolute path of the migrations directory
+func migrationsDir(schemaPath string, o options) (string, error) {
+	dir := o.migrationsDir
+	if dir == "" {
+		dir = filepath.Join(schemaengine.SchemaDir(schemaPath), "migrations")
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("migrations dir: %w", err)
+	}
+	return abs, nil
+}
+
+// localMigrations returns the names of the migrations in dir, in the order they are applied
+func localMigrations(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), "migration.sql")); err != nil {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
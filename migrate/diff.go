@@ -0,0 +1,104 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// Target is one side of Diff, e.g. a schema file or a database
+type Target struct {
+	tag string
+
+	// schemaPath, url or migrations dir depending on the tag
+	value string
+}
+
+// Empty is an empty schema, e.g. to get the SQL which creates a schema from scratch
+func Empty() Target {
+	return Target{tag: "empty"}
+}
+
+// SchemaFile is the schema of a Prisma schema file
+func SchemaFile(path string) Target {
+	return Target{tag: "schemaDatamodel", value: path}
+}
+
+// Database is the current schema of the database at url
+func Database(url string) Target {
+	return Target{tag: "url", value: url}
+}
+
+// MigrationsDir is the schema the migrations in dir produce. Applying them requires a shadow database, see
+// WithShadowDatabaseURL.
+func MigrationsDir(dir string) Target {
+	return Target{tag: "migrations", value: dir}
+}
+
+func (t Target) params() (map[string]interface{}, error) {
+	switch t.tag {
+	case "empty":
+		return map[string]interface{}{"tag": t.tag}, nil
+	case "schemaDatamodel":
+		files, err := schemaengine.ReadSchema(t.value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"tag":   t.tag,
+			"files": files,
+		}, nil
+	case "url":
+		return map[string]interface{}{"tag": t.tag, "url": t.value}, nil
+	case "migrations":
+		return map[string]interface{}{"tag": t.tag, "path": t.value}, nil
+	default:
+		return nil, fmt.Errorf("invalid diff target %q", t.tag)
+	}
+}
+
+// Diff returns the SQL script which migrates the schema from to the schema to, like `prisma migrate diff --script`,
+// e.g. to preview the changes of a schema file to a database before applying them. The script is empty if both
+// schemas are equal. Diff doesn't change any database.
+//
+//	sql, err := migrate.Diff(ctx, migrate.Database(url), migrate.SchemaFile("prisma/schema.prisma"))
+func Diff(ctx context.Context, from, to Target, opts ...Option) (string, error) {
+	o := newOptions(opts)
+
+	fromParams, err := from.params()
+	if err != nil {
+		return "", err
+	}
+	toParams, err := to.params()
+	if err != nil {
+		return "", err
+	}
+
+	e, err := startEngine(ctx, "", "", o)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	params := map[string]interface{}{
+		"from":     fromParams,
+		"to":       toParams,
+		"script":   true,
+		"exitCode": false,
+	}
+	if url := o.shadowDatabaseURL(); url != "" {
+		params["shadowDatabaseUrl"] = url
+	}
+	if err := e.Call(ctx, "diff", params, nil); err != nil {
+		return "", fmt.Errorf("diff: %w", err)
+	}
+
+	// the schema engine prints the script instead of returning it
+	return e.Printed(), nil
+}
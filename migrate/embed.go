@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+)
+
+var lockProvider = regexp.MustCompile(`(?m)^\s*provider\s*=\s*"([^"]+)"`)
+
+// DeployFS applies the pending migrations of a migrations directory embedded into the application binary, so that
+// neither the schema file nor the migrations have to be copied into the image:
+//
+//	//go:embed prisma/migrations
+//	var migrations embed.FS
+//
+//	result, err := migrate.DeployFS(ctx, migrations, os.Getenv("DATABASE_URL"), migrate.WithMigrationsDir("prisma/migrations"))
+//
+// WithMigrationsDir sets the path of the migrations directory within fsys; defaults to the root of fsys. The database
+// provider is read from the migration_lock.toml file of the migrations directory. If databaseURL is empty, the
+// DATABASE_URL environment variable is used.
+// As with Deploy, concurrent calls of multiple replicas are serialized with an advisory lock, see WithLockTimeout.
+func DeployFS(ctx context.Context, fsys fs.FS, databaseURL string, opts ...Option) (*DeployResult, error) {
+	o := newOptions(opts)
+
+	root := path.Clean(filepath.ToSlash(o.migrationsDir))
+	sub, err := fs.Sub(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("migrations dir: %w", err)
+	}
+
+	lock, err := fs.ReadFile(sub, "migration_lock.toml")
+	if err != nil {
+		return nil, fmt.Errorf("read migration lock of %s: %w", root, err)
+	}
+	match := lockProvider.FindSubmatch(lock)
+	if match == nil {
+		return nil, fmt.Errorf("the migration lock of %s doesn't contain a provider", root)
+	}
+
+	dir, err := os.MkdirTemp("", "prisma-migrations-")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(dir)
+
+	migrations := filepath.Join(dir, "migrations")
+	if err := copyFS(migrations, sub); err != nil {
+		return nil, fmt.Errorf("copy migrations: %w", err)
+	}
+
+	schemaPath := filepath.Join(dir, "schema.prisma")
+	schema := fmt.Sprintf("datasource db {\n  provider = %q\n  url      = env(\"DATABASE_URL\")\n}\n", match[1])
+	if err := os.WriteFile(schemaPath, []byte(schema), 0600); err != nil {
+		return nil, fmt.Errorf("write schema: %w", err)
+	}
+
+	return Deploy(ctx, schemaPath, databaseURL, append(opts, WithMigrationsDir(migrations))...)
+}
+
+// copyFS writes the files of fsys to dir
+func copyFS(dir string, fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0750)
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0600)
+	})
+}
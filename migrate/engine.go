@@ -0,0 +1,57 @@
+package migrate
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+)
+
+// schemaEngine reports the logs of the schema engine as progress events
+type schemaEngine struct {
+	*schemaengine.Engine
+
+	options options
+
+	// progressMu serializes progress events of the log goroutine and the caller
+	progressMu sync.Mutex
+}
+
+// startEngine starts the schema engine for the given schema. If databaseURL is not empty, it overrides the url of the
+// datasource, which must be read from an environment variable. If schemaPath is empty, the schema engine is started
+// without a schema.
+func startEngine(ctx context.Context, schemaPath string, databaseURL string, o options) (*schemaEngine, error) {
+	e := &schemaEngine{
+		options: o,
+	}
+	engine, err := schemaengine.Start(ctx, schemaengine.Config{
+		SchemaPath:        schemaPath,
+		DatabaseURL:       databaseURL,
+		ShadowDatabaseURL: o.shadowDatabaseURL(),
+		OnLog:             e.log,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.Engine = engine
+	return e, nil
+}
+
+var applyingMigration = regexp.MustCompile("^Applying migration `([^`]+)`")
+
+// log reports a log line of the schema engine as progress event
+func (e *schemaEngine) log(level, message string) {
+	if match := applyingMigration.FindStringSubmatch(message); match != nil {
+		e.progress(Event{Type: EventApplying, Migration: match[1]})
+		return
+	}
+	e.progress(Event{Type: EventLog, Level: level, Message: message})
+}
+
+// progress reports an event to the progress handler
+func (e *schemaEngine) progress(event Event) {
+	e.progressMu.Lock()
+	defer e.progressMu.Unlock()
+	e.options.progress(event)
+}
@@ -0,0 +1,36 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// Execute runs an SQL script against the database at databaseURL, like `prisma db execute --url`, e.g. to create or
+// drop a schema in tests. The script runs without a Prisma schema and its result is discarded.
+func Execute(ctx context.Context, databaseURL string, script string, opts ...Option) error {
+	o := newOptions(opts)
+
+	e, err := startEngine(ctx, "", "", o)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	params := map[string]interface{}{
+		"datasourceType": map[string]interface{}{
+			"tag": "url",
+			"url": databaseURL,
+		},
+		"script": script,
+	}
+	if err := e.Call(ctx, "dbExecute", params, nil); err != nil {
+		return fmt.Errorf("execute: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package migrate applies Prisma migrations from Go by talking to the Prisma schema engine directly, so that services
+// can migrate their database at startup without the Prisma CLI or NodeJS in production images.
+//
+// Example:
+//
+//	result, err := migrate.Deploy(ctx, "prisma/schema.prisma", os.Getenv("DATABASE_URL"))
+//	if err != nil {
+//	  log.Fatal(err)
+//	}
+//	log.Printf("applied %d migrations", len(result.Applied))
+//
+// DeployFS applies migrations embedded with embed.FS. Status reports pending migrations and drift, and Diff previews
+// the SQL between two schemas. Push syncs the database with the schema without migration files instead, and Reset
+// recreates the database from scratch, e.g. for integration tests. Execute runs SQL scripts.
+package migrate
+
+import (
+	"os"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+)
+
+// EventType describes the progress of a migration command
+type EventType string
+
+// EventType values
+const (
+	// EventStarted is sent when the schema engine was started; Migrations contains all local migrations
+	EventStarted EventType = "started"
+	// EventApplying is sent before a migration is applied
+	EventApplying EventType = "applying"
+	// EventApplied is sent for each migration which was applied
+	EventApplied EventType = "applied"
+	// EventWaitingForLock is sent when another process holds the migration lock; Message contains the reason
+	EventWaitingForLock EventType = "waitingForLock"
+	// EventLog is sent for other log lines of the schema engine
+	EventLog EventType = "log"
+)
+
+// Event reports the progress of a migration command
+type Event struct {
+	Type EventType
+
+	// Time is when the event happened
+	Time time.Time
+
+	// Migration is the name of the migration for EventApplying and EventApplied
+	Migration string
+
+	// Migrations contains the names of all local migrations for EventStarted
+	Migrations []string
+
+	// Level is the log level for EventLog, e.g. "info"
+	Level string
+
+	// Message is the log message for EventLog and EventWaitingForLock
+	Message string
+}
+
+// Option configures a migration command
+type Option func(*options)
+
+type options struct {
+	migrationsDir  string
+	onProgress     func(Event)
+	forceReset     bool
+	acceptDataLoss bool
+	detectDrift    bool
+	lockTimeout    time.Duration
+	shadowURL      string
+}
+
+// ShadowDatabaseEnv is the environment variable which sets the shadow database url if WithShadowDatabaseURL is not
+// used
+const ShadowDatabaseEnv = "SHADOW_DATABASE_URL"
+
+// defaultLockTimeout is how long Deploy waits for other processes applying migrations by default
+const defaultLockTimeout = 5 * time.Minute
+
+// WithMigrationsDir sets the directory containing the migrations. Defaults to the migrations directory next to the
+// schema file, as created by `prisma migrate dev`.
+func WithMigrationsDir(dir string) Option {
+	return func(o *options) {
+		o.migrationsDir = dir
+	}
+}
+
+// WithProgress registers a function which is called with the progress of the command, e.g. to log each applied
+// migration. The function is called synchronously and must not block.
+func WithProgress(handler func(Event)) Option {
+	return func(o *options) {
+		o.onProgress = handler
+	}
+}
+
+// ForceReset resets the database before Push, deleting all data, like `prisma db push --force-reset`
+func ForceReset(force bool) Option {
+	return func(o *options) {
+		o.forceReset = force
+	}
+}
+
+// AcceptDataLoss makes Push apply changes which may lose data, e.g. dropping a column, like
+// `prisma db push --accept-data-loss`
+func AcceptDataLoss(accept bool) Option {
+	return func(o *options) {
+		o.acceptDataLoss = accept
+	}
+}
+
+// DetectDrift makes Status check whether the database schema was changed without migrations. This applies all
+// migrations to a shadow database, which the database user must be allowed to create unless WithShadowDatabaseURL is
+// used.
+func DetectDrift(detect bool) Option {
+	return func(o *options) {
+		o.detectDrift = detect
+	}
+}
+
+// WithLockTimeout sets how long Deploy waits while another process applies migrations. The schema engine holds an
+// advisory lock while applying migrations on PostgreSQL, MySQL and SQL Server, so that only one of multiple replicas
+// starting at the same time applies them, and the others wait until it's done. Defaults to 5 minutes; 0 fails
+// immediately if the schema engine times out waiting for the lock.
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.lockTimeout = timeout
+	}
+}
+
+// WithShadowDatabaseURL sets the url of the shadow database, a temporary database which is used to detect drift and
+// to diff migrations. By default, the schema engine creates and drops the shadow database itself, which isn't allowed
+// on many cloud databases; create a separate empty database for it there. The url overrides the shadowDatabaseUrl of
+// the datasource, which must then be set with env(), or is added to the datasource if it isn't set. Defaults to the
+// SHADOW_DATABASE_URL environment variable.
+func WithShadowDatabaseURL(url string) Option {
+	return func(o *options) {
+		o.shadowURL = url
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		lockTimeout: defaultLockTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// shadowDatabaseURL returns the url of the shadow database, or an empty string to use the one of the schema
+func (o options) shadowDatabaseURL() string {
+	if o.shadowURL != "" {
+		return o.shadowURL
+	}
+	return os.Getenv(ShadowDatabaseEnv)
+}
+
+func (o options) progress(event Event) {
+	if o.onProgress == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	o.onProgress(event)
+}
+
+// Error is returned when the schema engine failed to execute a command, e.g. because a migration failed. Code contains
+// the Prisma error code, e.g. "P3009", if the error is a known error.
+type Error = schemaengine.Error
@@ -0,0 +1,652 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/internal/schemaengine/schemaenginetest"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeDatabaseURL is the only database url the fake schema engine accepts
+const fakeDatabaseURL = "postgresql://fake"
+
+// fakeShadowURL is the only shadow database url the fake schema engine accepts
+const fakeShadowURL = "postgresql://shadow"
+
+func TestMain(m *testing.M) {
+	if schemaenginetest.Main(fakeSchemaEngineHandlers) {
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// fakeSchemaEngineHandlers answer the requests of the fake schema engine. Applied migrations are recorded in a file in
+// the migrations directory, and migrations containing FAIL fail.
+var fakeSchemaEngineHandlers = map[string]schemaenginetest.Handler{
+	// diff and dbExecute don't need the database of the schema
+	"diff":                     fakeDiff,
+	"dbExecute":                fakeDBExecute,
+	"applyMigrations":          requireFakeDatabase(fakeApplyMigrations),
+	"diagnoseMigrationHistory": requireFakeDatabase(fakeDiagnoseMigrationHistory),
+	"markMigrationApplied":     requireFakeDatabase(fakeMarkMigrationApplied),
+	"schemaPush":               requireFakeDatabase(fakeSchemaPush),
+	"reset":                    requireFakeDatabase(fakeReset),
+}
+
+// requireFakeDatabase fails requests unless the schema engine was started for the fake database
+func requireFakeDatabase(handler schemaenginetest.Handler) schemaenginetest.Handler {
+	return func(req *schemaenginetest.Request) (interface{}, error) {
+		if os.Getenv("DATABASE_URL") != fakeDatabaseURL {
+			return nil, schemaenginetest.ErrDatabaseUnreachable
+		}
+		return handler(req)
+	}
+}
+
+// fakeParams decodes the params of a request into a map
+func fakeParams(req *schemaenginetest.Request) map[string]interface{} {
+	var params map[string]interface{}
+	_ = req.Decode(&params)
+	return params
+}
+
+func fakeDiff(req *schemaenginetest.Request) (interface{}, error) {
+	params := fakeParams(req)
+	from := params["from"].(map[string]interface{})
+	to := params["to"].(map[string]interface{})
+	if (from["tag"] == "migrations" || to["tag"] == "migrations") && params["shadowDatabaseUrl"] != fakeShadowURL {
+		return nil, fakeError("P3014", "Prisma Migrate could not create the shadow database.")
+	}
+	if fakeDiffSchema(from) != fakeDiffSchema(to) {
+		req.Print("-- CreateTable\nCREATE TABLE \"Post\" (\"id\" TEXT NOT NULL);\n")
+	}
+	return map[string]interface{}{"exitCode": 0}, nil
+}
+
+// fakeDBExecute connects to the url of its params instead of the schema
+func fakeDBExecute(req *schemaenginetest.Request) (interface{}, error) {
+	params := fakeParams(req)
+	if params["datasourceType"].(map[string]interface{})["url"] != fakeDatabaseURL {
+		return nil, schemaenginetest.ErrDatabaseUnreachable
+	}
+	if strings.Contains(params["script"].(string), "FAIL") {
+		return nil, fakeError("P1014", "The underlying table for model `FAIL` does not exist.")
+	}
+	return nil, nil
+}
+
+func fakeApplyMigrations(req *schemaenginetest.Request) (interface{}, error) {
+	params := fakeParams(req)
+	dir := params["migrationsDirectoryPath"].(string)
+	// locked contains how often the advisory lock is held by another process
+	locked, _ := os.ReadFile(filepath.Join(dir, "locked.txt"))
+	if n, _ := strconv.Atoi(string(locked)); n > 0 {
+		_ = os.WriteFile(filepath.Join(dir, "locked.txt"), []byte(strconv.Itoa(n-1)), 0600)
+		return nil, fakeError("P1002", "Timed out trying to acquire a postgres advisory lock (SELECT pg_advisory_lock(72707369)). Elapsed: 10000ms.")
+	}
+	state := filepath.Join(dir, "applied.txt")
+	content, _ := os.ReadFile(state)
+	applied := strings.Fields(string(content))
+
+	entries, _ := os.ReadDir(dir)
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.Contains(string(content), entry.Name()) {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, `{"level":"INFO","fields":{"message":"Applying migration `+"`%s`"+`"},"target":"schema_core"}`+"\n", entry.Name())
+		sql, _ := os.ReadFile(filepath.Join(dir, entry.Name(), "migration.sql"))
+		if strings.Contains(string(sql), "FAIL") {
+			return nil, fakeError("P3018", "A migration failed to apply. Migration name: "+entry.Name())
+		}
+		applied = append(applied, entry.Name())
+		names = append(names, entry.Name())
+		_ = os.WriteFile(state, []byte(strings.Join(applied, "\n")), 0600)
+	}
+	return map[string]interface{}{"appliedMigrationNames": names}, nil
+}
+
+func fakeDiagnoseMigrationHistory(req *schemaenginetest.Request) (interface{}, error) {
+	params := fakeParams(req)
+	dir := params["migrationsDirectoryPath"].(string)
+	content, err := os.ReadFile(filepath.Join(dir, "applied.txt"))
+	unapplied := []string{}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.Contains(string(content), entry.Name()) {
+			unapplied = append(unapplied, entry.Name())
+		}
+	}
+	result := map[string]interface{}{
+		"history":              nil,
+		"drift":                nil,
+		"failedMigrationNames": []string{},
+		"editedMigrationNames": []string{},
+		"hasMigrationsTable":   err == nil,
+	}
+	if len(unapplied) > 0 {
+		result["history"] = map[string]interface{}{
+			"diagnostic":              "databaseIsBehind",
+			"unappliedMigrationNames": unapplied,
+		}
+	}
+	if params["optInToShadowDatabase"].(bool) && fakeShadowDatabaseURL() != fakeShadowURL {
+		return nil, fakeError("P3014", "Prisma Migrate could not create the shadow database.")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "drift.txt")); err == nil && params["optInToShadowDatabase"].(bool) {
+		result["drift"] = map[string]interface{}{
+			"diagnostic": "driftDetected",
+			"summary":    "[+] Added tables\n  - Post",
+		}
+	}
+	return result, nil
+}
+
+func fakeMarkMigrationApplied(req *schemaenginetest.Request) (interface{}, error) {
+	params := fakeParams(req)
+	state := filepath.Join(params["migrationsDirectoryPath"].(string), "applied.txt")
+	content, _ := os.ReadFile(state)
+	name := params["migrationName"].(string)
+	if strings.Contains(string(content), name) {
+		return nil, fakeError("P3008", "The migration `"+name+"` is already recorded as applied in the database.")
+	}
+	_ = os.WriteFile(state, []byte(strings.TrimSpace(string(content)+"\n"+name)), 0600)
+	return map[string]interface{}{}, nil
+}
+
+func fakeSchemaPush(req *schemaenginetest.Request) (interface{}, error) {
+	params := fakeParams(req)
+	files := params["schema"].(map[string]interface{})["files"].([]interface{})
+	state := filepath.Join(filepath.Dir(files[0].(map[string]interface{})["path"].(string)), "pushed.txt")
+	var content string
+	for _, file := range files {
+		content += file.(map[string]interface{})["content"].(string)
+	}
+	current, _ := os.ReadFile(state)
+	if string(current) == content {
+		return map[string]interface{}{"executedSteps": 0, "unexecutable": []string{}, "warnings": []string{}}, nil
+	}
+	warnings := []string{}
+	if strings.Contains(string(current), "model Post") && !strings.Contains(content, "model Post") {
+		warnings = append(warnings, "You are about to drop the `Post` table, which is not empty (3 rows).")
+		if !params["force"].(bool) {
+			return map[string]interface{}{"executedSteps": 0, "unexecutable": []string{}, "warnings": warnings}, nil
+		}
+	}
+	_ = os.WriteFile(state, []byte(content), 0600)
+	return map[string]interface{}{"executedSteps": 1, "unexecutable": []string{}, "warnings": warnings}, nil
+}
+
+// fakeReset has no params, so the state is found via the schema the engine was started with
+func fakeReset(*schemaenginetest.Request) (interface{}, error) {
+	for i, arg := range os.Args {
+		if arg == "--datamodel" {
+			_ = os.Remove(filepath.Join(filepath.Dir(os.Args[i+1]), "pushed.txt"))
+			_ = os.Remove(filepath.Join(filepath.Dir(os.Args[i+1]), "migrations", "applied.txt"))
+		}
+	}
+	return nil, nil
+}
+
+// fakeShadowDatabaseURL returns the shadow database url of the schema the fake schema engine was started with
+func fakeShadowDatabaseURL() string {
+	for i, arg := range os.Args {
+		if arg != "--datamodel" {
+			continue
+		}
+		schema, _ := os.ReadFile(os.Args[i+1])
+		match := regexp.MustCompile(`shadowDatabaseUrl = env\("(\w+)"\)`).FindSubmatch(schema)
+		if match != nil {
+			return os.Getenv(string(match[1]))
+		}
+	}
+	return ""
+}
+
+// fakeDiffSchema returns a description of a diff target, which differs if the target contains a Post model
+func fakeDiffSchema(target map[string]interface{}) string {
+	if files, ok := target["files"].([]interface{}); ok {
+		if strings.Contains(files[0].(map[string]interface{})["content"].(string), "model Post") {
+			return "post"
+		}
+	}
+	return "empty"
+}
+
+func fakeError(code, message string) error {
+	return &schemaengine.Error{Code: code, Message: message}
+}
+
+// setupProject creates a schema and the given migrations and configures the fake schema engine
+func setupProject(t *testing.T, migrations map[string]string) string {
+	schemaenginetest.Setup(t)
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv(ShadowDatabaseEnv, "")
+
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.prisma")
+	if err := os.WriteFile(schema, []byte(`datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, sql := range migrations {
+		if err := os.MkdirAll(filepath.Join(dir, "migrations", name), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "migrations", name, "migration.sql"), []byte(sql), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return schema
+}
+
+func TestDeploy(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init":  "CREATE TABLE a ();",
+		"20240102000000_posts": "CREATE TABLE b ();",
+	})
+
+	var events []Event
+	result, err := Deploy(context.Background(), schema, fakeDatabaseURL, WithProgress(func(event Event) {
+		event.Time = time.Time{}
+		events = append(events, event)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"20240101000000_init", "20240102000000_posts"}, result.Applied)
+
+	var types []EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	massert.Equal(t, []EventType{EventStarted, EventApplying, EventApplying, EventApplied, EventApplied}, types)
+	massert.Equal(t, []string{"20240101000000_init", "20240102000000_posts"}, events[0].Migrations)
+	massert.Equal(t, "20240102000000_posts", events[2].Migration)
+
+	// applied migrations are skipped
+	result, err = Deploy(context.Background(), schema, fakeDatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{}, result.Applied)
+}
+
+func TestDeploy_failedMigration(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init":   "CREATE TABLE a ();",
+		"20240102000000_broken": "FAIL",
+	})
+
+	_, err := Deploy(context.Background(), schema, fakeDatabaseURL)
+	var migrateErr *Error
+	if !errors.As(err, &migrateErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	massert.Equal(t, "P3018", migrateErr.Code)
+	massert.Equal(t, "apply migrations: P3018: A migration failed to apply. Migration name: 20240102000000_broken", err.Error())
+}
+
+func TestDeploy_databaseURL(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init": "CREATE TABLE a ();",
+	})
+
+	_, err := Deploy(context.Background(), schema, "postgresql://other")
+	massert.Equal(t, "apply migrations: P1001: Can't reach database server", err.Error())
+
+	// the url is read from the environment if it is not passed
+	t.Setenv("DATABASE_URL", fakeDatabaseURL)
+	if _, err := Deploy(context.Background(), schema, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPush(t *testing.T) {
+	schema := setupProject(t, nil)
+	t.Setenv("DATABASE_URL", fakeDatabaseURL)
+
+	content, err := os.ReadFile(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withPosts := string(content) + "\nmodel Post {\n  id String @id\n}\n"
+	if err := os.WriteFile(schema, []byte(withPosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Push(context.Background(), schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 1, result.ExecutedSteps)
+
+	// the database is in sync
+	result, err = Push(context.Background(), schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 0, result.ExecutedSteps)
+
+	// dropping a table requires accepting data loss
+	if err := os.WriteFile(schema, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Push(context.Background(), schema, "")
+	massert.Equal(t, true, errors.Is(err, ErrDataLoss))
+	massert.Equal(t, "push schema: pushing the schema may lose data: You are about to drop the `Post` table, which is not empty (3 rows).", err.Error())
+
+	result, err = Push(context.Background(), schema, "", AcceptDataLoss(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 1, result.ExecutedSteps)
+	massert.Equal(t, 1, len(result.Warnings))
+}
+
+func TestPush_multiFile(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init": "CREATE TABLE a ();",
+	})
+	t.Setenv("DATABASE_URL", fakeDatabaseURL)
+
+	// the schema directory contains the schema files and the migrations
+	dir := filepath.Dir(schema)
+	if err := os.WriteFile(filepath.Join(dir, "post.prisma"), []byte("model Post {\n  id String @id\n}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Push(context.Background(), dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 1, result.ExecutedSteps)
+
+	// dropping the file drops the model
+	if err := os.Remove(filepath.Join(dir, "post.prisma")); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Push(context.Background(), dir, "")
+	massert.Equal(t, true, errors.Is(err, ErrDataLoss))
+
+	deployed, err := Deploy(context.Background(), dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"20240101000000_init"}, deployed.Applied)
+}
+
+func TestPush_forceReset(t *testing.T) {
+	schema := setupProject(t, nil)
+
+	if _, err := Push(context.Background(), schema, fakeDatabaseURL); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Push(context.Background(), schema, fakeDatabaseURL, ForceReset(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 1, result.ExecutedSteps)
+}
+
+func TestStatus(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init":  "CREATE TABLE a ();",
+		"20240102000000_posts": "CREATE TABLE b ();",
+	})
+	t.Setenv("DATABASE_URL", fakeDatabaseURL)
+
+	status, err := Status(context.Background(), schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, false, status.HasMigrationsTable)
+	massert.Equal(t, []string{"20240101000000_init", "20240102000000_posts"}, status.Pending)
+	massert.Equal(t, []string{}, status.Applied)
+	massert.Equal(t, false, status.UpToDate())
+
+	if _, err := Deploy(context.Background(), schema, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err = Status(context.Background(), schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, status.HasMigrationsTable)
+	massert.Equal(t, []string{"20240101000000_init", "20240102000000_posts"}, status.Applied)
+	massert.Equal(t, []string{}, status.Pending)
+	massert.Equal(t, true, status.UpToDate())
+}
+
+func TestStatus_drift(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init": "CREATE TABLE a ();",
+	})
+	if err := os.WriteFile(filepath.Join(filepath.Dir(schema), "migrations", "drift.txt"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// drift is only detected on request
+	status, err := Status(context.Background(), schema, fakeDatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, (*Drift)(nil), status.Drift)
+
+	// the shadow database can't be created
+	_, err = Status(context.Background(), schema, fakeDatabaseURL, DetectDrift(true))
+	massert.Equal(t, "diagnose migration history: P3014: Prisma Migrate could not create the shadow database.", err.Error())
+
+	status, err = Status(context.Background(), schema, fakeDatabaseURL, DetectDrift(true), WithShadowDatabaseURL(fakeShadowURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, &Drift{Summary: "[+] Added tables\n  - Post"}, status.Drift)
+	massert.Equal(t, false, status.UpToDate())
+}
+
+func TestDiff(t *testing.T) {
+	schema := setupProject(t, nil)
+
+	sql, err := Diff(context.Background(), SchemaFile(schema), Empty())
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "", sql)
+
+	content, err := os.ReadFile(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(schema, append(content, "\nmodel Post {\n  id String @id\n}\n"...), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sql, err = Diff(context.Background(), Empty(), SchemaFile(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "-- CreateTable\nCREATE TABLE \"Post\" (\"id\" TEXT NOT NULL);\n", sql)
+}
+
+func TestDeploy_lock(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init": "CREATE TABLE a ();",
+	})
+	locked := filepath.Join(filepath.Dir(schema), "migrations", "locked.txt")
+	if err := os.WriteFile(locked, []byte("2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var waiting int
+	result, err := Deploy(context.Background(), schema, fakeDatabaseURL, WithProgress(func(event Event) {
+		if event.Type == EventWaitingForLock {
+			waiting++
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 2, waiting)
+	massert.Equal(t, []string{"20240101000000_init"}, result.Applied)
+
+	if err := os.WriteFile(locked, []byte("1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Deploy(context.Background(), schema, fakeDatabaseURL, WithLockTimeout(0))
+	var migrateErr *Error
+	if !errors.As(err, &migrateErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	massert.Equal(t, "P1002", migrateErr.Code)
+}
+
+func TestDeployFS(t *testing.T) {
+	setupProject(t, nil)
+
+	fsys := fstest.MapFS{
+		"prisma/migrations/migration_lock.toml":                {Data: []byte("provider = \"postgresql\"\n")},
+		"prisma/migrations/20240101000000_init/migration.sql":  {Data: []byte("CREATE TABLE a ();")},
+		"prisma/migrations/20240102000000_posts/migration.sql": {Data: []byte("CREATE TABLE b ();")},
+	}
+
+	result, err := DeployFS(context.Background(), fsys, fakeDatabaseURL, WithMigrationsDir("prisma/migrations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"20240101000000_init", "20240102000000_posts"}, result.Applied)
+
+	_, err = DeployFS(context.Background(), fsys, fakeDatabaseURL)
+	massert.Equal(t, "read migration lock of .: open migration_lock.toml: file does not exist", err.Error())
+}
+
+func TestReset(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init":  "CREATE TABLE a ();",
+		"20240102000000_posts": "CREATE TABLE b ();",
+	})
+
+	if _, err := Deploy(context.Background(), schema, fakeDatabaseURL); err != nil {
+		t.Fatal(err)
+	}
+
+	// all migrations are applied again
+	result, err := Reset(context.Background(), schema, fakeDatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"20240101000000_init", "20240102000000_posts"}, result.Applied)
+	massert.Equal(t, false, result.Pushed)
+}
+
+func TestReset_push(t *testing.T) {
+	schema := setupProject(t, nil)
+
+	result, err := Reset(context.Background(), schema, fakeDatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, result.Pushed)
+
+	// the schema is pushed again after the reset
+	pushed, err := os.ReadFile(filepath.Join(filepath.Dir(schema), "pushed.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, string(content), string(pushed))
+}
+
+func TestStatus_shadowDatabaseEnv(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init": "CREATE TABLE a ();",
+	})
+	t.Setenv(ShadowDatabaseEnv, fakeShadowURL)
+
+	status, err := Status(context.Background(), schema, fakeDatabaseURL, DetectDrift(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, (*Drift)(nil), status.Drift)
+}
+
+func TestDiff_migrations(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init": "CREATE TABLE a ();",
+	})
+	dir := filepath.Join(filepath.Dir(schema), "migrations")
+
+	_, err := Diff(context.Background(), MigrationsDir(dir), SchemaFile(schema))
+	massert.Equal(t, "diff: P3014: Prisma Migrate could not create the shadow database.", err.Error())
+
+	sql, err := Diff(context.Background(), MigrationsDir(dir), SchemaFile(schema), WithShadowDatabaseURL(fakeShadowURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "", sql)
+}
+
+func TestBaseline(t *testing.T) {
+	schema := setupProject(t, map[string]string{
+		"20240101000000_init":  "CREATE TABLE a ();",
+		"20240102000000_users": "CREATE TABLE b ();",
+		"20240103000000_posts": "CREATE TABLE c ();",
+	})
+
+	result, err := Baseline(context.Background(), schema, fakeDatabaseURL, "20240102000000_users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"20240101000000_init", "20240102000000_users"}, result.Marked)
+
+	// applied migrations are skipped
+	result, err = Baseline(context.Background(), schema, fakeDatabaseURL, "20240102000000_users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{}, result.Marked)
+
+	// only the migrations after the baseline are applied
+	deployed, err := Deploy(context.Background(), schema, fakeDatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"20240103000000_posts"}, deployed.Applied)
+
+	_, err = Baseline(context.Background(), schema, fakeDatabaseURL, "20240104000000_missing")
+	massert.Equal(t, true, strings.Contains(err.Error(), "migration 20240104000000_missing doesn't exist"))
+}
+
+func TestExecute(t *testing.T) {
+	setupProject(t, nil)
+
+	if err := Execute(context.Background(), fakeDatabaseURL, `CREATE SCHEMA "test";`); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Execute(context.Background(), fakeDatabaseURL, "SELECT * FROM FAIL;")
+	massert.Equal(t, "execute: P1014: The underlying table for model `FAIL` does not exist.", err.Error())
+
+	err = Execute(context.Background(), "postgresql://other", `CREATE SCHEMA "test";`)
+	massert.Equal(t, "execute: P1001: Can't reach database server", err.Error())
+}
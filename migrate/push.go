@@ -0,0 +1,89 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// ErrDataLoss is returned by Push if applying the schema may lose data and AcceptDataLoss was not set
+var ErrDataLoss = errors.New("pushing the schema may lose data")
+
+// PushResult describes the outcome of Push
+type PushResult struct {
+	// ExecutedSteps is the number of migration steps which were executed; 0 if the database was already in sync
+	ExecutedSteps int
+
+	// Warnings describe possible data loss of the executed steps
+	Warnings []string
+}
+
+// Push syncs the database with the schema without creating migration files, like `prisma db push`, which is useful
+// for prototyping and integration tests. If databaseURL is not empty, it overrides the url of the datasource in the
+// schema, which must then be set with env().
+// If the changes may lose data, Push returns an error wrapping ErrDataLoss without changing the database, unless
+// AcceptDataLoss is set. Changes which can't be executed, e.g. adding a required column without a default to a table
+// with rows, return an error; use ForceReset to reset the database first.
+func Push(ctx context.Context, schemaPath string, databaseURL string, opts ...Option) (*PushResult, error) {
+	o := newOptions(opts)
+
+	files, err := schemaengine.ReadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := startEngine(ctx, schemaPath, databaseURL, o)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	if o.forceReset {
+		if err := e.Call(ctx, "reset", nil, nil); err != nil {
+			return nil, fmt.Errorf("reset: %w", err)
+		}
+	}
+
+	return e.pushSchema(ctx, files)
+}
+
+// pushSchema syncs the database with the schema
+func (e *schemaEngine) pushSchema(ctx context.Context, files []schemaengine.File) (*PushResult, error) {
+	var result struct {
+		ExecutedSteps int      `json:"executedSteps"`
+		Unexecutable  []string `json:"unexecutable"`
+		Warnings      []string `json:"warnings"`
+	}
+	params := map[string]interface{}{
+		"force": e.options.acceptDataLoss,
+		"schema": map[string]interface{}{
+			"files": files,
+		},
+	}
+	if err := e.Call(ctx, "schemaPush", params, &result); err != nil {
+		return nil, fmt.Errorf("push schema: %w", err)
+	}
+
+	if len(result.Unexecutable) > 0 {
+		return nil, fmt.Errorf("push schema: the changes can't be executed, use ForceReset to reset the database: %s",
+			strings.Join(result.Unexecutable, "; "))
+	}
+
+	// without force, the schema engine doesn't execute anything if there are warnings
+	if len(result.Warnings) > 0 && !e.options.acceptDataLoss {
+		return nil, fmt.Errorf("push schema: %w: %s", ErrDataLoss, strings.Join(result.Warnings, "; "))
+	}
+
+	return &PushResult{
+		ExecutedSteps: result.ExecutedSteps,
+		Warnings:      result.Warnings,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// ResetResult describes the outcome of Reset
+type ResetResult struct {
+	// Applied contains the names of the migrations which were applied after resetting the database, in order
+	Applied []string
+
+	// Pushed is true if the schema was pushed because there are no migrations
+	Pushed bool
+}
+
+// Reset deletes all data and tables of the database and recreates them, like `prisma migrate reset --skip-seed`, so
+// that integration tests can start with a clean database, e.g. in TestMain. If the migrations directory exists, all
+// migrations are applied; otherwise, the schema is pushed like with Push. If databaseURL is not empty, it overrides
+// the url of the datasource in the schema, which must then be set with env().
+//
+// Never call Reset with the url of a production database.
+func Reset(ctx context.Context, schemaPath string, databaseURL string, opts ...Option) (*ResetResult, error) {
+	o := newOptions(opts)
+
+	dir, err := migrationsDir(schemaPath, o)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := localMigrations(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	push := err != nil
+
+	e, err := startEngine(ctx, schemaPath, databaseURL, o)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	if err := e.Call(ctx, "reset", nil, nil); err != nil {
+		return nil, fmt.Errorf("reset: %w", err)
+	}
+
+	if push {
+		files, err := schemaengine.ReadSchema(schemaPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := e.pushSchema(ctx, files); err != nil {
+			return nil, err
+		}
+		return &ResetResult{Applied: []string{}, Pushed: true}, nil
+	}
+
+	e.progress(Event{Type: EventStarted, Migrations: migrations})
+
+	applied, err := e.applyMigrations(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// report the remaining logs of the schema engine first
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range applied {
+		e.progress(Event{Type: EventApplied, Migration: name})
+	}
+
+	return &ResetResult{
+		Applied: applied,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// StatusResult describes the migration history of a database compared to the local migrations
+type StatusResult struct {
+	// Migrations contains the names of all local migrations, in order
+	Migrations []string
+
+	// Applied contains the names of the local migrations which were applied to the database
+	Applied []string
+
+	// Pending contains the names of the local migrations which were not applied yet
+	Pending []string
+
+	// Unpersisted contains the names of the migrations which were applied to the database but don't exist locally
+	Unpersisted []string
+
+	// Failed contains the names of the migrations which failed to apply and have to be resolved
+	Failed []string
+
+	// Edited contains the names of the applied migrations which were changed locally afterwards
+	Edited []string
+
+	// HasMigrationsTable is false if no migration was ever applied to the database
+	HasMigrationsTable bool
+
+	// Drift is set if the database schema differs from the schema the applied migrations produce. It is only detected
+	// with DetectDrift.
+	Drift *Drift
+}
+
+// Drift describes changes of the database schema which were made without migrations
+type Drift struct {
+	// Summary describes the differences between the database and the migration history
+	Summary string
+}
+
+// UpToDate returns true if all local migrations were applied successfully and the database matches the migrations
+func (r *StatusResult) UpToDate() bool {
+	return len(r.Pending) == 0 && len(r.Unpersisted) == 0 && len(r.Failed) == 0 && len(r.Edited) == 0 && r.Drift == nil
+}
+
+// Status compares the migration history of the database with the local migrations, like `prisma migrate status`. If
+// databaseURL is not empty, it overrides the url of the datasource in the schema, which must then be set with env().
+// Status doesn't change the database.
+func Status(ctx context.Context, schemaPath string, databaseURL string, opts ...Option) (*StatusResult, error) {
+	o := newOptions(opts)
+
+	dir, err := migrationsDir(schemaPath, o)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := localMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := startEngine(ctx, schemaPath, databaseURL, o)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			logger.Debug.Printf("could not close schema engine: %s", err)
+		}
+	}()
+
+	var result struct {
+		History *struct {
+			Diagnostic                string   `json:"diagnostic"`
+			UnappliedMigrationNames   []string `json:"unappliedMigrationNames"`
+			UnpersistedMigrationNames []string `json:"unpersistedMigrationNames"`
+		} `json:"history"`
+		Drift *struct {
+			Diagnostic string `json:"diagnostic"`
+			Summary    string `json:"summary"`
+			Error      *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"drift"`
+		FailedMigrationNames []string `json:"failedMigrationNames"`
+		EditedMigrationNames []string `json:"editedMigrationNames"`
+		HasMigrationsTable   bool     `json:"hasMigrationsTable"`
+	}
+	params := map[string]interface{}{
+		"migrationsDirectoryPath": dir,
+		"optInToShadowDatabase":   o.detectDrift,
+	}
+	if err := e.Call(ctx, "diagnoseMigrationHistory", params, &result); err != nil {
+		return nil, fmt.Errorf("diagnose migration history: %w", err)
+	}
+
+	status := &StatusResult{
+		Migrations:         migrations,
+		Applied:            []string{},
+		Pending:            []string{},
+		Unpersisted:        []string{},
+		Failed:             result.FailedMigrationNames,
+		Edited:             result.EditedMigrationNames,
+		HasMigrationsTable: result.HasMigrationsTable,
+	}
+	if status.Failed == nil {
+		status.Failed = []string{}
+	}
+	if status.Edited == nil {
+		status.Edited = []string{}
+	}
+	if result.History != nil {
+		if result.History.UnappliedMigrationNames != nil {
+			status.Pending = result.History.UnappliedMigrationNames
+		}
+		if result.History.UnpersistedMigrationNames != nil {
+			status.Unpersisted = result.History.UnpersistedMigrationNames
+		}
+	}
+
+	notApplied := make(map[string]bool)
+	for _, name := range status.Pending {
+		notApplied[name] = true
+	}
+	for _, name := range status.Failed {
+		notApplied[name] = true
+	}
+	for _, name := range migrations {
+		if !notApplied[name] {
+			status.Applied = append(status.Applied, name)
+		}
+	}
+
+	if drift := result.Drift; drift != nil {
+		switch drift.Diagnostic {
+		case "driftDetected":
+			status.Drift = &Drift{Summary: drift.Summary}
+		case "migrationFailedToApply":
+			message := ""
+			if drift.Error != nil {
+				message = drift.Error.Message
+			}
+			return nil, fmt.Errorf("detect drift: a migration failed to apply to the shadow database: %s", message)
+		}
+	}
+
+	return status, nil
+}
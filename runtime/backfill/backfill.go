@@ -0,0 +1,211 @@
+// Package backfill applies updates to all records matching a FindMany query in batches, e.g. to backfill a new column.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// DefaultBatchSize is the number of records which are fetched at once if Config.BatchSize is not set
+const DefaultBatchSize = 100
+
+// Query is a FindMany query of a generated client, e.g. client.User.FindMany(...).OrderBy(...)
+type Query[Q any, C any, T any] interface {
+	Take(count int) Q
+	Skip(count int) Q
+	Cursor(cursor C) Q
+	Exec(ctx context.Context) ([]T, error)
+}
+
+// Config configures how updates are applied
+type Config[T any] struct {
+	// BatchSize is the number of records which are fetched at once; defaults to DefaultBatchSize
+	BatchSize int
+
+	// Concurrency is the number of records which are updated concurrently; defaults to 1
+	Concurrency int
+
+	// Rate is the maximum number of updates per second; 0 means unlimited
+	Rate float64
+
+	// OnProgress is called after each batch was updated completely
+	OnProgress func(Progress[T])
+}
+
+// Progress describes how many records were updated so far
+type Progress[T any] struct {
+	// Batches is the number of batches which were updated completely
+	Batches int
+
+	// Updated is the number of records which were updated
+	Updated int
+
+	// Last is the last record of the last batch. All records up to and including it were updated, so it can be
+	// persisted as a checkpoint to resume the backfill by filtering the query to the records following it.
+	Last T
+
+	// Elapsed is the time since the backfill started
+	Elapsed time.Duration
+}
+
+// Run fetches the records matching query batch by batch and calls update for each of them.
+//
+// The query must be ordered by a unique field and cursor must return the cursor of a record for this field, e.g.
+// db.User.ID.Cursor(user.ID), so that each batch continues after the last record of the previous one.
+// If an update fails, Run stops and returns the error after all running updates of the batch finished; the batch is
+// not reported as progress, so updates should be idempotent to allow resuming after the last reported checkpoint.
+func Run[Q Query[Q, C, T], C any, T any](
+	ctx context.Context,
+	query Q,
+	cursor func(record T) C,
+	update func(ctx context.Context, record T) error,
+	config Config[T],
+) (Progress[T], error) {
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	limit := newLimiter(config.Rate)
+
+	start := time.Now()
+	var progress Progress[T]
+
+	for {
+		// each batch is derived from the original query, so a query must not be executed before the previous one
+		// finished
+		next := query.Take(batchSize)
+		if progress.Batches > 0 {
+			next = query.Cursor(cursor(progress.Last)).Skip(1).Take(batchSize)
+		}
+
+		records, err := next.Exec(ctx)
+		if err != nil {
+			return progress, fmt.Errorf("fetch batch %d: %w", progress.Batches+1, err)
+		}
+		if len(records) == 0 {
+			return progress, nil
+		}
+
+		if err := updateBatch(ctx, records, update, concurrency, limit); err != nil {
+			return progress, fmt.Errorf("update batch %d: %w", progress.Batches+1, err)
+		}
+
+		progress.Batches++
+		progress.Updated += len(records)
+		progress.Last = records[len(records)-1]
+		progress.Elapsed = time.Since(start)
+
+		logger.Debug.Printf("backfill: updated %d records in %d batches (%s)", progress.Updated, progress.Batches, progress.Elapsed)
+
+		if config.OnProgress != nil {
+			config.OnProgress(progress)
+		}
+
+		if len(records) < batchSize {
+			return progress, nil
+		}
+	}
+}
+
+// updateBatch updates the records with the given concurrency and returns the first error
+func updateBatch[T any](
+	ctx context.Context,
+	records []T,
+	update func(ctx context.Context, record T) error,
+	concurrency int,
+	limit *limiter,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	work := make(chan T)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for record := range work {
+				if err := update(ctx, record); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	for _, record := range records {
+		if err := limit.wait(ctx); err != nil {
+			fail(err)
+			break
+		}
+		select {
+		case work <- record:
+			continue
+		case <-ctx.Done():
+			fail(ctx.Err())
+		}
+		break
+	}
+	close(work)
+	wg.Wait()
+
+	return firstErr
+}
+
+// limiter spaces out calls to wait so that at most rate calls happen per second
+type limiter struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newLimiter(rate float64) *limiter {
+	if rate <= 0 {
+		return &limiter{}
+	}
+	return &limiter{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+// wait blocks until the next call is allowed; it must not be called concurrently
+func (l *limiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+
+	if delay == 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,175 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type record struct {
+	ID int
+}
+
+type recordCursor struct {
+	ID int
+}
+
+// query behaves like a generated FindMany query ordered by id over a list of records
+type query struct {
+	records []record
+	cursor  *recordCursor
+	skip    int
+	take    int
+	execs   *int
+}
+
+func (q query) Take(count int) query {
+	q.take = count
+	return q
+}
+
+func (q query) Skip(count int) query {
+	q.skip = count
+	return q
+}
+
+func (q query) Cursor(cursor recordCursor) query {
+	q.cursor = &cursor
+	return q
+}
+
+func (q query) Exec(context.Context) ([]record, error) {
+	*q.execs++
+	start := 0
+	if q.cursor != nil {
+		for start < len(q.records) && q.records[start].ID < q.cursor.ID {
+			start++
+		}
+	}
+	start += q.skip
+	if start > len(q.records) {
+		return nil, nil
+	}
+	end := start + q.take
+	if end > len(q.records) {
+		end = len(q.records)
+	}
+	return q.records[start:end], nil
+}
+
+func newQuery(count int) query {
+	q := query{execs: new(int)}
+	for i := 1; i <= count; i++ {
+		q.records = append(q.records, record{ID: i})
+	}
+	return q
+}
+
+func cursorOf(r record) recordCursor {
+	return recordCursor{ID: r.ID}
+}
+
+func TestRun(t *testing.T) {
+	q := newQuery(25)
+
+	var mu sync.Mutex
+	updated := map[int]int{}
+	var reported []Progress[record]
+
+	progress, err := Run(context.Background(), q, cursorOf, func(ctx context.Context, r record) error {
+		mu.Lock()
+		updated[r.ID]++
+		mu.Unlock()
+		return nil
+	}, Config[record]{
+		BatchSize:   10,
+		Concurrency: 4,
+		OnProgress: func(p Progress[record]) {
+			reported = append(reported, p)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, 25, len(updated))
+	for id, count := range updated {
+		if count != 1 {
+			t.Errorf("record %d was updated %d times", id, count)
+		}
+	}
+	massert.Equal(t, 3, progress.Batches)
+	massert.Equal(t, 25, progress.Updated)
+	massert.Equal(t, record{ID: 25}, progress.Last)
+	massert.Equal(t, 3, len(reported))
+	massert.Equal(t, record{ID: 10}, reported[0].Last)
+	massert.Equal(t, record{ID: 20}, reported[1].Last)
+	// the last batch is smaller than the batch size, so no additional query is needed
+	massert.Equal(t, 3, *q.execs)
+}
+
+func TestRun_stopsOnError(t *testing.T) {
+	q := newQuery(25)
+	failure := errors.New("failure")
+
+	var reported []Progress[record]
+	progress, err := Run(context.Background(), q, cursorOf, func(ctx context.Context, r record) error {
+		if r.ID == 15 {
+			return failure
+		}
+		return nil
+	}, Config[record]{
+		BatchSize:   10,
+		Concurrency: 2,
+		OnProgress: func(p Progress[record]) {
+			reported = append(reported, p)
+		},
+	})
+
+	massert.Equal(t, true, errors.Is(err, failure))
+	massert.Equal(t, "update batch 2: failure", err.Error())
+	massert.Equal(t, 1, progress.Batches)
+	massert.Equal(t, record{ID: 10}, progress.Last)
+	massert.Equal(t, 1, len(reported))
+}
+
+func TestRun_rateLimit(t *testing.T) {
+	q := newQuery(5)
+
+	var count int32
+	start := time.Now()
+	_, err := Run(context.Background(), q, cursorOf, func(ctx context.Context, r record) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}, Config[record]{
+		Concurrency: 5,
+		Rate:        50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, int32(5), atomic.LoadInt32(&count))
+	// the first update is immediate, the others are spaced by 20ms
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected updates to be rate limited, took %s", elapsed)
+	}
+}
+
+func TestRun_cancel(t *testing.T) {
+	q := newQuery(5)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := Run(ctx, q, cursorOf, func(ctx context.Context, r record) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}, Config[record]{})
+
+	massert.Equal(t, true, errors.Is(err, context.Canceled))
+}
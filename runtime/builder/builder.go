@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,9 +14,13 @@ import (
 )
 
 type Input struct {
-	Name     string
-	Fields   []Field
-	Value    interface{}
+	Name   string
+	Fields []Field
+	Value  interface{}
+
+	// List saves whether the fields are a list of items, e.g. the records of a createMany query
+	List bool
+
 	WrapList bool
 }
 
@@ -71,6 +76,10 @@ type Query struct {
 	// Inputs contains function arguments
 	Inputs []Input
 
+	// Scope contains filters which are added to the where argument when the query is built, e.g. to exclude
+	// soft-deleted records
+	Scope []Field
+
 	// Outputs contains the return fields
 	Outputs []Output
 
@@ -78,9 +87,16 @@ type Query struct {
 	Start time.Time
 
 	TxResult chan []byte
+
+	// Err is returned when the query is built, e.g. if an input could not be encoded when the query was constructed
+	Err error
 }
 
 func (q Query) Build() (string, error) {
+	if q.Err != nil {
+		return "", q.Err
+	}
+
 	var builder strings.Builder
 
 	builder.WriteString(q.Operation + " " + q.Name)
@@ -103,8 +119,8 @@ func (q Query) BuildInner() (string, error) {
 
 	builder.WriteString(q.Method + q.Model)
 
-	if len(q.Inputs) > 0 {
-		str, err := q.buildInputs(q.Inputs)
+	if inputs := q.ScopedInputs(); len(inputs) > 0 {
+		str, err := q.buildInputs(inputs)
 		if err != nil {
 			return "", err
 		}
@@ -124,12 +140,29 @@ func (q Query) BuildInner() (string, error) {
 	return builder.String(), nil
 }
 
+// BuildArgs returns the arguments of the query as they are sent to the engine, sorted by name, e.g.
+// `(where:{id:"a",},)`, or an empty string if the query has none.
+func (q Query) BuildArgs() (string, error) {
+	inputs := q.ScopedInputs()
+	if len(inputs) == 0 {
+		return "", nil
+	}
+	return q.buildInputs(inputs)
+}
+
 func (q Query) buildInputs(inputs []Input) (string, error) {
 	var builder strings.Builder
 
 	builder.WriteString("(")
 
-	for _, i := range inputs {
+	// sort arguments by name for a canonical payload
+	sorted := make([]Input, len(inputs))
+	copy(sorted, inputs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, i := range sorted {
 		builder.WriteString(i.Name)
 
 		builder.WriteString(":")
@@ -137,15 +170,16 @@ func (q Query) buildInputs(inputs []Input) (string, error) {
 		if i.Value != nil {
 			builder.Write(Value(i.Value))
 		} else {
-			if i.WrapList {
+			list := i.List || i.WrapList
+			if list {
 				builder.WriteString("[")
 			}
-			str, err := q.buildFields(i.WrapList, i.WrapList, i.Fields)
+			str, err := q.buildFields(list, i.WrapList, i.Fields)
 			if err != nil {
 				return "", err
 			}
 			builder.WriteString(str)
-			if i.WrapList {
+			if list {
 				builder.WriteString("]")
 			}
 		}
@@ -198,32 +232,21 @@ func (q Query) buildFields(list bool, wrapList bool, fields []Field) (string, er
 	}
 
 	var final []Field
-	// remember the order in which the unique fields where added to the map
-	var uniqueNames []string
-
-	// check for duplicate fields so that multiple queries on the same field will be shared
-	// this is necessary for json filters and more
-	uniques := make(map[string]*Field)
-	for i, f := range fields {
-		if _, ok := uniques[f.Name]; ok {
-			// check if field is a model operation
-			if f.Fields != nil && f.Name != "AND" && f.Name != "OR" && f.Name != "NOT" {
-				// field already exists, join sub-fields
-				uniques[f.Name].Fields = append(uniques[f.Name].Fields, f.Fields...)
-			} else {
-				// if it's a list or just contains a value, just add it, which may result in a duplicate
-				// this is necessary for some operations, e.g. linking multiple records
-				final = append(final, f)
-			}
-		} else {
-			uniques[f.Name] = &fields[i]
-			uniqueNames = append(uniqueNames, f.Name)
-		}
+	switch {
+	case wrapList:
+		final = wrapListFields(fields)
+	case list:
+		final = joinFields(fields)
+	default:
+		final = joinFields(combineOperators(fields))
 	}
 
-	// use the list of unique names to add the unique fields in a deterministic order
-	for _, name := range uniqueNames {
-		final = append(final, *uniques[name])
+	// sort the fields of objects by name, so that identical queries always produce identical payloads,
+	// regardless of the order in which params were passed; the order of list items is meaningful and is kept
+	if !list {
+		sort.SliceStable(final, func(i, j int) bool {
+			return final[i].Name < final[j].Name
+		})
 	}
 
 	for _, f := range final {
@@ -277,6 +300,132 @@ func (q Query) buildFields(list bool, wrapList bool, fields []Field) (string, er
 	return builder.String(), nil
 }
 
+// joinFields joins the sub-fields of fields with the same name, so that multiple filters of the same field are shared
+func joinFields(fields []Field) []Field {
+	var final []Field
+	// remember the order in which the unique fields where added to the map
+	var uniqueNames []string
+
+	// check for duplicate fields so that multiple queries on the same field will be shared
+	// this is necessary for json filters and more
+	uniques := make(map[string]*Field)
+	for _, f := range fields {
+		// unnamed fields are items of a list of objects, e.g. composite types, which must never be joined
+		if f.Name == "" {
+			final = append(final, f)
+			continue
+		}
+		if u, ok := uniques[f.Name]; ok {
+			// check if field is a model operation
+			if f.Fields != nil && !logicalOperators[f.Name] {
+				// field already exists, join sub-fields without modifying the fields of the query
+				u.Fields = append(u.Fields[:len(u.Fields):len(u.Fields)], f.Fields...)
+			} else {
+				// if it's a list or just contains a value, just add it, which may result in a duplicate
+				// this is necessary for some operations, e.g. linking multiple records
+				final = append(final, f)
+			}
+		} else {
+			f := f
+			uniques[f.Name] = &f
+			uniqueNames = append(uniqueNames, f.Name)
+		}
+	}
+
+	// use the list of unique names to add the unique fields in a deterministic order
+	for _, name := range uniqueNames {
+		final = append(final, *uniques[name])
+	}
+
+	return final
+}
+
+// logicalOperators are the filters which combine other filters
+var logicalOperators = map[string]bool{
+	"AND": true,
+	"OR":  true,
+	"NOT": true,
+}
+
+// combineOperators combines the logical operators of an object with AND if one of them occurs more than once, e.g.
+// when two Or params are passed, as only the last one would be applied otherwise
+func combineOperators(fields []Field) []Field {
+	count := make(map[string]int)
+	duplicate := false
+	for _, f := range fields {
+		if logicalOperators[f.Name] {
+			count[f.Name]++
+			duplicate = duplicate || count[f.Name] > 1
+		}
+	}
+	if !duplicate {
+		return fields
+	}
+
+	var result, items []Field
+	for _, f := range fields {
+		if logicalOperators[f.Name] {
+			items = append(items, Field{Fields: []Field{f}})
+		} else {
+			result = append(result, f)
+		}
+	}
+	return append(result, Field{
+		Name:   "AND",
+		List:   true,
+		Fields: items,
+	})
+}
+
+// modifiers are the sub-fields of filters which only modify the other filters of the same field, e.g. the path of a
+// Json filter or the mode of a string filter
+var modifiers = map[string]bool{
+	"path": true,
+	"mode": true,
+}
+
+func isModifier(f Field) bool {
+	if f.Name == "" || len(f.Fields) == 0 {
+		return false
+	}
+	for _, sub := range f.Fields {
+		if !modifiers[sub.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// wrapListFields returns the items of a list of objects, e.g. the filters of Or, which are never joined, as each item
+// is a separate condition. Only modifiers are added to the other filters of their field.
+func wrapListFields(fields []Field) []Field {
+	mods := make(map[string][]Field)
+	filtered := make(map[string]bool)
+	for _, f := range fields {
+		if isModifier(f) {
+			mods[f.Name] = append(mods[f.Name], f.Fields...)
+		} else if f.Fields != nil {
+			filtered[f.Name] = true
+		}
+	}
+
+	final := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if isModifier(f) {
+			// modifiers without other filters are kept as they are
+			if !filtered[f.Name] {
+				final = append(final, f)
+			}
+			continue
+		}
+		if m, ok := mods[f.Name]; ok && f.Fields != nil {
+			f.Fields = append(f.Fields[:len(f.Fields):len(f.Fields)], m...)
+		}
+		final = append(final, f)
+	}
+	return final
+}
+
 func checkFields(parent Field, fields []Field) error {
 	uniqueObjectFields := make(map[string]Field)
 	for _, f := range fields {
@@ -291,6 +440,10 @@ func checkFields(parent Field, fields []Field) error {
 }
 
 func (q Query) Exec(ctx context.Context, into interface{}) error {
+	if err := ApplyPolicy(ctx, q.Engine, &q); err != nil {
+		return err
+	}
+
 	str, err := q.Build()
 	if err != nil {
 		return err
@@ -299,7 +452,12 @@ func (q Query) Exec(ctx context.Context, into interface{}) error {
 		Query:     str,
 		Variables: map[string]interface{}{},
 	}
-	return q.Do(ctx, payload, into)
+	start := time.Now()
+	err = execPolicy(ctx, q.Engine, q, into, func(ctx context.Context, into interface{}) error {
+		return q.Do(ctx, payload, into)
+	})
+	ObservePolicy(ctx, q.Engine, q, time.Since(start), err)
+	return err
 }
 
 func (q Query) Do(ctx context.Context, payload interface{}, into interface{}) error {
@@ -309,6 +467,7 @@ func (q Query) Do(ctx context.Context, payload interface{}, into interface{}) er
 
 	logger.Debug.Printf("[timing] building %q", time.Since(q.Start))
 
+	ctx = engine.WithOperation(ctx, q.Model, q.Method, q.Start)
 	err := q.Engine.Do(ctx, payload, into)
 	now := time.Now()
 	totalDuration := now.Sub(q.Start)
@@ -0,0 +1,245 @@
+package builder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/fixtures"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestValue_specialStrings(t *testing.T) {
+	for _, tt := range fixtures.SpecialStrings {
+		t.Run(tt.Name, func(t *testing.T) {
+			encoded := Value(tt.Value)
+
+			// GraphQL string literals must not contain raw line terminators or control characters
+			for _, r := range string(encoded) {
+				if r < 0x20 {
+					t.Fatalf("Value() contains raw control character %q: %s", r, encoded)
+				}
+			}
+
+			var actual string
+			if err := json.Unmarshal(encoded, &actual); err != nil {
+				t.Fatalf("could not decode %s: %s", encoded, err)
+			}
+			massert.Equal(t, tt.Value, actual)
+		})
+	}
+}
+
+func TestQuery_Build_escapesValues(t *testing.T) {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Inputs = []Input{{
+		Name: "where",
+		Fields: []Field{{
+			Name: "name",
+			Fields: []Field{{
+				Name:  "equals",
+				Value: "\"}) { evil } \\ \n 😀",
+			}},
+		}},
+	}}
+	q.Outputs = []Output{{Name: "id"}}
+
+	actual, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `query {result: findManyUser(where:{name:{equals:"\"}) { evil } \\ \n 😀",},},) {id }}`
+	massert.Equal(t, expected, actual)
+
+	if strings.Contains(actual, "\n") {
+		t.Errorf("query must not contain raw newlines: %s", actual)
+	}
+}
+
+func TestQuery_Build_listOfObjects(t *testing.T) {
+	q := NewQuery()
+	q.Operation = "mutation"
+	q.Method = "createOne"
+	q.Model = "User"
+	q.Inputs = []Input{{
+		Name: "data",
+		Fields: []Field{{
+			Name: "addresses",
+			Fields: []Field{{
+				Name: "set",
+				List: true,
+				Fields: []Field{{
+					Fields: []Field{{Name: "street", Value: "a"}},
+				}, {
+					Fields: []Field{{Name: "street", Value: "b"}},
+				}},
+			}},
+		}},
+	}}
+	q.Outputs = []Output{{Name: "id"}}
+
+	actual, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `mutation {result: createOneUser(data:{addresses:{set:[{street:"a",},{street:"b",},],},},) {id }}`
+	massert.Equal(t, expected, actual)
+}
+
+func TestQuery_Build_canonicalOrder(t *testing.T) {
+	build := func(inputs []Input) string {
+		q := NewQuery()
+		q.Operation = "query"
+		q.Method = "findMany"
+		q.Model = "User"
+		q.Inputs = inputs
+		q.Outputs = []Output{{Name: "id"}}
+		actual, err := q.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return actual
+	}
+
+	email := Field{Name: "email", Fields: []Field{{Name: "equals", Value: "a@b.c"}}}
+	name := Field{Name: "name", Fields: []Field{{Name: "contains", Value: "a"}, {Name: "mode", Value: "insensitive"}}}
+	nameReversed := Field{Name: "name", Fields: []Field{{Name: "mode", Value: "insensitive"}, {Name: "contains", Value: "a"}}}
+	orderBy := Input{Name: "orderBy", WrapList: true, Fields: []Field{{Name: "name", Value: "asc"}, {Name: "email", Value: "desc"}}}
+
+	a := build([]Input{
+		{Name: "where", Fields: []Field{email, name}},
+		{Name: "take", Value: 5},
+		orderBy,
+	})
+	b := build([]Input{
+		orderBy,
+		{Name: "take", Value: 5},
+		{Name: "where", Fields: []Field{nameReversed, email}},
+	})
+
+	expected := `query {result: findManyUser(orderBy:[{name:"asc"},{email:"desc"},],take:5,where:{email:{equals:"a@b.c",},name:{contains:"a",mode:"insensitive",},},) {id }}`
+	massert.Equal(t, expected, a)
+	massert.Equal(t, expected, b)
+}
+
+func TestQuery_Build_canonicalOrderKeepsListItems(t *testing.T) {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Inputs = []Input{{
+		Name: "where",
+		Fields: []Field{{
+			Name:     "OR",
+			List:     true,
+			WrapList: true,
+			Fields: []Field{
+				{Name: "name", Fields: []Field{{Name: "equals", Value: "b"}}},
+				{Name: "email", Fields: []Field{{Name: "equals", Value: "a"}}},
+			},
+		}},
+	}}
+	q.Outputs = []Output{{Name: "id"}}
+
+	actual, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `query {result: findManyUser(where:{OR:[{name:{equals:"b",}},{email:{equals:"a",}},],},) {id }}`
+	massert.Equal(t, expected, actual)
+}
+
+func TestValue_mapsAreSorted(t *testing.T) {
+	value := map[string]interface{}{
+		"z": 1,
+		"a": map[string]interface{}{"y": true, "b": false},
+		"m": "x",
+	}
+
+	for i := 0; i < 10; i++ {
+		massert.Equal(t, `{"a":{"b":false,"y":true},"m":"x","z":1}`, string(Value(value)))
+	}
+}
+
+func filter(name, operator string, value interface{}) Field {
+	return Field{Name: name, Fields: []Field{{Name: operator, Value: value}}}
+}
+
+func operator(name string, fields ...Field) Field {
+	return Field{Name: name, List: true, WrapList: true, Fields: fields}
+}
+
+func TestQuery_Build_logicalOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		where    []Field
+		expected string
+	}{{
+		name:     "filters of the same field are separate conditions",
+		where:    []Field{operator("OR", filter("age", "lt", 18), filter("age", "gt", 65))},
+		expected: `{OR:[{age:{lt:18,}},{age:{gt:65,}},],}`,
+	}, {
+		name:     "equal filters of the same field",
+		where:    []Field{operator("NOT", filter("email", "equals", "a"), filter("email", "equals", "b"))},
+		expected: `{NOT:[{email:{equals:"a",}},{email:{equals:"b",}},],}`,
+	}, {
+		name: "modifiers are added to the filters of their field",
+		where: []Field{operator("OR",
+			Field{Name: "meta", Fields: []Field{{Name: "path", Value: []string{"a"}}}},
+			filter("meta", "string_contains", "x"),
+			filter("name", "contains", "y"),
+		)},
+		expected: `{OR:[{meta:{path:["a"],string_contains:"x",}},{name:{contains:"y",}},],}`,
+	}, {
+		name: "operators which occur more than once are combined with AND",
+		where: []Field{
+			operator("OR", filter("name", "equals", "a"), filter("name", "equals", "b")),
+			filter("age", "gt", 18),
+			operator("OR", filter("email", "equals", "a"), operator("NOT", filter("age", "equals", 30))),
+		},
+		expected: `{AND:[{OR:[{name:{equals:"a",}},{name:{equals:"b",}},],},{OR:[{email:{equals:"a",}},{NOT:[{age:{equals:30,}},]},],},],age:{gt:18,},}`,
+	}, {
+		name: "nested relation filters",
+		where: []Field{{
+			Name: "posts",
+			Fields: []Field{{
+				Name: "some",
+				Fields: []Field{
+					operator("AND", filter("title", "startsWith", "a")),
+					operator("AND", filter("title", "endsWith", "b")),
+				},
+			}},
+		}},
+		expected: `{posts:{some:{AND:[{AND:[{title:{startsWith:"a",}},],},{AND:[{title:{endsWith:"b",}},],},],},},}`,
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery()
+			q.Operation = "query"
+			q.Method = "findMany"
+			q.Model = "User"
+			q.Inputs = []Input{{Name: "where", Fields: tt.where}}
+			q.Outputs = []Output{{Name: "id"}}
+
+			actual, err := q.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, `query {result: findManyUser(where:`+tt.expected+`,) {id }}`, actual)
+
+			// building a query doesn't modify it
+			again, err := q.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, actual, again)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package builder
+
+// countInputs are the arguments of a findMany query which also apply to counting its records
+var countInputs = map[string]bool{
+	"where":   true,
+	"orderBy": true,
+	"cursor":  true,
+	"skip":    true,
+	"take":    true,
+}
+
+// CountResult is the result of a query built with Count
+type CountResult struct {
+	Count struct {
+		All int `json:"_all"`
+	} `json:"_count"`
+}
+
+// Count returns a query which counts the records of a findMany query with the aggregate operation of the query engine.
+// Its filters, sorting and pagination are kept, while the selected fields and relations are replaced by the count.
+func Count(q Query) Query {
+	count := q
+	count.Method = "aggregate"
+	count.Inputs = nil
+	for _, input := range q.Inputs {
+		if countInputs[input.Name] {
+			count.Inputs = append(count.Inputs, input)
+		}
+	}
+	count.Outputs = []Output{{
+		Name:    "_count",
+		Outputs: []Output{{Name: "_all"}},
+	}}
+	return count
+}
@@ -0,0 +1,35 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestCount(t *testing.T) {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "UserStats"
+	q.Inputs = []Input{{
+		Name:   "where",
+		Fields: []Field{{Name: "posts", Fields: []Field{{Name: "gt", Value: 1}}}},
+	}, {
+		Name:  "take",
+		Value: 10,
+	}, {
+		Name:  "distinct",
+		Value: "email",
+	}}
+	q.Outputs = []Output{{Name: "id"}, {Name: "posts"}}
+
+	actual, err := Count(q).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `query {result: aggregateUserStats(take:10,where:{posts:{gt:1,},},) {_count {_all }}}`, actual)
+
+	// the find query is not changed
+	massert.Equal(t, "findMany", q.Method)
+	massert.Equal(t, 3, len(q.Inputs))
+}
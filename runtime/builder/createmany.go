@@ -0,0 +1,94 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+)
+
+// CreateMany returns a createMany query which creates the records of the createOne queries creates with a single
+// statement. createMany only accepts the scalar fields of a model, so relations which are linked by the fields they
+// reference are replaced by their foreign keys, e.g. `author:{connect:{id:"a"}}` by `authorID:"a"`, while other writes
+// of relations return an error. The query returns the number of created records; set its method to
+// createManyAndReturn and its outputs to the fields of the model to get the created records instead.
+func CreateMany(model *metadata.Model, creates []Query, skipDuplicates bool) (Query, error) {
+	q := NewQuery()
+	q.Operation = "mutation"
+	q.Method = "createMany"
+	q.Model = model.Name
+	q.Outputs = []Output{{Name: "count"}}
+
+	records := make([]Field, len(creates))
+	for i, create := range creates {
+		var data []Field
+		for _, input := range create.Inputs {
+			if input.Name == "data" {
+				data = input.Fields
+			}
+		}
+		fields, err := createManyFields(model, data)
+		if err != nil {
+			return Query{}, fmt.Errorf("create many: record %d: %w", i, err)
+		}
+		records[i] = Field{Fields: fields}
+	}
+
+	q.Inputs = append(q.Inputs, Input{
+		Name:   "data",
+		List:   true,
+		Fields: records,
+	})
+	if skipDuplicates {
+		q.Inputs = append(q.Inputs, Input{
+			Name:  "skipDuplicates",
+			Value: true,
+		})
+	}
+	return q, nil
+}
+
+// createManyFields returns the data of a createOne query with linked relations replaced by their foreign keys
+func createManyFields(model *metadata.Model, data []Field) ([]Field, error) {
+	fields := make([]Field, 0, len(data))
+	for _, f := range data {
+		// skip empty params, e.g. of SetIfPresent(nil)
+		if f.Name == "" && f.Value == nil && f.Fields == nil {
+			continue
+		}
+		field, ok := model.Field(f.Name)
+		if !ok || field.Kind != metadata.FieldKindRelation {
+			fields = append(fields, f)
+			continue
+		}
+		keys, err := foreignKeys(field, f)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, keys...)
+	}
+	return fields, nil
+}
+
+// foreignKeys returns the foreign keys of a relation which is linked by the fields it references
+func foreignKeys(field *metadata.Field, f Field) ([]Field, error) {
+	if len(field.RelationFromFields) == 0 || len(f.Fields) != 1 || f.Fields[0].Name != "connect" {
+		return nil, fmt.Errorf("relation %s can only be linked, as nested writes are not supported", field.Name)
+	}
+
+	values := make(map[string]interface{})
+	for _, c := range f.Fields[0].Fields {
+		values[c.Name] = c.Value
+	}
+	keys := make([]Field, len(field.RelationToFields))
+	for i, to := range field.RelationToFields {
+		value, ok := values[to]
+		if !ok || value == nil || len(values) != len(field.RelationToFields) {
+			return nil, fmt.Errorf("relation %s can only be linked by %v", field.Name, field.RelationToFields)
+		}
+		keys[i] = Field{
+			Name:  field.RelationFromFields[i],
+			Value: value,
+		}
+	}
+	return keys, nil
+}
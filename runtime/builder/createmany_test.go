@@ -0,0 +1,101 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+var postModel = &metadata.Model{
+	Name: "Post",
+	Fields: []metadata.Field{
+		{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true, IsID: true, HasDefaultValue: true},
+		{Name: "title", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true},
+		{Name: "authorID", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true},
+		{
+			Name:               "author",
+			Kind:               metadata.FieldKindRelation,
+			Type:               "User",
+			IsRequired:         true,
+			RelationFromFields: []string{"authorID"},
+			RelationToFields:   []string{"id"},
+		},
+	},
+}
+
+func createPost(data ...Field) Query {
+	q := NewQuery()
+	q.Operation = "mutation"
+	q.Method = "createOne"
+	q.Model = "Post"
+	q.Inputs = []Input{{
+		Name:   "data",
+		Fields: data,
+	}}
+	return q
+}
+
+func linkAuthor(fields ...Field) Field {
+	return Field{
+		Name:   "author",
+		Fields: []Field{{Name: "connect", Fields: fields}},
+	}
+}
+
+func TestCreateMany(t *testing.T) {
+	q, err := CreateMany(postModel, []Query{
+		createPost(Field{Name: "title", Value: "a"}, linkAuthor(Field{Name: "id", Value: "alice"})),
+		createPost(Field{Name: "title", Value: "b"}, linkAuthor(Field{Name: "id", Value: "bob"}), Field{}),
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	str, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `mutation {result: createManyPost(data:[{authorID:"alice",title:"a",},{authorID:"bob",title:"b",},],skipDuplicates:true,) {count }}`, str)
+
+	// the list of records is kept when the query is serialized
+	hash := SchemaHash("model Post {}")
+	data, err := q.Marshal(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := Unmarshal(data, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	built, err := actual.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, str, built)
+}
+
+func TestCreateMany_nestedWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     Field
+		expected string
+	}{{
+		name:     "create",
+		data:     Field{Name: "author", Fields: []Field{{Name: "create", Fields: []Field{{Name: "name", Value: "alice"}}}}},
+		expected: "create many: record 0: relation author can only be linked, as nested writes are not supported",
+	}, {
+		name:     "other unique field",
+		data:     linkAuthor(Field{Name: "email", Value: "alice@example.com"}),
+		expected: "create many: record 0: relation author can only be linked by [id]",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CreateMany(postModel, []Query{createPost(Field{Name: "title", Value: "a"}, tt.data)}, false)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			massert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
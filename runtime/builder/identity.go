@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/steebchen/prisma-client-go/engine"
+)
+
+// identityActions are the actions whose results are memoized by an identity map
+var identityActions = map[string]bool{
+	"findUnique":        true,
+	"findUniqueOrThrow": true,
+}
+
+// readActions are the actions which don't clear an identity map. Raw queries clear it, as they may write.
+var readActions = map[string]bool{
+	"findUnique":        true,
+	"findUniqueOrThrow": true,
+	"findFirst":         true,
+	"findFirstOrThrow":  true,
+	"findMany":          true,
+	"aggregate":         true,
+	"groupBy":           true,
+}
+
+type identityMapKey struct{}
+
+// identityKey identifies a query and the engine it is sent to, so that clients of different databases, e.g. the
+// tenants of a client pool, which share a context don't share results
+type identityKey struct {
+	engine engine.Engine
+	query  string
+}
+
+// identityMap memoizes the results of the findUnique queries of a context
+type identityMap struct {
+	mu      sync.Mutex
+	results map[identityKey]json.RawMessage
+	// generation counts how often the map was cleared, so that a result which was read before a write is not stored
+	// after it
+	generation uint64
+}
+
+// WithIdentityMap returns a context in which repeated FindUnique queries for the same record, with the same selection,
+// are sent to the engine only once, e.g. when multiple layers of a request load the current user. Each query still
+// returns its own copy of the record, and results are kept for each engine, so that the clients of different
+// databases can share the context. Any write with the context clears the map, and queries of interactive transactions
+// neither read nor fill it. If ctx has an identity map already, it is returned as it is.
+func WithIdentityMap(ctx context.Context) context.Context {
+	if identityMapFromContext(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, identityMapKey{}, &identityMap{
+		results: make(map[identityKey]json.RawMessage),
+	})
+}
+
+func identityMapFromContext(ctx context.Context) *identityMap {
+	m, _ := ctx.Value(identityMapKey{}).(*identityMap)
+	return m
+}
+
+// exec serves q from the map if it is a findUnique query which was executed before, or executes it with next
+func (m *identityMap) exec(ctx context.Context, q Query, transaction bool, into interface{}, next func(ctx context.Context, into interface{}) error) error {
+	if !identityActions[q.Method] || transaction {
+		err := next(ctx, into)
+		// clear even if the query failed, as it may have been applied before its context was cancelled
+		m.invalidate(q)
+		return err
+	}
+
+	// engines which can't be compared can't be told apart, so their queries are not memoized
+	if q.Engine == nil || !reflect.TypeOf(q.Engine).Comparable() {
+		return next(ctx, into)
+	}
+	query, err := q.BuildInner()
+	if err != nil {
+		return next(ctx, into)
+	}
+	key := identityKey{engine: q.Engine, query: query}
+
+	m.mu.Lock()
+	result, ok := m.results[key]
+	generation := m.generation
+	m.mu.Unlock()
+	if ok {
+		return decodeResult(result, into)
+	}
+
+	if err := next(ctx, &result); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if m.generation == generation {
+		m.results[key] = result
+	}
+	m.mu.Unlock()
+	return decodeResult(result, into)
+}
+
+// invalidate clears the map if q may have written
+func (m *identityMap) invalidate(q Query) {
+	if readActions[q.Method] {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = make(map[identityKey]json.RawMessage)
+	m.generation++
+}
+
+func decodeResult(result json.RawMessage, into interface{}) error {
+	if into == nil {
+		return nil
+	}
+	return json.Unmarshal(result, into)
+}
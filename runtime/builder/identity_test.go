@@ -0,0 +1,116 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// countingEngine responds to each query with the number of queries it received
+type countingEngine struct {
+	policyEngine
+	queries int
+}
+
+func (e *countingEngine) Do(ctx context.Context, payload interface{}, v interface{}) error {
+	e.queries++
+	return json.Unmarshal([]byte(fmt.Sprintf(`{"id":"1","n":%d}`, e.queries)), v)
+}
+
+func findUnique(id string) Query {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findUnique"
+	q.Model = "User"
+	q.Inputs = []Input{{Name: "where", Fields: []Field{{Name: "id", Value: id}}}}
+	q.Outputs = []Output{{Name: "id"}, {Name: "n"}}
+	return q
+}
+
+type identityResult struct {
+	ID string `json:"id"`
+	N  int    `json:"n"`
+}
+
+func TestIdentityMap(t *testing.T) {
+	e := &countingEngine{}
+	ctx := WithIdentityMap(context.Background())
+
+	exec := func(ctx context.Context, q Query) identityResult {
+		t.Helper()
+		q.Engine = e
+		var v identityResult
+		if err := q.Exec(ctx, &v); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	massert.Equal(t, identityResult{ID: "1", N: 1}, exec(ctx, findUnique("1")))
+	massert.Equal(t, identityResult{ID: "1", N: 1}, exec(ctx, findUnique("1")))
+	massert.Equal(t, 1, e.queries)
+
+	// a nested identity map is the same one
+	massert.Equal(t, identityResult{ID: "1", N: 1}, exec(WithIdentityMap(ctx), findUnique("1")))
+
+	// other records and contexts without identity map are queried
+	massert.Equal(t, 2, exec(ctx, findUnique("2")).N)
+	massert.Equal(t, 3, exec(context.Background(), findUnique("1")).N)
+
+	// reads keep the map
+	exec(ctx, findMany())
+	massert.Equal(t, 1, exec(ctx, findUnique("1")).N)
+	massert.Equal(t, 4, e.queries)
+
+	// writes clear it
+	update := findUnique("1")
+	update.Operation = "mutation"
+	update.Method = "updateOne"
+	exec(ctx, update)
+	massert.Equal(t, 6, exec(ctx, findUnique("1")).N)
+}
+
+func TestIdentityMap_transaction(t *testing.T) {
+	e := &countingEngine{}
+	e.policy, _ = (*Policy)(nil).Transaction()
+	ctx := WithIdentityMap(context.Background())
+
+	for i := 1; i <= 2; i++ {
+		q := findUnique("1")
+		q.Engine = e
+		var v identityResult
+		if err := q.Exec(ctx, &v); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, i, v.N)
+	}
+}
+
+func TestIdentityMap_engines(t *testing.T) {
+	tenantA := &countingEngine{}
+	tenantB := &countingEngine{}
+	tenantB.queries = 100
+	ctx := WithIdentityMap(context.Background())
+
+	exec := func(e *countingEngine) identityResult {
+		t.Helper()
+		q := findUnique("1")
+		q.Engine = e
+		var v identityResult
+		if err := q.Exec(ctx, &v); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	// the same query on another engine is not served from the results of the first one
+	massert.Equal(t, 1, exec(tenantA).N)
+	massert.Equal(t, 101, exec(tenantB).N)
+	massert.Equal(t, 1, exec(tenantA).N)
+	massert.Equal(t, 101, exec(tenantB).N)
+	massert.Equal(t, 1, tenantA.queries)
+	massert.Equal(t, 101, tenantB.queries)
+}
@@ -0,0 +1,23 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/steebchen/prisma-client-go/runtime/scan"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+// ExecInto executes the query and decodes its result into into with scan.Into, e.g. the fields chosen with Select into
+// a custom struct. It returns types.ErrNotFound if a query of a single record doesn't find one.
+func (q Query) ExecInto(ctx context.Context, into interface{}) error {
+	var data json.RawMessage
+	if err := q.Exec(ctx, &data); err != nil {
+		return err
+	}
+	if len(data) == 0 || bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return types.ErrNotFound
+	}
+	return scan.Into(data, into)
+}
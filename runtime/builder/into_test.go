@@ -0,0 +1,35 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/types"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQuery_ExecInto(t *testing.T) {
+	e := &scriptedEngine{responses: []json.RawMessage{
+		json.RawMessage(`[{"id":"1","name":"a"},{"id":"2","name":"a"}]`),
+		json.RawMessage(`null`),
+	}}
+	q := findUsers()
+	q.Engine = e
+
+	var names []struct {
+		Name string `prisma:"name"`
+	}
+	if err := q.ExecInto(context.Background(), &names); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 2, len(names))
+	massert.Equal(t, "a", names[1].Name)
+
+	q.Method = "findFirst"
+	var user struct {
+		ID string
+	}
+	err := q.ExecInto(context.Background(), &user)
+	massert.Equal(t, types.ErrNotFound, err)
+}
@@ -0,0 +1,235 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/runtime/cursor"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+// keysetInputs are the arguments of a findMany query which are replaced by the cursor and the limit
+var keysetInputs = map[string]bool{
+	"cursor": true,
+	"skip":   true,
+	"take":   true,
+}
+
+// keysetKey is a field which the records of a keyset page are ordered by
+type keysetKey struct {
+	name string
+	desc bool
+}
+
+// ExecKeyset executes the findMany query q for up to limit records following the record the cursor after was derived
+// from, or for the first records if after is empty. The records are decoded into into and the cursor of the next
+// page is returned, which is empty if there are no more records.
+//
+// Records are ordered by the scalar fields of OrderBy, followed by the unique field id, so that the order is stable
+// even if the other fields have duplicate values. Instead of skipping records, the values of these fields are
+// compared to the ones of the cursor, so records inserted or deleted in between don't shift the pages. The fields
+// must not be null. Cursor, Skip and Take of q are replaced.
+//
+// Cursors are tokens of the cursor package, which are sealed with the CursorSigner of the policy of the engine of q,
+// if it has one.
+func (q Query) ExecKeyset(ctx context.Context, id string, after string, limit int, into interface{}) (string, error) {
+	if limit < 1 {
+		return "", fmt.Errorf("keyset: limit must be at least 1, got %d", limit)
+	}
+
+	page := q
+	page.Inputs = nil
+	// the fields of all orderBy arguments are sent as one, so that the unique field can be added at the end
+	var orderBy []Field
+	var keys []keysetKey
+	for _, input := range q.Inputs {
+		if keysetInputs[input.Name] {
+			continue
+		}
+		if input.Name != "orderBy" {
+			page.Inputs = append(page.Inputs, input)
+			continue
+		}
+		for _, f := range input.Fields {
+			key, err := orderKey(f)
+			if err != nil {
+				return "", err
+			}
+			keys = append(keys, key)
+			orderBy = append(orderBy, f)
+		}
+	}
+	if !hasKey(keys, id) {
+		keys = append(keys, keysetKey{name: id})
+		orderBy = append(orderBy, Field{Name: id, Value: "asc"})
+	}
+	page.Inputs = append(page.Inputs, Input{
+		Name:     "orderBy",
+		Fields:   orderBy,
+		WrapList: true,
+	})
+
+	// the fields of the cursor are fetched even if they are not selected, so that the next cursor can be derived
+	page.Outputs = append([]Output{}, q.Outputs...)
+	for _, key := range keys {
+		if !hasOutput(page.Outputs, key.name) {
+			page.Outputs = append(page.Outputs, Output{Name: key.name})
+		}
+	}
+
+	if after != "" {
+		values, err := decodeKeyset(cursorSigner(q.Engine), after, keys)
+		if err != nil {
+			return "", err
+		}
+		page.Scope = append(q.Scope[:len(q.Scope):len(q.Scope)], keysetFilter(keys, values))
+	}
+
+	// one more record is read to find out whether there is a next page
+	var rows []json.RawMessage
+	if err := page.withTake(limit+1).Exec(ctx, &rows); err != nil {
+		return "", err
+	}
+
+	var next string
+	if len(rows) > limit {
+		rows = rows[:limit]
+		var err error
+		if next, err = encodeKeyset(cursorSigner(q.Engine), rows[limit-1], keys); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return "", fmt.Errorf("json data result unmarshal: %w", err)
+	}
+	return next, nil
+}
+
+// orderKey returns the field and direction of an orderBy field, which must order by a scalar field
+func orderKey(f Field) (keysetKey, error) {
+	var direction string
+	if f.Fields == nil && f.Value != nil {
+		raw, err := json.Marshal(f.Value)
+		if err != nil {
+			return keysetKey{}, err
+		}
+		if err := json.Unmarshal(raw, &direction); err != nil {
+			return keysetKey{}, fmt.Errorf("keyset: unsupported order of field %q", f.Name)
+		}
+	}
+	switch direction {
+	case "asc":
+		return keysetKey{name: f.Name}, nil
+	case "desc":
+		return keysetKey{name: f.Name, desc: true}, nil
+	default:
+		return keysetKey{}, fmt.Errorf("keyset: unsupported order of field %q, only scalar fields can be ordered by", f.Name)
+	}
+}
+
+func hasKey(keys []keysetKey, name string) bool {
+	for _, key := range keys {
+		if key.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOutput(outputs []Output, name string) bool {
+	for _, o := range outputs {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// keysetFilter returns a filter for the records following the given values of the keys, i.e. the records where one of
+// the keys is after its value while all keys before it are equal to theirs
+func keysetFilter(keys []keysetKey, values []json.RawMessage) Field {
+	or := make([]Field, len(keys))
+	for i, key := range keys {
+		var and []Field
+		for j := 0; j < i; j++ {
+			and = append(and, Field{
+				Name:   keys[j].name,
+				Fields: []Field{{Name: "equals", Value: values[j]}},
+			})
+		}
+		operator := "gt"
+		if key.desc {
+			operator = "lt"
+		}
+		and = append(and, Field{
+			Name:   key.name,
+			Fields: []Field{{Name: operator, Value: values[i]}},
+		})
+		or[i] = Field{Fields: and}
+	}
+	return Field{
+		Name:   "OR",
+		List:   true,
+		Fields: or,
+	}
+}
+
+// encodeKeyset returns a cursor of the values of the keys of a record, sealed with signer unless it is nil
+func encodeKeyset(signer *cursor.Signer, row json.RawMessage, keys []keysetKey) (string, error) {
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(row, &record); err != nil {
+		return "", fmt.Errorf("json data result unmarshal: %w", err)
+	}
+	values := make(map[string]json.RawMessage, len(keys))
+	for _, key := range keys {
+		value := record[key.name]
+		if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+			return "", fmt.Errorf("keyset: field %q of the last record is null", key.name)
+		}
+		values[key.name] = value
+	}
+	return cursor.Encode(signer, values)
+}
+
+// scalarValue returns whether value is a JSON string, number or boolean, as cursors are handed out to clients and
+// must not inject other filters
+func scalarValue(value json.RawMessage) bool {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case string, float64, bool:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeKeyset returns the values of the keys of a cursor. It returns types.ErrInvalidCursor if the cursor is
+// malformed, its signature doesn't match signer or it was derived from a query with a different order.
+func decodeKeyset(signer *cursor.Signer, token string, keys []keysetKey) ([]json.RawMessage, error) {
+	record, err := cursor.Decode[map[string]json.RawMessage](signer, token)
+	if err != nil {
+		return nil, types.ErrInvalidCursor
+	}
+	if len(record) != len(keys) {
+		return nil, types.ErrInvalidCursor
+	}
+	values := make([]json.RawMessage, len(keys))
+	for i, key := range keys {
+		value, ok := record[key.name]
+		if !ok || !scalarValue(value) {
+			return nil, types.ErrInvalidCursor
+		}
+		values[i] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,151 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/cursor"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQuery_ExecKeyset(t *testing.T) {
+	e := &scriptedEngine{responses: []json.RawMessage{
+		json.RawMessage(`[{"id":"1","name":"a"},{"id":"2","name":"a"},{"id":"3","name":"b"}]`),
+		json.RawMessage(`[{"id":"3","name":"b"}]`),
+	}}
+	q := findUsers()
+	q.Engine = e
+	q.Inputs = append(q.Inputs, Input{
+		Name:     "orderBy",
+		Fields:   []Field{{Name: "name", Value: "desc"}},
+		WrapList: true,
+	})
+	q.Outputs = []Output{{Name: "name"}}
+
+	var users []returnedUser
+	next, err := q.ExecKeyset(context.Background(), "id", "", 2, &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []returnedUser{{ID: "1", Name: "a"}, {ID: "2", Name: "a"}}, users)
+	massert.Equal(t, "eyJpZCI6IjIiLCJuYW1lIjoiYSJ9", next)
+
+	users = nil
+	next, err = q.ExecKeyset(context.Background(), "id", next, 2, &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []returnedUser{{ID: "3", Name: "b"}}, users)
+	massert.Equal(t, "", next)
+
+	massert.Equal(t, []string{
+		`query {result: findManyUser(orderBy:[{name:"desc"},{id:"asc"},],take:3,where:{name:{equals:"a",},},) {name id }}`,
+		`query {result: findManyUser(orderBy:[{name:"desc"},{id:"asc"},],take:3,where:{OR:[{name:{lt:"a",},},{id:{gt:"2",},name:{equals:"a",},},],name:{equals:"a",},},) {name id }}`,
+	}, e.queries)
+}
+
+// signedEngine is a scripted engine with a policy which signs cursors
+type signedEngine struct {
+	*scriptedEngine
+	policy *Policy
+}
+
+func (e signedEngine) QueryPolicy() *Policy {
+	return e.policy
+}
+
+func TestQuery_ExecKeyset_signed(t *testing.T) {
+	signer, err := cursor.NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := signedEngine{
+		scriptedEngine: &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`[{"id":"1"},{"id":"2"}]`),
+			json.RawMessage(`[{"id":"2"}]`),
+		}},
+		policy: &Policy{CursorSigner: signer},
+	}
+	q := findUsers()
+	q.Engine = e
+	q.Outputs = []Output{{Name: "id"}}
+
+	var users []returnedUser
+	next, err := q.ExecKeyset(context.Background(), "id", "", 1, &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the cursor is sealed, so its values can't be read
+	massert.Equal(t, false, next == "eyJpZCI6IjEifQ")
+
+	// unsealed cursors are rejected
+	_, err = q.ExecKeyset(context.Background(), "id", "eyJpZCI6IjEifQ", 1, &users)
+	massert.Equal(t, types.ErrInvalidCursor, err)
+
+	users = nil
+	if _, err := q.ExecKeyset(context.Background(), "id", next, 1, &users); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []returnedUser{{ID: "2"}}, users)
+}
+
+func TestQuery_ExecKeyset_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		orderBy  []Field
+		after    string
+		limit    int
+		response string
+		expected string
+	}{{
+		name:     "limit",
+		limit:    0,
+		expected: "keyset: limit must be at least 1, got 0",
+	}, {
+		name:     "relation order",
+		orderBy:  []Field{{Name: "posts", Fields: []Field{{Name: "_count", Value: "asc"}}}},
+		limit:    10,
+		expected: `keyset: unsupported order of field "posts", only scalar fields can be ordered by`,
+	}, {
+		name:     "malformed cursor",
+		after:    "!!",
+		limit:    10,
+		expected: types.ErrInvalidCursor.Error(),
+	}, {
+		name: "cursor of a different order",
+		// {"id":"2","name":"a"}
+		after:    "eyJpZCI6IjIiLCJuYW1lIjoiYSJ9",
+		limit:    10,
+		expected: types.ErrInvalidCursor.Error(),
+	}, {
+		name: "cursor with an object",
+		// {"id":{"not":null}}
+		after:    "eyJpZCI6eyJub3QiOm51bGx9fQ",
+		limit:    10,
+		expected: types.ErrInvalidCursor.Error(),
+	}, {
+		name:     "null key",
+		orderBy:  []Field{{Name: "bio", Value: "asc"}},
+		limit:    1,
+		response: `[{"id":"1","bio":null},{"id":"2","bio":null}]`,
+		expected: `keyset: field "bio" of the last record is null`,
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			q := findUsers()
+			q.Engine = &scriptedEngine{responses: []json.RawMessage{json.RawMessage(tt.response)}}
+			if tt.orderBy != nil {
+				q.Inputs = append(q.Inputs, Input{Name: "orderBy", Fields: tt.orderBy, WrapList: true})
+			}
+			var users []returnedUser
+			_, err := q.ExecKeyset(context.Background(), "id", tt.after, tt.limit, &users)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			massert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
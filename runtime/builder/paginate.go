@@ -0,0 +1,122 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+)
+
+// paginationInputs are the arguments of a findMany query which are replaced by the page
+var paginationInputs = map[string]bool{
+	"skip": true,
+	"take": true,
+}
+
+// ExecPage executes the findMany query q for the records of the given page, which starts at 1, and counts all records
+// matching its filters. Both queries are sent in a single batch request, within a transaction unless q is already part
+// of an interactive transaction, so that the count is consistent with the records. The records are decoded into into
+// and the count is returned. Skip and Take of q are replaced by the page.
+func (q Query) ExecPage(ctx context.Context, page, pageSize int, into interface{}) (int, error) {
+	if page < 1 {
+		return 0, fmt.Errorf("paginate: page must be at least 1, got %d", page)
+	}
+	if pageSize < 1 {
+		return 0, fmt.Errorf("paginate: page size must be at least 1, got %d", pageSize)
+	}
+
+	all := q
+	all.Inputs = nil
+	for _, input := range q.Inputs {
+		if !paginationInputs[input.Name] {
+			all.Inputs = append(all.Inputs, input)
+		}
+	}
+	count := Count(all)
+	find := all.withTake(pageSize)
+	find.Inputs = append(find.Inputs, Input{Name: "skip", Value: (page - 1) * pageSize})
+
+	queries := []Query{find, count}
+	requests := make([]protocol.GQLRequest, len(queries))
+	for i := range queries {
+		if err := ApplyPolicy(ctx, q.Engine, &queries[i]); err != nil {
+			return 0, err
+		}
+		str, err := queries[i].Build()
+		if err != nil {
+			return 0, err
+		}
+		requests[i] = protocol.GQLRequest{
+			Query:     str,
+			Variables: map[string]interface{}{},
+		}
+	}
+
+	start := time.Now()
+	results, err := q.batch(ctx, requests)
+	for _, query := range queries {
+		ObservePolicy(ctx, q.Engine, query, time.Since(start), err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := json.Unmarshal(results[0], into); err != nil {
+		return 0, fmt.Errorf("json data result unmarshal: %w", err)
+	}
+	var total CountResult
+	if err := json.Unmarshal(results[1], &total); err != nil {
+		return 0, fmt.Errorf("json data result unmarshal: %w", err)
+	}
+	return total.Count.All, nil
+}
+
+// batch sends the requests in a single batch request and returns their results
+func (q Query) batch(ctx context.Context, requests []protocol.GQLRequest) ([]json.RawMessage, error) {
+	if q.Engine == nil {
+		return nil, fmt.Errorf("client.Prisma.Connect() needs to be called before sending queries")
+	}
+
+	var response protocol.GQLBatchResponse
+	payload := protocol.GQLBatchRequest{
+		Batch:       requests,
+		Transaction: !inTransaction(q.Engine),
+	}
+	ctx = engine.WithOperation(ctx, q.Model, q.Method, q.Start)
+	if err := q.Engine.Batch(ctx, payload, &response); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	errs := response.Errors
+	for _, inner := range response.Result {
+		errs = append(errs, inner.Errors...)
+	}
+	if len(errs) > 0 {
+		if errs[0].UserFacingError != nil {
+			return nil, fmt.Errorf("user facing error: %w", errs[0].UserFacingError)
+		}
+		return nil, fmt.Errorf("internal error: %s", errs[0].RawMessage())
+	}
+	if len(response.Result) != len(requests) {
+		return nil, fmt.Errorf("batch request: expected %d results, got %d", len(requests), len(response.Result))
+	}
+
+	results := make([]json.RawMessage, len(response.Result))
+	for i, inner := range response.Result {
+		results[i] = inner.Data.Result
+	}
+	return results, nil
+}
+
+// inTransaction returns whether the queries of the given engine are sent within an interactive transaction
+func inTransaction(e engine.Engine) bool {
+	if provider, ok := e.(PolicyProvider); ok {
+		if p := provider.QueryPolicy(); p != nil && p.transaction {
+			return true
+		}
+	}
+	return engine.InTransaction(e)
+}
@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// batchEngine responds to batch requests with a fixed response
+type batchEngine struct {
+	scriptedEngine
+	response string
+	payloads []protocol.GQLBatchRequest
+}
+
+func (e *batchEngine) Batch(ctx context.Context, payload interface{}, v interface{}) error {
+	e.payloads = append(e.payloads, payload.(protocol.GQLBatchRequest))
+	return json.Unmarshal([]byte(e.response), v)
+}
+
+func findUsers() Query {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Inputs = []Input{{
+		Name:   "where",
+		Fields: []Field{{Name: "name", Fields: []Field{{Name: "equals", Value: "a"}}}},
+	}, {
+		Name:  "take",
+		Value: 100,
+	}}
+	q.Outputs = []Output{{Name: "id"}, {Name: "name"}}
+	return q
+}
+
+func TestQuery_ExecPage(t *testing.T) {
+	e := &batchEngine{response: `{"batchResult":[
+		{"data":{"result":[{"id":"3","name":"a"}]}},
+		{"data":{"result":{"_count":{"_all":5}}}}
+	]}`}
+	q := findUsers()
+	q.Engine = e
+
+	var users []returnedUser
+	total, err := q.ExecPage(context.Background(), 2, 2, &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 5, total)
+	massert.Equal(t, []returnedUser{{ID: "3", Name: "a"}}, users)
+	massert.Equal(t, []protocol.GQLBatchRequest{{
+		Batch: []protocol.GQLRequest{{
+			Query:     `query {result: findManyUser(skip:2,take:2,where:{name:{equals:"a",},},) {id name }}`,
+			Variables: map[string]interface{}{},
+		}, {
+			Query:     `query {result: aggregateUser(where:{name:{equals:"a",},},) {_count {_all }}}`,
+			Variables: map[string]interface{}{},
+		}},
+		Transaction: true,
+	}}, e.payloads)
+}
+
+func TestQuery_ExecPage_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int
+		pageSize int
+		response string
+		expected string
+	}{{
+		name:     "page",
+		page:     0,
+		pageSize: 10,
+		expected: "paginate: page must be at least 1, got 0",
+	}, {
+		name:     "page size",
+		page:     1,
+		pageSize: 0,
+		expected: "paginate: page size must be at least 1, got 0",
+	}, {
+		name:     "query",
+		page:     1,
+		pageSize: 10,
+		response: `{"batchResult":[{"errors":[{"error":"boom"}]},{"data":{"result":{"_count":{"_all":0}}}}]}`,
+		expected: "internal error: boom",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			q := findUsers()
+			q.Engine = &batchEngine{response: tt.response}
+			var users []returnedUser
+			_, err := q.ExecPage(context.Background(), tt.page, tt.pageSize, &users)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			massert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/cursor"
+)
+
+// Hook is called with every query before it is sent to the engine.
+// It can inspect and modify the query, e.g. to add inputs, or veto it by returning an error.
+type Hook func(ctx context.Context, q *Query) error
+
+// Policy holds defaults and hooks which are applied centrally to all queries of a client.
+type Policy struct {
+	// DefaultTake is set as take on findMany queries which don't specify one. 0 means no default.
+	DefaultTake int
+
+	// Hooks are called in order after the defaults were applied
+	Hooks []Hook
+
+	// Observers are called in order after each query was executed
+	Observers []Observer
+
+	// Cache serves read queries from a cache and is invalidated by writes; nil means no cache
+	Cache Cache
+
+	// CursorSigner seals the cursors of keyset pagination and rejects cursors it didn't seal; nil means cursors are
+	// only base64 encoded
+	CursorSigner *cursor.Signer
+
+	// transaction is set for the queries of an interactive transaction
+	transaction bool
+}
+
+// Cache serves read queries from a cache instead of the engine and invalidates them when the records they depend on
+// are written. It is implemented by the cache package.
+type Cache interface {
+	// Exec executes q, either with next, which sends it to the engine, or from the cache
+	Exec(ctx context.Context, q Query, into interface{}, next func(ctx context.Context, into interface{}) error) error
+
+	// Invalidate invalidates the cached queries which the query q may have changed, if it is a write. It is called
+	// for queries which were sent without Exec, e.g. in batch transactions.
+	Invalidate(ctx context.Context, q Query)
+
+	// Transaction returns a cache for the queries of an interactive transaction, which are never served from the
+	// cache, as they may depend on uncommitted writes. Their invalidations are deferred until commit is called after
+	// the transaction was committed.
+	Transaction() (tx Cache, commit func(ctx context.Context))
+}
+
+// Observer is called with every query after it was executed, with how long the engine took to respond and the error
+// returned by the engine, if any. It must not modify the query.
+type Observer func(ctx context.Context, q Query, duration time.Duration, err error)
+
+// PolicyProvider is implemented by engines which apply a policy to their queries, usually the generated client.
+type PolicyProvider interface {
+	QueryPolicy() *Policy
+}
+
+// ApplyPolicy applies the policy of the given engine, if any, to the query.
+func ApplyPolicy(ctx context.Context, e interface{}, q *Query) error {
+	provider, ok := e.(PolicyProvider)
+	if !ok {
+		return nil
+	}
+	return provider.QueryPolicy().Apply(ctx, q)
+}
+
+// ObservePolicy calls the observers of the policy of the given engine, if any, after the query was executed.
+func ObservePolicy(ctx context.Context, e interface{}, q Query, duration time.Duration, err error) {
+	provider, ok := e.(PolicyProvider)
+	if !ok {
+		return
+	}
+	provider.QueryPolicy().Observe(ctx, q, duration, err)
+}
+
+// InvalidatePolicy invalidates the cache of the policy of the given engine and the identity map of ctx, if any, after
+// the write query q was sent without Exec.
+func InvalidatePolicy(ctx context.Context, e interface{}, q Query) {
+	if m := identityMapFromContext(ctx); m != nil {
+		m.invalidate(q)
+	}
+	provider, ok := e.(PolicyProvider)
+	if !ok {
+		return
+	}
+	if p := provider.QueryPolicy(); p != nil && p.Cache != nil {
+		p.Cache.Invalidate(ctx, q)
+	}
+}
+
+// cursorSigner returns the cursor signer of the policy of the given engine, if any
+func cursorSigner(e interface{}) *cursor.Signer {
+	provider, ok := e.(PolicyProvider)
+	if !ok {
+		return nil
+	}
+	if p := provider.QueryPolicy(); p != nil {
+		return p.CursorSigner
+	}
+	return nil
+}
+
+// execPolicy executes the query with next, through the identity map of ctx and the cache of the policy of the given
+// engine, if any.
+func execPolicy(ctx context.Context, e interface{}, q Query, into interface{}, next func(ctx context.Context, into interface{}) error) error {
+	var p *Policy
+	if provider, ok := e.(PolicyProvider); ok {
+		p = provider.QueryPolicy()
+	}
+
+	exec := next
+	if p != nil && p.Cache != nil {
+		exec = func(ctx context.Context, into interface{}) error {
+			return p.Cache.Exec(ctx, q, into, next)
+		}
+	}
+	if m := identityMapFromContext(ctx); m != nil {
+		return m.exec(ctx, q, p != nil && p.transaction, into, exec)
+	}
+	return exec(ctx, into)
+}
+
+// Apply applies the defaults and runs all hooks for the given query.
+func (p *Policy) Apply(ctx context.Context, q *Query) error {
+	if p == nil {
+		return nil
+	}
+
+	if p.DefaultTake > 0 && q.Method == "findMany" && !q.HasInput("take") {
+		// copy inputs so that the original query, which may be executed again, is not modified
+		inputs := make([]Input, len(q.Inputs), len(q.Inputs)+1)
+		copy(inputs, q.Inputs)
+		q.Inputs = append(inputs, Input{
+			Name:  "take",
+			Value: p.DefaultTake,
+		})
+	}
+
+	for _, hook := range p.Hooks {
+		if err := hook(ctx, q); err != nil {
+			return fmt.Errorf("query policy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Observe calls all observers for the given query.
+func (p *Policy) Observe(ctx context.Context, q Query, duration time.Duration, err error) {
+	if p == nil {
+		return
+	}
+	for _, observer := range p.Observers {
+		observer(ctx, q, duration, err)
+	}
+}
+
+// Transaction returns the policy for the queries of an interactive transaction. Its cache, if any, and identity maps
+// are bypassed, and the invalidations of the writes of the transaction are deferred until commit is called after it
+// was committed.
+func (p *Policy) Transaction() (*Policy, func(ctx context.Context)) {
+	var tx Policy
+	if p != nil {
+		tx = *p
+	}
+	tx.transaction = true
+	if tx.Cache == nil {
+		return &tx, func(context.Context) {}
+	}
+	var commit func(ctx context.Context)
+	tx.Cache, commit = tx.Cache.Transaction()
+	return &tx, commit
+}
+
+// HasInput returns whether the query has an argument with the given name, e.g. "take" or "where".
+func (q Query) HasInput(name string) bool {
+	for _, i := range q.Inputs {
+		if i.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type policyEngine struct {
+	policy *Policy
+	query  string
+}
+
+func (e *policyEngine) Connect() error    { return nil }
+func (e *policyEngine) Disconnect() error { return nil }
+func (e *policyEngine) Name() string      { return "policy" }
+func (e *policyEngine) Batch(ctx context.Context, payload interface{}, v interface{}) error {
+	return nil
+}
+func (e *policyEngine) Do(ctx context.Context, payload interface{}, v interface{}) error {
+	e.query = payload.(protocol.GQLRequest).Query
+	return nil
+}
+func (e *policyEngine) QueryPolicy() *Policy {
+	return e.policy
+}
+
+func findMany(inputs ...Input) Query {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Inputs = inputs
+	q.Outputs = []Output{{Name: "id"}}
+	return q
+}
+
+func TestPolicy_defaultTake(t *testing.T) {
+	e := &policyEngine{policy: &Policy{DefaultTake: 100}}
+
+	q := findMany()
+	q.Engine = e
+	if err := q.Exec(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `query {result: findManyUser(take:100,) {id }}`, e.query)
+
+	// the original query must not be modified
+	massert.Equal(t, false, q.HasInput("take"))
+}
+
+func TestPolicy_defaultTakeKeepsExplicitTake(t *testing.T) {
+	e := &policyEngine{policy: &Policy{DefaultTake: 100}}
+
+	q := findMany(Input{Name: "take", Value: 5})
+	q.Engine = e
+	if err := q.Exec(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `query {result: findManyUser(take:5,) {id }}`, e.query)
+}
+
+func TestPolicy_defaultTakeOnlyFindMany(t *testing.T) {
+	q := findMany()
+	q.Method = "findFirst"
+	if err := (&Policy{DefaultTake: 100}).Apply(context.Background(), &q); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, false, q.HasInput("take"))
+}
+
+func TestPolicy_hooks(t *testing.T) {
+	var calls []string
+	e := &policyEngine{policy: &Policy{
+		Hooks: []Hook{
+			func(ctx context.Context, q *Query) error {
+				calls = append(calls, "first")
+				q.Inputs = append(q.Inputs, Input{Name: "skip", Value: 1})
+				return nil
+			},
+			func(ctx context.Context, q *Query) error {
+				calls = append(calls, "second")
+				return nil
+			},
+		},
+	}}
+
+	q := findMany()
+	q.Engine = e
+	if err := q.Exec(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"first", "second"}, calls)
+	massert.Equal(t, `query {result: findManyUser(skip:1,) {id }}`, e.query)
+}
+
+func TestPolicy_veto(t *testing.T) {
+	errUnbounded := errors.New("unbounded query")
+	e := &policyEngine{policy: &Policy{
+		Hooks: []Hook{
+			func(ctx context.Context, q *Query) error {
+				if q.Method == "findMany" && !q.HasInput("where") {
+					return errUnbounded
+				}
+				return nil
+			},
+		},
+	}}
+
+	q := findMany()
+	q.Engine = e
+	err := q.Exec(context.Background(), nil)
+	if !errors.Is(err, errUnbounded) {
+		t.Fatalf("expected veto error, got %v", err)
+	}
+	massert.Equal(t, "", e.query)
+}
+
+func TestPolicy_nil(t *testing.T) {
+	e := &policyEngine{}
+
+	q := findMany()
+	q.Engine = e
+	if err := q.Exec(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `query {result: findManyUser {id }}`, e.query)
+}
+
+func TestPolicy_observers(t *testing.T) {
+	var observed []string
+	e := &policyEngine{policy: &Policy{
+		Observers: []Observer{
+			func(ctx context.Context, q Query, duration time.Duration, err error) {
+				observed = append(observed, q.Model+"."+q.Method)
+			},
+		},
+	}}
+
+	q := findMany()
+	q.Engine = e
+	if err := q.Exec(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"User.findMany"}, observed)
+}
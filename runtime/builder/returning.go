@@ -0,0 +1,169 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+// countResult is the result of updateMany and deleteMany queries
+type countResult struct {
+	Count int `json:"count"`
+}
+
+// returningChunkSize is the maximum number of ids which are sent in a single `in` filter, as databases limit the
+// number of parameters of a statement; it is replaced in tests
+var returningChunkSize = 1000
+
+// ExecReturning executes an updateMany or deleteMany query and fetches the records it wrote into into, instead of
+// their count. The engine has no such operation, so the ids of the matching records are read first and the write is
+// restricted to them and to the filters of q, in chunks of at most returningChunkSize ids; updated records are read
+// afterwards, deleted records before. e must send the queries within an interactive transaction, which should be
+// serializable, so that other transactions can't change the matching records in between. If they do anyway, e.g. at a
+// weaker isolation level, fewer records than were read are written, and ExecReturning returns types.ErrStaleRecord so
+// that the transaction is rolled back. id is the name of the id field of the model, and outputs are the fields of the
+// returned records.
+func (q Query) ExecReturning(ctx context.Context, e engine.Engine, id string, outputs []Output, into interface{}) error {
+	q.Engine = e
+
+	find := q
+	find.Operation = "query"
+	find.Method = "findMany"
+	find.Inputs = nil
+	for _, input := range q.Inputs {
+		if input.Name == "where" {
+			find.Inputs = append(find.Inputs, input)
+		}
+	}
+	find.Outputs = []Output{{Name: id}}
+
+	// reads are limited explicitly, as the default take of a policy would leave out matching records
+	var count CountResult
+	if err := Count(find).Exec(ctx, &count); err != nil {
+		return err
+	}
+	if count.Count.All == 0 {
+		return json.Unmarshal([]byte("[]"), into)
+	}
+	var rows []map[string]json.RawMessage
+	if err := find.withTake(count.Count.All).Exec(ctx, &rows); err != nil {
+		return err
+	}
+
+	records := make([]json.RawMessage, 0, len(rows))
+	for start := 0; start < len(rows); start += returningChunkSize {
+		chunk := rows[start:min(start+returningChunkSize, len(rows))]
+		ids := make([]json.RawMessage, len(chunk))
+		for i, row := range chunk {
+			ids[i] = row[id]
+		}
+		written, err := q.execReturningChunk(ctx, find, ids, id, outputs)
+		if err != nil {
+			return err
+		}
+		records = append(records, written...)
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+// execReturningChunk writes the records with the given ids which still match the filters of q, and returns them
+func (q Query) execReturningChunk(ctx context.Context, find Query, ids []json.RawMessage, id string, outputs []Output) ([]json.RawMessage, error) {
+	matched := Field{
+		Name:   id,
+		Fields: []Field{{Name: "in", Value: ids}},
+	}
+
+	write := q
+	write.Scope = append(q.Scope[:len(q.Scope):len(q.Scope)], matched)
+	read := find.withTake(len(ids))
+	read.Outputs = outputs
+
+	var records []json.RawMessage
+	var result countResult
+	if strings.HasPrefix(q.Method, "delete") {
+		read.Scope = write.Scope
+		if err := read.Exec(ctx, &records); err != nil {
+			return nil, err
+		}
+		if err := write.Exec(ctx, &result); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := write.Exec(ctx, &result); err != nil {
+			return nil, err
+		}
+		// the updated records may not match the filters of the query anymore, e.g. when their status was changed
+		read.Inputs = []Input{{Name: "take", Value: len(ids)}}
+		read.Scope = []Field{matched}
+		if err := read.Exec(ctx, &records); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Count != len(ids) || len(records) != len(ids) {
+		return nil, fmt.Errorf("%w: %d of %d matching records were written, as the others were changed concurrently", types.ErrStaleRecord, result.Count, len(ids))
+	}
+	return records, nil
+}
+
+// withTake returns a copy of q which reads at most n records
+func (q Query) withTake(n int) Query {
+	inputs := make([]Input, len(q.Inputs), len(q.Inputs)+1)
+	copy(inputs, q.Inputs)
+	q.Inputs = append(inputs, Input{Name: "take", Value: n})
+	return q
+}
+
+// ExecWithoutResult executes an updateOne query as an updateMany query with the same filters, so that the engine
+// doesn't fetch the updated record afterwards. It returns types.ErrNotFound if no record matched. Updates which can't
+// be expressed as an updateMany query, i.e. by a compound unique key or of relations, are executed as usual.
+func (q Query) ExecWithoutResult(ctx context.Context, model *metadata.Model) error {
+	if !updatableMany(q, model) {
+		var v json.RawMessage
+		return q.Exec(ctx, &v)
+	}
+
+	many := q
+	many.Method = "updateMany"
+	many.Outputs = []Output{{Name: "count"}}
+
+	var result countResult
+	if err := many.Exec(ctx, &result); err != nil {
+		return err
+	}
+	if result.Count == 0 {
+		return types.ErrNotFound
+	}
+	return nil
+}
+
+// updatableMany returns whether the where and data arguments of an updateOne query only refer to scalar fields of
+// model, which updateMany queries accept as well
+func updatableMany(q Query, model *metadata.Model) bool {
+	for _, input := range q.Inputs {
+		if input.Name != "where" && input.Name != "data" {
+			continue
+		}
+		for _, f := range input.Fields {
+			// skip empty params, e.g. of SetIfPresent(nil)
+			if f.Name == "" && f.Value == nil && f.Fields == nil {
+				continue
+			}
+			field, ok := model.Field(f.Name)
+			if !ok || field.Kind == metadata.FieldKindRelation {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/types"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func writeManyUsers(method string, data ...Field) Query {
+	q := NewQuery()
+	q.Operation = "mutation"
+	q.Method = method
+	q.Model = "User"
+	q.Inputs = []Input{{
+		Name:   "where",
+		Fields: []Field{{Name: "name", Fields: []Field{{Name: "equals", Value: "a"}}}},
+	}}
+	if data != nil {
+		q.Inputs = append(q.Inputs, Input{
+			Name:   "data",
+			Fields: data,
+		})
+	}
+	q.Outputs = []Output{{Name: "count"}}
+	return q
+}
+
+type returnedUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestQuery_ExecReturning(t *testing.T) {
+	outputs := []Output{{Name: "id"}, {Name: "name"}}
+
+	t.Run("update", func(t *testing.T) {
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"_count":{"_all":2}}`),
+			json.RawMessage(`[{"id":"1"},{"id":"2"}]`),
+			json.RawMessage(`{"count":2}`),
+			json.RawMessage(`[{"id":"1","name":"b"},{"id":"2","name":"b"}]`),
+		}}
+		var users []returnedUser
+		if err := writeManyUsers("updateMany", set("name", "b")).ExecReturning(context.Background(), e, "id", outputs, &users); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, []returnedUser{{ID: "1", Name: "b"}, {ID: "2", Name: "b"}}, users)
+		massert.Equal(t, []string{
+			`query {result: aggregateUser(where:{name:{equals:"a",},},) {_count {_all }}}`,
+			`query {result: findManyUser(take:2,where:{name:{equals:"a",},},) {id }}`,
+			`mutation {result: updateManyUser(data:{name:{set:"b",},},where:{id:{in:["1","2"],},name:{equals:"a",},},) {count }}`,
+			`query {result: findManyUser(take:2,where:{id:{in:["1","2"],},},) {id name }}`,
+		}, e.queries)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"_count":{"_all":1}}`),
+			json.RawMessage(`[{"id":"1"}]`),
+			json.RawMessage(`[{"id":"1","name":"a"}]`),
+			json.RawMessage(`{"count":1}`),
+		}}
+		var users []returnedUser
+		if err := writeManyUsers("deleteMany").ExecReturning(context.Background(), e, "id", outputs, &users); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, []returnedUser{{ID: "1", Name: "a"}}, users)
+		massert.Equal(t, []string{
+			`query {result: aggregateUser(where:{name:{equals:"a",},},) {_count {_all }}}`,
+			`query {result: findManyUser(take:1,where:{name:{equals:"a",},},) {id }}`,
+			`query {result: findManyUser(take:1,where:{id:{in:["1"],},name:{equals:"a",},},) {id name }}`,
+			`mutation {result: deleteManyUser(where:{id:{in:["1"],},name:{equals:"a",},},) {count }}`,
+		}, e.queries)
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		original := returningChunkSize
+		defer func() {
+			returningChunkSize = original
+		}()
+		returningChunkSize = 2
+
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"_count":{"_all":3}}`),
+			json.RawMessage(`[{"id":"1"},{"id":"2"},{"id":"3"}]`),
+			json.RawMessage(`{"count":2}`),
+			json.RawMessage(`[{"id":"1","name":"b"},{"id":"2","name":"b"}]`),
+			json.RawMessage(`{"count":1}`),
+			json.RawMessage(`[{"id":"3","name":"b"}]`),
+		}}
+		var users []returnedUser
+		if err := writeManyUsers("updateMany", set("name", "b")).ExecReturning(context.Background(), e, "id", outputs, &users); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, []returnedUser{{ID: "1", Name: "b"}, {ID: "2", Name: "b"}, {ID: "3", Name: "b"}}, users)
+		massert.Equal(t, []string{
+			`query {result: aggregateUser(where:{name:{equals:"a",},},) {_count {_all }}}`,
+			`query {result: findManyUser(take:3,where:{name:{equals:"a",},},) {id }}`,
+			`mutation {result: updateManyUser(data:{name:{set:"b",},},where:{id:{in:["1","2"],},name:{equals:"a",},},) {count }}`,
+			`query {result: findManyUser(take:2,where:{id:{in:["1","2"],},},) {id name }}`,
+			`mutation {result: updateManyUser(data:{name:{set:"b",},},where:{id:{in:["3"],},name:{equals:"a",},},) {count }}`,
+			`query {result: findManyUser(take:1,where:{id:{in:["3"],},},) {id name }}`,
+		}, e.queries)
+	})
+
+	t.Run("changed concurrently", func(t *testing.T) {
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"_count":{"_all":2}}`),
+			json.RawMessage(`[{"id":"1"},{"id":"2"}]`),
+			json.RawMessage(`{"count":1}`),
+			json.RawMessage(`[{"id":"1","name":"b"},{"id":"2","name":"c"}]`),
+		}}
+		var users []returnedUser
+		err := writeManyUsers("updateMany", set("name", "b")).ExecReturning(context.Background(), e, "id", outputs, &users)
+		massert.Equal(t, true, errors.Is(err, types.ErrStaleRecord))
+	})
+
+	t.Run("no records", func(t *testing.T) {
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"_count":{"_all":0}}`),
+		}}
+		var users []returnedUser
+		if err := writeManyUsers("deleteMany").ExecReturning(context.Background(), e, "id", outputs, &users); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, []returnedUser{}, users)
+		massert.Equal(t, 1, len(e.queries))
+	})
+}
+
+func TestQuery_ExecWithoutResult(t *testing.T) {
+	t.Run("update many", func(t *testing.T) {
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"count":1}`),
+		}}
+		q := updateUser(set("name", "b"))
+		q.Engine = e
+		if err := q.ExecWithoutResult(context.Background(), userModel); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, []string{
+			`mutation {result: updateManyUser(data:{name:{set:"b",},},where:{id:"1",},) {count }}`,
+		}, e.queries)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"count":0}`),
+		}}
+		q := updateUser(set("name", "b"))
+		q.Engine = e
+		err := q.ExecWithoutResult(context.Background(), userModel)
+		massert.Equal(t, true, errors.Is(err, types.ErrNotFound))
+	})
+
+	t.Run("compound unique", func(t *testing.T) {
+		e := &scriptedEngine{responses: []json.RawMessage{
+			json.RawMessage(`{"id":"1","name":"b"}`),
+		}}
+		q := updateUser(set("name", "b"))
+		q.Inputs[0].Fields = []Field{{Name: "id_name", Fields: []Field{{Name: "id", Value: "1"}, {Name: "name", Value: "a"}}}}
+		q.Engine = e
+		if err := q.ExecWithoutResult(context.Background(), userModel); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, []string{
+			`mutation {result: updateOneUser(data:{name:{set:"b",},},where:{id_name:{id:"1",name:"a",},},) {id name }}`,
+		}, e.queries)
+	})
+}
@@ -0,0 +1,80 @@
+package builder
+
+// ScopedInputs returns the arguments of the query with the filters of Scope added to the where argument. They are
+// added next to the other filters, which unique queries require, unless a filter already refers to the same field, in
+// which case both are combined with AND.
+func (q Query) ScopedInputs() []Input {
+	if len(q.Scope) == 0 {
+		return q.Inputs
+	}
+
+	inputs := make([]Input, 0, len(q.Inputs)+1)
+	scoped := false
+	for _, input := range q.Inputs {
+		if input.Name == "where" && !scoped {
+			input.Fields = scopeFields(input.Fields, q.Scope)
+			scoped = true
+		}
+		inputs = append(inputs, input)
+	}
+	if !scoped {
+		inputs = append(inputs, Input{
+			Name:   "where",
+			Fields: q.Scope,
+		})
+	}
+	return inputs
+}
+
+// scopeFields adds the filters of a scope to the filters of a where argument
+func scopeFields(where []Field, scope []Field) []Field {
+	names := make(map[string]bool, len(where))
+	for _, f := range where {
+		names[f.Name] = true
+	}
+	for _, f := range scope {
+		if names[f.Name] {
+			// the scope and the filters are separate items, as fields with the same name are joined within an object
+			return []Field{{
+				Name: "AND",
+				List: true,
+				Fields: []Field{
+					{Fields: scope},
+					{Fields: where},
+				},
+			}}
+		}
+	}
+	return append(append([]Field{}, where...), scope...)
+}
+
+// ScopeRelation returns the filters of a relation filter with the filters of a scope added, e.g. to exclude
+// soft-deleted records from `some`, `every`, `none` and `is`. Deleted records don't count as related records, so
+// `every` only requires the records in the scope to match. The scope is not added if the filters already refer to
+// one of its fields, so that records outside the scope can still be filtered explicitly.
+func ScopeRelation(action string, fields []Field, scope []Field) []Field {
+	names := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		names[f.Name] = true
+	}
+	for _, f := range scope {
+		if names[f.Name] {
+			return fields
+		}
+	}
+
+	if action != "every" {
+		return append(append([]Field{}, fields...), scope...)
+	}
+	if len(fields) == 0 {
+		return fields
+	}
+	return []Field{{
+		Name: "OR",
+		List: true,
+		Fields: []Field{
+			{Fields: []Field{{Name: "NOT", Fields: scope}}},
+			{Fields: fields},
+		},
+	}}
+}
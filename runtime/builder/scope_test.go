@@ -0,0 +1,116 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQuery_ScopedInputs(t *testing.T) {
+	var null *string
+	scope := []Field{{
+		Name:   "deletedAt",
+		Fields: []Field{{Name: "equals", Value: null}},
+	}}
+
+	tests := []struct {
+		name     string
+		inputs   []Input
+		expected string
+	}{{
+		name:     "without where",
+		expected: `(where:{deletedAt:{equals:null,},},)`,
+	}, {
+		name: "with other filters",
+		inputs: []Input{{
+			Name:   "where",
+			Fields: []Field{{Name: "id", Value: "a"}},
+		}, {
+			Name:  "take",
+			Value: 1,
+		}},
+		expected: `(take:1,where:{deletedAt:{equals:null,},id:"a",},)`,
+	}, {
+		name: "with a filter on the same field",
+		inputs: []Input{{
+			Name: "where",
+			Fields: []Field{{
+				Name:   "deletedAt",
+				Fields: []Field{{Name: "lt", Value: "2024-01-01T00:00:00Z"}},
+			}},
+		}},
+		expected: `(where:{AND:[{deletedAt:{equals:null,},},{deletedAt:{lt:"2024-01-01T00:00:00Z",},},],},)`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery()
+			q.Inputs = tt.inputs
+			q.Scope = scope
+
+			actual, err := q.BuildArgs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.expected, actual)
+
+			// the inputs of the query itself are left untouched
+			massert.Equal(t, tt.inputs, q.Inputs)
+		})
+	}
+}
+
+func TestScopeRelation(t *testing.T) {
+	var null *string
+	scope := []Field{{
+		Name:   "deletedAt",
+		Fields: []Field{{Name: "equals", Value: null}},
+	}}
+	title := Field{Name: "title", Value: "a"}
+
+	tests := []struct {
+		name     string
+		action   string
+		fields   []Field
+		expected string
+	}{{
+		name:     "some",
+		action:   "some",
+		fields:   []Field{title},
+		expected: `(where:{posts:{some:{deletedAt:{equals:null,},title:"a",},},},)`,
+	}, {
+		name:     "is without filters",
+		action:   "is",
+		expected: `(where:{posts:{is:{deletedAt:{equals:null,},},},},)`,
+	}, {
+		name:     "every",
+		action:   "every",
+		fields:   []Field{title},
+		expected: `(where:{posts:{every:{OR:[{NOT:{deletedAt:{equals:null,},},},{title:"a",},],},},},)`,
+	}, {
+		name:   "filter on the same field",
+		action: "some",
+		fields: []Field{{
+			Name:   "deletedAt",
+			Fields: []Field{{Name: "not", Value: null}},
+		}},
+		expected: `(where:{posts:{some:{deletedAt:{not:null,},},},},)`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery()
+			q.Inputs = []Input{{
+				Name: "where",
+				Fields: []Field{{
+					Name:   "posts",
+					Fields: []Field{{Name: tt.action, Fields: ScopeRelation(tt.action, tt.fields, scope)}},
+				}},
+			}}
+
+			actual, err := q.BuildArgs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// serializedVersion is the version of the serialization format, which is bumped on incompatible changes
+const serializedVersion = 1
+
+// ErrSchemaMismatch is returned by Unmarshal if a query was serialized by a client generated from a different schema
+var ErrSchemaMismatch = errors.New("query was serialized with a different prisma schema")
+
+// SchemaHash returns a hash identifying a prisma schema, which is used to validate serialized queries
+func SchemaHash(schema string) string {
+	sum := sha256.Sum256([]byte(schema))
+	return hex.EncodeToString(sum[:])
+}
+
+type serializedQuery struct {
+	Version   int                `json:"version"`
+	Schema    string             `json:"schema"`
+	Operation string             `json:"operation"`
+	Name      string             `json:"name,omitempty"`
+	Method    string             `json:"method"`
+	Model     string             `json:"model"`
+	Inputs    []serializedInput  `json:"inputs,omitempty"`
+	Outputs   []serializedOutput `json:"outputs,omitempty"`
+}
+
+type serializedInput struct {
+	Name string `json:"name"`
+	// Fields distinguishes between nil and empty, as it affects the built query
+	Fields   []serializedField `json:"fields"`
+	Value    json.RawMessage   `json:"value,omitempty"`
+	List     bool              `json:"list,omitempty"`
+	WrapList bool              `json:"wrapList,omitempty"`
+}
+
+type serializedOutput struct {
+	Name    string             `json:"name"`
+	Inputs  []serializedInput  `json:"inputs,omitempty"`
+	Outputs []serializedOutput `json:"outputs,omitempty"`
+}
+
+type serializedField struct {
+	Name     string            `json:"name,omitempty"`
+	List     bool              `json:"list,omitempty"`
+	WrapList bool              `json:"wrapList,omitempty"`
+	Value    json.RawMessage   `json:"value,omitempty"`
+	Fields   []serializedField `json:"fields"`
+}
+
+// ExtractQuery returns the query itself, so that a query returned by Unmarshal can be serialized again like the
+// queries of a generated client
+func (q Query) ExtractQuery() Query {
+	return q
+}
+
+// Marshal serializes a query which was built but not executed yet, e.g. to queue it or to store a saved filter.
+// Values are stored in their JSON representation, so the query sent to the engine after Unmarshal is identical.
+// The schema hash is stored along with the query, so that it can't be executed against a client generated from a
+// different schema. The filters of the scope are stored as part of the where argument. Like Build, it returns the
+// error of a query which could not be constructed.
+func (q Query) Marshal(schemaHash string) ([]byte, error) {
+	if q.Err != nil {
+		return nil, q.Err
+	}
+	inputs, err := serializeInputs(q.ScopedInputs())
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := serializeOutputs(q.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(serializedQuery{
+		Version:   serializedVersion,
+		Schema:    schemaHash,
+		Operation: q.Operation,
+		Name:      q.Name,
+		Method:    q.Method,
+		Model:     q.Model,
+		Inputs:    inputs,
+		Outputs:   outputs,
+	})
+}
+
+// Unmarshal reconstructs a query serialized with Marshal. It returns ErrSchemaMismatch if the query was serialized
+// with a different schema. The engine of the returned query must be set before executing it.
+func Unmarshal(data []byte, schemaHash string) (Query, error) {
+	var s serializedQuery
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Query{}, fmt.Errorf("unmarshal query: %w", err)
+	}
+
+	if s.Version != serializedVersion {
+		return Query{}, fmt.Errorf("unsupported serialized query version %d", s.Version)
+	}
+
+	if s.Schema != schemaHash {
+		return Query{}, ErrSchemaMismatch
+	}
+
+	switch s.Operation {
+	case "query", "mutation":
+	default:
+		return Query{}, fmt.Errorf("unsupported operation %q", s.Operation)
+	}
+
+	q := NewQuery()
+	q.Operation = s.Operation
+	q.Name = s.Name
+	q.Method = s.Method
+	q.Model = s.Model
+	q.Inputs = deserializeInputs(s.Inputs)
+	q.Outputs = deserializeOutputs(s.Outputs)
+	return q, nil
+}
+
+// serializeValue returns the JSON representation of a value; values which are not nil but encode to null, e.g. nil
+// pointers, are kept as null
+func serializeValue(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("marshal value: %w", err)
+	}
+	return raw, nil
+}
+
+func deserializeValue(value json.RawMessage) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return value
+}
+
+func serializeInputs(inputs []Input) ([]serializedInput, error) {
+	if inputs == nil {
+		return nil, nil
+	}
+	result := make([]serializedInput, len(inputs))
+	for i, input := range inputs {
+		value, err := serializeValue(input.Value)
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %w", input.Name, err)
+		}
+		fields, err := serializeFields(input.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %w", input.Name, err)
+		}
+		result[i] = serializedInput{
+			Name:     input.Name,
+			Fields:   fields,
+			Value:    value,
+			List:     input.List,
+			WrapList: input.WrapList,
+		}
+	}
+	return result, nil
+}
+
+func deserializeInputs(inputs []serializedInput) []Input {
+	if inputs == nil {
+		return nil
+	}
+	result := make([]Input, len(inputs))
+	for i, input := range inputs {
+		result[i] = Input{
+			Name:     input.Name,
+			Fields:   deserializeFields(input.Fields),
+			Value:    deserializeValue(input.Value),
+			List:     input.List,
+			WrapList: input.WrapList,
+		}
+	}
+	return result
+}
+
+func serializeOutputs(outputs []Output) ([]serializedOutput, error) {
+	if outputs == nil {
+		return nil, nil
+	}
+	result := make([]serializedOutput, len(outputs))
+	for i, output := range outputs {
+		inputs, err := serializeInputs(output.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", output.Name, err)
+		}
+		nested, err := serializeOutputs(output.Outputs)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = serializedOutput{
+			Name:    output.Name,
+			Inputs:  inputs,
+			Outputs: nested,
+		}
+	}
+	return result, nil
+}
+
+func deserializeOutputs(outputs []serializedOutput) []Output {
+	if outputs == nil {
+		return nil
+	}
+	result := make([]Output, len(outputs))
+	for i, output := range outputs {
+		result[i] = Output{
+			Name:    output.Name,
+			Inputs:  deserializeInputs(output.Inputs),
+			Outputs: deserializeOutputs(output.Outputs),
+		}
+	}
+	return result
+}
+
+func serializeFields(fields []Field) ([]serializedField, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	result := make([]serializedField, len(fields))
+	for i, field := range fields {
+		value, err := serializeValue(field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		nested, err := serializeFields(field.Fields)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = serializedField{
+			Name:     field.Name,
+			List:     field.List,
+			WrapList: field.WrapList,
+			Value:    value,
+			Fields:   nested,
+		}
+	}
+	return result, nil
+}
+
+func deserializeFields(fields []serializedField) []Field {
+	if fields == nil {
+		return nil
+	}
+	result := make([]Field, len(fields))
+	for i, field := range fields {
+		result[i] = Field{
+			Name:     field.Name,
+			List:     field.List,
+			WrapList: field.WrapList,
+			Value:    deserializeValue(field.Value),
+			Fields:   deserializeFields(field.Fields),
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestQuery_Marshal(t *testing.T) {
+	var nilString *string
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Inputs = []Input{{
+		Name: "where",
+		Fields: []Field{{
+			Name:     "OR",
+			List:     true,
+			WrapList: true,
+			Fields: []Field{{
+				Name:   "name",
+				Fields: []Field{{Name: "contains", Value: `<"a">`}},
+			}, {
+				Name:   "bio",
+				Fields: []Field{{Name: "equals", Value: nilString}},
+			}},
+		}, {
+			Name:   "createdAt",
+			Fields: []Field{{Name: "gt", Value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		}},
+	}, {
+		Name:  "take",
+		Value: 10,
+	}}
+	q.Outputs = []Output{{Name: "id"}, {
+		Name:    "posts",
+		Inputs:  []Input{{Name: "take", Value: 1}},
+		Outputs: []Output{{Name: "title"}},
+	}}
+
+	expected, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := SchemaHash("model User {}")
+	data, err := q.Marshal(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := Unmarshal(data, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "findMany", actual.Method)
+	massert.Equal(t, "User", actual.Model)
+
+	built, err := actual.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, expected, built)
+
+	// unmarshalled queries can be serialized again
+	again, err := actual.Marshal(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, string(data), string(again))
+}
+
+func TestQuery_Marshal_err(t *testing.T) {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Err = errors.New("could not encode input")
+
+	data, err := q.Marshal(SchemaHash("a"))
+	massert.Equal(t, "could not encode input", err.Error())
+	massert.Equal(t, 0, len(data))
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	q := NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	data, err := q.Marshal(SchemaHash("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Unmarshal(data, SchemaHash("b"))
+	massert.Equal(t, true, errors.Is(err, ErrSchemaMismatch))
+
+	_, err = Unmarshal([]byte(`{"version":2}`), SchemaHash("a"))
+	massert.Equal(t, "unsupported serialized query version 2", err.Error())
+
+	_, err = Unmarshal([]byte(`{"version":1,"schema":"`+SchemaHash("a")+`","operation":"subscription"}`), SchemaHash("a"))
+	massert.Equal(t, `unsupported operation "subscription"`, err.Error())
+}
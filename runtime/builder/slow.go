@@ -0,0 +1,114 @@
+package builder
+
+import (
+	"context"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// redacted replaces the values of redacted arguments
+const redacted = "?"
+
+// SlowQuery is a query which took longer than the threshold of a slow query log
+type SlowQuery struct {
+	// Model is the name of the Prisma model, e.g. "User"; it is empty for raw queries
+	Model string
+
+	// Action is the operation, e.g. "findMany" or "queryRaw"
+	Action string
+
+	// Duration is how long the engine took to respond
+	Duration time.Duration
+
+	// Err is the error returned by the engine, if any
+	Err error
+
+	query Query
+}
+
+// Args returns the serialized arguments as sent to the engine, e.g. `(where:{email:"alice@example.com",},)`. They
+// may contain personal data or secrets, so prefer RedactedArgs for logs.
+func (s SlowQuery) Args() (string, error) {
+	return s.query.BuildArgs()
+}
+
+// RedactedArgs returns the serialized arguments with all values replaced by "?", e.g. `(where:{email:"?",},)`, so
+// that the shape of a query can be logged without its data. The SQL of raw queries is kept.
+func (s SlowQuery) RedactedArgs() (string, error) {
+	q := s.query
+	q.Inputs = redactInputs(q.Method, q.Inputs)
+	q.Scope = redactFields(q.Scope)
+	return q.BuildArgs()
+}
+
+// String returns the query with redacted arguments as a single line, e.g. `User.findMany(take:"?",) took 312ms`
+func (s SlowQuery) String() string {
+	args, err := s.RedactedArgs()
+	if err != nil {
+		args = "(" + err.Error() + ")"
+	}
+	return s.Model + "." + s.Action + args + " took " + s.Duration.String()
+}
+
+// SlowQueryLog returns an observer which calls handler with each query which took at least threshold. If handler is
+// nil, the queries are logged with redacted arguments.
+func SlowQueryLog(threshold time.Duration, handler func(ctx context.Context, q SlowQuery)) Observer {
+	if handler == nil {
+		handler = func(_ context.Context, q SlowQuery) {
+			logger.Info.Printf("slow query: %s", q)
+		}
+	}
+	return func(ctx context.Context, q Query, duration time.Duration, err error) {
+		if duration < threshold {
+			return
+		}
+		handler(ctx, SlowQuery{
+			Model:    q.Model,
+			Action:   q.Method,
+			Duration: duration,
+			Err:      err,
+			query:    q,
+		})
+	}
+}
+
+// redactInputs returns a copy of inputs with all values replaced
+func redactInputs(method string, inputs []Input) []Input {
+	if inputs == nil {
+		return nil
+	}
+	out := make([]Input, len(inputs))
+	for i, input := range inputs {
+		out[i] = input
+		if isRaw(method) && input.Name == "query" {
+			continue
+		}
+		if input.Value != nil {
+			out[i].Value = redacted
+		}
+		out[i].Fields = redactFields(input.Fields)
+	}
+	return out
+}
+
+// redactFields returns a copy of fields with all values replaced
+func redactFields(fields []Field) []Field {
+	if fields == nil {
+		return nil
+	}
+	out := make([]Field, len(fields))
+	for i, field := range fields {
+		out[i] = field
+		if field.Value != nil {
+			out[i].Value = redacted
+		}
+		out[i].Fields = redactFields(field.Fields)
+	}
+	return out
+}
+
+// isRaw returns whether method is a raw query, whose query input is SQL rather than data
+func isRaw(method string) bool {
+	return method == "queryRaw" || method == "executeRaw"
+}
@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	var slow []SlowQuery
+	observe := SlowQueryLog(100*time.Millisecond, func(_ context.Context, q SlowQuery) {
+		slow = append(slow, q)
+	})
+
+	q := findMany(Input{
+		Name: "where",
+		Fields: []Field{{
+			Name:   "email",
+			Fields: []Field{{Name: "equals", Value: "alice@example.com"}},
+		}},
+	})
+	q.Scope = []Field{{
+		Name:   "tenantID",
+		Fields: []Field{{Name: "equals", Value: "t1"}},
+	}}
+
+	errFailed := errors.New("failed")
+	observe(context.Background(), q, 50*time.Millisecond, nil)
+	observe(context.Background(), q, 150*time.Millisecond, errFailed)
+
+	massert.Equal(t, 1, len(slow))
+	massert.Equal(t, "User", slow[0].Model)
+	massert.Equal(t, "findMany", slow[0].Action)
+	massert.Equal(t, 150*time.Millisecond, slow[0].Duration)
+	massert.Equal(t, errFailed, slow[0].Err)
+
+	args, err := slow[0].Args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `(where:{email:{equals:"alice@example.com",},tenantID:{equals:"t1",},},)`, args)
+
+	redactedArgs, err := slow[0].RedactedArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `(where:{email:{equals:"?",},tenantID:{equals:"?",},},)`, redactedArgs)
+	massert.Equal(t, `User.findMany(where:{email:{equals:"?",},tenantID:{equals:"?",},},) took 150ms`, slow[0].String())
+}
+
+func TestSlowQuery_redactedRawArgs(t *testing.T) {
+	q := NewQuery()
+	q.Method = "queryRaw"
+	q.Inputs = []Input{{
+		Name:  "query",
+		Value: "SELECT * FROM users WHERE email = $1",
+	}, {
+		Name:  "parameters",
+		Value: `["alice@example.com"]`,
+	}}
+
+	args, err := SlowQuery{query: q}.RedactedArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `(parameters:"?",query:"SELECT * FROM users WHERE email = $1",)`, args)
+}
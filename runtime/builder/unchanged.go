@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+// OnlyIfChanged adds a condition to the where input of an updateOne query, so that the record is only matched if at
+// least one of the set values differs from the stored value. It returns false and leaves the query unchanged if the
+// update contains operations which can't be compared, e.g. atomic number operations, relation or JSON updates.
+func OnlyIfChanged(q *Query, model *metadata.Model) bool {
+	var data, where *Input
+	for i := range q.Inputs {
+		switch q.Inputs[i].Name {
+		case "data":
+			data = &q.Inputs[i]
+		case "where":
+			where = &q.Inputs[i]
+		}
+	}
+	if data == nil || where == nil || len(data.Fields) == 0 {
+		return false
+	}
+
+	var changed []Field
+	for _, f := range data.Fields {
+		// skip empty params, e.g. of SetIfPresent(nil)
+		if f.Name == "" && f.Value == nil && f.Fields == nil {
+			continue
+		}
+		field, ok := model.Field(f.Name)
+		if !ok || field.Kind == metadata.FieldKindRelation || field.Kind == metadata.FieldKindComposite || field.IsList {
+			return false
+		}
+		// the version is bumped by each update, so it is only written along with other changes
+		if field.IsVersion {
+			continue
+		}
+		if len(f.Fields) != 1 || f.Fields[0].Name != "set" {
+			return false
+		}
+		condition, ok := changedCondition(field, f.Fields[0].Value)
+		if !ok {
+			return false
+		}
+		changed = append(changed, condition)
+	}
+	if len(changed) == 0 {
+		return false
+	}
+
+	fields := make([]Field, len(where.Fields), len(where.Fields)+1)
+	copy(fields, where.Fields)
+	where.Fields = append(fields, Field{
+		Name:     "OR",
+		List:     true,
+		WrapList: true,
+		Fields:   changed,
+	})
+	return true
+}
+
+// changedCondition returns a filter matching records where the field does not equal value
+func changedCondition(field *metadata.Field, value interface{}) (Field, bool) {
+	if field.Type == "Json" {
+		return Field{}, false
+	}
+
+	if isNull(value) {
+		return Field{
+			Name:   field.Name,
+			Fields: []Field{{Name: "not", Value: types.Null[string]()}},
+		}, true
+	}
+
+	not := Field{
+		Name:   field.Name,
+		Fields: []Field{{Name: "not", Value: value}},
+	}
+	if field.IsRequired {
+		return not, true
+	}
+
+	// null never equals a value in SQL, so records where the field is null have to be matched explicitly; the
+	// conditions are unnamed list items, as both refer to the same field and must not be joined
+	return Field{
+		Name: "OR",
+		List: true,
+		Fields: []Field{{
+			Fields: []Field{not},
+		}, {
+			Fields: []Field{{
+				Name:   field.Name,
+				Fields: []Field{{Name: "equals", Value: types.Null[string]()}},
+			}},
+		}},
+	}, true
+}
+
+func isNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	return string(Value(value)) == "null"
+}
+
+// ExecOnlyIfChanged executes an updateOne query, but skips the write if none of the set values differ from the
+// stored values, so that the record, including @updatedAt fields, stays untouched. In that case, the current record
+// is fetched into into instead. If the update can't be compared, it is executed as usual.
+func (q Query) ExecOnlyIfChanged(ctx context.Context, model *metadata.Model, into interface{}) error {
+	conditional := q
+	conditional.Inputs = make([]Input, len(q.Inputs))
+	copy(conditional.Inputs, q.Inputs)
+	if !OnlyIfChanged(&conditional, model) {
+		return q.Exec(ctx, into)
+	}
+
+	err := conditional.Exec(ctx, into)
+	if !errors.Is(err, types.ErrNotFound) {
+		return err
+	}
+
+	// either nothing changed or the record does not exist
+	find := q
+	find.Operation = "query"
+	find.Method = "findUnique"
+	find.Inputs = nil
+	for _, input := range q.Inputs {
+		if input.Name == "where" {
+			find.Inputs = append(find.Inputs, input)
+		}
+	}
+
+	var current json.RawMessage
+	if err := find.Exec(ctx, &current); err != nil {
+		return err
+	}
+	if len(current) == 0 || string(current) == "null" {
+		return types.ErrNotFound
+	}
+	if err := json.Unmarshal(current, into); err != nil {
+		return fmt.Errorf("unmarshal current record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// scriptedEngine records queries and answers them with the given responses in order; a nil response returns
+// types.ErrNotFound
+type scriptedEngine struct {
+	responses []json.RawMessage
+	queries   []string
+}
+
+func (e *scriptedEngine) Connect() error    { return nil }
+func (e *scriptedEngine) Disconnect() error { return nil }
+func (e *scriptedEngine) Name() string      { return "scripted" }
+func (e *scriptedEngine) Batch(ctx context.Context, payload interface{}, v interface{}) error {
+	return nil
+}
+func (e *scriptedEngine) Do(ctx context.Context, payload interface{}, v interface{}) error {
+	e.queries = append(e.queries, payload.(protocol.GQLRequest).Query)
+	response := e.responses[0]
+	e.responses = e.responses[1:]
+	if response == nil {
+		return types.ErrNotFound
+	}
+	return json.Unmarshal(response, v)
+}
+
+var userModel = &metadata.Model{
+	Name: "User",
+	Fields: []metadata.Field{
+		{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true, IsID: true},
+		{Name: "name", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true},
+		{Name: "bio", Kind: metadata.FieldKindScalar, Type: "String"},
+		{Name: "age", Kind: metadata.FieldKindScalar, Type: "Int", IsRequired: true},
+		{Name: "meta", Kind: metadata.FieldKindScalar, Type: "Json"},
+		{Name: "version", Kind: metadata.FieldKindScalar, Type: "Int", IsRequired: true, IsVersion: true},
+	},
+}
+
+func updateUser(data ...Field) Query {
+	q := NewQuery()
+	q.Operation = "mutation"
+	q.Method = "updateOne"
+	q.Model = "User"
+	q.Inputs = []Input{{
+		Name:   "where",
+		Fields: []Field{{Name: "id", Value: "1"}},
+	}, {
+		Name:   "data",
+		Fields: data,
+	}}
+	q.Outputs = []Output{{Name: "id"}, {Name: "name"}}
+	return q
+}
+
+func set(name string, value interface{}) Field {
+	return Field{Name: name, Fields: []Field{{Name: "set", Value: value}}}
+}
+
+func TestOnlyIfChanged(t *testing.T) {
+	var nilString *string
+	tests := []struct {
+		name     string
+		data     []Field
+		expected string
+	}{{
+		name:     "required and optional",
+		data:     []Field{set("name", "a"), set("bio", "b")},
+		expected: `mutation {result: updateOneUser(data:{bio:{set:"b",},name:{set:"a",},},where:{OR:[{name:{not:"a",}},{OR:[{bio:{not:"b",},},{bio:{equals:null,},},]},],id:"1",},) {id name }}`,
+	}, {
+		name:     "null",
+		data:     []Field{set("bio", nilString)},
+		expected: `mutation {result: updateOneUser(data:{bio:{set:null,},},where:{OR:[{bio:{not:null,}},],id:"1",},) {id name }}`,
+	}, {
+		name:     "version",
+		data:     []Field{set("name", "a"), {Name: "version", Fields: []Field{{Name: "increment", Value: 1}}}},
+		expected: `mutation {result: updateOneUser(data:{name:{set:"a",},version:{increment:1,},},where:{OR:[{name:{not:"a",}},],id:"1",},) {id name }}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := updateUser(tt.data...)
+			massert.Equal(t, true, OnlyIfChanged(&q, userModel))
+
+			str, err := q.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.expected, str)
+		})
+	}
+}
+
+func TestOnlyIfChanged_incomparable(t *testing.T) {
+	tests := []struct {
+		name string
+		data []Field
+	}{{
+		name: "atomic operation",
+		data: []Field{set("name", "a"), {Name: "age", Fields: []Field{{Name: "increment", Value: 1}}}},
+	}, {
+		name: "json",
+		data: []Field{{Name: "meta", Value: types.JSON(`{}`)}},
+	}, {
+		name: "relation",
+		data: []Field{{Name: "posts", Fields: []Field{{Name: "connect"}}}},
+	}, {
+		name: "empty",
+		data: []Field{{}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := updateUser(tt.data...)
+			expected, err := q.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, false, OnlyIfChanged(&q, userModel))
+			actual, err := q.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestQuery_ExecOnlyIfChanged(t *testing.T) {
+	type user struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// changed values are written
+	e := &scriptedEngine{responses: []json.RawMessage{json.RawMessage(`{"id":"1","name":"b"}`)}}
+	q := updateUser(set("name", "b"))
+	q.Engine = e
+	var v user
+	if err := q.ExecOnlyIfChanged(context.Background(), userModel, &v); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, user{ID: "1", Name: "b"}, v)
+	massert.Equal(t, 1, len(e.queries))
+
+	// unchanged values return the current record
+	e = &scriptedEngine{responses: []json.RawMessage{nil, json.RawMessage(`{"id":"1","name":"a"}`)}}
+	q = updateUser(set("name", "a"))
+	q.Engine = e
+	v = user{}
+	if err := q.ExecOnlyIfChanged(context.Background(), userModel, &v); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, user{ID: "1", Name: "a"}, v)
+	massert.Equal(t, `query {result: findUniqueUser(where:{id:"1",},) {id name }}`, e.queries[1])
+
+	// missing records return ErrNotFound
+	e = &scriptedEngine{responses: []json.RawMessage{nil, json.RawMessage(`null`)}}
+	q = updateUser(set("name", "a"))
+	q.Engine = e
+	err := q.ExecOnlyIfChanged(context.Background(), userModel, &v)
+	massert.Equal(t, types.ErrNotFound, err)
+}
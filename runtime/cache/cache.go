@@ -0,0 +1,279 @@
+// Package cache serves read queries from a cache, so that read-heavy code paths don't need a hand-rolled cache around
+// every query. Cached queries are invalidated automatically when a query of the client writes to a model they depend
+// on, including models of relations which were fetched or filtered by and models whose records reference the written
+// ones, which referential actions may change.
+//
+// Example:
+//
+//	client := db.NewClient(db.WithCache(cache.NewLRU(10000), cache.WithTTL(time.Minute)))
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+)
+
+// DefaultTTL is how long query results are cached if no TTL is set
+const DefaultTTL = time.Minute
+
+// Store stores cached query results. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key, if it exists and has not expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key for ttl. The value is removed when one of models is invalidated.
+	Set(ctx context.Context, key string, value []byte, models []string, ttl time.Duration) error
+
+	// Invalidate removes all values which depend on model
+	Invalidate(ctx context.Context, model string) error
+}
+
+// readActions are the actions which are served from the cache
+var readActions = map[string]bool{
+	"findUnique":        true,
+	"findUniqueOrThrow": true,
+	"findFirst":         true,
+	"findFirstOrThrow":  true,
+	"findMany":          true,
+	"aggregate":         true,
+	"groupBy":           true,
+}
+
+// rawActions are neither cached nor invalidate the cache, as the models they read or write are unknown
+var rawActions = map[string]bool{
+	"queryRaw":      true,
+	"executeRaw":    true,
+	"runCommandRaw": true,
+	"findRaw":       true,
+	"aggregateRaw":  true,
+}
+
+// Cache serves read queries from a Store. It implements builder.Cache and is registered with db.WithCache.
+type Cache struct {
+	store  Store
+	schema *metadata.Schema
+	ttl    time.Duration
+	models map[string]bool
+
+	// namespace is a hash of the schema which prefixes all keys, so that clients generated from different schemas,
+	// e.g. during a rolling deployment, don't read results of a different shape from a shared store
+	namespace string
+
+	mu sync.Mutex
+	// generations counts the invalidations of each model, so that a result which was read before an invalidation
+	// is not stored after it
+	generations map[string]uint64
+}
+
+// Option configures a Cache
+type Option func(*Cache)
+
+// WithTTL sets how long query results are cached. Defaults to DefaultTTL.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// WithModels only caches queries of the given models, e.g. rarely changing lookup tables. By default, queries of all
+// models except views are cached.
+func WithModels(models ...string) Option {
+	return func(c *Cache) {
+		c.models = make(map[string]bool, len(models))
+		for _, model := range models {
+			c.models[model] = true
+		}
+	}
+}
+
+// New returns a cache which stores query results in store. The schema is used to find the models a query depends on.
+func New(store Store, schema *metadata.Schema, opts ...Option) *Cache {
+	c := &Cache{
+		store:       store,
+		schema:      schema,
+		ttl:         DefaultTTL,
+		namespace:   namespace(schema),
+		generations: make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type bypassKey struct{}
+
+// Bypass returns a context whose queries are sent to the engine instead of being served from the cache, e.g. to read
+// a record which is about to be updated. Their results are cached as usual.
+func Bypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// Exec serves q from the cache if it is a cached read, or executes it with next and invalidates the models it wrote to
+func (c *Cache) Exec(ctx context.Context, q builder.Query, into interface{}, next func(ctx context.Context, into interface{}) error) error {
+	if rawActions[q.Method] {
+		return next(ctx, into)
+	}
+	if !readActions[q.Method] {
+		err := next(ctx, into)
+		// invalidate even if the query failed, as it may have been applied before its context was cancelled
+		c.Invalidate(ctx, q)
+		return err
+	}
+	if !c.cached(q.Model) {
+		return next(ctx, into)
+	}
+
+	models := dependencies(c.schema, q)
+	key, err := Key(q)
+	if err != nil {
+		return next(ctx, into)
+	}
+	key = c.namespace + ":" + key
+
+	if bypass, _ := ctx.Value(bypassKey{}).(bool); !bypass {
+		value, ok, err := c.store.Get(ctx, key)
+		if err != nil {
+			logger.Debug.Printf("cache: get %s: %s", key, err)
+		}
+		if ok {
+			return decode(value, into)
+		}
+	}
+
+	generation := c.generation(models)
+	var result json.RawMessage
+	if err := next(ctx, &result); err != nil {
+		return err
+	}
+	if c.generation(models) == generation {
+		if err := c.store.Set(ctx, key, result, models, c.ttl); err != nil {
+			logger.Debug.Printf("cache: set %s: %s", key, err)
+		}
+	}
+	return decode(result, into)
+}
+
+// Invalidate invalidates all cached queries which depend on a model the query q wrote to
+func (c *Cache) Invalidate(ctx context.Context, q builder.Query) {
+	if readActions[q.Method] || rawActions[q.Method] {
+		return
+	}
+	c.invalidate(ctx, writeDependencies(c.schema, q))
+}
+
+// Transaction returns a cache for the queries of an interactive transaction; see builder.Cache
+func (c *Cache) Transaction() (builder.Cache, func(ctx context.Context)) {
+	tx := &txCache{cache: c}
+	return tx, tx.commit
+}
+
+func (c *Cache) invalidate(ctx context.Context, models []string) {
+	c.bump(models)
+	for _, model := range models {
+		if err := c.store.Invalidate(ctx, model); err != nil {
+			logger.Info.Printf("cache: invalidate %s: %s", model, err)
+		}
+	}
+}
+
+func (c *Cache) cached(model string) bool {
+	if c.models != nil {
+		return c.models[model]
+	}
+	m, ok := c.schema.Model(model)
+	return ok && !m.IsView
+}
+
+// generation returns the sum of the invalidation counts of models
+func (c *Cache) generation(models []string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sum uint64
+	for _, model := range models {
+		sum += c.generations[model]
+	}
+	return sum
+}
+
+func (c *Cache) bump(models []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, model := range models {
+		c.generations[model]++
+	}
+}
+
+// Key returns the cache key of a query, which is derived from its model and the normalized query as it is sent to the
+// engine, with its arguments sorted by name. A Cache prefixes it with a hash of the schema.
+func Key(q builder.Query) (string, error) {
+	str, err := q.BuildInner()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(str))
+	return q.Model + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// namespace returns a short hash of the schema. Models are invalidated by name regardless of the namespace, as clients
+// of all schemas write to the same tables.
+func namespace(schema *metadata.Schema) string {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+func decode(value []byte, into interface{}) error {
+	if into == nil {
+		return nil
+	}
+	return json.Unmarshal(value, into)
+}
+
+// txCache bypasses the cache for the queries of an interactive transaction and collects the models it wrote to
+type txCache struct {
+	cache *Cache
+
+	mu     sync.Mutex
+	models []string
+}
+
+func (t *txCache) Exec(ctx context.Context, q builder.Query, into interface{}, next func(ctx context.Context, into interface{}) error) error {
+	err := next(ctx, into)
+	t.Invalidate(ctx, q)
+	return err
+}
+
+func (t *txCache) Invalidate(_ context.Context, q builder.Query) {
+	if readActions[q.Method] || rawActions[q.Method] {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.models = append(t.models, writeDependencies(t.cache.schema, q)...)
+}
+
+func (t *txCache) Transaction() (builder.Cache, func(ctx context.Context)) {
+	return t, func(context.Context) {}
+}
+
+// commit invalidates the models the transaction wrote to
+func (t *txCache) commit(ctx context.Context) {
+	t.mu.Lock()
+	models := unique(t.models)
+	t.models = nil
+	t.mu.Unlock()
+
+	t.cache.invalidate(ctx, models)
+}
@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+var schema = &metadata.Schema{
+	Models: []metadata.Model{{
+		Name: "User",
+		Fields: []metadata.Field{
+			{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsID: true},
+			{Name: "posts", Kind: metadata.FieldKindRelation, Type: "Post", IsList: true, RelationName: "PostToUser"},
+		},
+	}, {
+		Name: "Post",
+		Fields: []metadata.Field{
+			{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsID: true},
+			{Name: "title", Kind: metadata.FieldKindScalar, Type: "String"},
+			{Name: "author", Kind: metadata.FieldKindRelation, Type: "User", RelationName: "PostToUser", RelationFromFields: []string{"authorID"}},
+			{Name: "comments", Kind: metadata.FieldKindRelation, Type: "Comment", IsList: true, RelationName: "CommentToPost"},
+			{Name: "tags", Kind: metadata.FieldKindRelation, Type: "Tag", IsList: true, RelationName: "PostToTag"},
+		},
+	}, {
+		Name: "Comment",
+		Fields: []metadata.Field{
+			{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsID: true},
+			{Name: "post", Kind: metadata.FieldKindRelation, Type: "Post", RelationName: "CommentToPost", RelationFromFields: []string{"postID"}},
+		},
+	}, {
+		Name: "Tag",
+		Fields: []metadata.Field{
+			{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsID: true},
+			{Name: "posts", Kind: metadata.FieldKindRelation, Type: "Post", IsList: true, RelationName: "PostToTag"},
+		},
+	}, {
+		Name:   "PostStats",
+		IsView: true,
+	}},
+}
+
+// cacheEngine counts the queries it receives and responds with an empty object
+type cacheEngine struct {
+	policy  *builder.Policy
+	queries int
+}
+
+func (e *cacheEngine) Connect() error    { return nil }
+func (e *cacheEngine) Disconnect() error { return nil }
+func (e *cacheEngine) Name() string      { return "cache" }
+func (e *cacheEngine) Batch(ctx context.Context, payload interface{}, v interface{}) error {
+	return nil
+}
+func (e *cacheEngine) Do(ctx context.Context, payload interface{}, v interface{}) error {
+	e.queries++
+	return json.Unmarshal([]byte(`{"id":"a"}`), v)
+}
+func (e *cacheEngine) QueryPolicy() *builder.Policy {
+	return e.policy
+}
+
+func query(e *cacheEngine, method, model string, inputs []builder.Input, outputs ...builder.Output) builder.Query {
+	q := builder.NewQuery()
+	q.Engine = e
+	q.Operation = "query"
+	q.Method = method
+	q.Model = model
+	q.Inputs = inputs
+	q.Outputs = append([]builder.Output{{Name: "id"}}, outputs...)
+	return q
+}
+
+func exec(t *testing.T, q builder.Query) {
+	t.Helper()
+	var v struct {
+		ID string `json:"id"`
+	}
+	if err := q.Exec(context.Background(), &v); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "a", v.ID)
+}
+
+func TestCache(t *testing.T) {
+	e := &cacheEngine{}
+	store := NewLRU(100)
+	e.policy = &builder.Policy{Cache: New(store, schema)}
+
+	users := query(e, "findMany", "User", nil)
+	usersWithPosts := query(e, "findMany", "User", nil, builder.Output{
+		Name:    "posts",
+		Outputs: []builder.Output{{Name: "id"}},
+	})
+	updatePost := query(e, "updateOne", "Post", []builder.Input{{
+		Name:   "data",
+		Fields: []builder.Field{{Name: "title", Fields: []builder.Field{{Name: "set", Value: "new"}}}},
+	}})
+
+	exec(t, users)
+	exec(t, users)
+	exec(t, usersWithPosts)
+	exec(t, usersWithPosts)
+	massert.Equal(t, 2, e.queries)
+
+	// only the users with posts depend on posts
+	exec(t, updatePost)
+	exec(t, users)
+	exec(t, usersWithPosts)
+	massert.Equal(t, 4, e.queries)
+
+	// bypassed queries are sent to the engine
+	if err := users.Exec(Bypass(context.Background()), nil); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 5, e.queries)
+
+	// views are not cached
+	stats := query(e, "findMany", "PostStats", nil)
+	exec(t, stats)
+	exec(t, stats)
+	massert.Equal(t, 7, e.queries)
+
+	if err := store.Invalidate(context.Background(), "User"); err != nil {
+		t.Fatal(err)
+	}
+	exec(t, users)
+	massert.Equal(t, 8, e.queries)
+
+	// deleting a user may delete its posts with a cascade
+	posts := query(e, "findMany", "Post", nil)
+	exec(t, posts)
+	exec(t, query(e, "deleteOne", "User", nil))
+	exec(t, posts)
+	massert.Equal(t, 11, e.queries)
+}
+
+func TestCache_namespace(t *testing.T) {
+	e := &cacheEngine{}
+	store := NewLRU(100)
+	e.policy = &builder.Policy{Cache: New(store, schema)}
+	exec(t, query(e, "findMany", "User", nil))
+
+	// a client of a different schema doesn't read the results of the other one
+	other := &cacheEngine{}
+	changed := &metadata.Schema{Models: append([]metadata.Model{{Name: "Account"}}, schema.Models...)}
+	other.policy = &builder.Policy{Cache: New(store, changed)}
+	exec(t, query(other, "findMany", "User", nil))
+	massert.Equal(t, 1, e.queries)
+	massert.Equal(t, 1, other.queries)
+
+	// but its writes invalidate them
+	exec(t, query(other, "updateOne", "User", nil))
+	exec(t, query(e, "findMany", "User", nil))
+	massert.Equal(t, 2, e.queries)
+}
+
+func TestCache_transaction(t *testing.T) {
+	e := &cacheEngine{}
+	e.policy = &builder.Policy{Cache: New(NewLRU(100), schema)}
+
+	posts := query(e, "findMany", "Post", nil)
+	exec(t, posts)
+
+	tx := &cacheEngine{}
+	var commit func(ctx context.Context)
+	tx.policy, commit = e.policy.Transaction()
+
+	// queries of the transaction bypass the cache
+	exec(t, query(tx, "findMany", "Post", nil))
+	exec(t, query(tx, "createOne", "Post", nil))
+	massert.Equal(t, 2, tx.queries)
+
+	// the invalidation is deferred until the transaction was committed
+	exec(t, posts)
+	massert.Equal(t, 1, e.queries)
+	commit(context.Background())
+	exec(t, posts)
+	massert.Equal(t, 2, e.queries)
+}
+
+func TestDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		query builder.Query
+		want  []string
+	}{{
+		name:  "model",
+		query: query(nil, "findMany", "Post", nil),
+		want:  []string{"Post"},
+	}, {
+		name: "relation filter",
+		query: query(nil, "findMany", "User", []builder.Input{{
+			Name: "where",
+			Fields: []builder.Field{{
+				Name:   "posts",
+				Fields: []builder.Field{{Name: "some", Fields: []builder.Field{{Name: "title", Value: "a"}}}},
+			}},
+		}}),
+		want: []string{"Post", "User"},
+	}, {
+		name: "nested relation",
+		query: query(nil, "findMany", "Post", nil, builder.Output{
+			Name:    "author",
+			Outputs: []builder.Output{{Name: "posts", Outputs: []builder.Output{{Name: "id"}}}},
+		}),
+		want: []string{"Post", "User"},
+	}, {
+		name: "nested write",
+		query: query(nil, "createOne", "User", []builder.Input{{
+			Name:   "data",
+			Fields: []builder.Field{{Name: "posts", Fields: []builder.Field{{Name: "create"}}}},
+		}}),
+		want: []string{"Post", "User"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			massert.Equal(t, tt.want, dependencies(schema, tt.query))
+		})
+	}
+}
+
+func TestWriteDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		query builder.Query
+		want  []string
+	}{{
+		name:  "referencing models",
+		query: query(nil, "deleteMany", "User", nil),
+		want:  []string{"Comment", "Post", "User"},
+	}, {
+		name:  "many to many",
+		query: query(nil, "deleteOne", "Tag", nil),
+		want:  []string{"Tag"},
+	}, {
+		name:  "referenced models",
+		query: query(nil, "updateMany", "Comment", nil),
+		want:  []string{"Comment"},
+	}, {
+		name:  "create",
+		query: query(nil, "createOne", "User", nil),
+		want:  []string{"User"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			massert.Equal(t, tt.want, writeDependencies(schema, tt.query))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in-memory Store which holds at most a fixed number of values and evicts the least recently used ones
+type LRU struct {
+	max int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	// models maps each model to the keys of the values depending on it
+	models map[string]map[string]bool
+
+	now func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	models  []string
+	expires time.Time
+}
+
+// NewLRU returns an in-memory store which holds at most max values
+func NewLRU(max int) *LRU {
+	return &LRU{
+		max:     max,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		models:  make(map[string]map[string]bool),
+		now:     time.Now,
+	}
+}
+
+// Get returns the value stored under key, if it exists and has not expired
+func (l *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := e.Value.(*lruEntry)
+	if !l.now().Before(entry.expires) {
+		l.remove(e)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(e)
+	return entry.value, true, nil
+}
+
+// Set stores value under key for ttl, evicting the least recently used value if the store is full
+func (l *LRU) Set(_ context.Context, key string, value []byte, models []string, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[key]; ok {
+		l.remove(e)
+	}
+
+	entry := &lruEntry{
+		key:     key,
+		value:   value,
+		models:  models,
+		expires: l.now().Add(ttl),
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	for _, model := range models {
+		if l.models[model] == nil {
+			l.models[model] = make(map[string]bool)
+		}
+		l.models[model][key] = true
+	}
+
+	for l.order.Len() > l.max {
+		l.remove(l.order.Back())
+	}
+	return nil
+}
+
+// Invalidate removes all values which depend on model
+func (l *LRU) Invalidate(_ context.Context, model string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key := range l.models[model] {
+		if e, ok := l.entries[key]; ok {
+			l.remove(e)
+		}
+	}
+	delete(l.models, model)
+	return nil
+}
+
+// Len returns the number of stored values, including expired ones which were not evicted yet
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *LRU) remove(e *list.Element) {
+	entry := e.Value.(*lruEntry)
+	l.order.Remove(e)
+	delete(l.entries, entry.key)
+	for _, model := range entry.models {
+		delete(l.models[model], entry.key)
+		if len(l.models[model]) == 0 {
+			delete(l.models, model)
+		}
+	}
+}
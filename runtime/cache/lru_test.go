@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLRU(2)
+	l.now = func() time.Time {
+		return now
+	}
+
+	get := func(key string) string {
+		t.Helper()
+		value, ok, err := l.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			return ""
+		}
+		return string(value)
+	}
+	set := func(key, value string, ttl time.Duration, models ...string) {
+		t.Helper()
+		if err := l.Set(ctx, key, []byte(value), models, ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	set("a", "1", time.Minute, "User")
+	set("b", "2", time.Minute, "User", "Post")
+	massert.Equal(t, "1", get("a"))
+
+	// b is the least recently used value
+	set("c", "3", time.Minute, "Post")
+	massert.Equal(t, "", get("b"))
+	massert.Equal(t, 2, l.Len())
+
+	// c depends on Post, a doesn't
+	if err := l.Invalidate(ctx, "Post"); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "1", get("a"))
+	massert.Equal(t, "", get("c"))
+
+	now = now.Add(time.Minute)
+	massert.Equal(t, "", get("a"))
+	massert.Equal(t, 0, l.Len())
+	massert.Equal(t, 0, len(l.models))
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+)
+
+// dependencies returns the model of q and the models of the relations it fetches, filters by or writes to, e.g. Post
+// for a User query with posts included or a nested create of a post. Names which are not relation fields of the
+// models are ignored, so that a name appearing in an unrelated place at worst invalidates a query too often.
+func dependencies(schema *metadata.Schema, q builder.Query) []string {
+	names := make(map[string]bool)
+	collectInputs(names, q.Inputs)
+	collectFields(names, q.Scope)
+	collectOutputs(names, q.Outputs)
+
+	models := map[string]bool{q.Model: true}
+	queue := []string{q.Model}
+	for len(queue) > 0 {
+		model, ok := schema.Model(queue[0])
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, relation := range model.Relations() {
+			if names[relation.Name] && !models[relation.Type] {
+				models[relation.Type] = true
+				queue = append(queue, relation.Type)
+			}
+		}
+	}
+
+	result := make([]string, 0, len(models))
+	for model := range models {
+		result = append(result, model)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// writeDependencies returns the models which a write query q may change: the models it writes to and, unless it only
+// creates records, the models whose records reference them, transitively, as referential actions such as
+// `onDelete: Cascade` or `SetNull` change them as well. Queries which fetch or filter by a relation already depend on
+// the related model, so only the models holding the foreign keys are added.
+func writeDependencies(schema *metadata.Schema, q builder.Query) []string {
+	models := dependencies(schema, q)
+	if strings.HasPrefix(q.Method, "create") {
+		return models
+	}
+	return referencing(schema, models)
+}
+
+// referencing returns models and the models which reference them with a foreign key, transitively, e.g. Post and
+// Comment for User if posts reference users and comments reference posts. If the other side of a relation is unknown,
+// the related model is included, so that a query is at worst invalidated too often.
+func referencing(schema *metadata.Schema, models []string) []string {
+	seen := make(map[string]bool, len(models))
+	for _, model := range models {
+		seen[model] = true
+	}
+	queue := append([]string{}, models...)
+	for len(queue) > 0 {
+		model, ok := schema.Model(queue[0])
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, relation := range model.Relations() {
+			if seen[relation.Type] {
+				continue
+			}
+			if back, ok := backRelation(schema, model.Name, relation); ok && len(back.RelationFromFields) == 0 {
+				continue
+			}
+			seen[relation.Type] = true
+			queue = append(queue, relation.Type)
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for model := range seen {
+		result = append(result, model)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// backRelation returns the field of the related model which is the other side of relation
+func backRelation(schema *metadata.Schema, model string, relation metadata.Field) (metadata.Field, bool) {
+	related, ok := schema.Model(relation.Type)
+	if !ok || relation.RelationName == "" {
+		return metadata.Field{}, false
+	}
+	for _, f := range related.Relations() {
+		if f.RelationName == relation.RelationName && f.Type == model {
+			return f, true
+		}
+	}
+	return metadata.Field{}, false
+}
+
+func collectInputs(names map[string]bool, inputs []builder.Input) {
+	for _, input := range inputs {
+		names[input.Name] = true
+		collectFields(names, input.Fields)
+	}
+}
+
+func collectFields(names map[string]bool, fields []builder.Field) {
+	for _, field := range fields {
+		names[field.Name] = true
+		collectFields(names, field.Fields)
+	}
+}
+
+func collectOutputs(names map[string]bool, outputs []builder.Output) {
+	for _, output := range outputs {
+		names[output.Name] = true
+		collectInputs(names, output.Inputs)
+		collectOutputs(names, output.Outputs)
+	}
+}
+
+func unique(models []string) []string {
+	seen := make(map[string]bool, len(models))
+	var result []string
+	for _, model := range models {
+		if !seen[model] {
+			seen[model] = true
+			result = append(result, model)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,65 @@
+package rediscache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Codec serializes the values of a Store, which are query results encoded as JSON
+type Codec interface {
+	Encode(value []byte) ([]byte, error)
+	Decode(data []byte) ([]byte, error)
+}
+
+// Raw stores values as they are
+var Raw Codec = rawCodec{}
+
+type rawCodec struct{}
+
+func (rawCodec) Encode(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+func (rawCodec) Decode(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// Gzip compresses values with the given level, e.g. gzip.BestSpeed, which trades CPU time for memory and network
+// traffic of large results
+func Gzip(level int) Codec {
+	return gzipCodec{level: level}
+}
+
+type gzipCodec struct {
+	level int
+}
+
+func (c gzipCodec) Encode(value []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, c.level)
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	if _, err := w.Write(value); err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decode(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gunzip: %w", err)
+	}
+	defer r.Close()
+	value, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("gunzip: %w", err)
+	}
+	return value, nil
+}
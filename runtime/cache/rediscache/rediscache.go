@@ -0,0 +1,158 @@
+// Package rediscache implements a cache.Store on Redis, so that multiple instances of a service share cached query
+// results and invalidate them for each other. It is a separate module, so that the client doesn't depend on go-redis.
+//
+// Example:
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	client := db.NewClient(db.WithCache(rediscache.New(rdb)))
+//
+// Each value is stored under its own key, and the keys of the values which depend on a model are tracked in a set per
+// model, which is read and cleared when the model is invalidated. The sets expire with the values they hold, which
+// requires Redis 7 or later.
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix is prepended to all keys if no prefix is set
+const DefaultPrefix = "prisma:cache:"
+
+// Store is a cache.Store which stores query results in Redis
+type Store struct {
+	client redis.UniversalClient
+	prefix string
+	codec  Codec
+	ttls   map[string]time.Duration
+}
+
+// Option configures a Store
+type Option func(*Store)
+
+// WithPrefix sets the prefix of all keys, e.g. to share a Redis database between services. Defaults to DefaultPrefix.
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// WithCodec sets how values are serialized. Defaults to Raw.
+func WithCodec(codec Codec) Option {
+	return func(s *Store) {
+		s.codec = codec
+	}
+}
+
+// WithModelTTL caches the results of queries which depend on model for ttl instead of the TTL of the cache. If a query
+// depends on multiple models with a TTL, e.g. because it fetches a relation, the shortest one is used.
+func WithModelTTL(model string, ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttls[model] = ttl
+	}
+}
+
+// New returns a store which stores query results with client, which may be a single node, cluster or failover client
+func New(client redis.UniversalClient, opts ...Option) *Store {
+	s := &Store{
+		client: client,
+		prefix: DefaultPrefix,
+		codec:  Raw,
+		ttls:   make(map[string]time.Duration),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Get returns the value stored under key, if it exists and has not expired
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := s.client.Get(ctx, s.valueKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("redis get: %w", err)
+	}
+	value, err := s.codec.Decode(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("decode: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl, or the TTL of the models it depends on, and adds key to the set of each model
+func (s *Store) Set(ctx context.Context, key string, value []byte, models []string, ttl time.Duration) error {
+	data, err := s.codec.Encode(value)
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	ttl = s.ttl(models, ttl)
+
+	pipe := s.client.Pipeline()
+	pipe.Set(ctx, s.valueKey(key), data, ttl)
+	for _, model := range models {
+		modelKey := s.modelKey(model)
+		pipe.SAdd(ctx, modelKey, key)
+		// the set must live as long as its longest living value, so it is only ever extended
+		pipe.ExpireNX(ctx, modelKey, ttl)
+		pipe.ExpireGT(ctx, modelKey, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// Invalidate removes all values which depend on model
+func (s *Store) Invalidate(ctx context.Context, model string) error {
+	modelKey := s.modelKey(model)
+	keys, err := s.client.SMembers(ctx, modelKey).Result()
+	if err != nil {
+		return fmt.Errorf("redis invalidate: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(keys))
+	pipe := s.client.Pipeline()
+	for i, key := range keys {
+		// values are deleted one by one, as they may be in different slots of a cluster
+		pipe.Del(ctx, s.valueKey(key))
+		members[i] = key
+	}
+	// keys which were added in the meantime are kept, so that they can be invalidated later
+	pipe.SRem(ctx, modelKey, members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis invalidate: %w", err)
+	}
+	return nil
+}
+
+// ttl returns the shortest TTL of models, or fallback if none of them has a TTL
+func (s *Store) ttl(models []string, fallback time.Duration) time.Duration {
+	ttl := time.Duration(0)
+	for _, model := range models {
+		if t, ok := s.ttls[model]; ok && (ttl == 0 || t < ttl) {
+			ttl = t
+		}
+	}
+	if ttl == 0 {
+		return fallback
+	}
+	return ttl
+}
+
+func (s *Store) valueKey(key string) string {
+	return s.prefix + "value:" + key
+}
+
+func (s *Store) modelKey(model string) string {
+	return s.prefix + "model:" + model
+}
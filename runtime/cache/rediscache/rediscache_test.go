@@ -0,0 +1,111 @@
+package rediscache
+
+import (
+	"compress/gzip"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func startStore(t *testing.T, opts ...Option) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return New(client, opts...), mr
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s, mr := startStore(t)
+
+	get := func(key string) string {
+		t.Helper()
+		value, ok, err := s.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			return ""
+		}
+		return string(value)
+	}
+	set := func(key, value string, ttl time.Duration, models ...string) {
+		t.Helper()
+		if err := s.Set(ctx, key, []byte(value), models, ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	set("a", "1", time.Minute, "User")
+	set("b", "2", 2*time.Minute, "User", "Post")
+	set("c", "3", time.Minute, "Post")
+	massert.Equal(t, "1", get("a"))
+	massert.Equal(t, "", get("d"))
+	massert.Equal(t, true, mr.Exists(DefaultPrefix+"value:a"))
+
+	// the set of a model expires with its longest living value
+	massert.Equal(t, 2*time.Minute, mr.TTL(DefaultPrefix+"model:Post"))
+
+	if err := s.Invalidate(ctx, "Post"); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "1", get("a"))
+	massert.Equal(t, "", get("b"))
+	massert.Equal(t, "", get("c"))
+	massert.Equal(t, false, mr.Exists(DefaultPrefix+"model:Post"))
+
+	mr.FastForward(time.Minute)
+	massert.Equal(t, "", get("a"))
+}
+
+func TestStore_modelTTL(t *testing.T) {
+	ctx := context.Background()
+	s, mr := startStore(t, WithModelTTL("Country", time.Hour), WithModelTTL("Price", time.Second))
+
+	for key, models := range map[string][]string{
+		"country": {"Country"},
+		"price":   {"Country", "Price"},
+		"user":    {"User"},
+	} {
+		if err := s.Set(ctx, key, []byte("{}"), models, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	massert.Equal(t, time.Hour, mr.TTL(DefaultPrefix+"value:country"))
+	massert.Equal(t, time.Second, mr.TTL(DefaultPrefix+"value:price"))
+	massert.Equal(t, time.Minute, mr.TTL(DefaultPrefix+"value:user"))
+}
+
+func TestStore_codec(t *testing.T) {
+	ctx := context.Background()
+	s, mr := startStore(t, WithPrefix("app:"), WithCodec(Gzip(gzip.BestSpeed)))
+
+	value := []byte(`{"data":{"result":[{"id":"1"}]}}`)
+	if err := s.Set(ctx, "User:1", value, []string{"User"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := mr.Get("app:value:User:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == string(value) {
+		t.Fatalf("expected value to be compressed")
+	}
+
+	got, ok, err := s.Get(ctx, "User:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, true, ok)
+	massert.Equal(t, string(value), string(got))
+}
@@ -0,0 +1,139 @@
+// Package cursor seals and opens pagination cursors, so that cursors exposed to API clients can neither be read nor
+// tampered with to access arbitrary rows.
+package cursor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidToken is returned when a token is malformed or was not sealed with the key of the signer
+var ErrInvalidToken = errors.New("invalid cursor token")
+
+// minKeySize is the minimum size of a signing key in bytes
+const minKeySize = 32
+
+// keyContext separates the encryption keys derived from a signing key from other uses of the same key
+const keyContext = "prisma-client-go cursor"
+
+var encoding = base64.RawURLEncoding
+
+// Signer seals and opens cursor tokens with AES-256-GCM, so that clients can't read the values of a cursor and any
+// change to a token is detected.
+//
+// A token consists of a random nonce and the encrypted JSON encoded cursor value, base64url encoded. The encryption
+// key is derived from the signing key with HMAC-SHA256.
+//
+// A nil Signer encodes tokens without encryption, i.e. only the base64url encoded value, and only accepts such tokens.
+type Signer struct {
+	aead     cipher.AEAD
+	previous []cipher.AEAD
+}
+
+// NewSigner creates a signer which seals tokens with key, which must be at least 32 bytes long.
+// Tokens sealed with one of the previous keys are still accepted, so that keys can be rotated without invalidating
+// the cursors handed out to clients.
+func NewSigner(key []byte, previous ...[]byte) (*Signer, error) {
+	var aeads []cipher.AEAD
+	for _, k := range append([][]byte{key}, previous...) {
+		if len(k) < minKeySize {
+			return nil, fmt.Errorf("cursor signing key must be at least %d bytes long, got %d", minKeySize, len(k))
+		}
+		aead, err := newAEAD(k)
+		if err != nil {
+			return nil, err
+		}
+		aeads = append(aeads, aead)
+	}
+	return &Signer{
+		aead:     aeads[0],
+		previous: aeads[1:],
+	}, nil
+}
+
+// newAEAD returns the cipher which seals tokens with the encryption key derived from key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(keyContext))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("cursor cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cursor cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// Sign encodes the value as JSON and returns a sealed token
+func (s *Signer) Sign(value interface{}) (string, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshal cursor: %w", err)
+	}
+	if s == nil {
+		return encoding.EncodeToString(payload), nil
+	}
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(payload)+s.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("cursor nonce: %w", err)
+	}
+	return encoding.EncodeToString(s.aead.Seal(nonce, nonce, payload, nil)), nil
+}
+
+// Verify opens the token and decodes its value into v
+func (s *Signer) Verify(token string, v interface{}) error {
+	payload, err := encoding.DecodeString(token)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if s != nil {
+		var ok bool
+		if payload, ok = s.open(payload); !ok {
+			return ErrInvalidToken
+		}
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+	return nil
+}
+
+// open decrypts a sealed token with the current or one of the previous keys
+func (s *Signer) open(sealed []byte) ([]byte, bool) {
+	for _, aead := range append([]cipher.AEAD{s.aead}, s.previous...) {
+		if len(sealed) < aead.NonceSize() {
+			return nil, false
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if payload, err := aead.Open(nil, nonce, ciphertext, nil); err == nil {
+			return payload, true
+		}
+	}
+	return nil, false
+}
+
+// Encode returns a sealed token for a typed cursor value
+func Encode[T any](s *Signer, value T) (string, error) {
+	return s.Sign(value)
+}
+
+// Decode opens a token and returns its typed cursor value
+func Decode[T any](s *Signer, token string) (T, error) {
+	var value T
+	if err := s.Verify(token, &value); err != nil {
+		var empty T
+		return empty, err
+	}
+	return value, nil
+}
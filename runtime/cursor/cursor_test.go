@@ -0,0 +1,157 @@
+package cursor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+var (
+	key      = []byte("0123456789abcdef0123456789abcdef")
+	otherKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+type postCursor struct {
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+func TestSigner_roundTrip(t *testing.T) {
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Encode(s, postCursor{ID: "abc", CreatedAt: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := Decode[postCursor](s, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, postCursor{ID: "abc", CreatedAt: 42}, actual)
+}
+
+func TestSigner_rejectsTamperedTokens(t *testing.T) {
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := s.Sign(postCursor{ID: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := encoding.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	forged := encoding.EncodeToString(sealed)
+
+	other, err := NewSigner(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := other.Sign(postCursor{ID: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"", "abc", "abc.def", "!!.!!", token[:10], forged, foreign} {
+		_, err := Decode[postCursor](s, token)
+		massert.Equal(t, true, errors.Is(err, ErrInvalidToken))
+	}
+}
+
+func TestSigner_opaque(t *testing.T) {
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := s.Sign(postCursor{ID: "secret-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Sign(postCursor{ID: "secret-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the values of a cursor can't be read from the token, and sealing the same value twice yields different tokens
+	sealed, err := encoding.DecodeString(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, false, strings.Contains(string(sealed), "secret-id"))
+	massert.Equal(t, false, first == second)
+}
+
+func TestSigner_acceptsPreviousKeys(t *testing.T) {
+	old, err := NewSigner(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := old.Sign("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := NewSigner(key, otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := Decode[string](rotated, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "abc", actual)
+
+	// new tokens are signed with the current key
+	token, err = rotated.Sign("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Decode[string](old, token)
+	massert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestSigner_nil(t *testing.T) {
+	var unsigned *Signer
+	token, err := Encode(unsigned, postCursor{ID: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "eyJpZCI6ImFiYyIsImNyZWF0ZWRBdCI6MH0", token)
+
+	actual, err := Decode[postCursor](unsigned, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, postCursor{ID: "abc"}, actual)
+
+	// unsigned tokens are rejected by signers, and signed tokens without one
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Decode[postCursor](s, token)
+	massert.Equal(t, ErrInvalidToken, err)
+
+	signed, err := s.Sign(postCursor{ID: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Decode[postCursor](unsigned, signed)
+	massert.Equal(t, true, errors.Is(err, ErrInvalidToken))
+}
+
+func TestNewSigner_shortKey(t *testing.T) {
+	_, err := NewSigner([]byte("short"))
+	massert.Equal(t, "cursor signing key must be at least 32 bytes long, got 5", err.Error())
+}
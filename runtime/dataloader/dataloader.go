@@ -0,0 +1,204 @@
+// Package dataloader batches lookups of records by a key, so that resolving a field for many parents, e.g. the author
+// of each post in a GraphQL response, sends a single query instead of one per parent (N+1). The generated client has
+// a loader for each unique field and relation if the generator option dataloaders is set.
+//
+// A Loader caches the values it loaded, so it is meant to be created for each request:
+//
+//	loaders := db.NewLoaders(client)
+//
+//	// in a resolver
+//	author, err := loaders.UserByID.Load(ctx, post.AuthorID)
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Fetch loads the values of the given keys. Keys without a value are left out of the result.
+type Fetch[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type options struct {
+	wait     time.Duration
+	maxBatch int
+	notFound error
+}
+
+// Option configures a Loader
+type Option func(*options)
+
+// WithWait sets how long a Loader waits for more keys before it fetches a batch, which is 2ms by default
+func WithWait(wait time.Duration) Option {
+	return func(o *options) {
+		o.wait = wait
+	}
+}
+
+// WithMaxBatch sets the maximum number of keys of a batch, which is 100 by default. A batch is fetched as soon as it
+// is full. Zero or less means that batches have no limit.
+func WithMaxBatch(maxBatch int) Option {
+	return func(o *options) {
+		o.maxBatch = maxBatch
+	}
+}
+
+// WithNotFound sets the error which is returned for keys without a value. By default, the zero value and no error is
+// returned.
+func WithNotFound(err error) Option {
+	return func(o *options) {
+		o.notFound = err
+	}
+}
+
+// batch is a set of keys which are fetched together
+type batch[K comparable, V any] struct {
+	keys  []K
+	timer *time.Timer
+	// dispatched is set when the batch is being fetched, either after the wait time or when it is full
+	dispatched bool
+	done       chan struct{}
+	values     map[K]V
+	err        error
+}
+
+// Loader batches the keys of concurrent Load calls and fetches them with a single call of its Fetch function. It is
+// safe for concurrent use.
+type Loader[K comparable, V any] struct {
+	fetch Fetch[K, V]
+	options
+
+	mu sync.Mutex
+	// cache contains the batch of each key which was loaded or is being loaded
+	cache map[K]*batch[K, V]
+	// current is the batch which collects keys, or nil
+	current *batch[K, V]
+}
+
+// New creates a Loader which fetches batches of keys with fetch
+func New[K comparable, V any](fetch Fetch[K, V], opts ...Option) *Loader[K, V] {
+	l := &Loader[K, V]{
+		fetch: fetch,
+		options: options{
+			wait:     2 * time.Millisecond,
+			maxBatch: 100,
+		},
+		cache: make(map[K]*batch[K, V]),
+	}
+	for _, opt := range opts {
+		opt(&l.options)
+	}
+	return l
+}
+
+// Load returns the value of key. The key is fetched together with the keys of other calls within the wait time, unless
+// it was loaded before.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	return l.value(ctx, key, l.add(ctx, key))
+}
+
+// LoadAll returns the values of the given keys in the same order. It returns the first error of any key.
+func (l *Loader[K, V]) LoadAll(ctx context.Context, keys []K) ([]V, error) {
+	batches := make([]*batch[K, V], len(keys))
+	for i, key := range keys {
+		batches[i] = l.add(ctx, key)
+	}
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		value, err := l.value(ctx, key, batches[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// Clear removes key from the cache, e.g. after the record was updated, so that it is fetched again on the next Load
+func (l *Loader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.cache, key)
+}
+
+// add adds key to the current batch and returns the batch of the key
+func (l *Loader[K, V]) add(ctx context.Context, key K) *batch[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.cache[key]; ok {
+		return b
+	}
+
+	b := l.current
+	if b == nil {
+		b = &batch[K, V]{done: make(chan struct{})}
+		l.current = b
+		// the batch must not be canceled with the first caller, as the other callers wait for it as well
+		fetchCtx := context.WithoutCancel(ctx)
+		b.timer = time.AfterFunc(l.options.wait, func() {
+			if l.dispatch(b) {
+				l.run(fetchCtx, b)
+			}
+		})
+	}
+	b.keys = append(b.keys, key)
+	l.cache[key] = b
+
+	if l.options.maxBatch > 0 && len(b.keys) >= l.options.maxBatch {
+		b.timer.Stop()
+		l.current = nil
+		b.dispatched = true
+		go l.run(context.WithoutCancel(ctx), b)
+	}
+	return b
+}
+
+// dispatch marks b as being fetched after the wait time and returns false if it is already being fetched because it
+// was full
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b.dispatched {
+		return false
+	}
+	b.dispatched = true
+	if l.current == b {
+		l.current = nil
+	}
+	return true
+}
+
+// run fetches the keys of b
+func (l *Loader[K, V]) run(ctx context.Context, b *batch[K, V]) {
+	b.values, b.err = l.fetch(ctx, b.keys)
+	if b.err != nil {
+		// failed keys are fetched again on the next Load
+		l.mu.Lock()
+		for _, key := range b.keys {
+			if l.cache[key] == b {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+	close(b.done)
+}
+
+// value waits until b is fetched and returns the value of key
+func (l *Loader[K, V]) value(ctx context.Context, key K, b *batch[K, V]) (V, error) {
+	var zero V
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case <-b.done:
+	}
+	if b.err != nil {
+		return zero, b.err
+	}
+	value, ok := b.values[key]
+	if !ok && l.options.notFound != nil {
+		return zero, l.options.notFound
+	}
+	return value, nil
+}
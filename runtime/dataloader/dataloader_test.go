@@ -0,0 +1,134 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fetcher records the batches it is called with and returns the doubled keys, except for negative keys
+type fetcher struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (f *fetcher) fetch(_ context.Context, keys []int) (map[int]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	batch := append([]int{}, keys...)
+	sort.Ints(batch)
+	f.batches = append(f.batches, batch)
+	if f.err != nil {
+		return nil, f.err
+	}
+	values := make(map[int]int)
+	for _, key := range keys {
+		if key >= 0 {
+			values[key] = key * 2
+		}
+	}
+	return values, nil
+}
+
+func TestLoader_batchesConcurrentLoads(t *testing.T) {
+	f := &fetcher{}
+	l := New(f.fetch, WithWait(20*time.Millisecond))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	values := make([]int, 5)
+	for i := range values {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := l.Load(ctx, i%3)
+			if err != nil {
+				t.Error(err)
+			}
+			values[i] = value
+		}()
+	}
+	wg.Wait()
+
+	massert.Equal(t, []int{0, 2, 4, 0, 2}, values)
+	massert.Equal(t, [][]int{{0, 1, 2}}, f.batches)
+
+	// loaded keys are cached
+	value, err := l.Load(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 4, value)
+	massert.Equal(t, 1, len(f.batches))
+}
+
+func TestLoader_LoadAll(t *testing.T) {
+	f := &fetcher{}
+	l := New(f.fetch, WithMaxBatch(2))
+
+	values, err := l.LoadAll(context.Background(), []int{3, 1, 2, 3, -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []int{6, 2, 4, 6, 0}, values)
+	// the first batch is fetched as soon as it is full
+	massert.Equal(t, 2, len(f.batches))
+}
+
+func TestLoader_WithNotFound(t *testing.T) {
+	notFound := errors.New("not found")
+	f := &fetcher{}
+	l := New(f.fetch, WithNotFound(notFound))
+
+	_, err := l.Load(context.Background(), -1)
+	massert.Equal(t, notFound, err)
+}
+
+func TestLoader_errorsAreNotCached(t *testing.T) {
+	f := &fetcher{err: errors.New("connection refused")}
+	l := New(f.fetch)
+	ctx := context.Background()
+
+	if _, err := l.Load(ctx, 1); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	f.mu.Lock()
+	f.err = nil
+	f.mu.Unlock()
+	value, err := l.Load(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 2, value)
+
+	l.Clear(1)
+	if _, err := l.Load(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 3, len(f.batches))
+}
+
+func TestLoader_canceledContext(t *testing.T) {
+	f := &fetcher{}
+	l := New(f.fetch, WithWait(20*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := l.Load(ctx, 1)
+	massert.Equal(t, context.Canceled, err)
+
+	// the batch is still fetched for other callers
+	value, err := l.Load(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 2, value)
+}
@@ -0,0 +1,82 @@
+// Package factory generates the fake values which the factories of generated clients, e.g. db.UserFactory(), set
+// on required fields. Each value is unique, so that records created by factories don't violate unique constraints.
+package factory
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+// MaxDepth is how deep factories create required relations, which prevents endless recursion for required
+// self-relations
+const MaxDepth = 16
+
+// sequence makes fake values unique. It starts at a random value, so that test packages running in parallel against
+// the same database are unlikely to create the same values, while staying within the range of 32-bit integer columns.
+var sequence atomic.Int64
+
+func init() {
+	sequence.Store(rand.Int63n(1 << 24))
+}
+
+// Next returns the next number of the sequence the fake values are based on
+func Next() int64 {
+	return sequence.Add(1)
+}
+
+// String returns a fake value for the string field with the given name, e.g. "title-42", or "email-42@example.com"
+// for fields whose name contains "email"
+func String(field string) string {
+	n := strconv.FormatInt(Next(), 10)
+	if strings.Contains(strings.ToLower(field), "email") {
+		return field + "-" + n + "@example.com"
+	}
+	return field + "-" + n
+}
+
+// Int returns a fake value for an Int field
+func Int() int {
+	return int(Next())
+}
+
+// Float returns a fake value for a Float field
+func Float() float64 {
+	return float64(Next())
+}
+
+// BigInt returns a fake value for a BigInt field
+func BigInt() types.BigInt {
+	return types.BigInt(Next())
+}
+
+// Decimal returns a fake value for a Decimal field
+func Decimal() types.Decimal {
+	return decimal.NewFromInt(Next())
+}
+
+// Boolean returns a fake value for a Boolean field
+func Boolean() bool {
+	return false
+}
+
+// DateTime returns a fake value for a DateTime field, which is the current time in millisecond precision
+func DateTime() types.DateTime {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// JSON returns a fake value for a Json field, which is an empty object
+func JSON() types.JSON {
+	return types.JSON("{}")
+}
+
+// Bytes returns a fake value for a Bytes field
+func Bytes() types.Bytes {
+	return types.Bytes(strconv.FormatInt(Next(), 10))
+}
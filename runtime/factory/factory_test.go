@@ -0,0 +1,20 @@
+package factory
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestString(t *testing.T) {
+	massert.Equal(t, true, regexp.MustCompile(`^title-\d+$`).MatchString(String("title")))
+	massert.Equal(t, true, regexp.MustCompile(`^contactEmail-\d+@example\.com$`).MatchString(String("contactEmail")))
+	massert.Equal(t, false, String("title") == String("title"))
+}
+
+func TestNext(t *testing.T) {
+	a := Next()
+	massert.Equal(t, a+1, Next())
+	massert.Equal(t, true, a < 1<<31)
+}
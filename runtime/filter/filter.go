@@ -0,0 +1,272 @@
+// Package filter builds where-clauses at runtime from field names, operators and values, e.g. of generic admin or
+// search endpoints which can't enumerate their filters at compile time. Fields, operators and values are validated
+// against the Prisma schema, so that arbitrary request parameters can't build invalid or unintended queries.
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+)
+
+// ErrInvalidFilter is returned when a condition refers to an unknown field, uses an operator which the field doesn't
+// support, or has a value which doesn't match the type of the field
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Operator compares the value of a field to the value of a condition
+type Operator string
+
+// Operator values, which are named like the filters of the generated client
+const (
+	Equals     Operator = "equals"
+	Not        Operator = "not"
+	In         Operator = "in"
+	NotIn      Operator = "notIn"
+	Lt         Operator = "lt"
+	Lte        Operator = "lte"
+	Gt         Operator = "gt"
+	Gte        Operator = "gte"
+	Contains   Operator = "contains"
+	StartsWith Operator = "startsWith"
+	EndsWith   Operator = "endsWith"
+)
+
+// Condition is a filter of a single field, e.g. {"field": "age", "operator": "gte", "value": 18}
+type Condition struct {
+	// Field is the name of the field in the Prisma schema
+	Field    string      `json:"field"`
+	Operator Operator    `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+var (
+	equality = []Operator{Equals, Not, In, NotIn}
+	ordered  = []Operator{Equals, Not, In, NotIn, Lt, Lte, Gt, Gte}
+	text     = []Operator{Equals, Not, In, NotIn, Lt, Lte, Gt, Gte, Contains, StartsWith, EndsWith}
+)
+
+// operators contains the operators supported by each scalar type
+var operators = map[string][]Operator{
+	"String":   text,
+	"Int":      ordered,
+	"BigInt":   ordered,
+	"Float":    ordered,
+	"Decimal":  ordered,
+	"DateTime": ordered,
+	"Boolean":  {Equals, Not},
+}
+
+// Build returns a filter of the records of the given model in schema which match all conditions. It returns an error
+// wrapping ErrInvalidFilter if a condition doesn't match the schema. Values are converted to the type of the field,
+// e.g. numbers and RFC 3339 timestamps given as strings, so that request parameters can be passed as they are.
+func Build(schema *metadata.Schema, model string, conditions []Condition) (builder.Field, error) {
+	m, ok := schema.Model(model)
+	if !ok {
+		return builder.Field{}, fmt.Errorf("filter: unknown model %s", model)
+	}
+
+	// each condition is a separate item, as conditions of the same field would be joined within an object otherwise
+	items := make([]builder.Field, len(conditions))
+	for i, c := range conditions {
+		field, err := build(schema, m, c)
+		if err != nil {
+			return builder.Field{}, err
+		}
+		items[i] = builder.Field{Fields: []builder.Field{field}}
+	}
+
+	return builder.Field{
+		Name:   "AND",
+		List:   true,
+		Fields: items,
+	}, nil
+}
+
+func build(schema *metadata.Schema, model *metadata.Model, c Condition) (builder.Field, error) {
+	field, ok := model.Field(c.Field)
+	if !ok {
+		return builder.Field{}, fmt.Errorf("%w: unknown field %q of model %s", ErrInvalidFilter, c.Field, model.Name)
+	}
+	if !slices.Contains(supported(field), c.Operator) {
+		return builder.Field{}, fmt.Errorf("%w: operator %q is not supported by field %q", ErrInvalidFilter, c.Operator, c.Field)
+	}
+
+	var value interface{}
+	var err error
+	switch {
+	case c.Operator == In || c.Operator == NotIn:
+		value, err = convertList(schema, field, c.Value)
+	case c.Value == nil:
+		// null checks are only possible for optional fields
+		if field.IsRequired || (c.Operator != Equals && c.Operator != Not) {
+			err = fmt.Errorf("value must not be null")
+		}
+		value = (*string)(nil)
+	default:
+		value, err = convert(schema, field, c.Value)
+	}
+	if err != nil {
+		return builder.Field{}, fmt.Errorf("%w: field %q: %s", ErrInvalidFilter, c.Field, err)
+	}
+
+	return builder.Field{
+		Name: field.Name,
+		Fields: []builder.Field{{
+			Name:  string(c.Operator),
+			Value: value,
+		}},
+	}, nil
+}
+
+// supported returns the operators of a field; relations, composite types, lists and Json and Bytes fields can't be
+// filtered by
+func supported(field *metadata.Field) []Operator {
+	if field.IsList {
+		return nil
+	}
+	switch field.Kind {
+	case metadata.FieldKindEnum:
+		return equality
+	case metadata.FieldKindScalar:
+		return operators[field.Type]
+	default:
+		return nil
+	}
+}
+
+func convertList(schema *metadata.Schema, field *metadata.Field, value interface{}) (interface{}, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("value must be a list")
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		item, err := convert(schema, field, v.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		values[i] = item
+	}
+	return values, nil
+}
+
+// convert returns the value as the type of the field
+func convert(schema *metadata.Schema, field *metadata.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, fmt.Errorf("value must not be null")
+	}
+	if field.Kind == metadata.FieldKindEnum {
+		s, ok := value.(string)
+		enum, _ := schema.Enum(field.Type)
+		if !ok || enum == nil || !slices.Contains(enum.Values, s) {
+			return nil, fmt.Errorf("%v is not a value of enum %s", value, field.Type)
+		}
+		return s, nil
+	}
+
+	switch field.Type {
+	case "String":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("%v is not a string", value)
+	case "Int", "BigInt":
+		switch v := value.(type) {
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i, nil
+			}
+			return nil, fmt.Errorf("%v is not an integer", value)
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return i, nil
+			}
+			return nil, fmt.Errorf("%v is not an integer", value)
+		}
+		f, err := number(value)
+		if err != nil || f != math.Trunc(f) {
+			return nil, fmt.Errorf("%v is not an integer", value)
+		}
+		return int64(f), nil
+	case "Float":
+		f, err := number(value)
+		if err != nil {
+			return nil, fmt.Errorf("%v is not a number", value)
+		}
+		return f, nil
+	case "Decimal":
+		// decimals are sent as strings to keep their precision
+		if s, ok := value.(string); ok {
+			if _, err := number(s); err != nil {
+				return nil, fmt.Errorf("%v is not a number", value)
+			}
+			return s, nil
+		}
+		f, err := number(value)
+		if err != nil {
+			return nil, fmt.Errorf("%v is not a number", value)
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	case "Boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not a boolean", value)
+	case "DateTime":
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not an RFC 3339 timestamp", value)
+	default:
+		return nil, fmt.Errorf("fields of type %s can't be filtered by", field.Type)
+	}
+}
+
+// number returns a finite numeric value, which may also be given as a string, as float64
+func number(value interface{}) (float64, error) {
+	var f float64
+	var err error
+	switch v := value.(type) {
+	case string:
+		f, err = strconv.ParseFloat(v, 64)
+	case json.Number:
+		f, err = v.Float64()
+	default:
+		rv := reflect.ValueOf(value)
+		switch {
+		case rv.CanInt():
+			f = float64(rv.Int())
+		case rv.CanUint():
+			f = float64(rv.Uint())
+		case rv.CanFloat():
+			f = rv.Float()
+		default:
+			err = fmt.Errorf("%v is not a number", value)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%v is not a finite number", value)
+	}
+	return f, nil
+}
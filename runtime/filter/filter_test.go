@@ -0,0 +1,165 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+var schema = &metadata.Schema{
+	Models: []metadata.Model{{
+		Name: "User",
+		Fields: []metadata.Field{
+			{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true, IsID: true},
+			{Name: "email", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true},
+			{Name: "bio", Kind: metadata.FieldKindScalar, Type: "String"},
+			{Name: "age", Kind: metadata.FieldKindScalar, Type: "Int", IsRequired: true},
+			{Name: "score", Kind: metadata.FieldKindScalar, Type: "Decimal", IsRequired: true},
+			{Name: "active", Kind: metadata.FieldKindScalar, Type: "Boolean", IsRequired: true},
+			{Name: "createdAt", Kind: metadata.FieldKindScalar, Type: "DateTime", IsRequired: true},
+			{Name: "role", Kind: metadata.FieldKindEnum, Type: "Role", IsRequired: true},
+			{Name: "meta", Kind: metadata.FieldKindScalar, Type: "Json"},
+			{Name: "tags", Kind: metadata.FieldKindScalar, Type: "String", IsList: true},
+			{Name: "posts", Kind: metadata.FieldKindRelation, Type: "Post", IsList: true},
+		},
+	}},
+	Enums: []metadata.Enum{{Name: "Role", Values: []string{"USER", "ADMIN"}}},
+}
+
+func condition(field, operator string, value interface{}) builder.Field {
+	return builder.Field{
+		Fields: []builder.Field{{
+			Name:   field,
+			Fields: []builder.Field{{Name: operator, Value: value}},
+		}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	var conditions []Condition
+	if err := json.Unmarshal([]byte(`[
+		{"field": "email", "operator": "endsWith", "value": "@example.com"},
+		{"field": "age", "operator": "gte", "value": 18},
+		{"field": "age", "operator": "lt", "value": "65"},
+		{"field": "score", "operator": "gt", "value": 1.5},
+		{"field": "active", "operator": "equals", "value": "true"},
+		{"field": "createdAt", "operator": "lt", "value": "2020-01-02T03:04:05Z"},
+		{"field": "role", "operator": "in", "value": ["USER", "ADMIN"]},
+		{"field": "bio", "operator": "not", "value": null}
+	]`), &conditions); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := Build(schema, "User", conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, builder.Field{
+		Name: "AND",
+		List: true,
+		Fields: []builder.Field{
+			condition("email", "endsWith", "@example.com"),
+			condition("age", "gte", int64(18)),
+			condition("age", "lt", int64(65)),
+			condition("score", "gt", "1.5"),
+			condition("active", "equals", true),
+			condition("createdAt", "lt", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+			condition("role", "in", []interface{}{"USER", "ADMIN"}),
+			condition("bio", "not", (*string)(nil)),
+		},
+	}, actual)
+
+	q := builder.NewQuery()
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Inputs = []builder.Input{{Name: "where", Fields: []builder.Field{actual}}}
+	q.Outputs = []builder.Output{{Name: "id"}}
+	str, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `query {result: findManyUser(where:{AND:[{email:{endsWith:"@example.com",},},{age:{gte:18,},},{age:{lt:65,},},{score:{gt:"1.5",},},{active:{equals:true,},},{createdAt:{lt:"2020-01-02T03:04:05Z",},},{role:{in:["USER","ADMIN"],},},{bio:{not:null,},},],},) {id }}`, str)
+}
+
+func TestBuild_invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition Condition
+		expected  string
+	}{{
+		name:      "unknown field",
+		condition: Condition{Field: "password", Operator: Equals, Value: "a"},
+		expected:  `invalid filter: unknown field "password" of model User`,
+	}, {
+		name:      "unknown operator",
+		condition: Condition{Field: "email", Operator: "like", Value: "a"},
+		expected:  `invalid filter: operator "like" is not supported by field "email"`,
+	}, {
+		name:      "operator of another type",
+		condition: Condition{Field: "age", Operator: Contains, Value: 1},
+		expected:  `invalid filter: operator "contains" is not supported by field "age"`,
+	}, {
+		name:      "relation",
+		condition: Condition{Field: "posts", Operator: Equals, Value: "a"},
+		expected:  `invalid filter: operator "equals" is not supported by field "posts"`,
+	}, {
+		name:      "list",
+		condition: Condition{Field: "tags", Operator: Equals, Value: "a"},
+		expected:  `invalid filter: operator "equals" is not supported by field "tags"`,
+	}, {
+		name:      "json",
+		condition: Condition{Field: "meta", Operator: Equals, Value: "a"},
+		expected:  `invalid filter: operator "equals" is not supported by field "meta"`,
+	}, {
+		name:      "wrong type",
+		condition: Condition{Field: "email", Operator: Equals, Value: 1},
+		expected:  `invalid filter: field "email": 1 is not a string`,
+	}, {
+		name:      "fraction",
+		condition: Condition{Field: "age", Operator: Equals, Value: 1.5},
+		expected:  `invalid filter: field "age": 1.5 is not an integer`,
+	}, {
+		name:      "not a number",
+		condition: Condition{Field: "score", Operator: Equals, Value: "NaN"},
+		expected:  `invalid filter: field "score": NaN is not a number`,
+	}, {
+		name:      "enum",
+		condition: Condition{Field: "role", Operator: Equals, Value: "ROOT"},
+		expected:  `invalid filter: field "role": ROOT is not a value of enum Role`,
+	}, {
+		name:      "timestamp",
+		condition: Condition{Field: "createdAt", Operator: Gt, Value: "yesterday"},
+		expected:  `invalid filter: field "createdAt": yesterday is not an RFC 3339 timestamp`,
+	}, {
+		name:      "null of a required field",
+		condition: Condition{Field: "email", Operator: Equals, Value: nil},
+		expected:  `invalid filter: field "email": value must not be null`,
+	}, {
+		name:      "null comparison",
+		condition: Condition{Field: "bio", Operator: Gt, Value: nil},
+		expected:  `invalid filter: field "bio": value must not be null`,
+	}, {
+		name:      "in without a list",
+		condition: Condition{Field: "email", Operator: In, Value: "a"},
+		expected:  `invalid filter: field "email": value must be a list`,
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(schema, "User", []Condition{tt.condition})
+			massert.Equal(t, true, errors.Is(err, ErrInvalidFilter))
+			massert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestBuild_unknownModel(t *testing.T) {
+	_, err := Build(schema, "Post", nil)
+	massert.Equal(t, "filter: unknown model Post", err.Error())
+}
@@ -1,11 +1,21 @@
 package lifecycle
 
 import (
+	"context"
+
 	"github.com/steebchen/prisma-client-go/engine"
 )
 
 type Lifecycle struct {
 	Engine engine.Engine
+
+	// BeforeConnect is called before the engine is connected, e.g. to verify the version of the generated code. If it
+	// returns an error, the engine is not connected and Connect returns the error.
+	BeforeConnect func() error
+
+	// OnConnect is called after the engine was connected, e.g. to verify the database schema. If it returns an error,
+	// the engine is disconnected again and Connect returns the error.
+	OnConnect func() error
 }
 
 // Connect connects to the Prisma query engine. Required to call before accessing data.
@@ -23,7 +33,24 @@ type Lifecycle struct {
 //	  }
 //	}()
 func (c *Lifecycle) Connect() error {
-	return c.Engine.Connect()
+	if c.BeforeConnect != nil {
+		if err := c.BeforeConnect(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Engine.Connect(); err != nil {
+		return err
+	}
+
+	if c.OnConnect != nil {
+		if err := c.OnConnect(); err != nil {
+			_ = c.Engine.Disconnect()
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Disconnect disconnects from the Prisma query engine.
@@ -42,3 +69,26 @@ func (c *Lifecycle) Connect() error {
 func (c *Lifecycle) Disconnect() error {
 	return c.Engine.Disconnect()
 }
+
+// contextDisconnecter is implemented by engines which support a graceful shutdown
+type contextDisconnecter interface {
+	DisconnectContext(ctx context.Context) error
+}
+
+// DisconnectContext gracefully disconnects from the Prisma query engine. New queries are rejected immediately, while
+// in-flight queries may finish until ctx is done. The query engine is then asked to shut down, and is killed if it
+// doesn't exit until ctx is done.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := client.Prisma.DisconnectContext(ctx); err != nil {
+//	  handle(err)
+//	}
+func (c *Lifecycle) DisconnectContext(ctx context.Context) error {
+	if e, ok := c.Engine.(contextDisconnecter); ok {
+		return e.DisconnectContext(ctx)
+	}
+	return c.Engine.Disconnect()
+}
@@ -0,0 +1,166 @@
+// Package metadata describes the Prisma schema a client was generated from, so that generic tooling such as admin UIs
+// or validation layers can be built without parsing the schema file.
+package metadata
+
+// FieldKind describes whether a field is a scalar, an enum, a relation or a composite type
+type FieldKind string
+
+// FieldKind values
+const (
+	FieldKindScalar    FieldKind = "scalar"
+	FieldKindEnum      FieldKind = "enum"
+	FieldKindRelation  FieldKind = "relation"
+	FieldKindComposite FieldKind = "composite"
+)
+
+// Schema describes the models, composite types and enums of a Prisma schema
+type Schema struct {
+	// Provider is the datasource provider, e.g. "postgresql"
+	Provider string
+
+	Models []Model
+
+	// Types contains composite types, which are only supported by MongoDB
+	Types []Model
+
+	Enums []Enum
+}
+
+// Model describes a model, which usually maps to a database table or collection
+type Model struct {
+	// Name is the name of the model in the Prisma schema
+	Name string
+
+	// DBName is the name of the table or collection in the database
+	DBName string
+
+	Fields []Field
+
+	// PrimaryKey contains the names of the fields of a compound primary key; fields of a single-field primary key
+	// have IsID set instead
+	PrimaryKey []string
+
+	// UniqueIndexes contains the field names of compound unique indexes
+	UniqueIndexes [][]string
+
+	// IsView is set for database views, which the client only reads
+	IsView bool
+}
+
+// Field describes a field of a model or composite type
+type Field struct {
+	// Name is the name of the field in the Prisma schema
+	Name string
+
+	// DBName is the name of the column or document field in the database
+	DBName string
+
+	Kind FieldKind
+
+	// Type is the Prisma type of the field, e.g. "String", the name of an enum or the name of the related model
+	Type string
+
+	IsList          bool
+	IsRequired      bool
+	IsUnique        bool
+	IsID            bool
+	IsUpdatedAt     bool
+	HasDefaultValue bool
+
+	// IsVersion is set for the field selected with a `/// @go.version` comment, which is bumped on each update for
+	// optimistic locking
+	IsVersion bool
+
+	// MaxLength is the maximum number of characters of a String field with a native type such as @db.VarChar(50),
+	// or 0 if the length is not limited
+	MaxLength int
+
+	// RelationName is the name of the relation, if the field is a relation
+	RelationName string
+
+	// RelationFromFields contains the fields of this model which reference the related model
+	RelationFromFields []string
+
+	// RelationToFields contains the referenced fields of the related model
+	RelationToFields []string
+
+	// Documentation contains the content of triple-slash comments
+	Documentation string
+}
+
+// Enum describes an enum and its values
+type Enum struct {
+	Name   string
+	Values []string
+}
+
+// Model returns the model with the given name
+func (s *Schema) Model(name string) (*Model, bool) {
+	for i := range s.Models {
+		if s.Models[i].Name == name {
+			return &s.Models[i], true
+		}
+	}
+	return nil, false
+}
+
+// Enum returns the enum with the given name
+func (s *Schema) Enum(name string) (*Enum, bool) {
+	for i := range s.Enums {
+		if s.Enums[i].Name == name {
+			return &s.Enums[i], true
+		}
+	}
+	return nil, false
+}
+
+// Field returns the field with the given name
+func (m *Model) Field(name string) (*Field, bool) {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// IDFields returns the fields of the primary key
+func (m *Model) IDFields() []Field {
+	var fields []Field
+	for _, f := range m.Fields {
+		if f.IsID {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	for _, name := range m.PrimaryKey {
+		if f, ok := m.Field(name); ok {
+			fields = append(fields, *f)
+		}
+	}
+	return fields
+}
+
+// ScalarFields returns the fields which are stored in the model's table, i.e. scalars and enums
+func (m *Model) ScalarFields() []Field {
+	var fields []Field
+	for _, f := range m.Fields {
+		if f.Kind == FieldKindScalar || f.Kind == FieldKindEnum {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Relations returns the relation fields
+func (m *Model) Relations() []Field {
+	var fields []Field
+	for _, f := range m.Fields {
+		if f.Kind == FieldKindRelation {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
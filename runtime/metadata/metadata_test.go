@@ -0,0 +1,63 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+var testSchema = Schema{
+	Provider: "postgresql",
+	Models: []Model{{
+		Name:   "User",
+		DBName: "users",
+		Fields: []Field{
+			{Name: "id", DBName: "id", Kind: FieldKindScalar, Type: "String", IsID: true, IsRequired: true},
+			{Name: "role", DBName: "role", Kind: FieldKindEnum, Type: "Role", IsRequired: true},
+			{Name: "posts", Kind: FieldKindRelation, Type: "Post", IsList: true, RelationName: "PostToUser"},
+		},
+	}, {
+		Name: "Membership",
+		Fields: []Field{
+			{Name: "userId", Kind: FieldKindScalar, Type: "String"},
+			{Name: "orgId", Kind: FieldKindScalar, Type: "String"},
+		},
+		PrimaryKey: []string{"userId", "orgId"},
+	}},
+	Enums: []Enum{{Name: "Role", Values: []string{"USER", "ADMIN"}}},
+}
+
+func TestSchema_lookup(t *testing.T) {
+	user, ok := testSchema.Model("User")
+	massert.Equal(t, true, ok)
+	massert.Equal(t, "users", user.DBName)
+
+	_, ok = testSchema.Model("Unknown")
+	massert.Equal(t, false, ok)
+
+	role, ok := user.Field("role")
+	massert.Equal(t, true, ok)
+	massert.Equal(t, FieldKindEnum, role.Kind)
+
+	enum, ok := testSchema.Enum(role.Type)
+	massert.Equal(t, true, ok)
+	massert.Equal(t, []string{"USER", "ADMIN"}, enum.Values)
+}
+
+func TestModel_fields(t *testing.T) {
+	user, _ := testSchema.Model("User")
+	massert.Equal(t, []string{"id"}, names(user.IDFields()))
+	massert.Equal(t, []string{"id", "role"}, names(user.ScalarFields()))
+	massert.Equal(t, []string{"posts"}, names(user.Relations()))
+
+	membership, _ := testSchema.Model("Membership")
+	massert.Equal(t, []string{"userId", "orgId"}, names(membership.IDFields()))
+}
+
+func names(fields []Field) []string {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
@@ -0,0 +1,349 @@
+// Package pool manages one connected client per tenant, e.g. for database-per-tenant applications.
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// ErrClosed is returned when acquiring a client from a closed pool
+var ErrClosed = errors.New("client pool is closed")
+
+// Client is a client which can be connected and disconnected, usually a generated *PrismaClient
+type Client interface {
+	Connect() error
+	Disconnect() error
+}
+
+// Config configures the limits of a pool
+type Config struct {
+	// MaxOpen is the maximum number of connected clients. When the limit is reached, the least recently used idle
+	// client is disconnected to make room for a new one; if all clients are in use, Acquire waits until one is
+	// released. 0 means unlimited.
+	MaxOpen int
+
+	// IdleTimeout disconnects clients which were not used for the given duration. 0 disables idle eviction.
+	IdleTimeout time.Duration
+}
+
+// Stats contains metrics of a pool
+type Stats struct {
+	// Open is the number of clients which are connected or connecting
+	Open int
+
+	// InUse is the number of clients which are currently acquired
+	InUse int
+
+	// Idle is the number of connected clients which are currently not acquired
+	Idle int
+
+	// Hits is the total number of times an existing client was acquired
+	Hits uint64
+
+	// Misses is the total number of times a new client had to be created
+	Misses uint64
+
+	// ConnectErrors is the total number of clients which failed to connect
+	ConnectErrors uint64
+
+	// IdleEvictions is the total number of clients which were disconnected because of IdleTimeout
+	IdleEvictions uint64
+
+	// MaxOpenEvictions is the total number of clients which were disconnected because of MaxOpen
+	MaxOpenEvictions uint64
+
+	// WaitCount is the total number of times Acquire had to wait for a client to be released
+	WaitCount uint64
+
+	// WaitDuration is the total time spent waiting for a client to be released
+	WaitDuration time.Duration
+}
+
+type entry[C Client] struct {
+	key    string
+	client C
+
+	// ready is closed when connecting finished; err holds the result and must only be read after ready is closed
+	ready chan struct{}
+	err   error
+
+	refs     int
+	lastUsed time.Time
+}
+
+// Pool lazily creates, connects and caches one client per key, usually the datasource url of a tenant.
+// Clients are acquired with Acquire or Do and are shared between all callers using the same key.
+type Pool[C Client] struct {
+	config Config
+	create func(key string) C
+
+	mu      sync.Mutex
+	entries map[string]*entry[C]
+	stats   Stats
+	closed  bool
+
+	// changed is closed and replaced whenever a client is released or removed, to wake up waiting callers
+	changed chan struct{}
+
+	// stop stops the idle eviction
+	stop chan struct{}
+}
+
+// New creates a pool which uses create to create a new client for a key
+func New[C Client](config Config, create func(key string) C) *Pool[C] {
+	p := &Pool[C]{
+		config:  config,
+		create:  create,
+		entries: make(map[string]*entry[C]),
+		changed: make(chan struct{}),
+		stop:    make(chan struct{}),
+	}
+
+	if config.IdleTimeout > 0 {
+		go p.evictIdle()
+	}
+
+	return p
+}
+
+// Acquire returns the connected client for the given key, creating and connecting it if necessary.
+// release must be called when the client is not used anymore, so that it can be evicted.
+func (p *Pool[C]) Acquire(ctx context.Context, key string) (client C, release func(), err error) {
+	var waitStart time.Time
+
+	for {
+		p.mu.Lock()
+
+		if p.closed {
+			p.mu.Unlock()
+			return client, nil, ErrClosed
+		}
+
+		if e, ok := p.entries[key]; ok {
+			e.refs++
+			p.stats.Hits++
+			p.recordWait(waitStart)
+			p.mu.Unlock()
+			return p.await(ctx, e)
+		}
+
+		var victim *entry[C]
+		if p.config.MaxOpen > 0 && len(p.entries) >= p.config.MaxOpen {
+			victim = p.leastRecentlyUsedIdle()
+			if victim == nil {
+				// all clients are in use; wait until one is released
+				if waitStart.IsZero() {
+					waitStart = time.Now()
+					p.stats.WaitCount++
+				}
+				changed := p.changed
+				p.mu.Unlock()
+
+				select {
+				case <-changed:
+					continue
+				case <-ctx.Done():
+					p.mu.Lock()
+					p.recordWait(waitStart)
+					p.mu.Unlock()
+					return client, nil, ctx.Err()
+				}
+			}
+			delete(p.entries, victim.key)
+			p.stats.MaxOpenEvictions++
+		}
+
+		e := &entry[C]{
+			key:    key,
+			client: p.create(key),
+			ready:  make(chan struct{}),
+			refs:   1,
+		}
+		p.entries[key] = e
+		p.stats.Misses++
+		p.recordWait(waitStart)
+		p.mu.Unlock()
+
+		if victim != nil {
+			disconnect(victim)
+		}
+
+		e.err = e.client.Connect()
+		if e.err != nil {
+			e.err = fmt.Errorf("connect client: %w", e.err)
+		}
+		close(e.ready)
+
+		return p.await(ctx, e)
+	}
+}
+
+// Do acquires the client for the given key, calls fn with it and releases it afterwards
+func (p *Pool[C]) Do(ctx context.Context, key string, fn func(client C) error) error {
+	client, release, err := p.Acquire(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn(client)
+}
+
+// Stats returns the current metrics of the pool
+func (p *Pool[C]) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Open = len(p.entries)
+	for _, e := range p.entries {
+		if e.refs > 0 {
+			stats.InUse++
+		} else {
+			stats.Idle++
+		}
+	}
+	return stats
+}
+
+// Close disconnects all clients. Clients which are still acquired are disconnected as well.
+func (p *Pool[C]) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.stop)
+
+	entries := make([]*entry[C], 0, len(p.entries))
+	for _, e := range p.entries {
+		entries = append(entries, e)
+	}
+	p.entries = make(map[string]*entry[C])
+	p.notify()
+	p.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		<-e.ready
+		if e.err != nil {
+			continue
+		}
+		if err := e.client.Disconnect(); err != nil {
+			errs = append(errs, fmt.Errorf("disconnect client: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// await waits until the client of the entry is connected
+func (p *Pool[C]) await(ctx context.Context, e *entry[C]) (client C, release func(), err error) {
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			p.mu.Lock()
+			e.refs--
+			e.lastUsed = time.Now()
+			p.notify()
+			p.mu.Unlock()
+		})
+	}
+
+	select {
+	case <-e.ready:
+	case <-ctx.Done():
+		release()
+		return client, nil, ctx.Err()
+	}
+
+	if e.err != nil {
+		p.mu.Lock()
+		// only the first caller removes the entry, so that the next call tries to connect again
+		if p.entries[e.key] == e {
+			delete(p.entries, e.key)
+			p.stats.ConnectErrors++
+		}
+		p.mu.Unlock()
+		release()
+		return client, nil, e.err
+	}
+
+	return e.client, release, nil
+}
+
+// leastRecentlyUsedIdle returns the idle entry which was not used for the longest time, or nil if all are in use.
+// Idle entries are always connected, as the entry is acquired while connecting.
+func (p *Pool[C]) leastRecentlyUsedIdle() *entry[C] {
+	var victim *entry[C]
+	for _, e := range p.entries {
+		if e.refs > 0 {
+			continue
+		}
+		if victim == nil || e.lastUsed.Before(victim.lastUsed) {
+			victim = e
+		}
+	}
+	return victim
+}
+
+func (p *Pool[C]) evictIdle() {
+	interval := p.config.IdleTimeout / 2
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		var idle []*entry[C]
+
+		p.mu.Lock()
+		for key, e := range p.entries {
+			if e.refs == 0 && time.Since(e.lastUsed) >= p.config.IdleTimeout {
+				idle = append(idle, e)
+				delete(p.entries, key)
+				p.stats.IdleEvictions++
+			}
+		}
+		if len(idle) > 0 {
+			p.notify()
+		}
+		p.mu.Unlock()
+
+		for _, e := range idle {
+			disconnect(e)
+		}
+	}
+}
+
+// notify wakes up callers waiting for a client to be released; p.mu must be held
+func (p *Pool[C]) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// recordWait adds the time spent waiting to the stats; p.mu must be held
+func (p *Pool[C]) recordWait(start time.Time) {
+	if !start.IsZero() {
+		p.stats.WaitDuration += time.Since(start)
+	}
+}
+
+// disconnect disconnects an evicted client; the key is not logged, as it usually is a connection string
+func disconnect[C Client](e *entry[C]) {
+	logger.Debug.Printf("disconnecting pooled client")
+	if err := e.client.Disconnect(); err != nil {
+		logger.Info.Printf("could not disconnect pooled client: %s", err)
+	}
+}
@@ -0,0 +1,284 @@
+// Package queries provides a registry of named, parameterized queries, which are registered at startup and executed
+// by name with bound values, e.g. from admin tooling. Only registered query shapes can be executed, while the values
+// are validated and decoded into the declared parameter types.
+package queries
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+)
+
+// ErrNotFound is returned when no query was registered with the given name
+var ErrNotFound = errors.New("named query not found")
+
+// Query is a query of a generated client which was built but not executed yet, e.g. client.User.FindMany(...)
+type Query interface {
+	ExtractQuery() builder.Query
+}
+
+// Parameter is a parameter of a named query, which is declared with NewParam
+type Parameter interface {
+	// Name returns the name the value is bound to
+	Name() string
+	// Required reports whether a value must be bound
+	Required() bool
+	// decode converts a bound value to the type of the parameter
+	decode(value interface{}) (interface{}, error)
+	// defaultValue returns the value which is used if no value is bound
+	defaultValue() interface{}
+}
+
+// Param is a typed parameter of a named query
+type Param[T any] struct {
+	name     string
+	optional bool
+	fallback T
+}
+
+// NewParam declares a required parameter with the given name. Its value is accessed with Get when building the query.
+func NewParam[T any](name string) Param[T] {
+	return Param[T]{name: name}
+}
+
+// Default makes the parameter optional; value is used if no value is bound
+func (p Param[T]) Default(value T) Param[T] {
+	p.optional = true
+	p.fallback = value
+	return p
+}
+
+// Name returns the name the value is bound to
+func (p Param[T]) Name() string {
+	return p.name
+}
+
+// Required reports whether a value must be bound
+func (p Param[T]) Required() bool {
+	return !p.optional
+}
+
+// Get returns the value bound to the parameter. It panics if the parameter was not declared when registering the
+// query.
+func (p Param[T]) Get(args Args) T {
+	value, ok := args.values[p.name]
+	if !ok {
+		panic(fmt.Sprintf("parameter %q was not declared", p.name))
+	}
+	return value.(T)
+}
+
+func (p Param[T]) defaultValue() interface{} {
+	return p.fallback
+}
+
+func (p Param[T]) decode(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case T:
+		return v, nil
+	case json.RawMessage:
+		return p.unmarshal(v)
+	case []byte:
+		return p.unmarshal(v)
+	}
+	// convert values of a different type, e.g. float64 values decoded from JSON for int parameters
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s: %w", p.name, err)
+	}
+	return p.unmarshal(raw)
+}
+
+func (p Param[T]) unmarshal(raw []byte) (interface{}, error) {
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("parameter %s: %w", p.name, err)
+	}
+	return value, nil
+}
+
+// Args contains the decoded values bound to the parameters of a named query
+type Args struct {
+	values map[string]interface{}
+}
+
+// BuildFunc builds a named query from the bound values
+type BuildFunc func(args Args) (Query, error)
+
+type entry struct {
+	build  BuildFunc
+	params []Parameter
+}
+
+// Registry contains named queries. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	queries map[string]entry
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		queries: make(map[string]entry),
+	}
+}
+
+// Register adds a named query with the given parameters. build is called for each execution with the bound values.
+//
+// Example:
+//
+//	email := queries.NewParam[string]("email")
+//	take := queries.NewParam[int]("take").Default(10)
+//	registry.Register("usersByEmail", func(args queries.Args) (queries.Query, error) {
+//	  return client.User.FindMany(db.User.Email.Contains(email.Get(args))).Take(take.Get(args)), nil
+//	}, email, take)
+func (r *Registry) Register(name string, build BuildFunc, params ...Parameter) error {
+	seen := make(map[string]bool, len(params))
+	for _, param := range params {
+		if seen[param.Name()] {
+			return fmt.Errorf("named query %s: duplicate parameter %s", name, param.Name())
+		}
+		seen[param.Name()] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.queries[name]; ok {
+		return fmt.Errorf("named query %s is already registered", name)
+	}
+	r.queries[name] = entry{
+		build:  build,
+		params: params,
+	}
+	return nil
+}
+
+// MustRegister is like Register, but panics on error
+func (r *Registry) MustRegister(name string, build BuildFunc, params ...Parameter) {
+	if err := r.Register(name, build, params...); err != nil {
+		panic(err)
+	}
+}
+
+// Names returns the names of all registered queries, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.queries))
+	for name := range r.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Params returns the parameters of a named query
+func (r *Registry) Params(name string) ([]Parameter, error) {
+	e, err := r.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.params, nil
+}
+
+func (r *Registry) lookup(name string) (entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.queries[name]
+	if !ok {
+		return entry{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return e, nil
+}
+
+// Build binds values to the parameters of a named query and builds it. Values may be Go values of the parameter type
+// or their JSON representation as json.RawMessage. Unknown and missing required parameters return an error.
+// The built query can be serialized with client.Prisma.MarshalQuery, e.g. to execute it later.
+func (r *Registry) Build(name string, values map[string]interface{}) (builder.Query, error) {
+	e, err := r.lookup(name)
+	if err != nil {
+		return builder.Query{}, err
+	}
+
+	declared := make(map[string]bool, len(e.params))
+	args := Args{values: make(map[string]interface{}, len(e.params))}
+	for _, param := range e.params {
+		declared[param.Name()] = true
+		value, ok := values[param.Name()]
+		if !ok {
+			if param.Required() {
+				return builder.Query{}, fmt.Errorf("named query %s: missing parameter %s", name, param.Name())
+			}
+			value = param.defaultValue()
+		} else {
+			value, err = param.decode(value)
+			if err != nil {
+				return builder.Query{}, fmt.Errorf("named query %s: %w", name, err)
+			}
+		}
+		args.values[param.Name()] = value
+	}
+	for key := range values {
+		if !declared[key] {
+			return builder.Query{}, fmt.Errorf("named query %s: unknown parameter %s", name, key)
+		}
+	}
+
+	query, err := e.build(args)
+	if err != nil {
+		return builder.Query{}, fmt.Errorf("named query %s: %w", name, err)
+	}
+	return query.ExtractQuery(), nil
+}
+
+// BuildJSON is like Build, but reads the values from a JSON object, e.g. the body of an admin API request
+func (r *Registry) BuildJSON(name string, data []byte) (builder.Query, error) {
+	values, err := unmarshalValues(data)
+	if err != nil {
+		return builder.Query{}, fmt.Errorf("named query %s: %w", name, err)
+	}
+	return r.Build(name, values)
+}
+
+// Exec builds a named query and executes it, decoding the result into into
+//
+// Example:
+//
+//	var users []db.UserModel
+//	err := registry.Exec(ctx, "usersByEmail", map[string]interface{}{"email": "@example.com"}, &users)
+func (r *Registry) Exec(ctx context.Context, name string, values map[string]interface{}, into interface{}) error {
+	query, err := r.Build(name, values)
+	if err != nil {
+		return err
+	}
+	return query.Exec(ctx, into)
+}
+
+// ExecJSON is like Exec, but reads the values from a JSON object
+func (r *Registry) ExecJSON(ctx context.Context, name string, data []byte, into interface{}) error {
+	query, err := r.BuildJSON(name, data)
+	if err != nil {
+		return err
+	}
+	return query.Exec(ctx, into)
+}
+
+func unmarshalValues(data []byte) (map[string]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal values: %w", err)
+	}
+	values := make(map[string]interface{}, len(raw))
+	for key, value := range raw {
+		values[key] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,123 @@
+package queries
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func usersByEmail() *Registry {
+	email := NewParam[string]("email")
+	take := NewParam[int]("take").Default(10)
+
+	registry := NewRegistry()
+	registry.MustRegister("usersByEmail", func(args Args) (Query, error) {
+		q := builder.NewQuery()
+		q.Operation = "query"
+		q.Method = "findMany"
+		q.Model = "User"
+		q.Inputs = []builder.Input{{
+			Name: "where",
+			Fields: []builder.Field{{
+				Name:   "email",
+				Fields: []builder.Field{{Name: "contains", Value: email.Get(args)}},
+			}},
+		}, {
+			Name:  "take",
+			Value: take.Get(args),
+		}}
+		q.Outputs = []builder.Output{{Name: "id"}}
+		return q, nil
+	}, email, take)
+	return registry
+}
+
+func TestRegistry_Build(t *testing.T) {
+	registry := usersByEmail()
+
+	query, err := registry.Build("usersByEmail", map[string]interface{}{"email": "@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "@example.com", query.Inputs[0].Fields[0].Fields[0].Value)
+	massert.Equal(t, 10, query.Inputs[1].Value)
+
+	query, err = registry.BuildJSON("usersByEmail", []byte(`{"email": "@prisma.io", "take": 5}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, "@prisma.io", query.Inputs[0].Fields[0].Fields[0].Value)
+	massert.Equal(t, 5, query.Inputs[1].Value)
+
+	// values of a different type are converted
+	query, err = registry.Build("usersByEmail", map[string]interface{}{"email": "a", "take": float64(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 3, query.Inputs[1].Value)
+}
+
+func TestRegistry_Build_invalid(t *testing.T) {
+	registry := usersByEmail()
+
+	tests := []struct {
+		name   string
+		query  string
+		values map[string]interface{}
+		err    string
+	}{{
+		name:   "missing parameter",
+		query:  "usersByEmail",
+		values: map[string]interface{}{"take": 1},
+		err:    "named query usersByEmail: missing parameter email",
+	}, {
+		name:   "unknown parameter",
+		query:  "usersByEmail",
+		values: map[string]interface{}{"email": "a", "role": "ADMIN"},
+		err:    "named query usersByEmail: unknown parameter role",
+	}, {
+		name:   "wrong type",
+		query:  "usersByEmail",
+		values: map[string]interface{}{"email": "a", "take": "all"},
+		err:    "named query usersByEmail: parameter take: json: cannot unmarshal string into Go value of type int",
+	}, {
+		name:  "unknown query",
+		query: "deleteUsers",
+		err:   "named query not found: deleteUsers",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registry.Build(tt.query, tt.values)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			massert.Equal(t, tt.err, err.Error())
+		})
+	}
+
+	_, err := registry.Build("deleteUsers", nil)
+	massert.Equal(t, true, errors.Is(err, ErrNotFound))
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := usersByEmail()
+
+	err := registry.Register("usersByEmail", nil)
+	massert.Equal(t, "named query usersByEmail is already registered", err.Error())
+
+	err = registry.Register("posts", nil, NewParam[string]("id"), NewParam[int]("id"))
+	massert.Equal(t, "named query posts: duplicate parameter id", err.Error())
+
+	massert.Equal(t, []string{"usersByEmail"}, registry.Names())
+
+	params, err := registry.Params("usersByEmail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 2, len(params))
+	massert.Equal(t, "email", params[0].Name())
+	massert.Equal(t, true, params[0].Required())
+	massert.Equal(t, false, params[1].Required())
+}
@@ -0,0 +1,126 @@
+package raw
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+)
+
+// RunCommandRaw sends a raw command to a MongoDB database, e.g. map[string]interface{}{"ping": 1}.
+// The command can be any value which can be marshalled to JSON, or a JSON string.
+func (r Raw) RunCommandRaw(command interface{}) QueryExec {
+	q := builder.NewQuery()
+	q.Engine = r.Engine
+	q.Operation = "mutation"
+	q.Method = "runCommandRaw"
+
+	value, err := mongoJSON(command)
+	q.Err = err
+	q.Inputs = append(q.Inputs, builder.Input{
+		Name:  "command",
+		Value: value,
+	})
+
+	return QueryExec{
+		query: q,
+	}
+}
+
+// FindRaw queries a MongoDB collection of the given model with a raw filter and an optional find options document.
+// It is used by the generated client; use client.<Model>.FindRaw instead.
+func FindRaw(engine engine.Engine, model string, filter interface{}, options ...interface{}) QueryExec {
+	q := builder.NewQuery()
+	q.Engine = engine
+	q.Operation = "query"
+	q.Method = "find"
+	q.Model = model + "Raw"
+
+	if filter != nil {
+		value, err := mongoJSON(filter)
+		if err != nil {
+			q.Err = err
+		}
+		q.Inputs = append(q.Inputs, builder.Input{
+			Name:  "filter",
+			Value: value,
+		})
+	}
+
+	addMongoOptions(&q, options)
+
+	return QueryExec{
+		query: q,
+	}
+}
+
+// AggregateRaw runs a raw aggregation pipeline on a MongoDB collection of the given model.
+// It is used by the generated client; use client.<Model>.AggregateRaw instead.
+func AggregateRaw(engine engine.Engine, model string, pipeline []interface{}, options ...interface{}) QueryExec {
+	q := builder.NewQuery()
+	q.Engine = engine
+	q.Operation = "query"
+	q.Method = "aggregate"
+	q.Model = model + "Raw"
+
+	stages := make([]string, 0, len(pipeline))
+	for _, stage := range pipeline {
+		value, err := mongoJSON(stage)
+		if err != nil && q.Err == nil {
+			q.Err = err
+		}
+		stages = append(stages, value)
+	}
+
+	q.Inputs = append(q.Inputs, builder.Input{
+		Name:  "pipeline",
+		Value: stages,
+	})
+
+	addMongoOptions(&q, options)
+
+	return QueryExec{
+		query: q,
+	}
+}
+
+// addMongoOptions adds the options document of a raw MongoDB query, of which there may be at most one
+func addMongoOptions(q *builder.Query, options []interface{}) {
+	if len(options) == 0 {
+		return
+	}
+	if len(options) > 1 {
+		if q.Err == nil {
+			q.Err = fmt.Errorf("expected at most one raw mongo options document, got %d", len(options))
+		}
+		return
+	}
+	value, err := mongoJSON(options[0])
+	if err != nil && q.Err == nil {
+		q.Err = err
+	}
+	q.Inputs = append(q.Inputs, builder.Input{
+		Name:  "options",
+		Value: value,
+	})
+}
+
+// mongoJSON converts a raw MongoDB document to the JSON string the query engine expects.
+// Strings and byte slices are passed through as they are expected to already contain JSON.
+func mongoJSON(input interface{}) (string, error) {
+	switch v := input.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case json.RawMessage:
+		return string(v), nil
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal raw mongo document: %w", err)
+	}
+	return string(data), nil
+}
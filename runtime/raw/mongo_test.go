@@ -0,0 +1,52 @@
+package raw
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestFindRaw_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    QueryExec
+		expected string
+	}{{
+		name:     "filter which can't be marshalled",
+		query:    FindRaw(nil, "User", map[string]interface{}{"a": make(chan int)}),
+		expected: "could not marshal raw mongo document: json: unsupported type: chan int",
+	}, {
+		name:     "pipeline stage which can't be marshalled",
+		query:    AggregateRaw(nil, "User", []interface{}{map[string]interface{}{"$match": func() {}}}),
+		expected: "could not marshal raw mongo document: json: unsupported type: func()",
+	}, {
+		name:     "command which can't be marshalled",
+		query:    Raw{}.RunCommandRaw(make(chan int)),
+		expected: "could not marshal raw mongo document: json: unsupported type: chan int",
+	}, {
+		name:     "multiple options",
+		query:    FindRaw(nil, "User", `{}`, `{"limit": 1}`, `{"skip": 1}`),
+		expected: "expected at most one raw mongo options document, got 2",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var result interface{}
+			err := tt.query.Exec(context.Background(), &result)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			massert.Equal(t, true, strings.HasSuffix(err.Error(), tt.expected))
+		})
+	}
+}
+
+func TestFindRaw(t *testing.T) {
+	str, err := FindRaw(nil, "User", map[string]interface{}{"a": 1}, `{"limit": 1}`).ExtractQuery().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `query {result: findUserRaw(filter:"{\"a\":1}",options:"{\"limit\": 1}",) }`, str)
+}
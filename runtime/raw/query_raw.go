@@ -42,6 +42,32 @@ func (r QueryExec) Exec(ctx context.Context, into interface{}) error {
 	return nil
 }
 
+// Into decodes the rows into v, e.g. a pointer to a slice of custom structs, mapping the columns onto struct fields by
+// their tags with scan.Into, so that the fields don't need to use the raw types
+func (r QueryExec) Into(v interface{}) QueryInto {
+	return QueryInto{
+		query: r.query,
+		into:  v,
+	}
+}
+
+type QueryInto struct {
+	query builder.Query
+	into  interface{}
+}
+
+func (r QueryInto) ExtractQuery() builder.Query {
+	return r.query
+}
+
+func (r QueryInto) Exec(ctx context.Context) error {
+	if err := r.query.ExecInto(ctx, r.into); err != nil {
+		return fmt.Errorf("could not send raw query: %w", err)
+	}
+
+	return nil
+}
+
 func NewTxQueryResult() TxQueryResult {
 	return TxQueryResult{
 		result: &transaction.Result{},
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -70,3 +71,15 @@ func convertType(input interface{}) string {
 		return string(builder.Value(p))
 	}
 }
+
+// Quote quotes an identifier, e.g. the name of a table or column, for the given provider
+func Quote(provider string, identifier string) string {
+	switch provider {
+	case "mysql":
+		return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+	case "sqlserver":
+		return "[" + strings.ReplaceAll(identifier, "]", "]]") + "]"
+	default:
+		return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+	}
+}
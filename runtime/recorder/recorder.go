@@ -0,0 +1,145 @@
+// Package recorder records the queries a client sends, so that tests can assert on the exact queries a code path
+// produces, e.g. to detect N+1 regressions. A Recorder is registered as a query hook of the client.
+//
+// Example:
+//
+//	rec := recorder.New()
+//	client := db.NewClient(db.WithQueryHook(rec.Hook))
+//
+//	// run the code under test
+//
+//	if n := rec.Count("Post", "findMany"); n != 1 {
+//	  t.Errorf("expected one query for posts, got %d:\n%s", n, rec)
+//	}
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+)
+
+// Query is a recorded query
+type Query struct {
+	// Model is the name of the Prisma model, e.g. "User"
+	Model string `json:"model"`
+
+	// Action is the operation, e.g. "findMany" or "createOne"
+	Action string `json:"action"`
+
+	// Args are the serialized arguments as sent to the engine, e.g. `(where:{id:"a",},)`
+	Args string `json:"args"`
+}
+
+// String returns the query as a single line, e.g. `User.findUnique(where:{id:"a",},)`
+func (q Query) String() string {
+	return q.Model + "." + q.Action + q.Args
+}
+
+// Recorder records queries. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	queries []Query
+	w       io.Writer
+}
+
+// Option configures a Recorder
+type Option func(*Recorder)
+
+// WithWriter additionally writes each query as a JSON line to w, e.g. a file, to compare the queries of a test run
+// against a stored snapshot
+func WithWriter(w io.Writer) Option {
+	return func(r *Recorder) {
+		r.w = w
+	}
+}
+
+// New creates a Recorder
+func New(opts ...Option) *Recorder {
+	r := &Recorder{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Hook records q. It is a builder.Hook, so it can be passed to db.WithQueryHook. Register it after all other hooks to
+// record the queries as they are sent to the engine.
+func (r *Recorder) Hook(_ context.Context, q *builder.Query) error {
+	args, err := q.BuildArgs()
+	if err != nil {
+		return err
+	}
+	query := Query{
+		Model:  q.Model,
+		Action: q.Method,
+		Args:   args,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queries = append(r.queries, query)
+
+	if r.w != nil {
+		line, err := json.Marshal(query)
+		if err != nil {
+			return fmt.Errorf("recorder: marshal: %w", err)
+		}
+		if _, err := r.w.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("recorder: write: %w", err)
+		}
+	}
+	return nil
+}
+
+// Queries returns all recorded queries in the order they were sent
+func (r *Recorder) Queries() []Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queries := make([]Query, len(r.queries))
+	copy(queries, r.queries)
+	return queries
+}
+
+// Count returns how many queries were recorded for the given model and action. An empty model or action matches all
+// models or actions, e.g. Count("", "") returns the number of all queries.
+func (r *Recorder) Count(model, action string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, q := range r.queries {
+		if (model == "" || q.Model == model) && (action == "" || q.Action == action) {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset removes all recorded queries, e.g. after preparing test data
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queries = nil
+}
+
+// String returns all recorded queries, one per line, e.g. to compare them against a snapshot
+func (r *Recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	for _, q := range r.queries {
+		b.WriteString(q.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
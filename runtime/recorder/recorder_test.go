@@ -0,0 +1,66 @@
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func query(model, method string, inputs ...builder.Input) *builder.Query {
+	q := builder.NewQuery()
+	q.Operation = "query"
+	q.Model = model
+	q.Method = method
+	q.Inputs = inputs
+	return &q
+}
+
+func TestRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	rec := New(WithWriter(&buf))
+	policy := &builder.Policy{Hooks: []builder.Hook{rec.Hook}}
+
+	ctx := context.Background()
+	queries := []*builder.Query{
+		query("User", "findMany"),
+		query("Post", "findMany", builder.Input{
+			Name: "where",
+			Fields: []builder.Field{{
+				Name:  "authorID",
+				Value: "a",
+			}},
+		}),
+		query("Post", "findMany", builder.Input{
+			Name: "where",
+			Fields: []builder.Field{{
+				Name:  "authorID",
+				Value: "b",
+			}},
+		}),
+	}
+	for _, q := range queries {
+		if err := policy.Apply(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	massert.Equal(t, []Query{
+		{Model: "User", Action: "findMany"},
+		{Model: "Post", Action: "findMany", Args: `(where:{authorID:"a",},)`},
+		{Model: "Post", Action: "findMany", Args: `(where:{authorID:"b",},)`},
+	}, rec.Queries())
+	massert.Equal(t, 2, rec.Count("Post", "findMany"))
+	massert.Equal(t, 3, rec.Count("", "findMany"))
+	massert.Equal(t, 0, rec.Count("User", "createOne"))
+	massert.Equal(t, "User.findMany\nPost.findMany(where:{authorID:\"a\",},)\nPost.findMany(where:{authorID:\"b\",},)\n", rec.String())
+	massert.Equal(t, `{"model":"User","action":"findMany","args":""}
+{"model":"Post","action":"findMany","args":"(where:{authorID:\"a\",},)"}
+{"model":"Post","action":"findMany","args":"(where:{authorID:\"b\",},)"}
+`, buf.String())
+
+	rec.Reset()
+	massert.Equal(t, 0, rec.Count("", ""))
+}
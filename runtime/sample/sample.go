@@ -0,0 +1,241 @@
+// Package sample selects random records of a table using connector specific SQL, so that samples of large tables
+// can be taken without sorting the whole table where the database supports it.
+package sample
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/raw"
+	rawtypes "github.com/steebchen/prisma-client-go/runtime/types/raw"
+)
+
+// Oversample is the factor by which more keys than requested are sampled, so that enough records remain after
+// applying the filters of the query
+const Oversample = 10
+
+// MaxRounds is the maximum number of samples Exec takes before it returns the records found so far
+const MaxRounds = 3
+
+// MaxKeys is the maximum number of keys sampled at once when more keys than requested are sampled, which limits the
+// size of the `in` filter sent to the database
+const MaxKeys = 10000
+
+// Table describes the sampled table and its primary key column
+type Table struct {
+	// Provider is the datasource provider, e.g. "postgresql"
+	Provider string
+
+	// Name is the name of the table in the database
+	Name string
+
+	// Field is the Prisma name of the primary key field
+	Field string
+
+	// Column is the name of the single primary key column in the database
+	Column string
+}
+
+// Exec runs the query restricted to a random sample of the table and returns up to n of its records, selected at
+// random. If the sample contains fewer than n matching records, e.g. because the filters of the query are selective or
+// TABLESAMPLE returned fewer rows than estimated, a larger sample is taken, until n records are found, the whole table
+// was sampled, MaxRounds samples were taken or MaxKeys keys were sampled. In the last two cases, fewer than n records
+// are returned even though more may match, so rarely matching filters should be combined with a regular query instead.
+func Exec[T any](ctx context.Context, q builder.Query, table Table, n int) ([]T, error) {
+	count := sampleSize(q, n)
+	for round := 1; ; round++ {
+		keys, exhaustive, err := sampleKeys(ctx, q.Engine, table, count)
+		if err != nil {
+			return nil, err
+		}
+
+		var records []T
+		if err := restrict(q, table, keys).Exec(ctx, &records); err != nil {
+			return nil, err
+		}
+
+		if len(records) >= n || exhaustive {
+			return Pick(records, n), nil
+		}
+
+		if round == MaxRounds || count >= maxKeys(n) {
+			logger.Debug.Printf("sample of %d keys of %s contained %d of %d records after %d rounds; returning a partial sample", count, table.Name, len(records), n, round)
+			return records, nil
+		}
+
+		logger.Debug.Printf("sample of %d keys of %s contained %d of %d records; sampling more keys", count, table.Name, len(records), n)
+		count = min(count*Oversample, maxKeys(n))
+	}
+}
+
+// Restrict samples random keys of the table and restricts the query to the records with these keys, while keeping its
+// filters. As the filters are applied after sampling, more keys than n are sampled if the query has filters.
+// The returned query may return more than n records; use Pick to select n of them. Use Exec to retry with a larger
+// sample if it returns fewer than n records.
+func Restrict(ctx context.Context, q builder.Query, table Table, n int) (builder.Query, error) {
+	keys, err := Keys(ctx, q.Engine, table, sampleSize(q, n))
+	if err != nil {
+		return q, err
+	}
+	return restrict(q, table, keys), nil
+}
+
+// sampleSize returns how many keys are sampled for n records, which is more than n if the query has filters
+func sampleSize(q builder.Query, n int) int {
+	for _, input := range q.Inputs {
+		if input.Name == "where" && len(input.Fields) > 0 {
+			return min(n*Oversample, maxKeys(n))
+		}
+	}
+	return n
+}
+
+// maxKeys returns the maximum number of keys sampled for n records, which is MaxKeys unless more records are requested
+func maxKeys(n int) int {
+	return max(n, MaxKeys)
+}
+
+// restrict restricts the query to the records with the given keys, while keeping its filters
+func restrict(q builder.Query, table Table, keys []json.RawMessage) builder.Query {
+	var where []builder.Field
+	inputs := make([]builder.Input, 0, len(q.Inputs))
+	for _, input := range q.Inputs {
+		switch input.Name {
+		case "where":
+			where = input.Fields
+		case "take", "skip", "cursor":
+			// the sample replaces pagination
+		default:
+			inputs = append(inputs, input)
+		}
+	}
+
+	in := builder.Field{
+		Name: table.Field,
+		Fields: []builder.Field{{
+			Name:  "in",
+			Value: keys,
+		}},
+	}
+
+	q.Inputs = append(inputs, builder.Input{
+		Name: "where",
+		Fields: []builder.Field{{
+			Name:     "AND",
+			List:     true,
+			WrapList: true,
+			Fields:   append(append([]builder.Field{}, where...), in),
+		}},
+	})
+
+	return q
+}
+
+// Pick returns n records selected at random, keeping their order
+func Pick[T any](records []T, n int) []T {
+	if len(records) <= n {
+		return records
+	}
+
+	indexes := rand.Perm(len(records))[:n]
+	sort.Ints(indexes)
+
+	picked := make([]T, n)
+	for i, index := range indexes {
+		picked[i] = records[index]
+	}
+	return picked
+}
+
+// Keys returns up to n primary keys of the table, selected at random.
+// On PostgreSQL, tables are sampled with TABLESAMPLE based on the estimated row count, so that only a fraction of the
+// table is read; other databases and small tables are sampled with ORDER BY RANDOM() or the equivalent of the database.
+func Keys(ctx context.Context, e engine.Engine, table Table, n int) ([]json.RawMessage, error) {
+	keys, _, err := sampleKeys(ctx, e, table, n)
+	return keys, err
+}
+
+// sampleKeys returns up to n random primary keys of the table and whether they are all keys of the table, i.e. if the
+// whole table was sampled and it has fewer than n rows
+func sampleKeys(ctx context.Context, e engine.Engine, table Table, n int) ([]json.RawMessage, bool, error) {
+	if n <= 0 {
+		return nil, false, nil
+	}
+
+	query, partial, err := keysQuery(ctx, e, table, n)
+	if err != nil {
+		return nil, false, err
+	}
+
+	logger.Debug.Printf("sampling %d keys of %s: %s", n, table.Name, query)
+
+	var rows []map[string]json.RawMessage
+	if err := (raw.Raw{Engine: e}).QueryRaw(query).Exec(ctx, &rows); err != nil {
+		return nil, false, fmt.Errorf("sample keys: %w", err)
+	}
+
+	keys := make([]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		if key, ok := row[table.Column]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys, !partial && len(rows) < n, nil
+}
+
+// keysQuery returns the query which samples n keys of the table and whether it only reads a part of the table, i.e.
+// whether it may return fewer than n keys even though the table has more rows
+func keysQuery(ctx context.Context, e engine.Engine, table Table, n int) (string, bool, error) {
+	column := raw.Quote(table.Provider, table.Column)
+	name := raw.Quote(table.Provider, table.Name)
+	limit := strconv.Itoa(n)
+
+	switch table.Provider {
+	case "postgresql", "postgres":
+		estimate, err := estimateRows(ctx, e, name)
+		if err != nil {
+			return "", false, err
+		}
+		// sample twice the required share of blocks, as rows are not distributed evenly across blocks
+		percent := float64(n) * 2 * 100 / estimate
+		if estimate <= 0 || percent >= 100 {
+			// unknown or small table; sorting it is cheap
+			return fmt.Sprintf("SELECT %s FROM %s ORDER BY random() LIMIT %s", column, name, limit), false, nil
+		}
+		return fmt.Sprintf(
+			"SELECT %s FROM %s TABLESAMPLE SYSTEM (%s) ORDER BY random() LIMIT %s",
+			column, name, strconv.FormatFloat(percent, 'f', 6, 64), limit,
+		), true, nil
+	case "mysql":
+		return fmt.Sprintf("SELECT %s FROM %s ORDER BY RAND() LIMIT %s", column, name, limit), false, nil
+	case "sqlserver":
+		return fmt.Sprintf("SELECT TOP %s %s FROM %s ORDER BY NEWID()", limit, column, name), false, nil
+	case "sqlite", "cockroachdb":
+		return fmt.Sprintf("SELECT %s FROM %s ORDER BY random() LIMIT %s", column, name, limit), false, nil
+	default:
+		return "", false, fmt.Errorf("sampling is not supported for provider %q", table.Provider)
+	}
+}
+
+// estimateRows returns the row count of a postgres table estimated by the query planner, or a value <= 0 if the
+// table was never analyzed
+func estimateRows(ctx context.Context, e engine.Engine, name string) (float64, error) {
+	var rows []struct {
+		Estimate rawtypes.Float `json:"estimate"`
+	}
+	query := "SELECT reltuples::float8 AS estimate FROM pg_class WHERE oid = to_regclass($1)"
+	if err := (raw.Raw{Engine: e}).QueryRaw(query, name).Exec(ctx, &rows); err != nil {
+		return 0, fmt.Errorf("estimate row count: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return float64(rows[0].Estimate), nil
+}
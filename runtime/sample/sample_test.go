@@ -0,0 +1,261 @@
+package sample
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeEngine answers raw queries with the next response and records the sent queries
+type fakeEngine struct {
+	responses []string
+	queries   []string
+}
+
+func (e *fakeEngine) Connect() error    { return nil }
+func (e *fakeEngine) Disconnect() error { return nil }
+func (e *fakeEngine) Name() string      { return "fake" }
+
+func (e *fakeEngine) Do(_ context.Context, payload interface{}, into interface{}) error {
+	e.queries = append(e.queries, payload.(protocol.GQLRequest).Query)
+	response := e.responses[0]
+	e.responses = e.responses[1:]
+	return json.Unmarshal([]byte(response), into)
+}
+
+func (e *fakeEngine) Batch(context.Context, interface{}, interface{}) error {
+	panic("not implemented")
+}
+
+func TestKeysQuery(t *testing.T) {
+	tests := []struct {
+		provider  string
+		responses []string
+		expected  string
+		partial   bool
+	}{{
+		provider:  "postgresql",
+		responses: []string{`[{"estimate":100000}]`},
+		expected:  `SELECT "id" FROM "user" TABLESAMPLE SYSTEM (0.200000) ORDER BY random() LIMIT 100`,
+		partial:   true,
+	}, {
+		provider:  "postgresql",
+		responses: []string{`[{"estimate":-1}]`},
+		expected:  `SELECT "id" FROM "user" ORDER BY random() LIMIT 100`,
+	}, {
+		provider:  "postgresql",
+		responses: []string{`[{"estimate":150}]`},
+		expected:  `SELECT "id" FROM "user" ORDER BY random() LIMIT 100`,
+	}, {
+		provider: "mysql",
+		expected: "SELECT `id` FROM `user` ORDER BY RAND() LIMIT 100",
+	}, {
+		provider: "sqlserver",
+		expected: "SELECT TOP 100 [id] FROM [user] ORDER BY NEWID()",
+	}, {
+		provider: "sqlite",
+		expected: `SELECT "id" FROM "user" ORDER BY random() LIMIT 100`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			e := &fakeEngine{responses: tt.responses}
+			actual, partial, err := keysQuery(context.Background(), e, Table{Provider: tt.provider, Name: "user", Column: "id"}, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.expected, actual)
+			massert.Equal(t, tt.partial, partial)
+		})
+	}
+}
+
+func TestKeysQuery_unsupported(t *testing.T) {
+	_, _, err := keysQuery(context.Background(), &fakeEngine{}, Table{Provider: "mongodb"}, 1)
+	massert.Equal(t, `sampling is not supported for provider "mongodb"`, err.Error())
+}
+
+func TestRestrict(t *testing.T) {
+	e := &fakeEngine{responses: []string{`[{"user_id":"a"},{"user_id":"b"}]`}}
+
+	q := builder.NewQuery()
+	q.Engine = e
+	q.Operation = "query"
+	q.Method = "findMany"
+	q.Model = "User"
+	q.Inputs = []builder.Input{{
+		Name: "where",
+		Fields: []builder.Field{{
+			Name:   "name",
+			Fields: []builder.Field{{Name: "equals", Value: "x"}},
+		}},
+	}, {
+		Name:  "take",
+		Value: 5,
+	}}
+	q.Outputs = []builder.Output{{Name: "id"}}
+
+	restricted, err := Restrict(context.Background(), q, Table{
+		Provider: "sqlite",
+		Name:     "User",
+		Field:    "id",
+		Column:   "user_id",
+	}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// more keys are sampled, as the query has filters
+	massert.Equal(t, true, strings.Contains(e.queries[0], `LIMIT 30`))
+
+	actual, err := restricted.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `query {result: findManyUser(where:{AND:[{name:{equals:"x",}},{id:{in:["a","b"],}},],},) {id }}`, actual)
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		expected  []string
+		sampled   []string
+	}{{
+		name: "sample with enough records",
+		responses: []string{
+			`[{"estimate":100000}]`,
+			`[{"user_id":"a"},{"user_id":"b"},{"user_id":"c"}]`,
+			`[{"id":"a"},{"id":"b"}]`,
+		},
+		expected: []string{"a", "b"},
+		sampled:  []string{"TABLESAMPLE SYSTEM (0.040000) ORDER BY random() LIMIT 20"},
+	}, {
+		name: "short sample is retried with more keys",
+		responses: []string{
+			`[{"estimate":100000}]`,
+			`[{"user_id":"a"}]`,
+			`[{"id":"a"}]`,
+			`[{"estimate":100000}]`,
+			`[{"user_id":"a"},{"user_id":"b"}]`,
+			`[{"id":"a"},{"id":"b"}]`,
+		},
+		expected: []string{"a", "b"},
+		sampled: []string{
+			"TABLESAMPLE SYSTEM (0.040000) ORDER BY random() LIMIT 20",
+			"TABLESAMPLE SYSTEM (0.400000) ORDER BY random() LIMIT 200",
+		},
+	}, {
+		name: "whole table was sampled",
+		responses: []string{
+			`[{"estimate":100000}]`,
+			`[{"user_id":"a"}]`,
+			`[]`,
+			`[{"estimate":100}]`,
+			`[{"user_id":"a"},{"user_id":"b"}]`,
+			`[{"id":"a"}]`,
+		},
+		expected: []string{"a"},
+		sampled: []string{
+			"TABLESAMPLE SYSTEM (0.040000) ORDER BY random() LIMIT 20",
+			`User\" ORDER BY random() LIMIT 200`,
+		},
+	}, {
+		name: "partial sample after the maximum number of rounds",
+		responses: []string{
+			`[{"estimate":100000}]`,
+			`[{"user_id":"a"}]`,
+			`[]`,
+			`[{"estimate":100000}]`,
+			`[{"user_id":"a"}]`,
+			`[]`,
+			`[{"estimate":100000}]`,
+			`[{"user_id":"a"}]`,
+			`[{"id":"a"}]`,
+		},
+		expected: []string{"a"},
+		sampled: []string{
+			"TABLESAMPLE SYSTEM (0.040000) ORDER BY random() LIMIT 20",
+			"TABLESAMPLE SYSTEM (0.400000) ORDER BY random() LIMIT 200",
+			"TABLESAMPLE SYSTEM (4.000000) ORDER BY random() LIMIT 2000",
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &fakeEngine{responses: tt.responses}
+
+			q := builder.NewQuery()
+			q.Engine = e
+			q.Operation = "query"
+			q.Method = "findMany"
+			q.Model = "User"
+			q.Inputs = []builder.Input{{
+				Name: "where",
+				Fields: []builder.Field{{
+					Name:   "name",
+					Fields: []builder.Field{{Name: "equals", Value: "x"}},
+				}},
+			}}
+			q.Outputs = []builder.Output{{Name: "id"}}
+
+			records, err := Exec[struct {
+				ID string `json:"id"`
+			}](context.Background(), q, Table{
+				Provider: "postgresql",
+				Name:     "User",
+				Field:    "id",
+				Column:   "user_id",
+			}, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, record := range records {
+				ids = append(ids, record.ID)
+			}
+			massert.Equal(t, tt.expected, ids)
+
+			var sampled []string
+			for _, query := range e.queries {
+				for _, expected := range tt.sampled {
+					if strings.Contains(query, expected) {
+						sampled = append(sampled, expected)
+					}
+				}
+			}
+			massert.Equal(t, tt.sampled, sampled)
+			massert.Equal(t, 0, len(e.responses))
+		})
+	}
+}
+
+func TestSampleSize(t *testing.T) {
+	filtered := builder.Query{Inputs: []builder.Input{{
+		Name:   "where",
+		Fields: []builder.Field{{Name: "name", Value: "x"}},
+	}}}
+
+	massert.Equal(t, 20, sampleSize(builder.Query{}, 20))
+	massert.Equal(t, 200, sampleSize(filtered, 20))
+	massert.Equal(t, MaxKeys, sampleSize(filtered, 2000))
+	massert.Equal(t, 20000, sampleSize(filtered, 20000))
+}
+
+func TestPick(t *testing.T) {
+	records := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	picked := Pick(records, 4)
+	massert.Equal(t, 4, len(picked))
+	for i := 1; i < len(picked); i++ {
+		if picked[i-1] >= picked[i] {
+			t.Errorf("expected the order to be kept, got %v", picked)
+		}
+	}
+
+	massert.Equal(t, []int{1, 2}, Pick([]int{1, 2}, 4))
+}
@@ -0,0 +1,269 @@
+// Package scan decodes query results into arbitrary structs, e.g. the fields chosen with Select or the rows of raw
+// queries, so that projections don't have to be decoded into maps first. Fields of the results are mapped onto struct
+// fields by their tags, and values are converted to the types of the struct fields where databases return them
+// differently, e.g. big integers and decimals as strings or booleans as 0 and 1.
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var unmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Into decodes data, a JSON object or an array of objects, into v, which must be a pointer to a struct or to a slice of
+// structs or struct pointers.
+//
+// A struct field is decoded from the result field named by its `prisma` tag, its `json` tag or its name, in this
+// order; names without a tag are matched case-insensitively. Fields tagged with `prisma:"-"` or `json:"-"` are skipped,
+// as are result fields without a struct field. Embedded structs without a tag are flattened. Types which implement
+// json.Unmarshaler, such as time.Time or decimal.Decimal, decode their values themselves.
+func Into(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("scan: expected a non-nil pointer, got %T", v)
+	}
+	return decode(data, rv.Elem(), "")
+}
+
+func decode(data []byte, v reflect.Value, path string) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decode(data, v.Elem(), path)
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(unmarshaler) {
+		return wrap(path, json.Unmarshal(data, v.Addr().Interface()))
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return decodeStruct(data, v, path)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// bytes are base64 encoded
+			break
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return wrap(path, err)
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decode(item, s.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Bool:
+		b, err := parseBool(data)
+		if err != nil {
+			return wrap(path, err)
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := parseInt(data, v.Type().Bits())
+		if err != nil {
+			return wrap(path, err)
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := parseUint(data, v.Type().Bits())
+		if err != nil {
+			return wrap(path, err)
+		}
+		v.SetUint(i)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(literal(data), v.Type().Bits())
+		if err != nil {
+			return wrap(path, fmt.Errorf("%s is not a number", data))
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.String:
+		v.SetString(literal(data))
+		return nil
+	}
+
+	return wrap(path, json.Unmarshal(data, v.Addr().Interface()))
+}
+
+func decodeStruct(data []byte, v reflect.Value, path string) error {
+	if len(data) == 0 || data[0] != '{' {
+		return wrap(path, fmt.Errorf("expected an object for %s, got %.32s", v.Type(), data))
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return wrap(path, err)
+	}
+	for _, f := range fields(v.Type(), nil) {
+		value, ok := lookup(object, f)
+		if !ok {
+			continue
+		}
+		field, err := fieldByIndex(v, f.index)
+		if err != nil {
+			return wrap(path, err)
+		}
+		if err := decode(value, field, join(path, f.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type field struct {
+	name   string
+	tagged bool
+	index  []int
+}
+
+// fields returns the fields of a struct type which can be decoded, including the fields of embedded structs
+func fields(t reflect.Type, index []int) []field {
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, tagged := tag(f)
+		if name == "-" {
+			continue
+		}
+		idx := append(index[:len(index):len(index)], i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && !tagged && ft.Kind() == reflect.Struct && !reflect.PointerTo(ft).Implements(unmarshaler) {
+			result = append(result, fields(ft, idx)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		result = append(result, field{name: name, tagged: tagged, index: idx})
+	}
+	return result
+}
+
+// tag returns the name of a struct field in the results and whether it was set by a tag
+func tag(f reflect.StructField) (string, bool) {
+	for _, key := range []string{"prisma", "json"} {
+		if value, ok := f.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(value, ",")
+			if name != "" {
+				return name, true
+			}
+		}
+	}
+	return f.Name, false
+}
+
+func lookup(object map[string]json.RawMessage, f field) (json.RawMessage, bool) {
+	if value, ok := object[f.name]; ok {
+		return value, true
+	}
+	if f.tagged {
+		return nil, false
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, f.name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// fieldByIndex returns the nested field of v, allocating embedded struct pointers on the way
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// literal returns the content of a JSON string, or the text of any other value, e.g. of a number
+func literal(data []byte) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	return string(data)
+}
+
+func parseBool(data []byte) (bool, error) {
+	switch literal(data) {
+	case "true", "TRUE", "1", "t":
+		return true, nil
+	case "false", "FALSE", "0", "f":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s is not a boolean", data)
+}
+
+func parseInt(data []byte, bits int) (int64, error) {
+	s := literal(data)
+	if i, err := strconv.ParseInt(s, 10, bits); err == nil {
+		return i, nil
+	}
+	// integers may be returned in exponent notation or with a zero fraction, e.g. 1e3 or 1.0
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f < math.Ldexp(-1, bits-1) || f >= math.Ldexp(1, bits-1) {
+		return 0, fmt.Errorf("%s is not an integer of %d bits", data, bits)
+	}
+	return int64(f), nil
+}
+
+func parseUint(data []byte, bits int) (uint64, error) {
+	s := literal(data)
+	if i, err := strconv.ParseUint(s, 10, bits); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f < 0 || f >= math.Ldexp(1, bits) {
+		return 0, fmt.Errorf("%s is not an unsigned integer of %d bits", data, bits)
+	}
+	return uint64(f), nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func wrap(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if path == "" {
+		return fmt.Errorf("scan: %w", err)
+	}
+	return fmt.Errorf("scan: field %s: %w", path, err)
+}
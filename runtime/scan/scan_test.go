@@ -0,0 +1,146 @@
+package scan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/steebchen/prisma-client-go/runtime/types"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type Base struct {
+	ID string `prisma:"id"`
+}
+
+type post struct {
+	Base
+	Title     string
+	Views     int64           `json:"view_count"`
+	Published bool            `prisma:"published"`
+	Rating    float64         `prisma:"rating"`
+	Price     decimal.Decimal `prisma:"price"`
+	Content   *string         `prisma:"content"`
+	Author    types.Nullable[string]
+	CreatedAt time.Time `prisma:"created_at"`
+	Tags      []string  `prisma:"tags"`
+	Data      []byte    `prisma:"data"`
+	Ignored   string    `prisma:"-"`
+	internal  string
+}
+
+func TestInto(t *testing.T) {
+	var posts []post
+	err := Into([]byte(`[{
+		"id": "a",
+		"TITLE": "Hello",
+		"view_count": "9007199254740993",
+		"published": 1,
+		"rating": "4.5",
+		"price": "1.10",
+		"content": null,
+		"author": "bob",
+		"created_at": "2020-01-02T03:04:05Z",
+		"tags": ["a", "b"],
+		"data": "aGk=",
+		"Ignored": "x",
+		"internal": "x",
+		"unknown": true
+	}, {
+		"id": "b",
+		"title": "World",
+		"view_count": 1e3,
+		"published": false,
+		"rating": 1,
+		"price": 2,
+		"content": "text",
+		"author": null
+	}]`), &posts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := "text"
+	massert.Equal(t, []post{{
+		Base:      Base{ID: "a"},
+		Title:     "Hello",
+		Views:     9007199254740993,
+		Published: true,
+		Rating:    4.5,
+		Price:     decimal.RequireFromString("1.10"),
+		Author:    types.NullableOf("bob"),
+		CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:      []string{"a", "b"},
+		Data:      []byte("hi"),
+	}, {
+		Base:    Base{ID: "b"},
+		Title:   "World",
+		Views:   1000,
+		Rating:  1,
+		Price:   decimal.RequireFromString("2"),
+		Content: &content,
+	}}, posts)
+}
+
+func TestInto_single(t *testing.T) {
+	var result *struct {
+		Count  int `json:"_count"`
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	}
+	if err := Into([]byte(`{"_count": 3, "author": {"name": "bob"}}`), &result); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, 3, result.Count)
+	massert.Equal(t, "bob", result.Author.Name)
+}
+
+func TestInto_errors(t *testing.T) {
+	type row struct {
+		Age   int8 `prisma:"age"`
+		Admin bool `prisma:"admin"`
+	}
+	tests := []struct {
+		name     string
+		data     string
+		into     interface{}
+		expected string
+	}{{
+		name:     "no pointer",
+		data:     `{}`,
+		into:     row{},
+		expected: "scan: expected a non-nil pointer, got scan.row",
+	}, {
+		name:     "overflow",
+		data:     `[{"age": 128}]`,
+		into:     &[]row{},
+		expected: "scan: field [0].age: 128 is not an integer of 8 bits",
+	}, {
+		name:     "fraction",
+		data:     `{"age": "1.5"}`,
+		into:     &row{},
+		expected: `scan: field age: "1.5" is not an integer of 8 bits`,
+	}, {
+		name:     "boolean",
+		data:     `{"admin": "yes"}`,
+		into:     &row{},
+		expected: `scan: field admin: "yes" is not a boolean`,
+	}, {
+		name:     "list into a struct",
+		data:     `[]`,
+		into:     &row{},
+		expected: "scan: expected an object for scan.row, got []",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := Into([]byte(tt.data), tt.into)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			massert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
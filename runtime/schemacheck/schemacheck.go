@@ -0,0 +1,300 @@
+// Package schemacheck compares the schema a client was generated from against the structure of the live database, so
+// that missing columns or type drift, e.g. after a partial deploy, are detected at startup instead of causing failing
+// queries or silently corrupted data later on.
+package schemacheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/runtime/raw"
+	rawtypes "github.com/steebchen/prisma-client-go/runtime/types/raw"
+)
+
+// Mode defines what happens when the database does not match the schema
+type Mode int
+
+const (
+	// Warn logs each mismatch
+	Warn Mode = iota
+	// Fail returns an *Error containing all mismatches
+	Fail
+)
+
+// Kind describes the kind of a mismatch
+type Kind string
+
+// Kind values
+const (
+	MissingTable  Kind = "missing table"
+	MissingColumn Kind = "missing column"
+	TypeDrift     Kind = "type drift"
+	Nullability   Kind = "nullability drift"
+)
+
+// Column describes a column of the live database
+type Column struct {
+	Table    string
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// Mismatch describes a difference between the schema and the database
+type Mismatch struct {
+	Kind Kind
+
+	Model string
+	// Field is empty for missing tables
+	Field string
+
+	Table  string
+	Column string
+
+	// Expected and Actual describe the type or nullability of the column for type and nullability drift
+	Expected string
+	Actual   string
+}
+
+func (m Mismatch) String() string {
+	switch m.Kind {
+	case MissingTable:
+		return fmt.Sprintf("%s: table %q of model %s does not exist", m.Kind, m.Table, m.Model)
+	case MissingColumn:
+		return fmt.Sprintf("%s: column %q of field %s.%s does not exist in table %q", m.Kind, m.Column, m.Model, m.Field, m.Table)
+	default:
+		return fmt.Sprintf("%s: column %q of field %s.%s is %s, expected %s", m.Kind, m.Column, m.Model, m.Field, m.Actual, m.Expected)
+	}
+}
+
+// Error is returned by Check in Fail mode if the database does not match the schema
+type Error struct {
+	Mismatches []Mismatch
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		lines[i] = m.String()
+	}
+	return fmt.Sprintf("database does not match the prisma schema; run your migrations or regenerate the client:\n  %s", strings.Join(lines, "\n  "))
+}
+
+// Check introspects the database and compares it against the schema. Depending on the mode, mismatches are logged or
+// returned as an *Error.
+func Check(ctx context.Context, e engine.Engine, schema *metadata.Schema, mode Mode) error {
+	columns, err := Columns(ctx, e, schema.Provider)
+	if err != nil {
+		return err
+	}
+
+	mismatches := Compare(schema, columns)
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	if mode == Fail {
+		return &Error{Mismatches: mismatches}
+	}
+
+	for _, m := range mismatches {
+		logger.Info.Printf("schema check: %s", m)
+	}
+	return nil
+}
+
+// Columns returns the columns of all tables in the current schema of the database
+func Columns(ctx context.Context, e engine.Engine, provider string) ([]Column, error) {
+	query, err := columnsQuery(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Table    rawtypes.String `json:"table_name"`
+		Column   rawtypes.String `json:"column_name"`
+		Type     rawtypes.String `json:"data_type"`
+		Nullable rawtypes.String `json:"is_nullable"`
+	}
+	if err := (raw.Raw{Engine: e}).QueryRaw(query).Exec(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("introspect columns: %w", err)
+	}
+
+	columns := make([]Column, len(rows))
+	for i, row := range rows {
+		columns[i] = Column{
+			Table:    string(row.Table),
+			Name:     string(row.Column),
+			Type:     string(row.Type),
+			Nullable: strings.EqualFold(string(row.Nullable), "YES"),
+		}
+	}
+	return columns, nil
+}
+
+func columnsQuery(provider string) (string, error) {
+	switch provider {
+	case "postgresql", "postgres", "cockroachdb":
+		// enums are reported as 'enum', as their type name may differ from the name of the enum in the schema
+		return `SELECT c.table_name::text AS table_name, c.column_name::text AS column_name,
+	CASE WHEN EXISTS (SELECT 1 FROM pg_enum e JOIN pg_type t ON t.oid = e.enumtypid WHERE t.typname = c.udt_name)
+		THEN 'enum' ELSE c.udt_name::text END AS data_type,
+	c.is_nullable::text AS is_nullable
+FROM information_schema.columns c WHERE c.table_schema = current_schema()`, nil
+	case "mysql":
+		return `SELECT table_name AS table_name, column_name AS column_name, data_type AS data_type, is_nullable AS is_nullable
+FROM information_schema.columns WHERE table_schema = DATABASE()`, nil
+	case "sqlserver":
+		return `SELECT table_name, column_name, data_type, is_nullable
+FROM information_schema.columns WHERE table_schema = SCHEMA_NAME()`, nil
+	case "sqlite":
+		return `SELECT m.name AS table_name, p.name AS column_name, p.type AS data_type,
+	CASE WHEN p."notnull" = 1 OR p.pk > 0 THEN 'NO' ELSE 'YES' END AS is_nullable
+FROM sqlite_master m JOIN pragma_table_info(m.name) p WHERE m.type IN ('table', 'view')`, nil
+	default:
+		return "", fmt.Errorf("schema check is not supported for provider %q", provider)
+	}
+}
+
+// Compare returns the differences between the schema and the given database columns. Tables and columns which are not
+// part of the schema are ignored, as they are common while migrations are rolled out.
+func Compare(schema *metadata.Schema, columns []Column) []Mismatch {
+	tables := make(map[string]map[string]Column)
+	for _, c := range columns {
+		table := strings.ToLower(c.Table)
+		if tables[table] == nil {
+			tables[table] = make(map[string]Column)
+		}
+		tables[table][strings.ToLower(c.Name)] = c
+	}
+
+	var mismatches []Mismatch
+	for _, model := range schema.Models {
+		table, ok := tables[strings.ToLower(model.DBName)]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{
+				Kind:  MissingTable,
+				Model: model.Name,
+				Table: model.DBName,
+			})
+			continue
+		}
+
+		for _, field := range model.ScalarFields() {
+			m := Mismatch{
+				Model:  model.Name,
+				Field:  field.Name,
+				Table:  model.DBName,
+				Column: field.DBName,
+			}
+
+			column, ok := table[strings.ToLower(field.DBName)]
+			if !ok {
+				m.Kind = MissingColumn
+				mismatches = append(mismatches, m)
+				continue
+			}
+
+			if !compatible(schema.Provider, field, column.Type) {
+				m.Kind = TypeDrift
+				m.Expected = expectedType(field)
+				m.Actual = column.Type
+				mismatches = append(mismatches, m)
+				continue
+			}
+
+			// list columns may be nullable even though lists are always required in prisma
+			if !field.IsList && field.IsRequired == column.Nullable {
+				m.Kind = Nullability
+				m.Expected, m.Actual = nullability(!field.IsRequired), nullability(column.Nullable)
+				mismatches = append(mismatches, m)
+			}
+		}
+	}
+	return mismatches
+}
+
+// families contains the database types which can hold the values of each prisma scalar type across all providers
+var families = map[string][]string{
+	"String": {
+		"text", "varchar", "character varying", "char", "character", "bpchar", "citext", "uuid", "xml", "inet",
+		"nvarchar", "nchar", "ntext", "tinytext", "mediumtext", "longtext", "uniqueidentifier",
+	},
+	"Int":      {"int", "integer", "int2", "int4", "smallint", "mediumint", "tinyint"},
+	"BigInt":   {"bigint", "int8", "integer"},
+	"Float":    {"float", "float4", "float8", "real", "double", "double precision"},
+	"Decimal":  {"decimal", "numeric", "money"},
+	"Boolean":  {"bool", "boolean", "bit", "tinyint"},
+	"DateTime": {"timestamp", "timestamptz", "datetime", "datetime2", "datetimeoffset", "smalldatetime", "date", "time", "timetz"},
+	"Json":     {"json", "jsonb"},
+	"Bytes":    {"bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "image"},
+}
+
+// compatible reports whether a column of the given database type can hold the values of the field
+func compatible(provider string, field metadata.Field, dbType string) bool {
+	t := normalize(dbType)
+
+	if field.IsList {
+		// only postgres and cockroachdb support scalar lists, whose array types are prefixed with an underscore
+		arrays := strings.HasPrefix(provider, "postgres") || provider == "cockroachdb"
+		return !arrays || strings.HasPrefix(t, "_")
+	}
+
+	if field.Kind == metadata.FieldKindEnum {
+		// enums are native enums on postgres and mysql, and strings on other databases
+		return t == "enum" || contains(families["String"], t)
+	}
+
+	accepted, ok := families[field.Type]
+	if !ok {
+		// unknown types can't be checked
+		return true
+	}
+
+	// sqlite only knows a few storage classes and accepts any type name
+	if provider == "sqlite" && (t == "integer" || t == "numeric" || t == "real") {
+		return field.Type != "String" && field.Type != "Bytes"
+	}
+
+	return contains(accepted, t)
+}
+
+// normalize lowercases a database type and strips its arguments, e.g. "VARCHAR(191)" becomes "varchar"
+func normalize(dbType string) string {
+	t := strings.ToLower(strings.TrimSpace(dbType))
+	if i := strings.IndexByte(t, '('); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+	t = strings.TrimSuffix(t, " unsigned")
+	for _, suffix := range []string{" without time zone", " with time zone"} {
+		t = strings.TrimSuffix(t, suffix)
+	}
+	return t
+}
+
+func contains(types []string, t string) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+func expectedType(field metadata.Field) string {
+	if field.IsList {
+		return field.Type + "[]"
+	}
+	return field.Type
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "nullable"
+	}
+	return "not null"
+}
@@ -0,0 +1,158 @@
+package schemacheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeEngine answers raw queries with a fixed response
+type fakeEngine struct {
+	response string
+}
+
+func (e *fakeEngine) Connect() error    { return nil }
+func (e *fakeEngine) Disconnect() error { return nil }
+func (e *fakeEngine) Name() string      { return "fake" }
+
+func (e *fakeEngine) Do(_ context.Context, _ interface{}, into interface{}) error {
+	return json.Unmarshal([]byte(e.response), into)
+}
+
+func (e *fakeEngine) Batch(context.Context, interface{}, interface{}) error {
+	panic("not implemented")
+}
+
+var schema = &metadata.Schema{
+	Provider: "postgresql",
+	Models: []metadata.Model{{
+		Name:   "User",
+		DBName: "users",
+		Fields: []metadata.Field{
+			{Name: "id", DBName: "id", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true, IsID: true},
+			{Name: "email", DBName: "email_address", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true},
+			{Name: "age", DBName: "age", Kind: metadata.FieldKindScalar, Type: "Int"},
+			{Name: "role", DBName: "role", Kind: metadata.FieldKindEnum, Type: "Role", IsRequired: true},
+			{Name: "tags", DBName: "tags", Kind: metadata.FieldKindScalar, Type: "String", IsList: true, IsRequired: true},
+			{Name: "posts", Kind: metadata.FieldKindRelation, Type: "Post", IsList: true},
+		},
+	}, {
+		Name:   "Post",
+		DBName: "Post",
+		Fields: []metadata.Field{
+			{Name: "id", DBName: "id", Kind: metadata.FieldKindScalar, Type: "Int", IsRequired: true, IsID: true},
+		},
+	}},
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  []Column
+		expected []Mismatch
+	}{{
+		name: "matching",
+		columns: []Column{
+			{Table: "users", Name: "id", Type: "text"},
+			{Table: "users", Name: "email_address", Type: "varchar"},
+			{Table: "users", Name: "age", Type: "int4", Nullable: true},
+			{Table: "users", Name: "role", Type: "enum"},
+			{Table: "users", Name: "tags", Type: "_text", Nullable: true},
+			{Table: "users", Name: "legacy", Type: "bool"},
+			{Table: "Post", Name: "id", Type: "int4"},
+		},
+	}, {
+		name: "missing table and column",
+		columns: []Column{
+			{Table: "users", Name: "id", Type: "text"},
+			{Table: "users", Name: "age", Type: "int4", Nullable: true},
+			{Table: "users", Name: "role", Type: "enum"},
+			{Table: "users", Name: "tags", Type: "_text"},
+		},
+		expected: []Mismatch{
+			{Kind: MissingColumn, Model: "User", Field: "email", Table: "users", Column: "email_address"},
+			{Kind: MissingTable, Model: "Post", Table: "Post"},
+		},
+	}, {
+		name: "drift",
+		columns: []Column{
+			{Table: "users", Name: "id", Type: "text"},
+			{Table: "users", Name: "email_address", Type: "varchar", Nullable: true},
+			{Table: "users", Name: "age", Type: "int8", Nullable: true},
+			{Table: "users", Name: "role", Type: "int4"},
+			{Table: "users", Name: "tags", Type: "text"},
+			{Table: "post", Name: "ID", Type: "int4"},
+		},
+		expected: []Mismatch{
+			{Kind: Nullability, Model: "User", Field: "email", Table: "users", Column: "email_address", Expected: "not null", Actual: "nullable"},
+			{Kind: TypeDrift, Model: "User", Field: "age", Table: "users", Column: "age", Expected: "Int", Actual: "int8"},
+			{Kind: TypeDrift, Model: "User", Field: "role", Table: "users", Column: "role", Expected: "Role", Actual: "int4"},
+			{Kind: TypeDrift, Model: "User", Field: "tags", Table: "users", Column: "tags", Expected: "String[]", Actual: "text"},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			massert.Equal(t, tt.expected, Compare(schema, tt.columns))
+		})
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		provider string
+		field    metadata.Field
+		dbType   string
+		expected bool
+	}{
+		{"mysql", metadata.Field{Type: "String"}, "VARCHAR(191)", true},
+		{"mysql", metadata.Field{Type: "Boolean"}, "tinyint", true},
+		{"mysql", metadata.Field{Type: "Int"}, "int unsigned", true},
+		{"mysql", metadata.Field{Type: "DateTime"}, "datetime", true},
+		{"mysql", metadata.Field{Type: "DateTime"}, "varchar", false},
+		{"mysql", metadata.Field{Type: "Role", Kind: metadata.FieldKindEnum}, "enum", true},
+		{"postgresql", metadata.Field{Type: "DateTime"}, "timestamp without time zone", true},
+		{"postgresql", metadata.Field{Type: "Json"}, "jsonb", true},
+		{"postgresql", metadata.Field{Type: "Bytes"}, "text", false},
+		{"sqlserver", metadata.Field{Type: "String"}, "nvarchar", true},
+		{"sqlserver", metadata.Field{Type: "Role", Kind: metadata.FieldKindEnum}, "nvarchar", true},
+		{"sqlite", metadata.Field{Type: "Boolean"}, "BOOLEAN", true},
+		{"sqlite", metadata.Field{Type: "Decimal"}, "DECIMAL", true},
+		{"sqlite", metadata.Field{Type: "DateTime"}, "INTEGER", true},
+		{"sqlite", metadata.Field{Type: "String"}, "INTEGER", false},
+		{"sqlite", metadata.Field{Type: "Int", IsList: true}, "TEXT", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+tt.field.Type+"/"+tt.dbType, func(t *testing.T) {
+			massert.Equal(t, tt.expected, compatible(tt.provider, tt.field, tt.dbType))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	e := &fakeEngine{response: `[
+		{"table_name":"users","column_name":"id","data_type":"text","is_nullable":"NO"},
+		{"table_name":"users","column_name":"email_address","data_type":"text","is_nullable":"NO"},
+		{"table_name":"users","column_name":"age","data_type":"int4","is_nullable":"YES"},
+		{"table_name":"users","column_name":"role","data_type":"enum","is_nullable":"NO"},
+		{"table_name":"users","column_name":"tags","data_type":"_text","is_nullable":"NO"}
+	]`}
+
+	if err := Check(context.Background(), e, schema, Warn); err != nil {
+		t.Fatalf("unexpected error in warn mode: %s", err)
+	}
+
+	err := Check(context.Background(), e, schema, Fail)
+	var checkErr *Error
+	if !errors.As(err, &checkErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	massert.Equal(t, []Mismatch{{Kind: MissingTable, Model: "Post", Table: "Post"}}, checkErr.Mismatches)
+	massert.Equal(t, "database does not match the prisma schema; run your migrations or regenerate the client:\n  missing table: table \"Post\" of model Post does not exist", err.Error())
+
+	_, err = Columns(context.Background(), e, "mongodb")
+	massert.Equal(t, `schema check is not supported for provider "mongodb"`, err.Error())
+}
@@ -0,0 +1,186 @@
+package sqldriver
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// rows are the rows of a raw query, which are read at once as the engine returns all of them in a single response
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+// newRows decodes the result of a raw query, an array of objects with a typed value for each column. Values without a
+// type, as returned by engines which don't keep them, are decoded by their JSON type.
+func newRows(data []byte) (*rows, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("sqldriver: decode rows: %w", err)
+	}
+
+	r := &rows{values: make([][]driver.Value, len(items))}
+	for i, item := range items {
+		// the columns are read in order, as database/sql scans values by their position
+		columns, values, err := entries(item)
+		if err != nil {
+			return nil, fmt.Errorf("sqldriver: decode rows: %w", err)
+		}
+		if i == 0 {
+			r.columns = columns
+		} else if len(columns) != len(r.columns) {
+			return nil, fmt.Errorf("sqldriver: decode rows: row %d has %d columns instead of %d", i, len(columns), len(r.columns))
+		}
+
+		row := make([]driver.Value, len(values))
+		for j, value := range values {
+			if row[j], err = convert(value); err != nil {
+				return nil, fmt.Errorf("sqldriver: column %s: %w", columns[j], err)
+			}
+		}
+		r.values[i] = row
+	}
+	return r, nil
+}
+
+// Columns returns the column names of the rows; they are only known if there is at least one row
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	r.values = nil
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// entries returns the keys and values of a JSON object in their order
+func entries(data []byte) ([]string, []json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if t, err := decoder.Token(); err != nil || t != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected an object, got %s", data)
+	}
+	var keys []string
+	var values []json.RawMessage
+	for decoder.More() {
+		t, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, t.(string))
+		values = append(values, value)
+	}
+	return keys, values, nil
+}
+
+// typed is a value of a raw query along with its type, e.g. {"prisma__type": "bigint", "prisma__value": "1"}
+type typed struct {
+	Type  *string         `json:"prisma__type"`
+	Value json.RawMessage `json:"prisma__value"`
+}
+
+// convert returns a JSON value of a raw query as a value of database/sql
+func convert(data json.RawMessage) (driver.Value, error) {
+	var t typed
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, err
+		}
+	}
+	if t.Type == nil {
+		return plain(data)
+	}
+	if len(t.Value) == 0 || string(t.Value) == "null" {
+		return nil, nil
+	}
+
+	switch *t.Type {
+	case "int", "bigint":
+		return strconv.ParseInt(literal(t.Value), 10, 64)
+	case "float", "double":
+		return strconv.ParseFloat(literal(t.Value), 64)
+	case "bool":
+		return strconv.ParseBool(literal(t.Value))
+	case "decimal":
+		// decimals are returned as strings to keep their precision
+		return literal(t.Value), nil
+	case "datetime", "date", "time":
+		s := literal(t.Value)
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly, "15:04:05.999999999"} {
+			if v, err := time.Parse(layout, s); err == nil {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid %s %s", *t.Type, t.Value)
+	case "bytes":
+		return base64.StdEncoding.DecodeString(literal(t.Value))
+	case "json":
+		return []byte(t.Value), nil
+	case "array":
+		var items []json.RawMessage
+		if err := json.Unmarshal(t.Value, &items); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(items))
+		for i, item := range items {
+			v, err := convert(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		// arrays are returned as JSON, as database/sql has no array type
+		return json.Marshal(values)
+	default:
+		return literal(t.Value), nil
+	}
+}
+
+// plain converts a JSON value without a type
+func plain(data json.RawMessage) (driver.Value, error) {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case nil, string, bool:
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	default:
+		// objects and arrays are returned as JSON
+		return []byte(data), nil
+	}
+}
+
+// literal returns the content of a JSON string, or the text of any other value, e.g. of a number
+func literal(data json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	return string(data)
+}
@@ -0,0 +1,242 @@
+// Package sqldriver exposes the query engine of a client as a database/sql driver, so that code and libraries which
+// expect a *sql.DB, e.g. migration tools or query builders, share the connections of the client. Queries are sent as
+// raw queries, so they use the SQL dialect and the placeholders of the database, e.g. $1 for PostgreSQL and ? for MySQL
+// and SQLite.
+package sqldriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/runtime/raw"
+)
+
+// Option configures a connector
+type Option func(*connector)
+
+// WithTransactionOptions sets the options of the interactive transactions which are started for sql transactions, e.g.
+// a timeout for transactions which take longer than the 5 seconds the engine allows by default. The isolation level of
+// sql.TxOptions takes precedence over the one of options.
+func WithTransactionOptions(options engine.TransactionOptions) Option {
+	return func(c *connector) {
+		c.options = options
+	}
+}
+
+// Open returns a database which sends its queries through e, e.g. the Engine of a connected client:
+//
+//	db := sqldriver.Open(client.Engine)
+//	rows, err := db.QueryContext(ctx, `SELECT id, title FROM "Post" WHERE published = $1`, true)
+//
+// The client must stay connected while the database is used; closing the database doesn't disconnect it.
+func Open(e engine.Engine, opts ...Option) *sql.DB {
+	return sql.OpenDB(NewConnector(e, opts...))
+}
+
+// NewConnector returns a connector which sends queries through e, e.g. to open a database with sql.OpenDB
+func NewConnector(e engine.Engine, opts ...Option) driver.Connector {
+	c := &connector{engine: e}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type connector struct {
+	engine  engine.Engine
+	options engine.TransactionOptions
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{connector: c}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return Driver{}
+}
+
+// Driver is the driver of the databases returned by Open. As queries are sent through an engine instead of a
+// connection string, databases can't be opened with sql.Open.
+type Driver struct{}
+
+// Open returns an error, as databases must be opened with Open or NewConnector
+func (Driver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("sqldriver: databases must be opened with sqldriver.Open instead of sql.Open")
+}
+
+type conn struct {
+	connector *connector
+
+	// tx is the id of the interactive transaction of the connection, if any
+	tx string
+}
+
+var (
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+)
+
+// engine returns the engine which sends the queries of the connection, i.e. within its transaction if there is one
+func (c *conn) engine() engine.Engine {
+	if c.tx != "" {
+		return engine.NewTransactionEngine(c.connector.engine, c.tx)
+	}
+	return c.connector.engine
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != "" {
+		return nil, errors.New("sqldriver: a transaction is already in progress")
+	}
+	transactor, ok := c.connector.engine.(engine.Transactor)
+	if !ok {
+		return nil, fmt.Errorf("sqldriver: the %s does not support interactive transactions", c.connector.engine.Name())
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sqldriver: read-only transactions are not supported")
+	}
+
+	options := c.connector.options
+	if level := sql.IsolationLevel(opts.Isolation); level != sql.LevelDefault {
+		var ok bool
+		if options.IsolationLevel, ok = isolationLevels[level]; !ok {
+			return nil, fmt.Errorf("sqldriver: isolation level %s is not supported", level)
+		}
+	}
+
+	id, err := transactor.StartTransaction(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	c.tx = id
+	return &tx{conn: c, transactor: transactor}, nil
+}
+
+// isolationLevels contains the names of the isolation levels of database/sql in the query engine
+var isolationLevels = map[sql.IsolationLevel]string{
+	sql.LevelReadUncommitted: "ReadUncommitted",
+	sql.LevelReadCommitted:   "ReadCommitted",
+	sql.LevelRepeatableRead:  "RepeatableRead",
+	sql.LevelSnapshot:        "Snapshot",
+	sql.LevelSerializable:    "Serializable",
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	params, err := parameters(args)
+	if err != nil {
+		return nil, err
+	}
+	var data json.RawMessage
+	if err := (raw.Raw{Engine: c.engine()}).QueryRaw(query, params...).Exec(engine.WithTypedResults(ctx), &data); err != nil {
+		return nil, err
+	}
+	return newRows(data)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	params, err := parameters(args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := (raw.Raw{Engine: c.engine()}).ExecuteRaw(query, params...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(result.Count), nil
+}
+
+// parameters returns the values of args, which must be positional
+func parameters(args []driver.NamedValue) ([]interface{}, error) {
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("sqldriver: named parameters are not supported, got %q", arg.Name)
+		}
+		params[i] = arg.Value
+	}
+	return params, nil
+}
+
+type tx struct {
+	conn       *conn
+	transactor engine.Transactor
+}
+
+func (t *tx) Commit() error {
+	id := t.conn.tx
+	t.conn.tx = ""
+	return t.transactor.CommitTransaction(context.Background(), id)
+}
+
+func (t *tx) Rollback() error {
+	id := t.conn.tx
+	t.conn.tx = ""
+	return t.transactor.RollbackTransaction(context.Background(), id)
+}
+
+// stmt is a query which is sent as is on each execution, as raw queries can't be prepared
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+var (
+	_ driver.StmtQueryContext = (*stmt)(nil)
+	_ driver.StmtExecContext  = (*stmt)(nil)
+)
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1, as the placeholders of the query are not parsed
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), named(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), named(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	values := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		values[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return values
+}
@@ -0,0 +1,185 @@
+package sqldriver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeEngine returns the responses in order and records the queries and transactions
+type fakeEngine struct {
+	responses []string
+	queries   []string
+	events    []string
+}
+
+func (e *fakeEngine) Connect() error    { return nil }
+func (e *fakeEngine) Disconnect() error { return nil }
+func (e *fakeEngine) Name() string      { return "fake engine" }
+func (e *fakeEngine) Batch(ctx context.Context, payload interface{}, into interface{}) error {
+	return nil
+}
+func (e *fakeEngine) Do(ctx context.Context, payload interface{}, into interface{}) error {
+	e.queries = append(e.queries, payload.(protocol.GQLRequest).Query)
+	response := e.responses[0]
+	e.responses = e.responses[1:]
+	return json.Unmarshal([]byte(response), into)
+}
+
+func (e *fakeEngine) StartTransaction(ctx context.Context, options engine.TransactionOptions) (string, error) {
+	e.events = append(e.events, "start "+options.IsolationLevel+" "+options.Timeout.String())
+	return "tx1", nil
+}
+
+func (e *fakeEngine) CommitTransaction(ctx context.Context, id string) error {
+	e.events = append(e.events, "commit "+id)
+	return nil
+}
+
+func (e *fakeEngine) RollbackTransaction(ctx context.Context, id string) error {
+	e.events = append(e.events, "rollback "+id)
+	return nil
+}
+
+// nameOnlyEngine doesn't support interactive transactions
+type nameOnlyEngine struct {
+	engine.Engine
+}
+
+func (nameOnlyEngine) Name() string { return "data proxy" }
+
+func TestQuery(t *testing.T) {
+	e := &fakeEngine{responses: []string{`[
+		{"title":{"prisma__type":"string","prisma__value":"a"},"id":{"prisma__type":"bigint","prisma__value":"9007199254740993"},"published":{"prisma__type":"bool","prisma__value":true},"createdAt":{"prisma__type":"datetime","prisma__value":"2020-01-02T03:04:05+00:00"},"price":{"prisma__type":"decimal","prisma__value":"1.10"},"data":{"prisma__type":"bytes","prisma__value":"aGk="},"meta":{"prisma__type":"json","prisma__value":{"a":1}},"content":{"prisma__type":"null","prisma__value":null}},
+		{"title":"b","id":2,"published":false,"createdAt":{"prisma__type":"date","prisma__value":"2020-01-02"},"price":1.5,"data":null,"meta":{"a":2},"content":"c"}
+	]`}}
+	db := Open(e)
+	defer db.Close()
+
+	rows, err := db.QueryContext(context.Background(), `SELECT * FROM "Post" WHERE published = $1`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []string{"title", "id", "published", "createdAt", "price", "data", "meta", "content"}, columns)
+
+	type post struct {
+		Title     string
+		ID        int64
+		Published bool
+		CreatedAt time.Time
+		Price     string
+		Data      []byte
+		Meta      string
+		Content   sql.NullString
+	}
+	var posts []post
+	for rows.Next() {
+		var p post
+		if err := rows.Scan(&p.Title, &p.ID, &p.Published, &p.CreatedAt, &p.Price, &p.Data, &p.Meta, &p.Content); err != nil {
+			t.Fatal(err)
+		}
+		posts = append(posts, p)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, []post{{
+		Title:     "a",
+		ID:        9007199254740993,
+		Published: true,
+		CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("", 0)),
+		Price:     "1.10",
+		Data:      []byte("hi"),
+		Meta:      `{"a":1}`,
+	}, {
+		Title:     "b",
+		ID:        2,
+		CreatedAt: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Price:     "1.5",
+		Meta:      `{"a":2}`,
+		Content:   sql.NullString{String: "c", Valid: true},
+	}}, posts)
+	massert.Equal(t, []string{
+		`mutation {result: queryRaw(parameters:"[true]",query:"SELECT * FROM \"Post\" WHERE published = $1",) }`,
+	}, e.queries)
+}
+
+func TestExec(t *testing.T) {
+	e := &fakeEngine{responses: []string{`3`}}
+	db := Open(e)
+	defer db.Close()
+
+	result, err := db.ExecContext(context.Background(), `UPDATE "Post" SET views = views + ?`, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, int64(3), affected)
+}
+
+func TestTransaction(t *testing.T) {
+	e := &fakeEngine{responses: []string{`1`, `1`}}
+	db := Open(e, WithTransactionOptions(engine.TransactionOptions{Timeout: time.Minute}))
+	defer db.Close()
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "Post"`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "Post"`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, []string{"start Serializable 1m0s", "commit tx1", "start  1m0s", "rollback tx1"}, e.events)
+
+	_, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	massert.Equal(t, "sqldriver: read-only transactions are not supported", err.Error())
+
+	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelLinearizable})
+	massert.Equal(t, "sqldriver: isolation level Linearizable is not supported", err.Error())
+
+	_, err = Open(nameOnlyEngine{}).Begin()
+	massert.Equal(t, "sqldriver: the data proxy does not support interactive transactions", err.Error())
+}
+
+func TestErrors(t *testing.T) {
+	db := Open(&fakeEngine{})
+	defer db.Close()
+
+	_, err := db.Exec(`SELECT 1`, sql.Named("a", 1))
+	massert.Equal(t, `sqldriver: named parameters are not supported, got "a"`, err.Error())
+
+	_, err = Driver{}.Open("")
+	massert.Equal(t, "sqldriver: databases must be opened with sqldriver.Open instead of sql.Open", err.Error())
+}
@@ -0,0 +1,160 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/logger"
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+)
+
+// Option configures an interactive transaction
+type Option func(*options)
+
+type options struct {
+	engine.TransactionOptions
+
+	// settings return the settings which are set at the start of the transaction
+	settings []func(ctx context.Context) (map[string]string, error)
+}
+
+// WithTimeout sets how long the transaction may run before the engine rolls it back. Defaults to 5 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithMaxWait sets how long to wait for a database connection to start the transaction. Defaults to 2 seconds.
+func WithMaxWait(maxWait time.Duration) Option {
+	return func(o *options) {
+		o.MaxWait = maxWait
+	}
+}
+
+// WithIsolationLevel sets the isolation level of the transaction, e.g. "Serializable" or "ReadCommitted". Defaults to
+// the isolation level of the database.
+func WithIsolationLevel(level string) Option {
+	return func(o *options) {
+		o.IsolationLevel = level
+	}
+}
+
+// WithSetLocal sets a PostgreSQL setting for the duration of the transaction, like `SET LOCAL key = value`, e.g. for
+// row-level security policies which use current_setting('app.tenant_id'). Custom settings need a prefix such as "app.".
+func WithSetLocal(key, value string) Option {
+	return WithSetLocalFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{key: value}, nil
+	})
+}
+
+// WithSetLocalFunc is like WithSetLocal, but determines the settings from the context the transaction is started
+// with, e.g. the tenant and user of a request. The transaction is rolled back if fn returns an error.
+func WithSetLocalFunc(fn func(ctx context.Context) (map[string]string, error)) Option {
+	return func(o *options) {
+		o.settings = append(o.settings, fn)
+	}
+}
+
+// Run starts an interactive transaction on e and calls fn with an engine which sends all queries within it. The
+// transaction is committed if fn returns nil and rolled back otherwise, including when fn panics.
+func Run(ctx context.Context, e engine.Engine, fn func(tx engine.Engine) error, opts ...Option) error {
+	t, ok := e.(engine.Transactor)
+	if !ok {
+		return fmt.Errorf("the %s does not support interactive transactions", e.Name())
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	id, err := t.StartTransaction(ctx, o.TransactionOptions)
+	if err != nil {
+		return err
+	}
+
+	done := false
+	defer func() {
+		if done {
+			return
+		}
+		// fn panicked or called runtime.Goexit, e.g. via t.FailNow in tests
+		if err := t.RollbackTransaction(context.Background(), id); err != nil {
+			logger.Debug.Printf("could not roll back transaction %s: %s", id, err)
+		}
+	}()
+
+	tx := engine.NewTransactionEngine(e, id)
+	fnErr := setLocal(ctx, tx, o.settings)
+	if fnErr == nil {
+		fnErr = fn(tx)
+	}
+	done = true
+
+	if fnErr != nil {
+		if err := t.RollbackTransaction(ctx, id); err != nil {
+			return fmt.Errorf("%w (after %s)", err, fnErr)
+		}
+		return fnErr
+	}
+
+	return t.CommitTransaction(ctx, id)
+}
+
+// setLocal applies the settings within the transaction with set_config, which is the same as SET LOCAL but accepts
+// the keys and values as parameters
+func setLocal(ctx context.Context, tx engine.Engine, settings []func(ctx context.Context) (map[string]string, error)) error {
+	values := make(map[string]string)
+	for _, fn := range settings {
+		s, err := fn(ctx)
+		if err != nil {
+			return fmt.Errorf("transaction settings: %w", err)
+		}
+		for key, value := range s {
+			values[key] = value
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	calls := make([]string, len(keys))
+	params := make([]string, 0, len(keys)*2)
+	for i, key := range keys {
+		calls[i] = fmt.Sprintf("set_config($%d, $%d, true)", i*2+1, i*2+2)
+		params = append(params, key, values[key])
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	q := builder.NewQuery()
+	q.Engine = tx
+	q.Operation = "mutation"
+	q.Method = "queryRaw"
+	q.Inputs = []builder.Input{{
+		Name:  "query",
+		Value: "SELECT " + strings.Join(calls, ", "),
+	}, {
+		Name:  "parameters",
+		Value: string(encoded),
+	}}
+	var result json.RawMessage
+	if err := q.Exec(ctx, &result); err != nil {
+		return fmt.Errorf("transaction settings: %w", err)
+	}
+	return nil
+}
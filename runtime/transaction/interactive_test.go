@@ -0,0 +1,79 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/engine"
+	"github.com/steebchen/prisma-client-go/engine/protocol"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeEngine records the transaction calls and queries it receives
+type fakeEngine struct {
+	calls []string
+}
+
+func (e *fakeEngine) Connect() error    { return nil }
+func (e *fakeEngine) Disconnect() error { return nil }
+func (e *fakeEngine) Name() string      { return "fake" }
+func (e *fakeEngine) Do(ctx context.Context, payload interface{}, v interface{}) error {
+	e.calls = append(e.calls, payload.(protocol.GQLRequest).Query)
+	return nil
+}
+func (e *fakeEngine) Batch(ctx context.Context, payload interface{}, v interface{}) error {
+	return nil
+}
+func (e *fakeEngine) StartTransaction(context.Context, engine.TransactionOptions) (string, error) {
+	e.calls = append(e.calls, "start")
+	return "tx", nil
+}
+func (e *fakeEngine) CommitTransaction(context.Context, string) error {
+	e.calls = append(e.calls, "commit")
+	return nil
+}
+func (e *fakeEngine) RollbackTransaction(context.Context, string) error {
+	e.calls = append(e.calls, "rollback")
+	return nil
+}
+
+type tenantKey struct{}
+
+func TestRun_setLocal(t *testing.T) {
+	e := &fakeEngine{}
+	ctx := context.WithValue(context.Background(), tenantKey{}, "42")
+
+	err := Run(ctx, e, func(tx engine.Engine) error {
+		e.calls = append(e.calls, "fn")
+		return nil
+	}, WithSetLocal("app.user_id", "alice"), WithSetLocalFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"app.tenant_id": ctx.Value(tenantKey{}).(string)}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	massert.Equal(t, []string{
+		"start",
+		`mutation {result: queryRaw(parameters:"[\"app.tenant_id\",\"42\",\"app.user_id\",\"alice\"]",query:"SELECT set_config($1, $2, true), set_config($3, $4, true)",) }`,
+		"fn",
+		"commit",
+	}, e.calls)
+}
+
+func TestRun_setLocalError(t *testing.T) {
+	e := &fakeEngine{}
+	expected := errors.New("no tenant")
+
+	err := Run(context.Background(), e, func(tx engine.Engine) error {
+		e.calls = append(e.calls, "fn")
+		return nil
+	}, WithSetLocalFunc(func(ctx context.Context) (map[string]string, error) {
+		return nil, expected
+	}))
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected %v, got %v", expected, err)
+	}
+	massert.Equal(t, []string{"start", "rollback"}, e.calls)
+}
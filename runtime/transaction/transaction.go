@@ -36,8 +36,14 @@ type Exec struct {
 
 func (r Exec) Exec(ctx context.Context) error {
 	r.requests = make([]protocol.GQLRequest, len(r.queries))
-	for i, query := range r.queries {
-		str, err := query.ExtractQuery().Build()
+	queries := make([]builder.Query, len(r.queries))
+	for i, q := range r.queries {
+		query := q.ExtractQuery()
+		if err := builder.ApplyPolicy(ctx, r.engine, &query); err != nil {
+			return err
+		}
+		queries[i] = query
+		str, err := query.Build()
 		if err != nil {
 			return err
 		}
@@ -60,6 +66,9 @@ func (r Exec) Exec(ctx context.Context) error {
 	if err := r.engine.Batch(ctx, payload, &result); err != nil {
 		return fmt.Errorf("could not send raw query: %w", err)
 	}
+	for _, query := range queries {
+		builder.InvalidatePolicy(ctx, r.engine, query)
+	}
 	if len(result.Errors) > 0 {
 		first := result.Errors[0]
 		return fmt.Errorf("pql error: %s", first.RawMessage())
@@ -15,6 +15,35 @@ func IsErrNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
 }
 
+// ErrStaleRecord gets returned when an update expects a version of a record, but the record was changed or deleted
+// since that version was read
+var ErrStaleRecord = errors.New("ErrStaleRecord")
+
+// IsErrStaleRecord is true if the error is a ErrStaleRecord, which gets returned when an update with an expected
+// version does not match a record, as it was changed or deleted concurrently.
+func IsErrStaleRecord(err error) bool {
+	return errors.Is(err, ErrStaleRecord)
+}
+
+// ErrInvalidCursor gets returned when a keyset pagination cursor is malformed or was derived from a query with a
+// different order
+var ErrInvalidCursor = errors.New("ErrInvalidCursor")
+
+// IsErrInvalidCursor is true if the error is a ErrInvalidCursor, which gets returned when a cursor passed to Keyset
+// was not returned by a query with the same order, e.g. because a client changed it.
+func IsErrInvalidCursor(err error) bool {
+	return errors.Is(err, ErrInvalidCursor)
+}
+
+// ErrInvalidFieldMask gets returned when a field mask contains a field which doesn't exist or can't be updated
+var ErrInvalidFieldMask = errors.New("ErrInvalidFieldMask")
+
+// IsErrInvalidFieldMask is true if the error is a ErrInvalidFieldMask, which gets returned when a field mask passed to
+// UpdateFromStruct contains an unknown field, e.g. because a client sent it.
+func IsErrInvalidFieldMask(err error) bool {
+	return errors.Is(err, ErrInvalidFieldMask)
+}
+
 type F interface {
 	~string
 }
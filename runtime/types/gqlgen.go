@@ -0,0 +1,66 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MarshalGQL implements the graphql.Marshaler interface of gqlgen. The value is written as a string, as GraphQL
+// integers are limited to 32 bits and JavaScript clients lose precision beyond 2^53.
+func (m BigInt) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, strconv.Quote(strconv.FormatInt(int64(m), 10)))
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface of gqlgen, accepting both strings and integers
+func (m *BigInt) UnmarshalGQL(v interface{}) error {
+	if m == nil {
+		return errors.New("BigInt: UnmarshalGQL on nil pointer")
+	}
+	var str string
+	switch v := v.(type) {
+	case string:
+		str = v
+	case json.Number:
+		str = v.String()
+	case int:
+		*m = BigInt(v)
+		return nil
+	case int64:
+		*m = BigInt(v)
+		return nil
+	default:
+		return fmt.Errorf("BigInt: UnmarshalGQL: unsupported value of type %T", v)
+	}
+	i, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return fmt.Errorf("BigInt: UnmarshalGQL: %w", err)
+	}
+	*m = BigInt(i)
+	return nil
+}
+
+// MarshalGQL implements the graphql.Marshaler interface of gqlgen. The value is written as the JSON it contains, so
+// that clients receive objects and arrays instead of strings.
+func (m JSON) MarshalGQL(w io.Writer) {
+	if m == nil {
+		_, _ = io.WriteString(w, "null")
+		return
+	}
+	_, _ = w.Write(m)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface of gqlgen, encoding any GraphQL input value as JSON
+func (m *JSON) UnmarshalGQL(v interface{}) error {
+	if m == nil {
+		return errors.New("JSON: UnmarshalGQL on nil pointer")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("JSON: UnmarshalGQL: %w", err)
+	}
+	*m = data
+	return nil
+}
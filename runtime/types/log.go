@@ -0,0 +1,100 @@
+package types
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive fields in the String and LogValue methods of generated models
+const Redacted = "[redacted]"
+
+var logValuer = reflect.TypeOf((*slog.LogValuer)(nil)).Elem()
+
+// LogValue converts a field value to a slog.Value. Optional values are dereferenced, JSON is logged as a string and
+// bytes are only logged by their length so that log output stays readable.
+func LogValue(value interface{}) slog.Value {
+	if value == nil {
+		return slog.AnyValue(nil)
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		return LogValue(v.Elem().Interface())
+	}
+
+	// lists of values which redact fields themselves, e.g. composite types, are logged as a group with index keys, as
+	// handlers would otherwise marshal the elements directly and bypass their LogValue method
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && v.Type().Elem().Implements(logValuer) {
+		attrs := make([]slog.Attr, v.Len())
+		for i := range attrs {
+			attrs[i] = slog.Attr{Key: strconv.Itoa(i), Value: slog.AnyValue(v.Index(i).Interface()).Resolve()}
+		}
+		return slog.GroupValue(attrs...)
+	}
+
+	switch v := value.(type) {
+	case JSON:
+		return slog.StringValue(string(v))
+	case Bytes:
+		return slog.StringValue(fmt.Sprintf("<%d bytes>", len(v)))
+	case BigInt:
+		return slog.Int64Value(int64(v))
+	case Decimal:
+		return slog.StringValue(v.String())
+	}
+
+	return slog.AnyValue(value)
+}
+
+// FormatLogValue formats a group value as a readable string, e.g. `User{id: "123", email: [redacted]}`
+func FormatLogValue(name string, value slog.Value) string {
+	var builder strings.Builder
+	builder.WriteString(name)
+	writeLogValue(&builder, value)
+	return builder.String()
+}
+
+func writeLogValue(builder *strings.Builder, value slog.Value) {
+	value = value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		builder.WriteString("{")
+		for i, attr := range value.Group() {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			builder.WriteString(attr.Key)
+			builder.WriteString(": ")
+			writeLogValue(builder, attr.Value)
+		}
+		builder.WriteString("}")
+	case slog.KindString:
+		if s := value.String(); s == Redacted {
+			builder.WriteString(s)
+		} else {
+			builder.WriteString(fmt.Sprintf("%q", s))
+		}
+	case slog.KindAny:
+		v := reflect.ValueOf(value.Any())
+		if v.Kind() == reflect.Slice {
+			builder.WriteString("[")
+			for i := 0; i < v.Len(); i++ {
+				if i > 0 {
+					builder.WriteString(", ")
+				}
+				writeLogValue(builder, LogValue(v.Index(i).Interface()))
+			}
+			builder.WriteString("]")
+			return
+		}
+		builder.WriteString(fmt.Sprintf("%v", value.Any()))
+	default:
+		builder.WriteString(value.String())
+	}
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type secret struct {
+	Name     string
+	Password string
+}
+
+func (s secret) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", s.Name),
+		slog.String("password", Redacted),
+	)
+}
+
+func TestFormatLogValue(t *testing.T) {
+	name := "John"
+	date := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    slog.Value
+		expected string
+	}{{
+		name: "scalars",
+		value: slog.GroupValue(
+			slog.Attr{Key: "id", Value: LogValue("123")},
+			slog.Attr{Key: "age", Value: LogValue(5)},
+			slog.Attr{Key: "active", Value: LogValue(true)},
+			slog.Attr{Key: "big", Value: LogValue(BigInt(9007199254740993))},
+			slog.Attr{Key: "createdAt", Value: LogValue(date)},
+		),
+		expected: `User{id: "123", age: 5, active: true, big: 9007199254740993, createdAt: 2020-01-02 03:04:05 +0000 UTC}`,
+	}, {
+		name: "optional values",
+		value: slog.GroupValue(
+			slog.Attr{Key: "name", Value: LogValue(&name)},
+			slog.Attr{Key: "nickname", Value: LogValue((*string)(nil))},
+		),
+		expected: `User{name: "John", nickname: <nil>}`,
+	}, {
+		name: "nullable values",
+		value: slog.GroupValue(
+			slog.Attr{Key: "name", Value: LogValue(NullableOf(name))},
+			slog.Attr{Key: "nickname", Value: LogValue(Nullable[string]{})},
+			slog.Attr{Key: "big", Value: LogValue(NullableOf(BigInt(1)))},
+		),
+		expected: `User{name: "John", nickname: <nil>, big: 1}`,
+	}, {
+		name: "redacted",
+		value: slog.GroupValue(
+			slog.String("email", Redacted),
+		),
+		expected: `User{email: [redacted]}`,
+	}, {
+		name: "json and bytes",
+		value: slog.GroupValue(
+			slog.Attr{Key: "json", Value: LogValue(JSON(`{"a":1}`))},
+			slog.Attr{Key: "bytes", Value: LogValue(Bytes("abc"))},
+		),
+		expected: `User{json: "{\"a\":1}", bytes: "<3 bytes>"}`,
+	}, {
+		name: "lists",
+		value: slog.GroupValue(
+			slog.Attr{Key: "tags", Value: LogValue([]string{"a", "b"})},
+		),
+		expected: `User{tags: ["a", "b"]}`,
+	}, {
+		name: "nested log valuers",
+		value: slog.GroupValue(
+			slog.Attr{Key: "secret", Value: LogValue(secret{Name: "a", Password: "b"})},
+			slog.Attr{Key: "secrets", Value: LogValue([]secret{{Name: "c", Password: "d"}})},
+		),
+		expected: `User{secret: {name: "a", password: [redacted]}, secrets: {0: {name: "c", password: [redacted]}}}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			massert.Equal(t, tt.expected, FormatLogValue("User", tt.value))
+		})
+	}
+}
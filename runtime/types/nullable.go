@@ -0,0 +1,79 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+)
+
+// Nullable is an optional value without a pointer. Generated models use it for optional fields instead of pointers
+// if the generator option optionalFields is set to "nullable". The zero value is NULL, and two values can be compared
+// with == if T is comparable.
+type Nullable[T any] struct {
+	// Value is the value, which is the zero value of T if Valid is false
+	Value T
+	// Valid is true if the value is set, and false if it is NULL
+	Valid bool
+}
+
+// NullableOf returns a valid Nullable of the given value
+func NullableOf[T any](value T) Nullable[T] {
+	return Nullable[T]{Value: value, Valid: true}
+}
+
+// NullableFromPtr returns a Nullable of the value the pointer points to, which is NULL if the pointer is nil
+func NullableFromPtr[T any](value *T) Nullable[T] {
+	if value == nil {
+		return Nullable[T]{}
+	}
+	return NullableOf(*value)
+}
+
+// Get returns the value and whether it is set, like the accessor methods of optional fields
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Valid
+}
+
+// Ptr returns a pointer to a copy of the value, or nil if the value is NULL, e.g. for XOptional methods
+func (n Nullable[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Value
+}
+
+// ValueOr returns the value, or fallback if the value is NULL
+func (n Nullable[T]) ValueOr(fallback T) T {
+	if !n.Valid {
+		return fallback
+	}
+	return n.Value
+}
+
+// MarshalJSON encodes the value, or null if the value is NULL
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	// encode a pointer so that methods with pointer receivers, e.g. of BigInt, are used
+	return json.Marshal(&n.Value)
+}
+
+// UnmarshalJSON decodes the value, where null sets the value to NULL
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Nullable[T]{}
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*n = NullableOf(value)
+	return nil
+}
+
+// LogValue implements slog.LogValuer, so that the value is logged like the pointer of an optional field
+func (n Nullable[T]) LogValue() slog.Value {
+	return LogValue(n.Ptr())
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type nullableModel struct {
+	Name  Nullable[string] `json:"name"`
+	Count Nullable[BigInt] `json:"count"`
+}
+
+func TestNullable_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		value nullableModel
+		json  string
+	}{{
+		name:  "null",
+		value: nullableModel{},
+		json:  `{"name":null,"count":null}`,
+	}, {
+		name:  "valid",
+		value: nullableModel{Name: NullableOf("a"), Count: NullableOf(BigInt(9007199254740993))},
+		json:  `{"name":"a","count":"9007199254740993"}`,
+	}, {
+		name:  "zero values",
+		value: nullableModel{Name: NullableOf(""), Count: NullableOf(BigInt(0))},
+		json:  `{"name":"","count":"0"}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.json, string(data))
+
+			var actual nullableModel
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.value, actual)
+		})
+	}
+}
+
+func TestNullable_UnmarshalJSON_missing(t *testing.T) {
+	actual := nullableModel{Name: NullableOf("a")}
+	if err := json.Unmarshal([]byte(`{"name":null}`), &actual); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, nullableModel{}, actual)
+}
+
+func TestNullable_helpers(t *testing.T) {
+	null := NullableFromPtr[string](nil)
+	massert.Equal(t, Nullable[string]{}, null)
+	massert.Equal(t, (*string)(nil), null.Ptr())
+	massert.Equal(t, "fallback", null.ValueOr("fallback"))
+
+	valid := NullableFromPtr(Ptr("a"))
+	massert.Equal(t, NullableOf("a"), valid)
+	massert.Equal(t, "a", *valid.Ptr())
+	massert.Equal(t, "a", valid.ValueOr("fallback"))
+	value, ok := valid.Get()
+	massert.Equal(t, "a", value)
+	massert.Equal(t, true, ok)
+}
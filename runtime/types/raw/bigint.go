@@ -1,3 +1,28 @@
 package raw
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type BigInt int64
+
+// UnmarshalJSON accepts both JSON numbers and strings, as raw queries may return bigint values in either form.
+// Values are parsed as integers directly so that values beyond 2^53 don't lose precision.
+func (r *BigInt) UnmarshalJSON(b []byte) error {
+	str := string(b)
+	if strings.HasPrefix(str, `"`) {
+		var err error
+		str, err = strconv.Unquote(str)
+		if err != nil {
+			return fmt.Errorf("unquote: %w", err)
+		}
+	}
+	i, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	*r = BigInt(i)
+	return nil
+}
@@ -0,0 +1,65 @@
+package raw
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestBigInt_UnmarshalJSON(t *testing.T) {
+	type args struct {
+		b []byte
+	}
+	tests := []struct {
+		name     string
+		expected BigInt
+		args     args
+		wantErr  bool
+	}{{
+		name:     "number",
+		expected: 5,
+		args: args{
+			b: []byte(`5`),
+		},
+	}, {
+		name:     "string",
+		expected: 5,
+		args: args{
+			b: []byte(`"5"`),
+		},
+	}, {
+		name:     "number beyond 2^53",
+		expected: 9007199254740993,
+		args: args{
+			b: []byte(`9007199254740993`),
+		},
+	}, {
+		name:     "string beyond 2^53",
+		expected: 9223372036854775807,
+		args: args{
+			b: []byte(`"9223372036854775807"`),
+		},
+	}, {
+		name:    "error on overflow",
+		wantErr: true,
+		args: args{
+			b: []byte(`9223372036854775808`),
+		},
+	}, {
+		name:    "error on float",
+		wantErr: true,
+		args: args{
+			b: []byte(`1.5`),
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v BigInt
+			if err := json.Unmarshal(tt.args.b, &v); (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			massert.Equal(t, tt.expected, v)
+		})
+	}
+}
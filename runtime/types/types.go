@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -16,6 +18,56 @@ type BatchResult struct {
 	Count int `json:"count"`
 }
 
+// Page is a page of the records of a query, along with the number of all records which match the query
+type Page[T any] struct {
+	Items     []T `json:"items"`
+	Total     int `json:"total"`
+	Page      int `json:"page"`
+	PageCount int `json:"pageCount"`
+}
+
+// NewPage returns the page with the given number of records, which has a size of pageSize out of total records
+func NewPage[T any](items []T, total, page, pageSize int) *Page[T] {
+	return &Page[T]{
+		Items:     items,
+		Total:     total,
+		Page:      page,
+		PageCount: (total + pageSize - 1) / pageSize,
+	}
+}
+
+// KeysetPage is a page of the records of a query along with the cursor of the next page, which is empty if there are no
+// more records
+type KeysetPage[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next,omitempty"`
+}
+
+// Ptr returns a pointer to the given value, which is useful for optional fields and XIfPresent or XOptional methods
+func Ptr[T any](value T) *T {
+	return &value
+}
+
+// Null returns a nil pointer of the given type, which can be used to explicitly set or query for SQL NULL
+func Null[T any]() *T {
+	return nil
+}
+
+// If returns param if cond is true, and the zero value of its type otherwise, i.e. an empty param or nil, which the
+// queries of the client skip
+func If[T any](cond bool, param T) T {
+	if !cond {
+		var empty T
+		return empty
+	}
+	return param
+}
+
+// IsZero returns whether value is the zero value of its type, e.g. an empty string, a nil pointer or a zero time
+func IsZero[T any](value T) bool {
+	return reflect.ValueOf(&value).Elem().IsZero()
+}
+
 // DateTime is a type alias for time.Time
 type DateTime = time.Time
 
@@ -29,13 +81,18 @@ type Bytes = []byte
 type BigInt int64
 
 // UnmarshalJSON converts the Prisma QE value of string to int64
+// Plain JSON numbers are accepted as well and are parsed directly, so values beyond 2^53 don't lose precision.
 func (m *BigInt) UnmarshalJSON(data []byte) error {
 	if m == nil {
 		return errors.New("BigInt: UnmarshalJSON on nil pointer")
 	}
-	str, err := strconv.Unquote(string(data))
-	if err != nil {
-		return fmt.Errorf("BigInt: unquote: %w", err)
+	str := string(data)
+	if strings.HasPrefix(str, "\"") {
+		var err error
+		str, err = strconv.Unquote(str)
+		if err != nil {
+			return fmt.Errorf("BigInt: unquote: %w", err)
+		}
 	}
 	i, err := strconv.ParseInt(str, 10, 64)
 	if err != nil {
@@ -57,11 +114,14 @@ func (m *BigInt) MarshalJSON() ([]byte, error) {
 type JSON json.RawMessage
 
 // MarshalJSON returns m as the JSON encoding of m.
+// The value is encoded as a JSON string, as the Prisma QE expects json values to be serialized.
+// JSON string escaping is used (as opposed to Go quoting) so that control characters and
+// non-printable runes result in valid JSON and GraphQL.
 func (m JSON) MarshalJSON() ([]byte, error) {
 	if m == nil {
 		return []byte("null"), nil
 	}
-	return []byte(fmt.Sprintf("%q", m)), nil
+	return json.Marshal(string(m))
 }
 
 // UnmarshalJSON sets *m to a copy of data.
@@ -69,8 +129,8 @@ func (m *JSON) UnmarshalJSON(data []byte) error {
 	if m == nil {
 		return errors.New("JSON: UnmarshalJSON on nil pointer")
 	}
-	str, err := strconv.Unquote(string(data))
-	if err != nil {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
 		return fmt.Errorf("JSON: UnmarshalJSON error: %w", err)
 	}
 	*m = append((*m)[0:0], str...)
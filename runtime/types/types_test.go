@@ -0,0 +1,159 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/fixtures"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestJSON_roundTrip(t *testing.T) {
+	for _, tt := range fixtures.SpecialStrings {
+		t.Run(tt.Name, func(t *testing.T) {
+			inner, err := json.Marshal(map[string]string{"value": tt.Value})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := json.Marshal(JSON(inner))
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+
+			if !json.Valid(data) {
+				t.Fatalf("MarshalJSON() produced invalid json: %s", data)
+			}
+
+			var actual JSON
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+
+			massert.Equal(t, string(inner), string(actual))
+
+			var decoded map[string]string
+			if err := json.Unmarshal(actual, &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, tt.Value, decoded["value"])
+		})
+	}
+}
+
+func TestJSON_UnmarshalJSON_surrogatePairs(t *testing.T) {
+	var actual JSON
+	if err := json.Unmarshal([]byte(`"{\"a\":\"\ud83d\ude00 \u00fc\"}"`), &actual); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+
+	massert.Equal(t, `{"a":"😀 ü"}`, string(actual))
+}
+
+func TestBigInt_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected BigInt
+		wantErr  bool
+	}{{
+		name:     "string",
+		data:     `"9007199254740993"`,
+		expected: 9007199254740993,
+	}, {
+		name:     "number",
+		data:     `9007199254740993`,
+		expected: 9007199254740993,
+	}, {
+		name:    "invalid",
+		data:    `"abc"`,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual BigInt
+			if err := json.Unmarshal([]byte(tt.data), &actual); (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			massert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestBigInt_GQL(t *testing.T) {
+	var buf bytes.Buffer
+	BigInt(9007199254740993).MarshalGQL(&buf)
+	massert.Equal(t, `"9007199254740993"`, buf.String())
+
+	for _, v := range []interface{}{"9007199254740993", json.Number("9007199254740993"), int64(9007199254740993)} {
+		var actual BigInt
+		if err := actual.UnmarshalGQL(v); err != nil {
+			t.Fatal(err)
+		}
+		massert.Equal(t, BigInt(9007199254740993), actual)
+	}
+
+	var actual BigInt
+	massert.Equal(t, "BigInt: UnmarshalGQL: unsupported value of type float64", actual.UnmarshalGQL(1.5).Error())
+}
+
+func TestJSON_GQL(t *testing.T) {
+	var buf bytes.Buffer
+	JSON(`{"a":[1,"b"]}`).MarshalGQL(&buf)
+	massert.Equal(t, `{"a":[1,"b"]}`, buf.String())
+
+	var actual JSON
+	if err := actual.UnmarshalGQL(map[string]interface{}{"a": []interface{}{json.Number("1"), "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, JSON(`{"a":[1,"b"]}`), actual)
+}
+
+func TestNewPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		pageCount int
+	}{
+		{"empty", 0, 0},
+		{"partial page", 5, 3},
+		{"full pages", 6, 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewPage([]string{"a"}, tt.total, 2, 2)
+			massert.Equal(t, tt.pageCount, page.PageCount)
+			massert.Equal(t, 2, page.Page)
+			massert.Equal(t, tt.total, page.Total)
+		})
+	}
+}
+
+func TestIf(t *testing.T) {
+	massert.Equal(t, "a", If(true, "a"))
+	massert.Equal(t, "", If(false, "a"))
+
+	var param fmt.Stringer = time.Second
+	massert.Equal(t, param, If(true, param))
+	massert.Equal(t, nil, If(false, param))
+}
+
+func TestIsZero(t *testing.T) {
+	massert.Equal(t, true, IsZero(""))
+	massert.Equal(t, false, IsZero("a"))
+	massert.Equal(t, true, IsZero(time.Time{}))
+	massert.Equal(t, false, IsZero(time.Now()))
+	massert.Equal(t, true, IsZero((*string)(nil)))
+	massert.Equal(t, false, IsZero(new(string)))
+	massert.Equal(t, true, IsZero(JSON(nil)))
+	massert.Equal(t, true, IsZero(Nullable[int]{}))
+	massert.Equal(t, false, IsZero(NullableOf(0)))
+
+	var param fmt.Stringer
+	massert.Equal(t, true, IsZero(param))
+}
@@ -0,0 +1,326 @@
+// Package upsert turns batches of upserts into single INSERT statements which update existing records, so that a
+// batch runs as one statement per chunk instead of one upsert for each record on connectors which support it.
+package upsert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/raw"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+)
+
+// MaxParams is the maximum number of parameters of a single statement. Larger batches are split into several
+// statements, which are run in one transaction.
+var MaxParams = map[string]int{
+	"sqlite":      999,
+	"postgresql":  32767,
+	"postgres":    32767,
+	"cockroachdb": 32767,
+	"mysql":       32767,
+}
+
+// Table describes the table of a model and its columns
+type Table struct {
+	// Provider is the datasource provider, e.g. "postgresql"
+	Provider string
+
+	// Name is the name of the table in the database
+	Name string
+
+	// Columns maps the Prisma names of the scalar fields which can be written with raw SQL to their columns
+	Columns map[string]Column
+
+	// Keys are the Prisma names of the required single unique fields which can identify the records of a batch
+	Keys []string
+
+	// Constraints is the number of unique constraints of the table, including the primary key. MySQL updates the
+	// record conflicting with any of them, so batches only run as a single statement if there is exactly one.
+	Constraints int
+}
+
+// Column describes the column of a scalar field
+type Column struct {
+	// Name is the name of the column in the database
+	Name string
+
+	// Enum is the name of the enum type of the column in the database, if any
+	Enum string
+
+	// NativeType is the name of the native type of the column, e.g. "Uuid" for @db.Uuid, if any
+	NativeType string
+
+	// UpdatedAt is set for @updatedAt fields, which are set to the current time when a record is written
+	UpdatedAt bool
+
+	// Default is the default function of the field which is evaluated by the query engine instead of the database,
+	// e.g. "cuid" or "now", if any
+	Default string
+}
+
+// Batch contains the statements which run a batch of upserts
+type Batch struct {
+	// Key is the Prisma name of the unique field which identifies the records
+	Key string
+
+	// Keys are the values of Key of the upserted records, in the order of the upserts
+	Keys []interface{}
+
+	// Statements upsert the records; they must be run in one transaction
+	Statements []Statement
+}
+
+// Statement is a SQL statement and its parameters
+type Statement struct {
+	Query  string
+	Params []interface{}
+}
+
+// Build returns the statements which run the upserts, or false if they can't run as single statements and must be
+// run one by one. This is the case if the provider doesn't support them, if an upsert writes relations or changes
+// a field with an operation such as increment, if its where clause doesn't select a key of the table which the
+// create sets to the same value, if its update sets a field to a different value than the create, if the upserts
+// don't write the same fields, or if two upserts write the same record.
+// Default values which are generated by the query engine are filled in with now for now() and @updatedAt fields, and
+// upserts which don't set a field with another generated default, e.g. cuid(), can't run as single statements.
+func Build(table Table, upserts []builder.Query, now time.Time) (Batch, bool) {
+	maxParams, ok := MaxParams[table.Provider]
+	if !ok || len(upserts) == 0 || (table.Provider == "mysql" && table.Constraints != 1) {
+		return Batch{}, false
+	}
+
+	var batch Batch
+	var insert, update []string
+	rows := make([][]interface{}, 0, len(upserts))
+	seen := make(map[interface{}]bool, len(upserts))
+	for i, q := range upserts {
+		u, ok := parse(table, q)
+		if !ok {
+			return Batch{}, false
+		}
+		if i == 0 {
+			batch.Key = u.key
+			insert, update = table.columns(u)
+		} else if u.key != batch.Key || !sameColumns(table, u, insert, update) {
+			return Batch{}, false
+		}
+		if seen[u.value] {
+			return Batch{}, false
+		}
+		seen[u.value] = true
+		batch.Keys = append(batch.Keys, u.value)
+
+		row := make([]interface{}, len(insert))
+		for j, name := range insert {
+			value, ok := u.create[name]
+			if !ok {
+				value = now
+			}
+			row[j] = param(value)
+		}
+		rows = append(rows, row)
+	}
+
+	perStatement := max(maxParams/len(insert), 1)
+	for start := 0; start < len(rows); start += perStatement {
+		end := min(start+perStatement, len(rows))
+		batch.Statements = append(batch.Statements, table.statement(batch.Key, insert, update, rows[start:end]))
+	}
+	return batch, true
+}
+
+// parsed is an upsert which only sets scalar fields
+type parsed struct {
+	key    string
+	value  interface{}
+	create map[string]interface{}
+	update map[string]bool
+}
+
+// parse returns the key and the fields of an upsert, or false if it can't run as a single statement
+func parse(table Table, q builder.Query) (parsed, bool) {
+	u := parsed{
+		create: make(map[string]interface{}),
+		update: make(map[string]bool),
+	}
+	var hasWhere, hasCreate, hasUpdate bool
+	for _, input := range q.Inputs {
+		switch input.Name {
+		case "where":
+			if len(input.Fields) != 1 || input.Fields[0].Fields != nil || !table.isKey(input.Fields[0].Name) {
+				return parsed{}, false
+			}
+			u.key, u.value = input.Fields[0].Name, input.Fields[0].Value
+			hasWhere = true
+		case "create":
+			for _, f := range input.Fields {
+				if _, ok := table.Columns[f.Name]; !ok || f.Fields != nil {
+					return parsed{}, false
+				}
+				u.create[f.Name] = f.Value
+			}
+			hasCreate = true
+		case "update":
+			for _, f := range input.Fields {
+				value, ok := setValue(f)
+				if _, isColumn := table.Columns[f.Name]; !ok || !isColumn {
+					return parsed{}, false
+				}
+				created, ok := u.create[f.Name]
+				if !ok || !reflect.DeepEqual(created, value) {
+					return parsed{}, false
+				}
+				u.update[f.Name] = true
+			}
+			hasUpdate = true
+		default:
+			return parsed{}, false
+		}
+	}
+	if !hasWhere || !hasCreate || !hasUpdate {
+		return parsed{}, false
+	}
+	if created, ok := u.create[u.key]; !ok || !reflect.DeepEqual(created, u.value) {
+		return parsed{}, false
+	}
+	for name, column := range table.Columns {
+		if _, ok := u.create[name]; !ok && column.Default != "" && column.Default != "now" {
+			return parsed{}, false
+		}
+	}
+	return u, true
+}
+
+// setValue returns the value an update field sets, or false if it changes the field with another operation, e.g.
+// increment
+func setValue(f builder.Field) (interface{}, bool) {
+	if f.Fields == nil {
+		return f.Value, true
+	}
+	if len(f.Fields) == 1 && f.Fields[0].Name == "set" && f.Fields[0].Fields == nil {
+		return f.Fields[0].Value, true
+	}
+	return nil, false
+}
+
+func (t Table) isKey(name string) bool {
+	for _, key := range t.Keys {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// columns returns the sorted Prisma names of the fields which are inserted and the ones which are updated. Fields
+// with a now() default or @updatedAt are inserted even if the upsert doesn't set them, and @updatedAt fields are
+// updated as well unless the update is empty.
+func (t Table) columns(u parsed) ([]string, []string) {
+	var insert, update []string
+	for name, column := range t.Columns {
+		_, created := u.create[name]
+		if created || column.UpdatedAt || column.Default == "now" {
+			insert = append(insert, name)
+		}
+		if u.update[name] || (column.UpdatedAt && len(u.update) > 0) {
+			update = append(update, name)
+		}
+	}
+	sort.Strings(insert)
+	sort.Strings(update)
+	return insert, update
+}
+
+// sameColumns returns whether the upsert writes the same fields as the one the columns were taken from
+func sameColumns(t Table, u parsed, insert []string, update []string) bool {
+	actualInsert, actualUpdate := t.columns(u)
+	return reflect.DeepEqual(actualInsert, insert) && reflect.DeepEqual(actualUpdate, update)
+}
+
+// param converts a value of a query into a parameter of a raw query
+func param(value interface{}) interface{} {
+	if v, ok := value.(types.JSON); ok {
+		return json.RawMessage(v)
+	}
+	return value
+}
+
+// statement returns the statement which inserts the rows and updates the records which already exist
+func (t Table) statement(key string, insert []string, update []string, rows [][]interface{}) Statement {
+	var b strings.Builder
+	columns := make([]string, len(insert))
+	for i, name := range insert {
+		columns[i] = raw.Quote(t.Provider, t.Columns[name].Name)
+	}
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", raw.Quote(t.Provider, t.Name), strings.Join(columns, ", "))
+
+	var params []interface{}
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		for j, value := range row {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			params = append(params, value)
+			b.WriteString(t.placeholder(t.Columns[insert[j]], len(params)))
+		}
+		b.WriteString(")")
+	}
+
+	if t.Provider == "mysql" {
+		b.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if len(update) == 0 {
+			// a no-op update, as INSERT IGNORE would ignore other errors as well
+			column := raw.Quote(t.Provider, t.Columns[key].Name)
+			fmt.Fprintf(&b, "%s = %s", column, column)
+		}
+		for i, name := range update {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			column := raw.Quote(t.Provider, t.Columns[name].Name)
+			fmt.Fprintf(&b, "%s = VALUES(%s)", column, column)
+		}
+		return Statement{Query: b.String(), Params: params}
+	}
+
+	fmt.Fprintf(&b, " ON CONFLICT (%s) DO ", raw.Quote(t.Provider, t.Columns[key].Name))
+	if len(update) == 0 {
+		b.WriteString("NOTHING")
+	} else {
+		b.WriteString("UPDATE SET ")
+	}
+	for i, name := range update {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		column := raw.Quote(t.Provider, t.Columns[name].Name)
+		fmt.Fprintf(&b, "%s = EXCLUDED.%s", column, column)
+	}
+	return Statement{Query: b.String(), Params: params}
+}
+
+// placeholder returns the placeholder of the nth parameter. PostgreSQL doesn't cast the text parameters of raw
+// queries to enums and UUIDs implicitly, so they are cast explicitly.
+func (t Table) placeholder(column Column, n int) string {
+	if t.Provider == "sqlite" || t.Provider == "mysql" {
+		return "?"
+	}
+	placeholder := "$" + strconv.Itoa(n)
+	switch {
+	case column.Enum != "":
+		return fmt.Sprintf("CAST(%s AS %s)", placeholder, raw.Quote(t.Provider, column.Enum))
+	case column.NativeType == "Uuid":
+		return fmt.Sprintf("CAST(%s AS uuid)", placeholder)
+	}
+	return placeholder
+}
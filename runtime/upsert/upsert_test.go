@@ -0,0 +1,177 @@
+package upsert
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/types"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+var now = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func table(provider string) Table {
+	return Table{
+		Provider: provider,
+		Name:     "Post",
+		Columns: map[string]Column{
+			"id":        {Name: "_id"},
+			"title":     {Name: "title"},
+			"kind":      {Name: "kind", Enum: "Kind"},
+			"meta":      {Name: "meta"},
+			"views":     {Name: "views"},
+			"createdAt": {Name: "created_at", Default: "now"},
+			"updatedAt": {Name: "updated_at", UpdatedAt: true},
+		},
+		Keys:        []string{"id"},
+		Constraints: 1,
+	}
+}
+
+// upsert returns an upsert query like the one of the generated client
+func upsert(id string, create []builder.Field, update []builder.Field) builder.Query {
+	q := builder.NewQuery()
+	q.Method = "upsertOne"
+	q.Inputs = []builder.Input{
+		{Name: "where", Fields: []builder.Field{{Name: "id", Value: id}}},
+		{Name: "create", Fields: append([]builder.Field{{Name: "id", Value: id}}, create...)},
+		{Name: "update", Fields: update},
+	}
+	return q
+}
+
+func set(name string, value interface{}) builder.Field {
+	return builder.Field{Name: name, Fields: []builder.Field{{Name: "set", Value: value}}}
+}
+
+func TestBuild(t *testing.T) {
+	upserts := []builder.Query{
+		upsert("a", []builder.Field{{Name: "title", Value: "A"}, {Name: "kind", Value: "NEWS"}}, []builder.Field{set("title", "A")}),
+		upsert("b", []builder.Field{{Name: "title", Value: "B"}, {Name: "kind", Value: "BLOG"}}, []builder.Field{set("title", "B")}),
+	}
+
+	tests := []struct {
+		provider string
+		expected string
+	}{{
+		provider: "postgresql",
+		expected: `INSERT INTO "Post" ("created_at", "_id", "kind", "title", "updated_at") VALUES ($1, $2, CAST($3 AS "Kind"), $4, $5), ($6, $7, CAST($8 AS "Kind"), $9, $10) ON CONFLICT ("_id") DO UPDATE SET "title" = EXCLUDED."title", "updated_at" = EXCLUDED."updated_at"`,
+	}, {
+		provider: "sqlite",
+		expected: `INSERT INTO "Post" ("created_at", "_id", "kind", "title", "updated_at") VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?) ON CONFLICT ("_id") DO UPDATE SET "title" = EXCLUDED."title", "updated_at" = EXCLUDED."updated_at"`,
+	}, {
+		provider: "mysql",
+		expected: "INSERT INTO `Post` (`created_at`, `_id`, `kind`, `title`, `updated_at`) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `title` = VALUES(`title`), `updated_at` = VALUES(`updated_at`)",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			batch, ok := Build(table(tt.provider), upserts, now)
+			massert.Equal(t, true, ok)
+			massert.Equal(t, "id", batch.Key)
+			massert.Equal(t, []interface{}{"a", "b"}, batch.Keys)
+			massert.Equal(t, 1, len(batch.Statements))
+			massert.Equal(t, tt.expected, batch.Statements[0].Query)
+			massert.Equal(t, []interface{}{now, "a", "NEWS", "A", now, now, "b", "BLOG", "B", now}, batch.Statements[0].Params)
+		})
+	}
+}
+
+func TestBuild_emptyUpdate(t *testing.T) {
+	upserts := []builder.Query{upsert("a", []builder.Field{{Name: "meta", Value: types.JSON(`{}`)}}, nil)}
+
+	batch, ok := Build(table("postgresql"), upserts, now)
+	massert.Equal(t, true, ok)
+	massert.Equal(t, `INSERT INTO "Post" ("created_at", "_id", "meta", "updated_at") VALUES ($1, $2, $3, $4) ON CONFLICT ("_id") DO NOTHING`, batch.Statements[0].Query)
+	// JSON values are sent as JSON parameters
+	massert.Equal(t, []interface{}{now, "a", json.RawMessage(`{}`), now}, batch.Statements[0].Params)
+
+	batch, ok = Build(table("mysql"), upserts, now)
+	massert.Equal(t, true, ok)
+	massert.Equal(t, "INSERT INTO `Post` (`created_at`, `_id`, `meta`, `updated_at`) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE `_id` = `_id`", batch.Statements[0].Query)
+}
+
+func TestBuild_chunks(t *testing.T) {
+	var upserts []builder.Query
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		upserts = append(upserts, upsert(id, nil, nil))
+	}
+
+	MaxParams["sqlite"] = 6
+	defer func() {
+		MaxParams["sqlite"] = 999
+	}()
+
+	// each row has 3 parameters, the id, createdAt and updatedAt
+	batch, ok := Build(table("sqlite"), upserts, now)
+	massert.Equal(t, true, ok)
+	massert.Equal(t, 3, len(batch.Statements))
+	massert.Equal(t, []interface{}{now, "e", now}, batch.Statements[2].Params)
+	massert.Equal(t, []interface{}{"a", "b", "c", "d", "e"}, batch.Keys)
+}
+
+func TestBuild_fallback(t *testing.T) {
+	withCUID := table("postgresql")
+	withCUID.Columns["slug"] = Column{Name: "slug", Default: "cuid"}
+
+	withConstraints := table("mysql")
+	withConstraints.Constraints = 2
+
+	other := upsert("b", []builder.Field{{Name: "title", Value: "B"}}, nil)
+
+	tests := []struct {
+		name    string
+		table   Table
+		upserts []builder.Query
+	}{{
+		name:    "unsupported provider",
+		table:   table("sqlserver"),
+		upserts: []builder.Query{upsert("a", nil, nil)},
+	}, {
+		name:    "mysql with several unique constraints",
+		table:   withConstraints,
+		upserts: []builder.Query{upsert("a", nil, nil)},
+	}, {
+		name:    "increment",
+		table:   table("postgresql"),
+		upserts: []builder.Query{upsert("a", nil, []builder.Field{{Name: "views", Fields: []builder.Field{{Name: "increment", Value: 1}}}})},
+	}, {
+		name:    "update differs from create",
+		table:   table("postgresql"),
+		upserts: []builder.Query{upsert("a", []builder.Field{{Name: "title", Value: "created"}}, []builder.Field{set("title", "updated")})},
+	}, {
+		name:    "update not in create",
+		table:   table("postgresql"),
+		upserts: []builder.Query{upsert("a", nil, []builder.Field{set("title", "A")})},
+	}, {
+		name:    "relation",
+		table:   table("postgresql"),
+		upserts: []builder.Query{upsert("a", []builder.Field{{Name: "author", Fields: []builder.Field{{Name: "connect"}}}}, nil)},
+	}, {
+		name:    "generated default",
+		table:   withCUID,
+		upserts: []builder.Query{upsert("a", nil, nil)},
+	}, {
+		name:    "different fields",
+		table:   table("postgresql"),
+		upserts: []builder.Query{upsert("a", nil, nil), other},
+	}, {
+		name:    "duplicate key",
+		table:   table("postgresql"),
+		upserts: []builder.Query{upsert("a", nil, nil), upsert("a", nil, nil)},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := Build(tt.table, tt.upserts, now)
+			massert.Equal(t, false, ok)
+		})
+	}
+}
+
+func TestBuild_keyDiffersFromCreate(t *testing.T) {
+	q := upsert("a", nil, nil)
+	q.Inputs[1].Fields[0].Value = "b"
+	_, ok := Build(table("postgresql"), []builder.Query{q}, now)
+	massert.Equal(t, false, ok)
+}
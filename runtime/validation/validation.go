@@ -0,0 +1,195 @@
+// Package validation checks the data of create, update and upsert queries against the constraints of the Prisma
+// schema before they are sent to the database, so that invalid input can be answered with a validation response
+// listing each invalid field instead of a database error.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+)
+
+// Code describes which constraint a value violates
+type Code string
+
+const (
+	// CodeRequired is used for required fields which are missing or set to null
+	CodeRequired Code = "required"
+	// CodeEnum is used for values which are not a value of the enum of the field
+	CodeEnum Code = "enum"
+	// CodeMaxLength is used for strings which are longer than the length of the native type, e.g. @db.VarChar(50)
+	CodeMaxLength Code = "max_length"
+)
+
+// FieldError describes why the value of a field is invalid
+type FieldError struct {
+	// Field is the name of the field in the Prisma schema
+	Field   string `json:"field"`
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Errors contains an error for each invalid field. It is returned by the Validate methods of the generated client.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Query checks the data which a create, update or upsert query writes against the constraints of the model of the
+// query in schema, i.e. required fields, enum values and the maximum length of strings. It returns Errors if any field
+// is invalid.
+func Query(schema *metadata.Schema, query builder.Query) error {
+	model, ok := schema.Model(query.Model)
+	if !ok {
+		return fmt.Errorf("validation: unknown model %s", query.Model)
+	}
+
+	var errs Errors
+	for _, input := range query.Inputs {
+		switch {
+		case input.Name == "create", input.Name == "data" && strings.HasPrefix(query.Method, "create"):
+			errs = append(errs, check(schema, model, input.Fields, true)...)
+		case input.Name == "update", input.Name == "data":
+			errs = append(errs, check(schema, model, input.Fields, false)...)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// check validates the fields written to model; on create, required fields without a default value must be set
+func check(schema *metadata.Schema, model *metadata.Model, fields []builder.Field, create bool) Errors {
+	values := make(map[string]interface{})
+	for _, f := range fields {
+		if f.Name != "" {
+			values[f.Name] = value(f)
+		}
+	}
+	isSet := func(name string) bool {
+		v, ok := values[name]
+		return ok && !isNull(v)
+	}
+
+	// foreign keys are set either directly or with their relation, so they are checked with the relation
+	foreignKeys := make(map[string]bool)
+	for _, f := range model.Relations() {
+		for _, key := range f.RelationFromFields {
+			foreignKeys[key] = true
+		}
+	}
+
+	var errs Errors
+	for _, f := range model.Fields {
+		v, ok := values[f.Name]
+		required := f.IsRequired && !f.IsList
+		if create && required && !ok && !f.HasDefaultValue && !f.IsUpdatedAt && !foreignKeys[f.Name] {
+			linked := f.Kind == metadata.FieldKindRelation && len(f.RelationFromFields) > 0
+			for _, key := range f.RelationFromFields {
+				linked = linked && isSet(key)
+			}
+			if !linked {
+				errs = append(errs, FieldError{Field: f.Name, Code: CodeRequired, Message: "is required"})
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if isNull(v) {
+			if required {
+				errs = append(errs, FieldError{Field: f.Name, Code: CodeRequired, Message: "is required"})
+			}
+			continue
+		}
+		if err := checkValue(schema, f, v); err != nil {
+			errs = append(errs, *err)
+		}
+	}
+	return errs
+}
+
+// value returns the value which is written to a field, which updates wrap into a set operation. Fields with other
+// operations, e.g. increment or relation links, have no value.
+func value(f builder.Field) interface{} {
+	if len(f.Fields) == 1 && f.Fields[0].Name == "set" {
+		return f.Fields[0].Value
+	}
+	if len(f.Fields) > 0 {
+		return operation{}
+	}
+	return f.Value
+}
+
+// operation is the value of fields which are not set to a value, e.g. when they are incremented
+type operation struct{}
+
+// isNull returns whether v is nil or a nil pointer
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// checkValue checks the enum values and the length of the value of f
+func checkValue(schema *metadata.Schema, f metadata.Field, v interface{}) *FieldError {
+	if _, ok := v.(operation); ok {
+		return nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	var items []reflect.Value
+	if rv.Kind() == reflect.Slice && f.IsList {
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, reflect.Indirect(rv.Index(i)))
+		}
+	} else {
+		items = append(items, rv)
+	}
+
+	for _, item := range items {
+		if item.Kind() != reflect.String {
+			continue
+		}
+		s := item.String()
+		if enum, ok := schema.Enum(f.Type); ok && f.Kind == metadata.FieldKindEnum && !contains(enum.Values, s) {
+			return &FieldError{
+				Field:   f.Name,
+				Code:    CodeEnum,
+				Message: fmt.Sprintf("must be one of %s", strings.Join(enum.Values, ", ")),
+			}
+		}
+		if f.MaxLength > 0 && utf8.RuneCountInString(s) > f.MaxLength {
+			return &FieldError{
+				Field:   f.Name,
+				Code:    CodeMaxLength,
+				Message: fmt.Sprintf("must be at most %d characters long", f.MaxLength),
+			}
+		}
+	}
+	return nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/builder"
+	"github.com/steebchen/prisma-client-go/runtime/metadata"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type role string
+
+var schema = metadata.Schema{
+	Models: []metadata.Model{{
+		Name: "User",
+		Fields: []metadata.Field{
+			{Name: "id", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true, IsID: true, HasDefaultValue: true},
+			{Name: "email", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true, MaxLength: 10},
+			{Name: "name", Kind: metadata.FieldKindScalar, Type: "String", MaxLength: 5},
+			{Name: "role", Kind: metadata.FieldKindEnum, Type: "Role", IsRequired: true},
+			{Name: "roles", Kind: metadata.FieldKindEnum, Type: "Role", IsList: true},
+			{Name: "updatedAt", Kind: metadata.FieldKindScalar, Type: "DateTime", IsRequired: true, IsUpdatedAt: true},
+		},
+	}, {
+		Name: "Post",
+		Fields: []metadata.Field{
+			{Name: "id", Kind: metadata.FieldKindScalar, Type: "Int", IsRequired: true, IsID: true},
+			{Name: "authorId", Kind: metadata.FieldKindScalar, Type: "String", IsRequired: true},
+			{Name: "author", Kind: metadata.FieldKindRelation, Type: "User", IsRequired: true, RelationFromFields: []string{"authorId"}},
+		},
+	}},
+	Enums: []metadata.Enum{{Name: "Role", Values: []string{"USER", "ADMIN"}}},
+}
+
+func query(method, model string, inputs ...builder.Input) builder.Query {
+	return builder.Query{Method: method, Model: model, Inputs: inputs}
+}
+
+func data(name string, fields ...builder.Field) builder.Input {
+	return builder.Input{Name: name, Fields: fields}
+}
+
+func set(name string, value interface{}) builder.Field {
+	return builder.Field{Name: name, Fields: []builder.Field{{Name: "set", Value: value}}}
+}
+
+func TestQuery(t *testing.T) {
+	name := "Alexander"
+	var nilName *string
+	tests := []struct {
+		name  string
+		query builder.Query
+		want  Errors
+	}{{
+		name: "valid create",
+		query: query("createOne", "User", data("data",
+			builder.Field{Name: "email", Value: "a@b.c"},
+			builder.Field{Name: "role", Value: role("ADMIN")},
+			builder.Field{Name: "name", Value: nilName},
+		)),
+	}, {
+		name: "invalid create",
+		query: query("createOne", "User", data("data",
+			builder.Field{Name: "email", Value: "alexander@example.com"},
+			builder.Field{Name: "name", Value: &name},
+			builder.Field{Name: "roles", Fields: []builder.Field{{Name: "set", Value: []role{"USER", "GUEST"}}}},
+		)),
+		want: Errors{
+			{Field: "email", Code: CodeMaxLength, Message: "must be at most 10 characters long"},
+			{Field: "name", Code: CodeMaxLength, Message: "must be at most 5 characters long"},
+			{Field: "role", Code: CodeRequired, Message: "is required"},
+			{Field: "roles", Code: CodeEnum, Message: "must be one of USER, ADMIN"},
+		},
+	}, {
+		name:  "multi-byte characters",
+		query: query("createOne", "User", data("data", builder.Field{Name: "email", Value: "äöüäöüäöüä"}, builder.Field{Name: "role", Value: role("USER")})),
+	}, {
+		name:  "relation",
+		query: query("createOne", "Post", data("data", builder.Field{Name: "id", Value: 1}, builder.Field{Name: "author", Fields: []builder.Field{{Name: "connect"}}})),
+	}, {
+		name:  "foreign key",
+		query: query("createOne", "Post", data("data", builder.Field{Name: "id", Value: 1}, builder.Field{Name: "authorId", Value: "a"})),
+	}, {
+		name:  "missing relation",
+		query: query("createOne", "Post", data("data", builder.Field{Name: "id", Value: 1})),
+		want:  Errors{{Field: "author", Code: CodeRequired, Message: "is required"}},
+	}, {
+		name:  "valid update",
+		query: query("updateOne", "User", data("data", set("role", role("USER")), set("name", nilName))),
+	}, {
+		name:  "invalid update",
+		query: query("updateMany", "User", data("data", set("role", role("GUEST")), set("email", nilName))),
+		want: Errors{
+			{Field: "email", Code: CodeRequired, Message: "is required"},
+			{Field: "role", Code: CodeEnum, Message: "must be one of USER, ADMIN"},
+		},
+	}, {
+		name: "upsert",
+		query: query("upsertOne", "User",
+			data("where", builder.Field{Name: "email", Value: "alexander@example.com"}),
+			data("create", builder.Field{Name: "email", Value: "a@b.c"}, builder.Field{Name: "role", Value: role("USER")}),
+			data("update", set("name", &name)),
+		),
+		want: Errors{{Field: "name", Code: CodeMaxLength, Message: "must be at most 5 characters long"}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Query(&schema, tt.query)
+			if tt.want == nil {
+				massert.Equal(t, nil, err)
+				return
+			}
+			var errs Errors
+			massert.Equal(t, true, errors.As(err, &errs))
+			massert.Equal(t, tt.want, errs)
+		})
+	}
+}
+
+func TestErrors_Error(t *testing.T) {
+	err := Errors{
+		{Field: "email", Code: CodeRequired, Message: "is required"},
+		{Field: "role", Code: CodeEnum, Message: "must be one of USER, ADMIN"},
+	}
+	massert.Equal(t, "validation failed: email: is required; role: must be one of USER, ADMIN", err.Error())
+}
@@ -0,0 +1,108 @@
+// Package version detects when a generated client is used with a different version of the prisma-client-go runtime
+// than the one which generated it, which otherwise leads to obscure compile or marshaling errors.
+package version
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/steebchen/prisma-client-go/logger"
+)
+
+// Module is the module path of prisma-client-go
+const Module = "github.com/steebchen/prisma-client-go"
+
+// SkipEnv disables the version check if set to "true", e.g. while developing prisma-client-go itself
+const SkipEnv = "PRISMA_CLIENT_GO_SKIP_VERSION_CHECK"
+
+// devel is reported by the go tool for modules which are not built from a tagged version, e.g. local replacements
+const devel = "(devel)"
+
+// readBuildInfo is replaced in tests
+var readBuildInfo = debug.ReadBuildInfo
+
+// Current returns the version of the prisma-client-go module the binary was built with, or an empty string if it is
+// unknown, e.g. because prisma-client-go was replaced with a local directory
+func Current() string {
+	info, ok := readBuildInfo()
+	if !ok {
+		return ""
+	}
+	return moduleVersion(info)
+}
+
+func moduleVersion(info *debug.BuildInfo) string {
+	module := &info.Main
+	if module.Path != Module {
+		module = nil
+		for _, dep := range info.Deps {
+			if dep.Path == Module {
+				module = dep
+				break
+			}
+		}
+	}
+	if module == nil {
+		return ""
+	}
+	if module.Replace != nil {
+		module = module.Replace
+	}
+	if module.Version == devel {
+		return ""
+	}
+	return module.Version
+}
+
+// MismatchError is returned by Check if the client was generated with a different version than the runtime
+type MismatchError struct {
+	// Generated is the version of prisma-client-go which generated the client
+	Generated string
+
+	// Runtime is the version of prisma-client-go the binary is built with
+	Runtime string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf(
+		"the prisma client was generated with prisma-client-go %s, but %s is used at runtime; re-run go generate or `go run %s generate` to regenerate it, or set %s=true to skip this check",
+		e.Generated, e.Runtime, Module, SkipEnv,
+	)
+}
+
+// Check compares the version which generated a client with the version of the runtime. Only a different major or
+// minor version is an error, as the generated code may depend on internals which changed; a different patch version is
+// logged. Unknown versions, e.g. of local development builds, are not compared.
+func Check(generated string) error {
+	if os.Getenv(SkipEnv) == "true" {
+		return nil
+	}
+
+	current := Current()
+	if generated == "" || current == "" || generated == current {
+		return nil
+	}
+
+	if majorMinor(generated) == majorMinor(current) {
+		logger.Info.Printf("the prisma client was generated with prisma-client-go %s, but %s is used at runtime; consider regenerating it", generated, current)
+		return nil
+	}
+
+	logger.Debug.Printf("client was generated with %s, runtime is %s", generated, current)
+
+	return &MismatchError{
+		Generated: generated,
+		Runtime:   current,
+	}
+}
+
+// majorMinor returns the major and minor version of a semantic version, e.g. v0.41 for v0.41.2-rc.1
+func majorMinor(v string) string {
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	return parts[0] + "." + parts[1]
+}
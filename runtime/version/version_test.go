@@ -0,0 +1,94 @@
+package version
+
+import (
+	"errors"
+	"runtime/debug"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestModuleVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     debug.BuildInfo
+		expected string
+	}{{
+		name: "dependency",
+		info: debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/app", Version: devel},
+			Deps: []*debug.Module{{Path: Module, Version: "v0.40.0"}},
+		},
+		expected: "v0.40.0",
+	}, {
+		name: "main module",
+		info: debug.BuildInfo{
+			Main: debug.Module{Path: Module, Version: "v0.41.0"},
+		},
+		expected: "v0.41.0",
+	}, {
+		name: "replaced with version",
+		info: debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/app"},
+			Deps: []*debug.Module{{Path: Module, Version: "v0.40.0", Replace: &debug.Module{Path: "example.com/fork", Version: "v0.40.1"}}},
+		},
+		expected: "v0.40.1",
+	}, {
+		name: "replaced with directory",
+		info: debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/app"},
+			Deps: []*debug.Module{{Path: Module, Version: "v0.40.0", Replace: &debug.Module{Path: "../prisma-client-go", Version: devel}}},
+		},
+		expected: "",
+	}, {
+		name: "development build",
+		info: debug.BuildInfo{
+			Main: debug.Module{Path: Module, Version: devel},
+		},
+		expected: "",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			massert.Equal(t, tt.expected, moduleVersion(&tt.info))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	original := readBuildInfo
+	defer func() {
+		readBuildInfo = original
+	}()
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/app", Version: devel},
+			Deps: []*debug.Module{{Path: Module, Version: "v0.41.0"}},
+		}, true
+	}
+
+	massert.Equal(t, nil, Check("v0.41.0"))
+	massert.Equal(t, nil, Check(""))
+	massert.Equal(t, nil, Check("v0.41.3"))
+
+	err := Check("v0.40.0")
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a MismatchError, got %v", err)
+	}
+	massert.Equal(t, &MismatchError{Generated: "v0.40.0", Runtime: "v0.41.0"}, mismatch)
+
+	err = Check("v1.41.0")
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a MismatchError, got %v", err)
+	}
+
+	t.Setenv(SkipEnv, "true")
+	massert.Equal(t, nil, Check("v0.40.0"))
+}
+
+func TestMajorMinor(t *testing.T) {
+	massert.Equal(t, "v0.41", majorMinor("v0.41.2"))
+	massert.Equal(t, "v0.41", majorMinor("v0.41.2-rc.1"))
+	massert.Equal(t, "v1.0", majorMinor("v1.0.0-20240101000000-abcdef123456"))
+	massert.Equal(t, "v2", majorMinor("v2"))
+}
@@ -0,0 +1,306 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// indent is the indentation of the lines within a block
+const indent = "  "
+
+// blockKeywords are the keywords of the top-level blocks of a schema. Datasources and generators contain key value
+// pairs, the other blocks contain fields or enum values.
+var blockKeywords = map[string]bool{
+	"datasource": true,
+	"generator":  true,
+	"model":      true,
+	"view":       true,
+	"type":       true,
+	"enum":       true,
+}
+
+// FormatContent returns the formatted content of a schema file, like `prisma format`, e.g. of a file which wasn't
+// saved yet. Like `prisma format`, the fields, enum values and key value pairs of a block are aligned in columns,
+// where an empty line starts a new group of aligned lines, and blocks are separated by a single empty line.
+// Formatting doesn't validate the schema, but a file which can't be parsed into blocks returns an error.
+func FormatContent(content string) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var out []string
+	var b *block
+	blank := false
+	for i := 0; i < len(lines); i++ {
+		code, comment := splitComment(lines[i])
+
+		if b != nil {
+			if code == "}" {
+				out = append(out, b.render()...)
+				out = append(out, joinComment("}", comment))
+				b = nil
+				continue
+			}
+			// arrays, e.g. the preview features of a generator, may span multiple lines
+			start := i
+			for depth(code) > 0 && i+1 < len(lines) {
+				i++
+				next, nextComment := splitComment(lines[i])
+				code += " " + next
+				if nextComment != "" {
+					comment = nextComment
+				}
+			}
+			if depth(code) > 0 {
+				return "", fmt.Errorf("line %d: unclosed bracket", start+1)
+			}
+			b.add(code, comment)
+			continue
+		}
+
+		switch {
+		case code == "" && comment == "":
+			blank = len(out) > 0
+		case code == "":
+			out = appendSeparated(out, blank)
+			out = append(out, comment)
+			blank = false
+		default:
+			keyword, name, empty, ok := parseHeader(code)
+			if !ok {
+				return "", fmt.Errorf("line %d: expected a block such as a model, got %q", i+1, code)
+			}
+			out = appendSeparated(out, blank)
+			out = append(out, joinComment(keyword+" "+name+" {", comment))
+			if empty {
+				out = append(out, "}")
+			} else {
+				b = &block{keyValue: keyword == "datasource" || keyword == "generator", line: i + 1}
+			}
+			blank = false
+		}
+	}
+	if b != nil {
+		return "", fmt.Errorf("line %d: block is not closed", b.line)
+	}
+	if len(out) == 0 {
+		return "", nil
+	}
+	return strings.Join(out, "\n") + "\n", nil
+}
+
+// appendSeparated adds an empty line if the previous lines ended with one, or with a block
+func appendSeparated(out []string, blank bool) []string {
+	if len(out) > 0 && (blank || out[len(out)-1] == "}" || strings.HasPrefix(out[len(out)-1], "} ")) {
+		return append(out, "")
+	}
+	return out
+}
+
+// parseHeader parses the first line of a block, e.g. "model User {", and returns whether the block is empty, e.g.
+// "enum Role {}"
+func parseHeader(code string) (keyword string, name string, empty bool, ok bool) {
+	if strings.HasSuffix(code, "}") {
+		code = strings.TrimSpace(strings.TrimSuffix(code, "}"))
+		empty = true
+	}
+	if !strings.HasSuffix(code, "{") {
+		return "", "", false, false
+	}
+	fields := strings.Fields(strings.TrimSuffix(code, "{"))
+	if len(fields) != 2 || !blockKeywords[fields[0]] {
+		return "", "", false, false
+	}
+	return fields[0], fields[1], empty, true
+}
+
+// block collects the lines of a block until it is closed
+type block struct {
+	// keyValue is set for datasources and generators
+	keyValue bool
+
+	// line is the line number of the beginning of the block
+	line int
+
+	lines []blockLine
+}
+
+// blockLine is a line within a block, which is either empty, a comment, a block attribute, or a row of columns which
+// are aligned with the rows around it
+type blockLine struct {
+	text    string
+	columns []string
+	empty   bool
+}
+
+func (b *block) add(code, comment string) {
+	switch {
+	case code == "" && comment == "":
+		b.lines = append(b.lines, blockLine{empty: true})
+	case code == "":
+		b.lines = append(b.lines, blockLine{text: comment})
+	case strings.HasPrefix(code, "@@"):
+		b.lines = append(b.lines, blockLine{text: joinComment(strings.Join(splitTokens(code), " "), comment)})
+	case b.keyValue:
+		key, value, _ := strings.Cut(code, "=")
+		b.lines = append(b.lines, blockLine{columns: []string{
+			strings.TrimSpace(key),
+			strings.TrimSpace("= " + strings.TrimSpace(value)),
+			comment,
+		}})
+	default:
+		// fields have a name, a type and attributes, enum values a name and attributes
+		tokens := splitTokens(code)
+		columns := []string{tokens[0], "", "", comment}
+		for _, token := range tokens[1:] {
+			if strings.HasPrefix(token, "@") {
+				columns[2] = strings.TrimSpace(columns[2] + " " + token)
+			} else {
+				columns[1] = strings.TrimSpace(columns[1] + " " + token)
+			}
+		}
+		b.lines = append(b.lines, blockLine{columns: columns})
+	}
+}
+
+// render returns the indented lines of the block, with consecutive empty lines collapsed and the columns of the rows
+// between two empty lines aligned
+func (b *block) render() []string {
+	// drop leading, trailing and repeated empty lines
+	var lines []blockLine
+	for _, line := range b.lines {
+		if line.empty && (len(lines) == 0 || lines[len(lines)-1].empty) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && lines[len(lines)-1].empty {
+		lines = lines[:len(lines)-1]
+	}
+
+	out := make([]string, 0, len(lines))
+	for start := 0; start < len(lines); {
+		end := start
+		for end < len(lines) && !lines[end].empty {
+			end++
+		}
+		out = append(out, renderSection(lines[start:end])...)
+		if end < len(lines) {
+			out = append(out, "")
+		}
+		start = end + 1
+	}
+	return out
+}
+
+// renderSection aligns the columns of the rows of a group of lines
+func renderSection(lines []blockLine) []string {
+	var widths []int
+	for _, line := range lines {
+		for i, column := range line.columns {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], len(column))
+		}
+	}
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if line.columns == nil {
+			out[i] = indent + line.text
+			continue
+		}
+		var s strings.Builder
+		for j, column := range line.columns {
+			// columns which are empty in all rows, e.g. attributes of fields which have none, take no space
+			if widths[j] == 0 {
+				continue
+			}
+			if s.Len() > 0 {
+				s.WriteString(" ")
+			}
+			s.WriteString(column + strings.Repeat(" ", widths[j]-len(column)))
+		}
+		out[i] = strings.TrimRight(indent+s.String(), " ")
+	}
+	return out
+}
+
+// joinComment appends a trailing comment to a line, if there is one
+func joinComment(code, comment string) string {
+	if comment == "" {
+		return code
+	}
+	return code + " " + comment
+}
+
+// splitComment splits a line into its trimmed code and its trailing comment, ignoring slashes within strings
+func splitComment(line string) (string, string) {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case !inString && c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i:])
+		}
+	}
+	return strings.TrimSpace(line), ""
+}
+
+// splitTokens splits code at whitespace outside of strings, parentheses and brackets, so that an attribute and its
+// arguments are one token
+func splitTokens(code string) []string {
+	var tokens []string
+	var token strings.Builder
+	nesting := 0
+	inString := false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(code):
+			token.WriteByte(c)
+			i++
+			c = code[i]
+		case inString && c == '"':
+			inString = false
+		case c == '"':
+			inString = true
+		case c == '(' || c == '[':
+			nesting++
+		case c == ')' || c == ']':
+			nesting--
+		case nesting == 0 && (c == ' ' || c == '\t'):
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+			continue
+		}
+		token.WriteByte(c)
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
+
+// depth returns how many brackets of code are not closed
+func depth(code string) int {
+	n := 0
+	inString := false
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case !inString && (c == '[' || c == '('):
+			n++
+		case !inString && (c == ']' || c == ')'):
+			n--
+		}
+	}
+	return n
+}
@@ -0,0 +1,200 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestFormatContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{{
+		name: "datasource and generator",
+		content: `datasource db {
+provider = "postgresql"
+    url = env("DATABASE_URL") // the url
+}
+generator db {
+  provider = "go run github.com/steebchen/prisma-client-go"
+  previewFeatures = ["fullTextSearch",
+    "views"]
+}`,
+		expected: `datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL") // the url
+}
+
+generator db {
+  provider        = "go run github.com/steebchen/prisma-client-go"
+  previewFeatures = ["fullTextSearch", "views"]
+}
+`,
+	}, {
+		name: "model",
+		content: `
+
+
+// a user
+model   User{
+  id String @id   @default(cuid())
+  /// the email
+  email String @unique
+  name String?
+  posts Post[] // written posts
+
+
+
+  createdAt DateTime @default(now()) @map("created_at")
+    @@index([name, email])
+}
+model Post {
+  id String @id
+  author User @relation(fields: [authorID], references: [id])
+  authorID String
+}
+
+
+`,
+		expected: `// a user
+model User {
+  id    String  @id @default(cuid())
+  /// the email
+  email String  @unique
+  name  String?
+  posts Post[]                       // written posts
+
+  createdAt DateTime @default(now()) @map("created_at")
+  @@index([name, email])
+}
+
+model Post {
+  id       String @id
+  author   User   @relation(fields: [authorID], references: [id])
+  authorID String
+}
+`,
+	}, {
+		name: "enum",
+		content: `enum Role {
+  USER
+  ADMIN @map("admin")
+}
+enum Empty {}`,
+		expected: `enum Role {
+  USER
+  ADMIN @map("admin")
+}
+
+enum Empty {
+}
+`,
+	}, {
+		name: "strings",
+		content: `model Settings {
+  id Int @id
+  value Json @default("{\"a\": \"//b\"}")
+}`,
+		expected: `model Settings {
+  id    Int  @id
+  value Json @default("{\"a\": \"//b\"}")
+}
+`,
+	}, {
+		name:     "empty",
+		content:  "\n\n",
+		expected: "",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := FormatContent(tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, tt.expected, actual)
+
+			// formatting is idempotent
+			again, err := FormatContent(actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, actual, again)
+		})
+	}
+}
+
+func TestFormatContent_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{{
+		name:     "unknown block",
+		content:  "modle User {\n}\n",
+		expected: `line 1: expected a block such as a model, got "modle User {"`,
+	}, {
+		name:     "unclosed block",
+		content:  "model User {\n  id String @id\n",
+		expected: "line 1: block is not closed",
+	}, {
+		name:     "unclosed bracket",
+		content:  "generator db {\n  previewFeatures = [\"views\"\n",
+		expected: "line 2: unclosed bracket",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FormatContent(tt.content)
+			massert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "models"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"schema.prisma":      datasource,
+		"models/user.prisma": "model User {\nid String @id\n  email String\n}\n",
+		"README.md":          "not a schema",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// directories are formatted file by file
+	actual, err := Format(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []File{{
+		Path:    filepath.Join(dir, "models/user.prisma"),
+		Content: "model User {\n  id    String @id\n  email String\n}\n",
+	}, {
+		Path:    filepath.Join(dir, "schema.prisma"),
+		Content: datasource,
+	}}, actual)
+
+	actual, err = Format(filepath.Join(dir, "schema.prisma"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, []File{{Path: filepath.Join(dir, "schema.prisma"), Content: datasource}}, actual)
+
+	if err := os.WriteFile(filepath.Join(dir, "models/broken.prisma"), []byte("model User {\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Format(dir)
+	massert.Equal(t, "format schema "+filepath.Join(dir, "models/broken.prisma")+": line 1: block is not closed", err.Error())
+
+	_, err = Format(filepath.Join(dir, "missing.prisma"))
+	massert.Equal(t, true, errors.Is(err, os.ErrNotExist))
+}
@@ -0,0 +1,64 @@
+// Package schema validates and formats Prisma schema files without the Prisma CLI, e.g. for editor tooling and
+// pre-commit hooks written in Go.
+//
+// Example:
+//
+//	if err := schema.Validate(ctx, "prisma/schema.prisma"); err != nil {
+//	  log.Fatal(err)
+//	}
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steebchen/prisma-client-go/internal/queryengine"
+	"github.com/steebchen/prisma-client-go/internal/schemaengine"
+)
+
+// Error is returned by Validate when the schema is invalid. Code is "P1012" for validation errors, and Message lists
+// each problem including its location in the schema.
+type Error = queryengine.Error
+
+// Validate checks that the schema at path is valid, like `prisma validate`. path is a schema file or a directory of
+// .prisma files which form the schema together. If it isn't valid, an *Error describing all problems is returned. The
+// query engine, which parses the schema, is downloaded to the global cache directory on first use; set
+// PRISMA_QUERY_ENGINE_BINARY to use a binary from a different location.
+func Validate(ctx context.Context, path string) error {
+	files, err := schemaengine.ReadSchema(path)
+	if err != nil {
+		return err
+	}
+	return ValidateContent(ctx, schemaengine.JoinSchema(files))
+}
+
+// ValidateContent checks that the content of a schema is valid, e.g. of a file which wasn't saved yet
+func ValidateContent(ctx context.Context, content string) error {
+	if _, err := queryengine.DMMF(ctx, content, nil); err != nil {
+		return fmt.Errorf("validate schema: %w", err)
+	}
+	return nil
+}
+
+// File is a file of a schema and its content
+type File = schemaengine.File
+
+// Format returns the formatted files of the schema at path, like `prisma format`, without changing them. path is a
+// schema file or a directory of .prisma files, which are formatted one by one. Compare the content of each file with
+// the current one to check whether it is formatted, or write it back to format the file. The schema is formatted in
+// Go, so neither the Prisma CLI nor an engine is needed.
+func Format(path string) ([]File, error) {
+	files, err := schemaengine.ReadSchema(path)
+	if err != nil {
+		return nil, err
+	}
+	formatted := make([]File, len(files))
+	for i, file := range files {
+		content, err := FormatContent(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("format schema %s: %w", file.Path, err)
+		}
+		formatted[i] = File{Path: file.Path, Content: content}
+	}
+	return formatted, nil
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/binaries"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+// fakeQueryEngineEnv makes the test binary act as the query engine, so that validation can be tested without
+// downloading the query engine
+const fakeQueryEngineEnv = "PRISMA_CLIENT_GO_FAKE_QUERY_ENGINE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeQueryEngineEnv) != "" {
+		runFakeDMMF()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeDMMF rejects schemas containing a model without fields, like the query engine does
+func runFakeDMMF() {
+	content, err := base64.StdEncoding.DecodeString(os.Getenv("PRISMA_DML"))
+	if err != nil {
+		os.Exit(2)
+	}
+	if strings.Contains(string(content), "model Empty {}") {
+		fmt.Fprintln(os.Stderr, `{"is_panic":false,"message":"error: Each model must have at least one unique criteria.\n  -->  schema.prisma:6\n","meta":{},"error_code":"P1012"}`)
+		os.Exit(1)
+	}
+	fmt.Println(`{"datamodel": {"models": [], "enums": [], "types": []}}`)
+}
+
+const datasource = `datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+`
+
+func TestValidate(t *testing.T) {
+	t.Setenv(fakeQueryEngineEnv, "true")
+	t.Setenv(binaries.QueryEngineEnv, os.Args[0])
+
+	path := filepath.Join(t.TempDir(), "schema.prisma")
+	if err := os.WriteFile(path, []byte(datasource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ValidateContent(context.Background(), datasource+"\nmodel Empty {}\n")
+	var schemaErr *Error
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	massert.Equal(t, "P1012", schemaErr.Code)
+	massert.Equal(t, "error: Each model must have at least one unique criteria.\n  -->  schema.prisma:6\n", schemaErr.Message)
+
+	err = Validate(context.Background(), filepath.Join(t.TempDir(), "missing.prisma"))
+	massert.Equal(t, true, errors.Is(err, os.ErrNotExist))
+}
@@ -0,0 +1,154 @@
+package composite_types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestCompositeTypes(t *testing.T) {
+	t.Parallel()
+
+	zip := "12345"
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "create and find",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			created, err := client.User.CreateOne(
+				User.Email.Set("john@example.com"),
+				User.Address.Set(Address{
+					Street: "Main Street 1",
+					Zip:    &zip,
+					Geo:    &Geo{Lat: 52.5, Lng: 13.25},
+				}),
+				User.ID.Set("123"),
+				User.Locations.Set([]Address{{
+					Street: "Second Street 2",
+				}}),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			expected := &UserModel{
+				InnerUser: InnerUser{
+					ID:    "123",
+					Email: "john@example.com",
+					Address: Address{
+						Street: "Main Street 1",
+						Zip:    &zip,
+						Geo:    &Geo{Lat: 52.5, Lng: 13.25},
+					},
+					Locations: []Address{{
+						Street: "Second Street 2",
+					}},
+				},
+			}
+
+			massert.Equal(t, expected, created)
+
+			actual, err := client.User.FindFirst(
+				User.Address.Is(
+					AddressQuery.Street.Equals("Main Street 1"),
+					AddressQuery.Geo.Is(
+						GeoQuery.Lat.Equals(52.5),
+					),
+				),
+				User.Billing.IsSet(false),
+				User.Locations.Some(
+					AddressQuery.Street.Equals("Second Street 2"),
+				),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			massert.Equal(t, expected, actual)
+
+			_, err = client.User.FindFirst(
+				User.Address.IsNot(
+					AddressQuery.Street.Equals("Main Street 1"),
+				),
+			).Exec(ctx)
+			massert.Equal(t, ErrNotFound, err)
+		},
+	}, {
+		name: "update",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			_, err := client.User.CreateOne(
+				User.Email.Set("john@example.com"),
+				User.Address.Set(Address{
+					Street: "Main Street 1",
+				}),
+				User.ID.Set("123"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			updated, err := client.User.FindUnique(
+				User.ID.Equals("123"),
+			).Update(
+				User.Address.Update(
+					AddressQuery.Street.Set("Main Street 2"),
+				),
+				User.Billing.Upsert(
+					Address{Street: "Billing Street 1"},
+					AddressQuery.Street.Set("Billing Street 2"),
+				),
+				User.Locations.Push(Address{Street: "Third Street 3"}),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			massert.Equal(t, &UserModel{
+				InnerUser: InnerUser{
+					ID:    "123",
+					Email: "john@example.com",
+					Address: Address{
+						Street: "Main Street 2",
+					},
+					Billing: &Address{
+						Street: "Billing Street 1",
+					},
+					Locations: []Address{{
+						Street: "Third Street 3",
+					}},
+				},
+			}, updated)
+
+			updated, err = client.User.FindUnique(
+				User.ID.Equals("123"),
+			).Update(
+				User.Billing.Unset(),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			_, ok := updated.Billing()
+			massert.Equal(t, false, ok)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.MongoDB}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, ctx)
+			})
+		})
+	}
+}
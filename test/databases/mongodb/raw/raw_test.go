@@ -0,0 +1,140 @@
+package raw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+type RawUser struct {
+	ID       string `json:"_id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Int      int    `json:"int"`
+}
+
+func TestRaw(t *testing.T) {
+	t.Parallel()
+
+	// language=GraphQL
+	before := []string{`
+		mutation {
+			result: createOneUser(data: {
+				id: "id1",
+				email: "email1",
+				username: "a",
+				int: 5,
+			}) {
+				id
+			}
+		}
+	`, `
+		mutation {
+			result: createOneUser(data: {
+				id: "id2",
+				email: "email2",
+				username: "b",
+				int: 10,
+			}) {
+				id
+			}
+		}
+	`}
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name:   "find raw",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var actual []RawUser
+			if err := client.User.FindRaw(map[string]interface{}{
+				"username": "b",
+			}).Exec(ctx, &actual); err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			massert.Equal(t, []RawUser{{
+				ID:       "id2",
+				Email:    "email2",
+				Username: "b",
+				Int:      10,
+			}}, actual)
+		},
+	}, {
+		name:   "find raw with json string and options",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var actual []RawUser
+			if err := client.User.FindRaw(
+				`{"int": {"$gte": 5}}`,
+				`{"sort": {"int": -1}, "projection": {"email": 0}}`,
+			).Exec(ctx, &actual); err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			massert.Equal(t, []RawUser{{
+				ID:       "id2",
+				Username: "b",
+				Int:      10,
+			}, {
+				ID:       "id1",
+				Username: "a",
+				Int:      5,
+			}}, actual)
+		},
+	}, {
+		name:   "aggregate raw",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var actual []struct {
+				ID  interface{} `json:"_id"`
+				Sum int         `json:"sum"`
+			}
+			if err := client.User.AggregateRaw([]interface{}{
+				map[string]interface{}{"$match": map[string]interface{}{"int": map[string]interface{}{"$gt": 0}}},
+				map[string]interface{}{"$group": map[string]interface{}{"_id": nil, "sum": map[string]interface{}{"$sum": "$int"}}},
+			}).Exec(ctx, &actual); err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			massert.Equal(t, 1, len(actual))
+			massert.Equal(t, 15, actual[0].Sum)
+		},
+	}, {
+		name:   "run command raw",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var actual struct {
+				N  int     `json:"n"`
+				OK float64 `json:"ok"`
+			}
+			if err := client.Prisma.RunCommandRaw(map[string]interface{}{
+				"count": "User",
+			}).Exec(ctx, &actual); err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			massert.Equal(t, 2, actual.N)
+			massert.Equal(t, 1.0, actual.OK)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.MongoDB}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, ctx)
+			})
+		})
+	}
+}
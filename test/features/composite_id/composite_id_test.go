@@ -206,6 +206,79 @@ func TestCompositeID(t *testing.T) {
 				t.Fatalf("fail %s", err)
 			}
 		},
+	}, {
+		name: "upsert",
+		before: []string{`
+			mutation {
+				result: createOneCompany(data: {
+					id: "123",
+					name: "name",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			for _, name := range []string{"a", "b"} {
+				_, err := client.Organization.UpsertOne(
+					Organization.OrganizationID(
+						Organization.PlatformKind.Equals("kind"),
+						Organization.PlatformID.Equals("id"),
+					),
+				).Create(
+					Organization.PlatformID.Set("id"),
+					Organization.PlatformKind.Set("kind"),
+					Organization.Name.Set(name),
+				).Update(
+					Organization.Name.Set(name),
+				).Exec(ctx)
+				if err != nil {
+					t.Fatalf("fail %s", err)
+				}
+			}
+
+			access, err := client.Access.UpsertOne(
+				Access.CompanyIDEmail(
+					Access.CompanyID.Equals("123"),
+					Access.Email.Equals("email"),
+				),
+			).Create(
+				Access.CompanyRelation.Link(
+					Company.ID.Equals("123"),
+				),
+				Access.Email.Set("email"),
+			).Update().Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+			massert.Equal(t, "email", access.Email)
+
+			orgs, err := client.Organization.FindMany().Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+			massert.Equal(t, 1, len(orgs))
+			massert.Equal(t, "b", orgs[0].Name)
+		},
+	}, {
+		name: "factory",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			member, err := MemberFactory().Create(ctx, client)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			org, err := client.Organization.FindUnique(
+				Organization.OrganizationID(
+					Organization.PlatformKind.Equals(member.OrgKind),
+					Organization.PlatformID.Equals(member.OrgID),
+				),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+			massert.Equal(t, member.OrgID, org.PlatformID)
+		},
 	}}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestCreateMany(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "create many",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			result, err := client.User.CreateMany(
+				client.User.CreateOne(User.ID.Set("b"), User.Name.Set("Bob")),
+				client.User.CreateOne(User.ID.Set("c"), User.Name.Set("Carol")),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 2, result.Count)
+
+			count, err := client.User.FindMany().Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 3, count)
+		},
+	}, {
+		name: "skip duplicates",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			result, err := client.User.CreateMany(
+				client.User.CreateOne(User.ID.Set("a"), User.Name.Set("Alice")),
+				client.User.CreateOne(User.ID.Set("b"), User.Name.Set("Bob")),
+			).SkipDuplicates().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, result.Count)
+		},
+	}, {
+		name: "returning",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			create := client.Post.CreateMany()
+			for _, title := range []string{"A", "B"} {
+				create = create.Add(client.Post.CreateOne(
+					Post.Title.Set(title),
+					Post.Author.Link(User.ID.Equals("a")),
+				))
+			}
+			actual, err := create.Returning().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, 2, len(actual))
+			for i, title := range []string{"A", "B"} {
+				massert.Equal(t, title, actual[i].Title)
+				massert.Equal(t, "a", actual[i].AuthorID)
+				massert.Equal(t, false, actual[i].Published)
+				if actual[i].ID == "" {
+					t.Fatalf("expected a generated id for post %d", i)
+				}
+			}
+		},
+	}, {
+		name: "without creates",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			result, err := client.User.CreateMany().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 0, result.Count)
+
+			actual, err := client.Post.CreateMany().Returning().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []PostModel{}, actual)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOneUser(data: {id: "a", name: "Alice"}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestDataloaders(t *testing.T) {
+	t.Parallel()
+
+	before := []string{`
+		mutation {
+			result: createOneUser(data: {
+				id: "alice",
+				email: "alice@example.com",
+				name: "Alice",
+				posts: {
+					create: [{ id: "a1", title: "First" }, { id: "a2", title: "Second" }],
+				},
+			}) {
+				id
+			}
+		}
+	`, `
+		mutation {
+			result: createOneUser(data: {
+				id: "bob",
+				email: "bob@example.com",
+				name: "Bob",
+			}) {
+				id
+			}
+		}
+	`}
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name:   "unique field",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			loaders := NewLoaders(client)
+
+			users, err := loaders.UserByID.LoadAll(ctx, []string{"bob", "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Bob", users[0].Name)
+			massert.Equal(t, "Alice", users[1].Name)
+
+			user, err := loaders.UserByEmail.Load(ctx, "alice@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "alice", user.ID)
+
+			_, err = loaders.UserByID.Load(ctx, "carol")
+			massert.Equal(t, ErrNotFound, err)
+		},
+	}, {
+		name:   "relation",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			loader := NewPostsByAuthorIDLoader(client)
+
+			var wg sync.WaitGroup
+			titles := make(map[string][]string)
+			var mu sync.Mutex
+			for _, id := range []string{"alice", "bob"} {
+				id := id
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					posts, err := loader.Load(ctx, id)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					titles[id] = []string{}
+					for _, post := range posts {
+						titles[id] = append(titles[id], post.Title)
+					}
+				}()
+			}
+			wg.Wait()
+			sort.Strings(titles["alice"])
+
+			massert.Equal(t, []string{"First", "Second"}, titles["alice"])
+			massert.Equal(t, []string{}, titles["bob"])
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
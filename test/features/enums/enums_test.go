@@ -2,6 +2,7 @@ package enums
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -236,3 +237,31 @@ func TestEnums(t *testing.T) {
 		})
 	}
 }
+
+func TestEnumHelpers(t *testing.T) {
+	t.Parallel()
+
+	massert.Equal(t, []Role{RoleUser, RoleModerator, RoleAdmin}, RoleAdmin.Values())
+	massert.Equal(t, true, RoleModerator.IsValid())
+	massert.Equal(t, false, Role("Guest").IsValid())
+	massert.Equal(t, "Admin", RoleAdmin.String())
+
+	type payload struct {
+		Role Role `json:"role"`
+	}
+
+	data, err := json.Marshal(payload{Role: RoleAdmin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, `{"role":"Admin"}`, string(data))
+
+	var p payload
+	if err := json.Unmarshal([]byte(`{"role":"Moderator"}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, RoleModerator, p.Role)
+
+	err = json.Unmarshal([]byte(`{"role":"Guest"}`), &p)
+	massert.Equal(t, `invalid Role value "Guest"`, err.Error())
+}
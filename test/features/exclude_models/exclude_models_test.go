@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestExcludeModels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "foreign key of excluded relation",
+		before: []string{`
+			mutation {
+				result: createOneLegacyUser(data: {
+					id: "legacy",
+					email: "old@example.com",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			// the relation to the excluded model is dropped, but its foreign key can still be set
+			created, err := client.User.CreateOne(
+				User.Email.Set("new@example.com"),
+				User.LegacyID.Set("legacy"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			user, err := client.User.FindUnique(
+				User.LegacyID.Equals("legacy"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, created.ID, user.ID)
+		},
+	}, {
+		name: "excluded tables",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			// excluded models are still migrated and can be queried with raw queries
+			var versions []struct {
+				Version string `json:"version"`
+			}
+			if err := client.Prisma.QueryRaw(`SELECT version FROM "SchemaMigration"`).Exec(ctx, &versions); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 0, len(versions))
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
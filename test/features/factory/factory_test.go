@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestFactory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "required fields",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			users, err := UserFactory().CreateMany(ctx, client, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, 2, len(users))
+			massert.Equal(t, true, strings.HasSuffix(users[0].Email, "@example.com"))
+			massert.Equal(t, false, users[0].Email == users[1].Email)
+			massert.Equal(t, RoleUser, users[0].Role)
+			_, ok := users[0].Name()
+			massert.Equal(t, false, ok)
+		},
+	}, {
+		name: "with",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			user, err := UserFactory().With(
+				User.Email.Set("a@example.com"),
+				User.Name.Set("a"),
+			).Create(ctx, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, "a@example.com", user.Email)
+			name, _ := user.Name()
+			massert.Equal(t, "a", name)
+		},
+	}, {
+		name: "relations",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			post, err := PostFactory().Create(ctx, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			author, err := client.User.FindUnique(User.ID.Equals(post.AuthorID)).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// an existing record is linked instead of creating a new one
+			linked, err := PostFactory().With(
+				Post.Author.Link(User.ID.Equals(author.ID)),
+			).Create(ctx, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, author.ID, linked.AuthorID)
+
+			users, err := client.User.FindMany().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(users))
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, test.Databases, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
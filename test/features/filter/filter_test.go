@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/filter"
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func ids(users []UserModel) []string {
+	var result []string
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+	return result
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "conditions from json",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var conditions []filter.Condition
+			if err := json.Unmarshal([]byte(`[
+				{"field": "email", "operator": "endsWith", "value": "@example.com"},
+				{"field": "age", "operator": "gte", "value": "18"},
+				{"field": "age", "operator": "lt", "value": 65}
+			]`), &conditions); err != nil {
+				t.Fatal(err)
+			}
+
+			where, err := User.Filter(conditions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			users, err := client.User.FindMany(where).OrderBy(User.ID.Order(SortOrderAsc)).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, []string{"a", "b"}, ids(users))
+		},
+	}, {
+		name: "combined with other params",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			where, err := User.Filter(
+				filter.Condition{Field: "role", Operator: filter.In, Value: []string{"ADMIN"}},
+				filter.Condition{Field: "bio", Operator: filter.Equals, Value: nil},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			users, err := client.User.FindMany(
+				User.Email.EndsWith("@example.com"),
+				where,
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, []string{"b"}, ids(users))
+		},
+	}, {
+		name: "invalid",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			_, err := User.Filter(filter.Condition{Field: "role", Operator: filter.Equals, Value: "ROOT"})
+			massert.Equal(t, true, errors.Is(err, ErrInvalidFilter))
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOneUser(data: {id: "a", email: "a@example.com", age: 20, bio: "a", role: USER}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOneUser(data: {id: "b", email: "b@example.com", age: 30, role: ADMIN}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOneUser(data: {id: "c", email: "c@example.com", age: 70, role: ADMIN}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOneUser(data: {id: "d", email: "d@prisma.io", age: 20, role: USER}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
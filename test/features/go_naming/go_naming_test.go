@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestGoNaming(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "identifiers",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			created, err := client.ProductVariants.CreateOne(
+				ProductVariants.ProductSKU.Set("a-1"),
+				ProductVariants.ID.Set("variant"),
+				ProductVariants.ImageURL.Set("https://example.com/a.png"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			variant, err := client.ProductVariants.FindUnique(
+				ProductVariants.ID.Equals(created.ID),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "a-1", variant.ProductSKU)
+			imageURL, _ := variant.ImageURL()
+			massert.Equal(t, "https://example.com/a.png", imageURL)
+			massert.Equal(t, created.CreatedAt, variant.CreatedAt)
+		},
+	}, {
+		name: "raw",
+		before: []string{`
+			mutation {
+				result: createOneproduct_variants(data: {
+					id: "variant",
+					productSku: "a-1",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			// raw queries return the column names of @map
+			var actual []RawProductVariantsModel
+			if err := client.Prisma.QueryRaw(`SELECT variant_id, product_sku, image_url FROM variants`).Exec(ctx, &actual); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(actual))
+			massert.Equal(t, RawString("variant"), actual[0].ID)
+			massert.Equal(t, RawString("a-1"), actual[0].ProductSKU)
+			massert.Equal(t, (*RawString)(nil), actual[0].ImageURL)
+		},
+	}, {
+		name: "table and column names",
+		before: []string{`
+			mutation {
+				result: createOneproduct_variants(data: {
+					id: "variant",
+					productSku: "a-1",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			massert.Equal(t, "variants", ProductVariants.TableName)
+			massert.Equal(t, "variant_id", ProductVariants.Columns.ID)
+			massert.Equal(t, "product_sku", ProductVariants.Columns.ProductSKU)
+			massert.Equal(t, "created_at", ProductVariants.Columns.CreatedAt)
+
+			columns := ProductVariants.Columns
+			query := fmt.Sprintf(`SELECT %s, %s FROM %s`, columns.ID, columns.ProductSKU, ProductVariants.TableName)
+			var actual []RawProductVariantsModel
+			if err := client.Prisma.QueryRaw(query).Exec(ctx, &actual); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(actual))
+			massert.Equal(t, RawString("a-1"), actual[0].ProductSKU)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
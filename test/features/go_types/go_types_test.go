@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestGoTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "create and find",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			id := AccountID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+			created, err := client.Account.CreateOne(
+				Account.Balance.Set(Cents(150)),
+				Account.ID.Set(id),
+				Account.Refs.Set([]AccountID{id}),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, id, created.ID)
+			massert.Equal(t, Cents(150), created.Balance)
+
+			account, err := client.Account.FindFirst(
+				Account.ID.In([]AccountID{id}),
+				Account.Balance.Gte(Cents(100)),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []AccountID{id}, account.Refs)
+			_, ok := account.Timeout()
+			massert.Equal(t, false, ok)
+		},
+	}, {
+		name: "update",
+		before: []string{`
+			mutation {
+				result: createOneAccount(data: {
+					id: "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+					balance: 100,
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			account, err := client.Account.FindUnique(
+				Account.ID.Equals("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+			).Update(
+				Account.Balance.Increment(Cents(50)),
+				Account.Timeout.Set(time.Millisecond),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, Cents(150), account.Balance)
+			timeout, _ := account.Timeout()
+			massert.Equal(t, time.Millisecond, timeout)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
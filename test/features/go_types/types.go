@@ -0,0 +1,7 @@
+package db
+
+// AccountID is the Go type of uuid columns, set with the goTypes option
+type AccountID string
+
+// Cents is the Go type of Account.balance, set with a @go.type annotation
+type Cents int
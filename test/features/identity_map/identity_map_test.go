@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+// renameUser changes the name of a user with a context without identity map, which the identity map doesn't notice
+func renameUser(t *testing.T, client *PrismaClient, id, name string) {
+	t.Helper()
+	if _, err := client.Prisma.ExecuteRaw(`UPDATE "User" SET name = $1 WHERE id = $2`, name, id).Exec(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findName(t *testing.T, client *PrismaClient, ctx cx) string {
+	t.Helper()
+	user, err := client.User.FindUnique(User.ID.Equals("alice")).Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.Name
+}
+
+func TestIdentityMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "memoize find unique",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			ctx = WithIdentityMap(ctx)
+			massert.Equal(t, "Alice", findName(t, client, ctx))
+
+			renameUser(t, client, "alice", "Renamed")
+			massert.Equal(t, "Alice", findName(t, client, ctx))
+			massert.Equal(t, "Renamed", findName(t, client, context.Background()))
+
+			if _, err := client.User.FindUnique(User.ID.Equals("alice")).Update(
+				User.Name.Set("Updated"),
+			).Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Updated", findName(t, client, ctx))
+		},
+	}, {
+		name: "not found",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			ctx = WithIdentityMap(ctx)
+			_, err := client.User.FindUnique(User.ID.Equals("bob")).Exec(ctx)
+			massert.Equal(t, ErrNotFound, err)
+
+			if _, err := client.User.CreateOne(
+				User.ID.Set("bob"),
+				User.Name.Set("Bob"),
+			).Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+			user, err := client.User.FindUnique(User.ID.Equals("bob")).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Bob", user.Name)
+		},
+	}, {
+		name: "bypass in transaction",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			ctx = WithIdentityMap(ctx)
+			massert.Equal(t, "Alice", findName(t, client, ctx))
+
+			err := client.Prisma.InteractiveTransaction(ctx, func(tx *PrismaClient) error {
+				renameUser(t, tx, "alice", "Renamed")
+				massert.Equal(t, "Renamed", findName(t, tx, ctx))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOneUser(data: {
+							id: "alice",
+							name: "Alice",
+						}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
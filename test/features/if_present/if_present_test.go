@@ -81,6 +81,46 @@ func TestIfPresent(t *testing.T) {
 
 			massert.Equal(t, expected, actual)
 		},
+	}, {
+		name: "scalar pointer helpers",
+		// language=GraphQL
+		before: []string{`
+			mutation {
+				result: createOneUser(data: {
+					id: "helpers",
+					email: "john@example.com",
+					username: "johndoe",
+					name: "John",
+					age: 1,
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			updated, err := client.User.FindUnique(
+				User.ID.Equals("helpers"),
+			).Update(
+				User.Username.SetIfPresent(StringPtr("new-username")),
+				User.Name.SetOptional(NullString()),
+				User.Age.SetOptional(IntPtr(5)),
+				User.Age2.SetIfPresent(NullInt()),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			expected := &UserModel{
+				InnerUser: InnerUser{
+					ID:       "helpers",
+					Email:    "john@example.com",
+					Username: "new-username",
+					Age:      IntPtr(5),
+				},
+			}
+
+			massert.Equal(t, expected, updated)
+		},
 	}, {
 		name: "update operations",
 		// language=GraphQL
@@ -218,6 +258,75 @@ func TestIfPresent(t *testing.T) {
 
 			massert.Equal(t, expected, post)
 		},
+	}, {
+		name: "conditional params",
+		// language=GraphQL
+		before: []string{`
+			mutation {
+				result: createOneUser(data: {
+					id: "a",
+					email: "a@example.com",
+					username: "johndoe",
+					name: "John",
+				}) {
+					id
+				}
+			}
+		`, `
+			mutation {
+				result: createOneUser(data: {
+					id: "b",
+					email: "b@example.com",
+					username: "janedoe",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			username, name := "", "Jane"
+
+			var where Params[UserWhereParam]
+			where.Add(User.Email.Contains("@example.com"))
+			where.AddIf(username != "", User.Username.Equals(username))
+			where.Add(
+				If(username != "", User.Name.Equals(username)),
+				// the zero value of a param interface is nil and skipped as well
+				If[UserWhereParam](false, User.Age.Equals(1)),
+			)
+
+			users, err := client.User.FindMany(where...).OrderBy(
+				If(name == "", User.Email.Order(SortOrderAsc)),
+				User.ID.Order(SortOrderDesc),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+			massert.Equal(t, 2, len(users))
+			massert.Equal(t, "b", users[0].ID)
+
+			var set Params[UserSetParam]
+			set.AddIf(name != "", User.Name.Set(name))
+			set.Add(If[UserSetParam](username != "", User.Username.Set(username)))
+
+			updated, err := client.User.FindUnique(
+				User.ID.Equals("b"),
+			).Update(set...).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			name2 := "Jane"
+			expected := &UserModel{
+				InnerUser: InnerUser{
+					ID:       "b",
+					Email:    "b@example.com",
+					Username: "janedoe",
+					Name:     &name2,
+				},
+			}
+			massert.Equal(t, expected, updated)
+		},
 	}}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+type summary struct {
+	ID    string `prisma:"id"`
+	Title string `prisma:"title"`
+	Views int64  `prisma:"views"`
+}
+
+func TestInto(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "select",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var posts []summary
+			err := client.Post.FindMany().Select(
+				Post.ID.Field(),
+				Post.Title.Field(),
+				Post.Views.Field(),
+			).OrderBy(
+				Post.ID.Order(SortOrderAsc),
+			).Into(&posts).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, []summary{
+				{ID: "a", Title: "A", Views: 5},
+				{ID: "b", Title: "B", Views: 2},
+			}, posts)
+		},
+	}, {
+		name: "find unique",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var post struct {
+				Title   string
+				Content *string
+			}
+			err := client.Post.FindUnique(Post.ID.Equals("b")).Into(&post).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "B", post.Title)
+			massert.Equal(t, "content", *post.Content)
+
+			err = client.Post.FindUnique(Post.ID.Equals("c")).Into(&post).Exec(ctx)
+			massert.Equal(t, ErrNotFound, err)
+		},
+	}, {
+		name: "raw",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var rows []struct {
+				Published bool   `prisma:"published"`
+				Count     int    `prisma:"count"`
+				Views     uint64 `prisma:"views"`
+			}
+			err := client.Prisma.QueryRaw(`SELECT published, count(*) AS count, sum(views) AS views FROM "Post" GROUP BY published ORDER BY published`).Into(&rows).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, 2, len(rows))
+			massert.Equal(t, false, rows[0].Published)
+			massert.Equal(t, 1, rows[0].Count)
+			massert.Equal(t, uint64(2), rows[0].Views)
+			massert.Equal(t, true, rows[1].Published)
+			massert.Equal(t, uint64(5), rows[1].Views)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOnePost(data: {id: "a", title: "A", published: true, views: 5}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "b", title: "B", published: false, views: 2, content: "content"}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
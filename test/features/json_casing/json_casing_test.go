@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestJSONCasing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "marshal",
+		before: []string{`
+			mutation {
+				result: createOneUser(data: {
+					id: "user",
+					createdAt: "2024-01-01T00:00:00Z",
+					firstName: "Alice",
+					posts: {
+						create: [{ id: "post", title: "a" }],
+					},
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			user, err := client.User.FindUnique(
+				User.ID.Equals("user"),
+			).With(
+				User.Posts.Fetch(),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := json.Marshal(user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, `{"id":"user","created_at":"2024-01-01T00:00:00Z","first_name":"Alice","nick_name":null,"posts":[{"id":"post","title":"a","author_id":"user"}]}`, string(data))
+
+			// the configured names can be decoded again
+			var decoded UserModel
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, *user, decoded)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
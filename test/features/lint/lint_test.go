@@ -0,0 +1,140 @@
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// allowedNolint are the declarations of the generated code which may have a //nolint pragma; their names are kept for
+// compatibility
+var allowedNolint = map[string]bool{
+	"ASC":  true,
+	"DESC": true,
+}
+
+// TestGeneratedCodeIsLintClean checks the generated client in ./db for the findings of revive's exported rule, which
+// golangci-lint, revive and stylecheck all report, so that the generated code doesn't need to be excluded from linting
+// and doesn't need a blanket //nolint directive
+func TestGeneratedCodeIsLintClean(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "db", nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no generated code in ./db; run go generate -tags setup ./... first")
+	}
+
+	for _, pkg := range pkgs {
+		for name, file := range pkg.Files {
+			if !ast.IsGenerated(file) {
+				t.Errorf("%s: missing the generated code header", name)
+			}
+			for _, group := range file.Comments {
+				for _, c := range group.List {
+					if strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(c.Text, "//")), "nolint") {
+						if !allowedNolint[commentedValue(file, group)] {
+							t.Errorf("%s: unexpected %s", fset.Position(c.Pos()), c.Text)
+						}
+					}
+				}
+			}
+			for _, decl := range file.Decls {
+				for _, finding := range lintDecl(decl) {
+					t.Errorf("%s: %s", fset.Position(decl.Pos()), finding)
+				}
+			}
+		}
+	}
+}
+
+// lintDecl returns the findings of revive's exported rule for a declaration
+func lintDecl(decl ast.Decl) []string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if !decl.Name.IsExported() || !exportedReceiver(decl) {
+			return nil
+		}
+		kind := "function"
+		if decl.Recv != nil {
+			kind = "method"
+		}
+		return lintDoc(kind, decl.Name.Name, decl.Doc, "")
+	case *ast.GenDecl:
+		var findings []string
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if !spec.Name.IsExported() {
+					continue
+				}
+				doc := spec.Doc
+				if doc == nil && !decl.Lparen.IsValid() {
+					doc = decl.Doc
+				}
+				findings = append(findings, lintDoc("type", spec.Name.Name, doc, "A ", "An ")...)
+			case *ast.ValueSpec:
+				if !spec.Names[0].IsExported() {
+					continue
+				}
+				// a doc comment of a parenthesized group documents all of its values
+				if decl.Lparen.IsValid() && decl.Doc != nil {
+					continue
+				}
+				doc := spec.Doc
+				if doc == nil {
+					doc = decl.Doc
+				}
+				findings = append(findings, lintDoc(decl.Tok.String(), spec.Names[0].Name, doc)...)
+			}
+		}
+		return findings
+	}
+	return nil
+}
+
+// lintDoc returns a finding if doc doesn't exist or doesn't start with the name of the declaration
+func lintDoc(kind, name string, doc *ast.CommentGroup, prefixes ...string) []string {
+	if doc == nil {
+		return []string{"exported " + kind + " " + name + " should have comment or be unexported"}
+	}
+	text := doc.Text()
+	for _, prefix := range append([]string{""}, prefixes...) {
+		if strings.HasPrefix(text, prefix+name+" ") || strings.HasPrefix(text, prefix+name+"\n") {
+			return nil
+		}
+	}
+	return []string{"comment on exported " + kind + " " + name + " should be of the form \"" + name + " ...\""}
+}
+
+// exportedReceiver reports whether a function is not a method or a method of an exported type, as revive doesn't
+// report methods of unexported types
+func exportedReceiver(decl *ast.FuncDecl) bool {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return true
+	}
+	typ := decl.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if index, ok := typ.(*ast.IndexExpr); ok {
+		typ = index.X
+	}
+	ident, ok := typ.(*ast.Ident)
+	return ok && ident.IsExported()
+}
+
+// commentedValue returns the name of the value which has group as its trailing comment, if any
+func commentedValue(file *ast.File, group *ast.CommentGroup) string {
+	var name string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok && spec.Comment == group {
+			name = spec.Names[0].Name
+		}
+		return name == ""
+	})
+	return name
+}
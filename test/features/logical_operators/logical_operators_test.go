@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func ids(posts []PostModel) []string {
+	var result []string
+	for _, p := range posts {
+		result = append(result, p.ID)
+	}
+	return result
+}
+
+func TestLogicalOperators(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		where []PostWhereParam
+		ids   []string
+	}{{
+		name: "or of the same field",
+		where: []PostWhereParam{
+			Post.Or(
+				Post.Views.Lt(10),
+				Post.Views.Gt(100),
+			),
+		},
+		ids: []string{"a", "c"},
+	}, {
+		name: "not of the same field",
+		where: []PostWhereParam{
+			Post.Not(
+				Post.Title.Equals("a"),
+				Post.Title.Equals("b"),
+			),
+		},
+		ids: []string{"c", "d"},
+	}, {
+		name: "multiple ors",
+		where: []PostWhereParam{
+			Post.Or(
+				Post.ID.Equals("a"),
+				Post.ID.Equals("b"),
+				Post.ID.Equals("c"),
+			),
+			Post.Or(
+				Post.Published.Equals(false),
+				Post.Views.Gte(1000),
+			),
+		},
+		ids: []string{"b", "c"},
+	}, {
+		name: "nested across fields and relations",
+		where: []PostWhereParam{
+			Post.Or(
+				Post.And(
+					Post.Published.Equals(true),
+					Post.Comments.Some(
+						Comment.Or(
+							Comment.Content.Contains("great"),
+							Comment.Content.Contains("nice"),
+						),
+					),
+				),
+				Post.And(
+					Post.Published.Equals(false),
+					Post.Not(
+						Post.Comments.Some(),
+					),
+				),
+			),
+		},
+		ids: []string{"a", "d"},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOnePost(data: {
+							id: "a", title: "a", published: true, views: 5,
+							comments: {create: [{id: "a1", content: "great post"}]},
+						}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {
+							id: "b", title: "b", published: false, views: 50,
+							comments: {create: [{id: "b1", content: "nice"}]},
+						}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {
+							id: "c", title: "c", published: true, views: 5000,
+							comments: {create: [{id: "c1", content: "meh"}]},
+						}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "d", title: "d", published: false, views: 50}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+
+				posts, err := client.Post.FindMany(tt.where...).OrderBy(
+					Post.ID.Order(SortOrderAsc),
+				).Exec(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				massert.Equal(t, tt.ids, ids(posts))
+			})
+		})
+	}
+}
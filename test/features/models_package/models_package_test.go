@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/features/models_package/models"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+// describe only depends on the models package, not on the client
+func describe(user *models.UserModel) string {
+	return user.Email + " (" + user.Role.String() + ")"
+}
+
+func TestModelsPackage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "models of the client are the models of the models package",
+		before: []string{`
+			mutation {
+				result: createOneUser(data: {
+					id: "alice",
+					email: "alice@example.com",
+					role: Admin,
+					posts: {
+						create: [{ id: "a1", title: "First" }],
+					},
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			user, err := client.User.FindUnique(
+				User.ID.Equals("alice"),
+			).With(
+				User.Posts.Fetch(),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, "alice@example.com (Admin)", describe(user))
+			massert.Equal(t, models.RoleAdmin, user.Role)
+			massert.Equal(t, "First", user.Posts()[0].Title)
+
+			created, err := client.User.CreateOne(
+				User.ID.Set("bob"),
+				User.Email.Set("bob@example.com"),
+				User.Role.Set(models.RoleMember),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, RoleMember, created.Role)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
@@ -62,3 +62,78 @@ func TestTableCasing(t *testing.T) {
 		massert.Equal(t, expectedPost, actualUpdatedPost)
 	})
 }
+
+func TestNumberOperationsMany(t *testing.T) {
+	test.RunParallel(t, []test.Database{test.MySQL, test.PostgreSQL, test.SQLite}, func(t *testing.T, db test.Database, ctx context.Context) {
+		client := NewClient()
+
+		// language=GraphQL
+		mockDB := test.Start(t, db, client.Engine, []string{`
+			mutation {
+				result: createOnePost(data: {
+					id: "a",
+					int: 10,
+					float: 10,
+					int2: 10,
+					float2: 10,
+					big: 5,
+				}) {
+					id
+				}
+			}
+		`, `
+			mutation {
+				result: createOnePost(data: {
+					id: "b",
+					int: 20,
+					float: 10,
+					int2: 10,
+					float2: 10,
+					big: 1,
+				}) {
+					id
+				}
+			}
+		`})
+		defer test.End(t, db, client.Engine, mockDB)
+
+		result, err := client.Post.FindMany().Update(
+			Post.Int.Increment(1),
+			Post.Big.Increment(2),
+		).Exec(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		massert.Equal(t, &BatchResult{Count: 2}, result)
+
+		actual, err := client.Post.FindMany().OrderBy(
+			Post.ID.Order(SortOrderAsc),
+		).Exec(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []PostModel{{
+			InnerPost: InnerPost{
+				ID:     "a",
+				Int:    11,
+				Float:  10,
+				Int2:   10,
+				Float2: 10,
+				Big:    7,
+			},
+		}, {
+			InnerPost: InnerPost{
+				ID:     "b",
+				Int:    21,
+				Float:  10,
+				Int2:   10,
+				Float2: 10,
+				Big:    3,
+			},
+		}}
+
+		massert.Equal(t, expected, actual)
+	})
+}
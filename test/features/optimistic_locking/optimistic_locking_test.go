@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestOptimisticLocking(t *testing.T) {
+	t.Parallel()
+
+	before := []string{`
+		mutation {
+			result: createOnePost(data: {
+				id: "a",
+				title: "First",
+			}) {
+				id
+			}
+		}
+	`, `
+		mutation {
+			result: createOnePost(data: {
+				id: "b",
+				title: "Second",
+			}) {
+				id
+			}
+		}
+	`}
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name:   "update bumps the version",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			post, err := client.Post.FindUnique(Post.ID.Equals("a")).Update(
+				Post.Title.Set("Changed"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 2, post.Version)
+
+			result, err := client.Post.FindMany().Update(
+				Post.Title.Set("All"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 2, result.Count)
+
+			post, err = client.Post.FindUnique(Post.ID.Equals("a")).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 3, post.Version)
+		},
+	}, {
+		name:   "if version",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			post, err := client.Post.FindUnique(Post.ID.Equals("a")).Update(
+				Post.Title.Set("Changed"),
+			).IfVersion(1).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Changed", post.Title)
+			massert.Equal(t, 2, post.Version)
+
+			// the record was changed since version 1 was read
+			_, err = client.Post.FindUnique(Post.ID.Equals("a")).Update(
+				Post.Title.Set("Stale"),
+			).IfVersion(1).Exec(ctx)
+			if !IsErrStaleRecord(err) {
+				t.Fatalf("expected ErrStaleRecord, got %v", err)
+			}
+
+			post, err = client.Post.FindUnique(Post.ID.Equals("a")).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Changed", post.Title)
+		},
+	}, {
+		name:   "only if changed keeps the version",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			post, err := client.Post.FindUnique(Post.ID.Equals("a")).Update(
+				Post.Title.Set("First"),
+			).OnlyIfChanged().IfVersion(1).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, post.Version)
+		},
+	}, {
+		name: "date time version",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			note, err := client.Note.CreateOne(
+				Note.ID.Set("a"),
+				Note.Content.Set("First"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			updated, err := client.Note.FindUnique(Note.ID.Equals("a")).Update(
+				Note.Content.Set("Changed"),
+			).IfVersion(note.UpdatedAt).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = client.Note.FindUnique(Note.ID.Equals("a")).Update(
+				Note.Content.Set("Stale"),
+			).IfVersion(note.UpdatedAt).Exec(ctx)
+			if !IsErrStaleRecord(err) {
+				t.Fatalf("expected ErrStaleRecord, got %v", err)
+			}
+
+			if _, err := client.Note.FindUnique(Note.ID.Equals("a")).Update(
+				Note.Content.Set("Latest"),
+			).IfVersion(updated.UpdatedAt).Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
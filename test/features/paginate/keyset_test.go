@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestKeyset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "all pages",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var ids []string
+			var after string
+			for {
+				page, err := client.Post.FindMany().OrderBy(
+					Post.Published.Order(SortOrderDesc),
+				).Keyset(after, 3).Exec(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, item := range page.Items {
+					ids = append(ids, item.ID)
+				}
+				if page.Next == "" {
+					break
+				}
+				after = page.Next
+			}
+
+			massert.Equal(t, []string{"a", "b", "c", "d"}, ids)
+		},
+	}, {
+		name: "records inserted before the cursor",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			first, err := client.Post.FindMany(
+				Post.Published.Equals(true),
+			).Keyset("", 2).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []PostModel{post("a"), post("b")}, first.Items)
+
+			if _, err := client.Post.CreateOne(
+				Post.ID.Set("0"),
+				Post.Title.Set("0"),
+				Post.Published.Set(true),
+			).Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			second, err := client.Post.FindMany(
+				Post.Published.Equals(true),
+			).Keyset(first.Next, 2).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []PostModel{post("c")}, second.Items)
+			massert.Equal(t, "", second.Next)
+		},
+	}, {
+		name: "invalid cursor",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			_, err := client.Post.FindMany().Keyset("abc", 2).Exec(ctx)
+			massert.Equal(t, true, IsErrInvalidCursor(err))
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOnePost(data: {id: "a", title: "a", published: true}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "b", title: "b", published: true}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "c", title: "c", published: true}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "d", title: "d", published: false}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
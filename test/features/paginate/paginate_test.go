@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/types"
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func post(id string) PostModel {
+	return PostModel{
+		InnerPost: InnerPost{
+			ID:        id,
+			Title:     id,
+			Published: true,
+		},
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "first page",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.Post.FindMany(
+				Post.Published.Equals(true),
+			).OrderBy(
+				Post.ID.Order(SortOrderAsc),
+			).Paginate(1, 2).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, &types.Page[PostModel]{
+				Items:     []PostModel{post("a"), post("b")},
+				Total:     3,
+				Page:      1,
+				PageCount: 2,
+			}, actual)
+		},
+	}, {
+		name: "last page",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.Post.FindMany(
+				Post.Published.Equals(true),
+			).OrderBy(
+				Post.ID.Order(SortOrderAsc),
+			).Take(10).Paginate(2, 2).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, &types.Page[PostModel]{
+				Items:     []PostModel{post("c")},
+				Total:     3,
+				Page:      2,
+				PageCount: 2,
+			}, actual)
+		},
+	}, {
+		name: "beyond the last page",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.Post.FindMany().Paginate(5, 2).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, &types.Page[PostModel]{
+				Items:     []PostModel{},
+				Total:     4,
+				Page:      5,
+				PageCount: 2,
+			}, actual)
+		},
+	}, {
+		name: "within transaction",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var actual *types.Page[PostModel]
+			err := client.Prisma.InteractiveTransaction(ctx, func(tx *PrismaClient) error {
+				var err error
+				actual, err = tx.Post.FindMany(
+					Post.Published.Equals(false),
+				).Paginate(1, 10).Exec(ctx)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, 1, actual.Total)
+			massert.Equal(t, "d", actual.Items[0].ID)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOnePost(data: {id: "a", title: "a", published: true}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "b", title: "b", published: true}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "c", title: "c", published: true}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "d", title: "d", published: false}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
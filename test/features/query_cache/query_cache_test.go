@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/cache"
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+// renamePost changes the title of a post with a raw query, which the cache doesn't notice
+func renamePost(t *testing.T, client *PrismaClient, ctx cx, id, title string) {
+	t.Helper()
+	if _, err := client.Prisma.ExecuteRaw(`UPDATE "Post" SET title = $1 WHERE id = $2`, title, id).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueryCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "invalidate on write",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			find := func() string {
+				post, err := client.Post.FindUnique(Post.ID.Equals("a")).Exec(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return post.Title
+			}
+
+			massert.Equal(t, "First", find())
+
+			renamePost(t, client, ctx, "a", "Renamed")
+			massert.Equal(t, "First", find())
+
+			bypassed, err := client.Post.FindUnique(Post.ID.Equals("a")).Exec(cache.Bypass(ctx))
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Renamed", bypassed.Title)
+
+			if _, err := client.Post.FindUnique(Post.ID.Equals("a")).Update(
+				Post.Title.Set("Updated"),
+			).Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Updated", find())
+		},
+	}, {
+		name: "invalidate relations",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			titles := func() []string {
+				user, err := client.User.FindUnique(User.ID.Equals("alice")).With(
+					User.Posts.Fetch(),
+				).Exec(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var titles []string
+				for _, post := range user.Posts() {
+					titles = append(titles, post.Title)
+				}
+				return titles
+			}
+
+			massert.Equal(t, []string{"First"}, titles())
+
+			if _, err := client.Post.CreateOne(
+				Post.ID.Set("b"),
+				Post.Title.Set("Second"),
+				Post.Author.Link(User.ID.Equals("alice")),
+			).Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []string{"First", "Second"}, titles())
+		},
+	}, {
+		name: "invalidate after transaction",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			find := func() string {
+				post, err := client.Post.FindUnique(Post.ID.Equals("a")).Exec(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return post.Title
+			}
+
+			massert.Equal(t, "First", find())
+
+			err := client.Prisma.InteractiveTransaction(ctx, func(tx *PrismaClient) error {
+				_, err := tx.Post.FindUnique(Post.ID.Equals("a")).Update(
+					Post.Title.Set("Updated"),
+				).Exec(ctx)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Updated", find())
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient(WithCache(cache.NewLRU(100)))
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOneUser(data: {
+							id: "alice",
+							name: "Alice",
+							posts: {
+								create: [{id: "a", title: "First"}],
+							},
+						}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
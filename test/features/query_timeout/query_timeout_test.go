@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+type activity struct {
+	Count int `json:"count"`
+}
+
+// runningSleeps returns how many pg_sleep statements of the database are still running
+func runningSleeps(t *testing.T, client *PrismaClient, ctx cx) int {
+	t.Helper()
+	var result []activity
+	if err := client.Prisma.QueryRaw(`SELECT count(*)::int AS count FROM pg_stat_activity WHERE state = 'active' AND query LIKE 'SELECT pg_sleep%'`).Exec(ctx, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result[0].Count
+}
+
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []func(*PrismaConfig)
+		before  []string
+		run     Func
+	}{{
+		name: "within timeout",
+		before: []string{`
+			mutation {
+				result: createOnePost(data: {
+					id: "a",
+					title: "First",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			post, err := client.Post.FindUnique(
+				Post.ID.Equals("a"),
+			).Exec(WithTimeout(ctx, 5*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "First", post.Title)
+		},
+	}, {
+		name: "cancels statement",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var result []map[string]interface{}
+			err := client.Prisma.QueryRaw(`SELECT pg_sleep(5)`).Exec(WithTimeout(ctx, 200*time.Millisecond), &result)
+			if err == nil {
+				t.Fatal("expected a timeout error")
+			}
+
+			// the database cancelled the statement instead of sleeping until it is done
+			time.Sleep(500 * time.Millisecond)
+			massert.Equal(t, 0, runningSleeps(t, client, ctx))
+		},
+	}, {
+		name: "within transaction",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			err := client.Prisma.InteractiveTransaction(ctx, func(tx *PrismaClient) error {
+				_, err := tx.Post.CreateOne(
+					Post.ID.Set("b"),
+					Post.Title.Set("Second"),
+				).Exec(WithTimeout(ctx, 5*time.Second))
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			post, err := client.Post.FindUnique(Post.ID.Equals("b")).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Second", post.Title)
+		},
+	}, {
+		name:    "cancelled context",
+		options: []func(*PrismaConfig){WithStatementCancellation()},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			cancelled, cancel := context.WithCancel(ctx)
+			time.AfterFunc(200*time.Millisecond, cancel)
+
+			var result []map[string]interface{}
+			err := client.Prisma.QueryRaw(`SELECT pg_sleep(5)`).Exec(cancelled, &result)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context canceled, got %v", err)
+			}
+
+			time.Sleep(500 * time.Millisecond)
+			massert.Equal(t, 0, runningSleeps(t, client, ctx))
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient(tt.options...)
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
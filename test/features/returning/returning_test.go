@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func post(id, title string, published bool) PostModel {
+	return PostModel{
+		InnerPost: InnerPost{
+			ID:        id,
+			Title:     title,
+			Published: published,
+		},
+	}
+}
+
+func TestReturning(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "update many",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.Post.FindMany(
+				Post.Published.Equals(false),
+			).Update(
+				Post.Published.Set(true),
+			).Returning().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, []PostModel{post("a", "A", true), post("b", "B", true)}, actual)
+		},
+	}, {
+		name: "delete many",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.Post.FindMany(
+				Post.Title.Equals("A"),
+			).Delete().Returning().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []PostModel{post("a", "A", false)}, actual)
+
+			count, err := client.Post.FindMany().Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 2, count)
+		},
+	}, {
+		name: "no records",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.Post.FindMany(
+				Post.Title.Equals("D"),
+			).Delete().Returning().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []PostModel{}, actual)
+		},
+	}, {
+		name: "within transaction",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var actual []PostModel
+			err := client.Prisma.InteractiveTransaction(ctx, func(tx *PrismaClient) error {
+				var err error
+				actual, err = tx.Post.FindMany(
+					Post.Published.Equals(true),
+				).Update(
+					Post.Title.Set("Updated"),
+				).Returning().Exec(ctx)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, []PostModel{post("c", "Updated", true)}, actual)
+		},
+	}, {
+		name: "update without result",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			err := client.Post.FindUnique(
+				Post.ID.Equals("a"),
+			).Update(
+				Post.Title.Set("Updated"),
+			).ExecWithoutResult(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := client.Post.FindUnique(Post.ID.Equals("a")).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "Updated", actual.Title)
+
+			err = client.Post.FindUnique(
+				Post.ID.Equals("d"),
+			).Update(
+				Post.Title.Set("Updated"),
+			).ExecWithoutResult(ctx)
+			massert.Equal(t, ErrNotFound, err)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOnePost(data: {id: "a", title: "A", published: false}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "b", title: "B", published: false}) {
+							id
+						}
+					}
+				`, `
+					mutation {
+						result: createOnePost(data: {id: "c", title: "C", published: true}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
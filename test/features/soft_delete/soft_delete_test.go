@@ -0,0 +1,236 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestSoftDelete(t *testing.T) {
+	t.Parallel()
+
+	before := []string{`
+		mutation {
+			result: createOneUser(data: {
+				id: "alice",
+				email: "alice@example.com",
+				posts: {
+					create: [
+						{ id: "a1", title: "First" },
+						{ id: "a2", title: "Second", deletedAt: "2024-01-01T00:00:00Z" },
+					],
+				},
+			}) {
+				id
+			}
+		}
+	`, `
+		mutation {
+			result: createOneUser(data: {
+				id: "bob",
+				email: "bob@example.com",
+				deletedAt: "2024-01-01T00:00:00Z",
+			}) {
+				id
+			}
+		}
+	`}
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name:   "find excludes deleted records",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			users, err := client.User.FindMany().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(users))
+			massert.Equal(t, "alice", users[0].ID)
+
+			_, err = client.User.FindUnique(User.ID.Equals("bob")).Exec(ctx)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("expected ErrNotFound, got %v", err)
+			}
+
+			count, err := client.User.FindMany().Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, count)
+
+			user, err := client.User.FindUnique(User.ID.Equals("alice")).With(
+				User.Posts.Fetch(),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(user.Posts()))
+			massert.Equal(t, "a1", user.Posts()[0].ID)
+		},
+	}, {
+		name:   "with deleted and only deleted",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			users, err := client.User.FindMany().WithDeleted().OrderBy(User.ID.Order(SortOrderAsc)).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 2, len(users))
+
+			bob, err := client.User.FindUnique(User.ID.Equals("bob")).WithDeleted().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := bob.DeletedAt(); !ok {
+				t.Fatal("expected deletedAt to be set")
+			}
+
+			deleted, err := client.User.FindMany().OnlyDeleted().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(deleted))
+			massert.Equal(t, "bob", deleted[0].ID)
+
+			user, err := client.User.FindUnique(User.ID.Equals("alice")).With(
+				User.Posts.Fetch().OnlyDeleted(),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(user.Posts()))
+			massert.Equal(t, "a2", user.Posts()[0].ID)
+
+			// filters on the field are combined with the scope
+			posts, err := client.Post.FindMany(
+				Post.DeletedAt.IsNotNull(),
+			).WithDeleted().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(posts))
+
+			posts, err = client.Post.FindMany(
+				Post.DeletedAt.IsNotNull(),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 0, len(posts))
+		},
+	}, {
+		name:   "relation filters exclude deleted records",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			users, err := client.User.FindMany(
+				User.Posts.Some(Post.Title.Equals("Second")),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 0, len(users))
+
+			users, err = client.User.FindMany(
+				User.Posts.Every(Post.Title.Equals("First")),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(users))
+			massert.Equal(t, "alice", users[0].ID)
+
+			// filters on the field match deleted records
+			users, err = client.User.FindMany(
+				User.Posts.Some(Post.DeletedAt.IsNotNull()),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(users))
+
+			posts, err := client.Post.FindMany(
+				Post.Author.Where(User.ID.Equals("alice")),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(posts))
+		},
+	}, {
+		name:   "delete sets the field",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			deleted, err := client.User.FindUnique(User.ID.Equals("alice")).Delete().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := deleted.DeletedAt(); !ok {
+				t.Fatal("expected deletedAt to be set")
+			}
+
+			_, err = client.User.FindUnique(User.ID.Equals("alice")).Exec(ctx)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("expected ErrNotFound, got %v", err)
+			}
+
+			result, err := client.Post.FindMany().Delete().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, result.Count)
+
+			count, err := client.Post.FindMany().WithDeleted().Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 2, count)
+		},
+	}, {
+		name:   "hard delete",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			result, err := client.Post.FindMany().OnlyDeleted().HardDelete().Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, result.Count)
+
+			count, err := client.Post.FindMany().WithDeleted().Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, count)
+
+			// soft-deleted records are not found by HardDelete unless they are included
+			_, err = client.User.FindUnique(User.ID.Equals("bob")).HardDelete().Exec(ctx)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("expected ErrNotFound, got %v", err)
+			}
+
+			if _, err := client.User.FindUnique(User.ID.Equals("bob")).WithDeleted().HardDelete().Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
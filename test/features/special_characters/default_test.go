@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/fixtures"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+func TestSpecialCharacters(t *testing.T) {
+	test.RunSerial(t, []test.Database{test.MySQL, test.PostgreSQL, test.SQLite}, func(t *testing.T, db test.Database, ctx context.Context) {
+		client := NewClient()
+		mockDB := test.Start(t, db, client.Engine, []string{})
+		defer test.End(t, db, client.Engine, mockDB)
+
+		for _, tt := range fixtures.SpecialStrings {
+			t.Run(tt.Name, func(t *testing.T) {
+				data, err := json.Marshal(map[string]string{"value": tt.Value})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				created, err := client.User.CreateOne(
+					User.Name.Set(tt.Value),
+					User.JSON.Set(data),
+				).Exec(ctx)
+				if err != nil {
+					t.Fatalf("fail %s", err)
+				}
+
+				massert.Equal(t, tt.Value, created.Name)
+
+				actual, err := client.User.FindFirst(
+					User.ID.Equals(created.ID),
+					User.Name.Equals(tt.Value),
+					User.Name.Contains(tt.Value),
+				).Exec(ctx)
+				if err != nil {
+					t.Fatalf("fail %s", err)
+				}
+
+				massert.Equal(t, tt.Value, actual.Name)
+
+				raw, ok := actual.JSON()
+				if !ok {
+					t.Fatalf("expected json to be set")
+				}
+
+				var decoded map[string]string
+				if err := json.Unmarshal(raw, &decoded); err != nil {
+					t.Fatal(err)
+				}
+
+				massert.Equal(t, tt.Value, decoded["value"])
+			})
+		}
+	})
+}
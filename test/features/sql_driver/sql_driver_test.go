@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/sqldriver"
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestSQLDriver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "query",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			db := sqldriver.Open(client.Engine)
+			defer db.Close()
+
+			var id, title string
+			var views int64
+			var createdAt time.Time
+			var content sql.NullString
+			err := db.QueryRowContext(ctx, `SELECT title, id, views, "createdAt", content FROM "Post" WHERE id = $1`, "a").
+				Scan(&title, &id, &views, &createdAt, &content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, "a", id)
+			massert.Equal(t, "A", title)
+			massert.Equal(t, int64(5), views)
+			massert.Equal(t, true, createdAt.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+			massert.Equal(t, false, content.Valid)
+		},
+	}, {
+		name: "transaction",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			db := sqldriver.Open(client.Engine)
+			defer db.Close()
+
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := tx.ExecContext(ctx, `UPDATE "Post" SET title = $1`, "B")
+			if err != nil {
+				t.Fatal(err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, int64(1), affected)
+			if err := tx.Rollback(); err != nil {
+				t.Fatal(err)
+			}
+
+			post, err := client.Post.FindUnique(Post.ID.Equals("a")).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "A", post.Title)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOnePost(data: {id: "a", title: "A", views: 5, createdAt: "2020-01-02T03:04:05Z"}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
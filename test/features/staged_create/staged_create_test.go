@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestStagedCreate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "required and optional fields",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			user, err := client.User.Create().
+				Email("alice@example.com").
+				Name("Alice").
+				ID("alice").
+				Nickname("al").
+				Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, &UserModel{
+				InnerUser: InnerUser{
+					ID:       "alice",
+					Email:    "alice@example.com",
+					Name:     "Alice",
+					Nickname: Ptr("al"),
+				},
+			}, user)
+		},
+	}, {
+		name: "required relation",
+		before: []string{`
+			mutation {
+				result: createOneUser(data: {
+					id: "alice",
+					email: "alice@example.com",
+					name: "Alice",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			post, err := client.Post.Create().
+				Title("Hello").
+				Author(Post.Author.Link(User.ID.Equals("alice"))).
+				Set(Post.ID.Set("post")).
+				Build().
+				With(Post.Author.Fetch()).
+				Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, "post", post.ID)
+			massert.Equal(t, "Hello", post.Title)
+			massert.Equal(t, "Alice", post.Author().Name)
+		},
+	}, {
+		name: "same query as CreateOne",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			staged := client.User.Create().Email("a").Name("b")
+
+			expected, err := client.User.CreateOne(
+				User.Email.Set("a"),
+				User.Name.Set("b"),
+				User.Nickname.Set("c"),
+			).ExtractQuery().Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := staged.Nickname("c").Build().ExtractQuery().Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, expected, actual)
+
+			// steps can be reused without affecting each other
+			expected, err = client.User.CreateOne(
+				User.Email.Set("a"),
+				User.Name.Set("b"),
+			).ExtractQuery().Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err = staged.Build().ExtractQuery().Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, expected, actual)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/runtime/transaction"
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+type tenantKey struct{}
+
+type setting struct {
+	Tenant string `json:"tenant"`
+}
+
+// currentTenant returns the tenant setting of the connection, which is empty outside of transactions
+func currentTenant(t *testing.T, client *PrismaClient, ctx cx) string {
+	t.Helper()
+	var result []setting
+	if err := client.Prisma.QueryRaw(`SELECT coalesce(current_setting('app.tenant_id', true), '') AS tenant`).Exec(ctx, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result[0].Tenant
+}
+
+func TestTransactionSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name: "set local",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			err := client.Prisma.InteractiveTransaction(ctx, func(tx *PrismaClient) error {
+				massert.Equal(t, "tenant_1", currentTenant(t, tx, ctx))
+				return nil
+			}, transaction.WithSetLocal("app.tenant_id", "tenant_1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, "", currentTenant(t, client, ctx))
+		},
+	}, {
+		name: "set local from context",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			tenant := transaction.WithSetLocalFunc(func(ctx context.Context) (map[string]string, error) {
+				return map[string]string{"app.tenant_id": ctx.Value(tenantKey{}).(string)}, nil
+			})
+
+			ctx = context.WithValue(ctx, tenantKey{}, "tenant_2")
+			err := client.Prisma.InteractiveTransaction(ctx, func(tx *PrismaClient) error {
+				_, err := tx.Post.CreateOne(
+					Post.ID.Set("a"),
+					Post.Title.Set("First"),
+					Post.TenantID.Set(currentTenant(t, tx, ctx)),
+				).Exec(ctx)
+				return err
+			}, tenant)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			post, err := client.Post.FindUnique(Post.ID.Equals("a")).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "tenant_2", post.TenantID)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+func TestUpdateFromStruct(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  Func
+	}{{
+		name: "mask",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			var payload UserModel
+			if err := json.Unmarshal([]byte(`{"name": "B", "age": 0, "email": "ignored@example.com"}`), &payload); err != nil {
+				t.Fatal(err)
+			}
+
+			params, err := User.UpdateFromStruct(payload, "name", "age", "bio")
+			if err != nil {
+				t.Fatal(err)
+			}
+			user, err := client.User.FindUnique(User.ID.Equals("a")).Update(params...).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, "a@example.com", user.Email)
+			massert.Equal(t, "B", user.Name)
+			massert.Equal(t, 0, user.Age)
+			massert.Equal(t, (*string)(nil), user.InnerUser.Bio)
+		},
+	}, {
+		name: "non-zero fields",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			params, err := User.UpdateFromStruct(UserModel{
+				InnerUser: InnerUser{
+					Name: "B",
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			user, err := client.User.FindUnique(User.ID.Equals("a")).Update(params...).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			massert.Equal(t, "a@example.com", user.Email)
+			massert.Equal(t, "B", user.Name)
+			massert.Equal(t, 20, user.Age)
+			bio, _ := user.Bio()
+			massert.Equal(t, "bio", bio)
+		},
+	}, {
+		name: "invalid mask",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			_, err := User.UpdateFromStruct(UserModel{}, "name", "posts")
+			massert.Equal(t, true, IsErrInvalidFieldMask(err))
+			massert.Equal(t, `ErrInvalidFieldMask: "posts" is not a field of User which can be updated`, err.Error())
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, []string{`
+					mutation {
+						result: createOneUser(data: {id: "a", email: "a@example.com", name: "A", age: 20, bio: "bio"}) {
+							id
+						}
+					}
+				`})
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
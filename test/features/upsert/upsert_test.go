@@ -124,6 +124,116 @@ func TestUpsert(t *testing.T) {
 
 			massert.Equal(t, expected, query.Result())
 		},
+	}, {
+		name: "upsert batch",
+		// language=GraphQL
+		before: []string{`
+			mutation {
+				result: createOnePost(data: {
+					id: "existing",
+					title: "title",
+					views: 0,
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			upsert := client.Post.UpsertBatch()
+			for _, id := range []string{"existing", "new"} {
+				upsert = upsert.Add(client.Post.UpsertOne(
+					Post.ID.Equals(id),
+				).Create(
+					Post.Title.Set("created"),
+					Post.Views.Set(0),
+					Post.ID.Set(id),
+				).Update(
+					Post.Title.Set("updated"),
+					Post.Views.Increment(1),
+				))
+			}
+
+			actual, err := upsert.Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			expected := []PostModel{{
+				InnerPost: InnerPost{
+					ID:    "existing",
+					Title: "updated",
+					Views: 1,
+				},
+			}, {
+				InnerPost: InnerPost{
+					ID:    "new",
+					Title: "created",
+					Views: 0,
+				},
+			}}
+
+			massert.Equal(t, expected, actual)
+		},
+	}, {
+		name: "upsert batch in a single statement",
+		// language=GraphQL
+		before: []string{`
+			mutation {
+				result: createOnePost(data: {
+					id: "existing",
+					title: "title",
+					views: 5,
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			// the updates set the fields to the created values, so the batch runs as INSERT ... ON CONFLICT
+			upsert := client.Post.UpsertBatch()
+			for _, id := range []string{"new", "existing"} {
+				upsert = upsert.Add(client.Post.UpsertOne(
+					Post.ID.Equals(id),
+				).Create(
+					Post.Title.Set("synced "+id),
+					Post.Views.Set(0),
+					Post.ID.Set(id),
+				).Update(
+					Post.Title.Set("synced " + id),
+				))
+			}
+
+			actual, err := upsert.Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			expected := []PostModel{{
+				InnerPost: InnerPost{
+					ID:    "new",
+					Title: "synced new",
+					Views: 0,
+				},
+			}, {
+				InnerPost: InnerPost{
+					ID:    "existing",
+					Title: "synced existing",
+					Views: 5,
+				},
+			}}
+
+			massert.Equal(t, expected, actual)
+		},
+	}, {
+		name: "upsert batch without upserts",
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.Post.UpsertBatch().Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			massert.Equal(t, []PostModel{}, actual)
+		},
 	}}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steebchen/prisma-client-go/test"
+	"github.com/steebchen/prisma-client-go/test/helpers/massert"
+)
+
+type cx = context.Context
+type Func func(t *testing.T, client *PrismaClient, ctx cx)
+
+// createView creates the view of the schema, as views are not created by db push
+func createView(t *testing.T, client *PrismaClient, ctx cx) {
+	t.Helper()
+	if _, err := client.Prisma.ExecuteRaw(`
+		CREATE VIEW user_stats AS
+		SELECT u.id AS "userID", u.email, count(p.id)::int AS posts
+		FROM "User" u LEFT JOIN "Post" p ON p."authorID" = u.id
+		GROUP BY u.id, u.email
+	`).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestViews(t *testing.T) {
+	t.Parallel()
+
+	before := []string{`
+		mutation {
+			result: createOneUser(data: {
+				id: "alice",
+				email: "alice@example.com",
+				posts: {
+					create: [{ id: "a1", title: "First" }, { id: "a2", title: "Second" }],
+				},
+			}) {
+				id
+			}
+		}
+	`, `
+		mutation {
+			result: createOneUser(data: {
+				id: "bob",
+				email: "bob@example.com",
+			}) {
+				id
+			}
+		}
+	`}
+
+	tests := []struct {
+		name   string
+		before []string
+		run    Func
+	}{{
+		name:   "find",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			createView(t, client, ctx)
+
+			stats, err := client.UserStats.FindMany(
+				UserStats.Posts.Gt(0),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, len(stats))
+			massert.Equal(t, "alice@example.com", stats[0].Email)
+			massert.Equal(t, 2, stats[0].Posts)
+
+			first, err := client.UserStats.FindFirst(
+				UserStats.Email.Equals("bob@example.com"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 0, first.Posts)
+
+			unique, err := client.UserStats.FindUnique(
+				UserStats.UserID.Equals("alice"),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, "alice@example.com", unique.Email)
+		},
+	}, {
+		name:   "count",
+		before: before,
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			createView(t, client, ctx)
+
+			count, err := client.UserStats.FindMany().Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 2, count)
+
+			count, err = client.UserStats.FindMany(
+				UserStats.Posts.Equals(0),
+			).Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, count)
+
+			// models can be counted as well
+			count, err = client.Post.FindMany().Take(1).Count(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			massert.Equal(t, 1, count)
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.RunSerial(t, []test.Database{test.PostgreSQL}, func(t *testing.T, db test.Database, ctx context.Context) {
+				client := NewClient()
+				mockDBName := test.Start(t, db, client.Engine, tt.before)
+				defer test.End(t, db, client.Engine, mockDBName)
+				tt.run(t, client, context.Background())
+			})
+		})
+	}
+}
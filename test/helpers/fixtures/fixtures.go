@@ -0,0 +1,26 @@
+// Package fixtures contains test values which are shared by the tests of several packages
+package fixtures
+
+// SpecialString is a string which is hard to escape, named after what makes it special
+type SpecialString struct {
+	Name  string
+	Value string
+}
+
+// SpecialStrings are strings which must survive encoding, e.g. into query strings and JSON, and round trips through
+// the database unchanged
+var SpecialStrings = []SpecialString{
+	{"empty", ``},
+	{"double quotes", `say "hi"`},
+	{"single quotes", `it's`},
+	{"backticks", "`code`"},
+	{"backslashes", `C:\path\to\file \\ \n`},
+	{"newlines and tabs", "line1\nline2\r\n\ttabbed"},
+	{"emoji", "smile 😀 family 👨‍👩‍👧"},
+	{"multi-byte", "日本語 ümlaut ß"},
+	{"NUL-adjacent control characters", "a\x01b\x07c\x1fd\x7fe"},
+	{"line and paragraph separators", "a\u2028b\u2029c"},
+	{"html characters", `<script>alert("&")</script>`},
+	{"graphql syntax", `"}) { result: deleteManyUser { count } } #`},
+	{"graphql variables and directives", `{ query: "x" } $var @directive`},
+}
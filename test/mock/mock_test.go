@@ -86,3 +86,93 @@ func TestMockError(t *testing.T) {
 	massert.Equal(t, expectedErr, err)
 	massert.Equal(t, true, actual == nil)
 }
+
+func TestNewMockClient(t *testing.T) {
+	client, mock := NewMockClient(t)
+
+	first := UserModel{InnerUser: InnerUser{ID: "123", Name: "first"}}
+	second := UserModel{InnerUser: InnerUser{ID: "123", Name: "second"}}
+
+	// the same query can be expected multiple times, and the results are returned in order
+	mock.User.Expect(client.User.FindUnique(User.ID.Equals("123"))).Returns(first)
+	mock.User.Expect(client.User.FindUnique(User.ID.Equals("123"))).Returns(second)
+
+	actual, err := client.User.FindUnique(User.ID.Equals("123")).Exec(context.Background())
+	massert.Equal(t, nil, err)
+	massert.Equal(t, "first", actual.Name)
+
+	actual, err = client.User.FindUnique(User.ID.Equals("123")).Exec(context.Background())
+	massert.Equal(t, nil, err)
+	massert.Equal(t, "second", actual.Name)
+
+	// queries without an expectation return an error
+	_, err = client.User.FindUnique(User.ID.Equals("456")).Exec(context.Background())
+	massert.Equal(t, true, err != nil)
+}
+
+func TestMockTransaction(t *testing.T) {
+	client, mock := NewMockClient(t)
+
+	expected := UserModel{InnerUser: InnerUser{ID: "123", Name: "foo"}}
+	create := client.User.CreateOne(User.ID.Set("123"), User.Name.Set("foo")).Tx()
+	mock.User.Expect(client.User.CreateOne(User.ID.Set("123"), User.Name.Set("foo"))).Returns(expected)
+
+	if err := client.Prisma.Transaction(create).Exec(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	massert.Equal(t, expected, *create.Result())
+}
+
+func TestClientInterface(t *testing.T) {
+	// application code depends on the interface
+	do := func(ctx context.Context, client Client) (*UserModel, error) {
+		return client.UserActions().FindUnique(User.ID.Equals("123")).Exec(ctx)
+	}
+
+	client, mock := NewMockClient(t)
+	expected := UserModel{InnerUser: InnerUser{ID: "123", Name: "foo"}}
+	mock.User.Expect(client.User.FindUnique(User.ID.Equals("123"))).Returns(expected)
+
+	actual, err := do(context.Background(), client)
+	massert.Equal(t, nil, err)
+	massert.Equal(t, expected, *actual)
+}
+
+// fakeClient is a hand-written fake of Client, which embeds the interface so that it only implements the methods used
+type fakeClient struct {
+	Client
+	users UserActions
+}
+
+func (c fakeClient) UserActions() UserActions {
+	return c.users
+}
+
+type fakeUserActions struct {
+	UserActions
+	user UserModel
+}
+
+func (f fakeUserActions) FindUnique(params UserEqualsUniqueWhereParam) UserFindUniqueQuery {
+	return fakeUserFindUnique{user: f.user}
+}
+
+type fakeUserFindUnique struct {
+	UserFindUniqueQuery
+	user UserModel
+}
+
+func (f fakeUserFindUnique) Exec(ctx context.Context) (*UserModel, error) {
+	return &f.user, nil
+}
+
+func TestClientInterface_fake(t *testing.T) {
+	do := func(ctx context.Context, client Client) (*UserModel, error) {
+		return client.UserActions().FindUnique(User.ID.Equals("123")).Exec(ctx)
+	}
+
+	expected := UserModel{InnerUser: InnerUser{ID: "123", Name: "foo"}}
+	actual, err := do(context.Background(), fakeClient{users: fakeUserActions{user: expected}})
+	massert.Equal(t, nil, err)
+	massert.Equal(t, expected, *actual)
+}
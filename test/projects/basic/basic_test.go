@@ -3,12 +3,10 @@ package basic
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/steebchen/prisma-client-go/runtime/builder"
 	"github.com/steebchen/prisma-client-go/test"
 	"github.com/steebchen/prisma-client-go/test/helpers/massert"
 )
@@ -836,7 +834,7 @@ func TestBasic(t *testing.T) {
 			}
 		`},
 		run: func(t *testing.T, client *PrismaClient, ctx cx) {
-			_, err := client.User.FindMany(
+			actual, err := client.User.FindMany(
 				User.Or(
 					User.And(
 						User.Email.Equals("email1"),
@@ -857,8 +855,31 @@ func TestBasic(t *testing.T) {
 			).OrderBy(
 				User.ID.Order(SortOrderAsc),
 			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
 
-			assert.Equal(t, builder.ErrDuplicateField, errors.Unwrap(err))
+			expected := []UserModel{{
+				InnerUser: InnerUser{
+					ID:       "id1",
+					Email:    "email1",
+					Username: "a",
+				},
+			}, {
+				InnerUser: InnerUser{
+					ID:       "id2",
+					Email:    "email2",
+					Username: "b",
+				},
+			}, {
+				InnerUser: InnerUser{
+					ID:       "id4",
+					Email:    "email4",
+					Username: "d",
+				},
+			}}
+
+			massert.Equal(t, expected, actual)
 		},
 	}, {
 		name: "id in",
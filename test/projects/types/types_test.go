@@ -355,6 +355,69 @@ func TestTypes(t *testing.T) {
 			massert.Equal(t, expected, actual)
 		},
 	}, {
+		name: "IsNotNull",
+		// language=GraphQL
+		before: []string{`
+			mutation {
+				result: createOneUser(data: {
+					id: "id1",
+					createdAt: "2000-01-01T00:00:00Z",
+					updatedAt: "2000-01-01T00:00:00Z",
+					str: "filled",
+					strOpt: "filled",
+					bool: true,
+					date: "2000-01-01T00:00:00Z",
+					int: 5,
+					float: 5.5,
+					type: "x",
+				}) {
+					id
+				}
+			}
+		`, `
+			mutation {
+				result: createOneUser(data: {
+					id: "id2",
+					createdAt: "2000-01-01T00:00:00Z",
+					updatedAt: "2000-01-01T00:00:00Z",
+					str: "",
+					strOpt: null,
+					bool: true,
+					date: "2000-01-01T00:00:00Z",
+					int: 5,
+					float: 5.5,
+					type: "x",
+				}) {
+					id
+				}
+			}
+		`},
+		run: func(t *testing.T, client *PrismaClient, ctx cx) {
+			actual, err := client.User.FindMany(
+				User.StrOpt.IsNotNull(),
+			).Exec(ctx)
+			if err != nil {
+				t.Fatalf("fail %s", err)
+			}
+
+			expected := []UserModel{{
+				InnerUser: InnerUser{
+					ID:        "id1",
+					CreatedAt: date,
+					UpdatedAt: date,
+					Str:       "filled",
+					StrOpt:    Ptr("filled"),
+					Int:       5,
+					Float:     5.5,
+					Bool:      true,
+					Date:      date,
+					Type:      "x",
+				},
+			}}
+
+			massert.Equal(t, expected, actual)
+		},
+	}, {
 		name: "nullable dynamic nil field",
 		// language=GraphQL
 		before: []string{`